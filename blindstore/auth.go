@@ -0,0 +1,126 @@
+package blindstore
+
+import (
+	"context"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"redwood.dev/crypto"
+	"redwood.dev/types"
+)
+
+// Every request to the blind store is signed by the node making it, so that
+// the server only serves the nodes it has been configured to trust.  The
+// signature covers the method, the marshaled request and a timestamp, and the
+// server rejects requests that are too old or that it has already seen.
+
+const (
+	authTimestampKey = "blindstore-timestamp"
+	authSignatureKey = "blindstore-signature"
+
+	// maxClockSkew is how far a request's timestamp may be from the server's
+	// clock before the request is rejected.
+	maxClockSkew = time.Minute
+)
+
+// Signer is satisfied by identity.Identity and *crypto.SigKeypair.
+type Signer interface {
+	Address() types.Address
+	SignHash(hash types.Hash) ([]byte, error)
+}
+
+func requestHash(method string, timestamp int64, req proto.Message) (types.Hash, error) {
+	bs, err := proto.Marshal(req)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	return types.HashBytes(append([]byte(method+":"+strconv.FormatInt(timestamp, 10)+":"), bs...)), nil
+}
+
+func signRequest(ctx context.Context, signer Signer, method string, req proto.Message) (context.Context, error) {
+	timestamp := time.Now().UnixNano()
+	hash, err := requestHash(method, timestamp, req)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.SignHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx,
+		authTimestampKey, strconv.FormatInt(timestamp, 10),
+		authSignatureKey, hex.EncodeToString(sig),
+	), nil
+}
+
+// authenticator checks the signatures on incoming requests.
+type authenticator struct {
+	clients types.AddressSet
+
+	seen   map[types.Hash]time.Time // requests seen within the last 2*maxClockSkew
+	seenMu sync.Mutex
+}
+
+func newAuthenticator(clients []types.Address) *authenticator {
+	return &authenticator{
+		clients: types.NewAddressSet(clients),
+		seen:    make(map[types.Hash]time.Time),
+	}
+}
+
+// authenticate returns the address of the client that signed the request, or
+// an Unauthenticated/PermissionDenied status if it's not a signed, fresh
+// request from one of the configured clients.
+func (a *authenticator) authenticate(ctx context.Context, method string, req proto.Message) (types.Address, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	timestampStrs := md.Get(authTimestampKey)
+	sigStrs := md.Get(authSignatureKey)
+	if len(timestampStrs) != 1 || len(sigStrs) != 1 {
+		return types.Address{}, status.Error(codes.Unauthenticated, "request is not signed")
+	}
+
+	timestamp, err := strconv.ParseInt(timestampStrs[0], 10, 64)
+	if err != nil {
+		return types.Address{}, status.Error(codes.Unauthenticated, "bad request timestamp")
+	}
+	now := time.Now()
+	if age := now.Sub(time.Unix(0, timestamp)); age > maxClockSkew || age < -maxClockSkew {
+		return types.Address{}, status.Error(codes.Unauthenticated, "request timestamp is too far from the server's clock")
+	}
+
+	sig, err := hex.DecodeString(sigStrs[0])
+	if err != nil {
+		return types.Address{}, status.Error(codes.Unauthenticated, "bad request signature")
+	}
+	hash, err := requestHash(method, timestamp, req)
+	if err != nil {
+		return types.Address{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	pubkey, err := crypto.RecoverSigningPubkey(hash, sig)
+	if err != nil || !pubkey.VerifySignature(hash, sig) {
+		return types.Address{}, status.Error(codes.Unauthenticated, "bad request signature")
+	} else if !a.clients.Contains(pubkey.Address()) {
+		return types.Address{}, status.Errorf(codes.PermissionDenied, "%v is not a client of this blind store", pubkey.Address().Hex())
+	}
+
+	a.seenMu.Lock()
+	defer a.seenMu.Unlock()
+	for h, seenAt := range a.seen {
+		if now.Sub(seenAt) > 2*maxClockSkew {
+			delete(a.seen, h)
+		}
+	}
+	if _, exists := a.seen[hash]; exists {
+		return types.Address{}, status.Error(codes.Unauthenticated, "request has already been seen")
+	}
+	a.seen[hash] = now
+
+	return pubkey.Address(), nil
+}
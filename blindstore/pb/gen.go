@@ -0,0 +1,3 @@
+package pb
+
+//go:generate protoc -I=. -I=$GOPATH/src --gogoslick_opt=paths=source_relative --gogoslick_out=plugins=grpc:. blindstore.proto
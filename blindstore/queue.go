@@ -0,0 +1,162 @@
+package blindstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"redwood.dev/errors"
+	"redwood.dev/log"
+)
+
+// opQueue is the TxStore's queue of ops that haven't been applied to the
+// blind store yet.  It's kept in its own badger DB so that ops survive a
+// crash or restart, and are applied in the order they were pushed.
+type opQueue struct {
+	log.Logger
+	db         *badger.DB
+	badgerOpts badger.Options
+	nextSeq    uint64
+	mu         sync.Mutex
+	chNotify   chan struct{}
+}
+
+func newOpQueue(badgerOpts badger.Options) *opQueue {
+	return &opQueue{
+		Logger:     log.NewLogger("blindstore"),
+		badgerOpts: badgerOpts,
+		chNotify:   make(chan struct{}, 1),
+	}
+}
+
+func (q *opQueue) Start() error {
+	q.Infof(0, "opening blind store op queue at %v", q.badgerOpts.Dir)
+
+	db, err := badger.Open(q.badgerOpts)
+	if err != nil {
+		return err
+	}
+	q.db = db
+
+	return q.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Reverse = true
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		iter.Rewind()
+		if iter.Valid() {
+			q.nextSeq = binary.BigEndian.Uint64(iter.Item().Key()) + 1
+		}
+		if q.nextSeq > 0 {
+			q.notify()
+		}
+		return nil
+	})
+}
+
+func (q *opQueue) Close() {
+	if q.db != nil {
+		err := q.db.Close()
+		if err != nil {
+			q.Errorf("could not close blind store op queue: %v", err)
+		}
+	}
+}
+
+func seqKey(seq uint64) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], seq)
+	return k[:]
+}
+
+// Push appends ops to the queue.  Either all of them are queued or none are.
+func (q *opQueue) Push(ops ...mirrorOp) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	seq := q.nextSeq
+	err := q.db.Update(func(txn *badger.Txn) error {
+		for _, op := range ops {
+			bs, err := json.Marshal(op)
+			if err != nil {
+				return err
+			}
+			err = txn.Set(seqKey(seq), bs)
+			if err != nil {
+				return err
+			}
+			seq++
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "while queueing blind store ops")
+	}
+	q.nextSeq = seq
+	q.notify()
+	return nil
+}
+
+// Peek returns the op at the front of the queue, if there is one.
+func (q *opQueue) Peek() (seq uint64, op mirrorOp, exists bool, _ error) {
+	err := q.db.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+
+		iter.Rewind()
+		if !iter.Valid() {
+			return nil
+		}
+		seq = binary.BigEndian.Uint64(iter.Item().Key())
+		exists = true
+		return iter.Item().Value(func(bs []byte) error {
+			return json.Unmarshal(bs, &op)
+		})
+	})
+	return seq, op, exists, err
+}
+
+// Remove drops an op from the queue once it has been applied.
+func (q *opQueue) Remove(seq uint64) error {
+	return q.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(seqKey(seq))
+	})
+}
+
+// Keys returns the blind store keys of the queued ops.
+func (q *opQueue) Keys() (map[string]struct{}, error) {
+	keys := make(map[string]struct{})
+	err := q.db.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			var op mirrorOp
+			err := iter.Item().Value(func(bs []byte) error {
+				return json.Unmarshal(bs, &op)
+			})
+			if err != nil {
+				return err
+			}
+			keys[string(op.Key)] = struct{}{}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// Notify fires when ops are pushed.
+func (q *opQueue) Notify() <-chan struct{} {
+	return q.chNotify
+}
+
+func (q *opQueue) notify() {
+	select {
+	case q.chNotify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,388 @@
+package blindstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"redwood.dev/blindstore/pb"
+	"redwood.dev/errors"
+	"redwood.dev/log"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/types"
+)
+
+// Server is the blind store service, backed by a badger DB.
+type Server struct {
+	log.Logger
+	db         *badger.DB
+	badgerOpts badger.Options
+	grpcServer *grpc.Server
+	auth       *authenticator
+	quorum     protoprune.Quorum
+}
+
+var _ pb.BlindStoreServer = (*Server)(nil)
+
+// NewServer creates a blind store server that only serves requests signed by
+// one of `clients` (the addresses of the nodes that mirror their txs to it).
+// Entries are only ever deleted with the approval of `quorum` (see Delete).
+// If `tlsCertFile` and `tlsKeyFile` are empty, the server accepts unencrypted
+// connections.
+func NewServer(badgerOpts badger.Options, tlsCertFile, tlsKeyFile string, clients []types.Address, quorum protoprune.Quorum) (*Server, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("blind store has no clients configured")
+	}
+
+	var opts []grpc.ServerOption
+	if tlsCertFile != "" || tlsKeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(tlsCertFile, tlsKeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while loading blind store TLS cert")
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s := &Server{
+		Logger:     log.NewLogger("blindstore"),
+		badgerOpts: badgerOpts,
+		grpcServer: grpc.NewServer(opts...),
+		auth:       newAuthenticator(clients),
+		quorum:     quorum,
+	}
+	pb.RegisterBlindStoreServer(s.grpcServer, s)
+	return s, nil
+}
+
+func (s *Server) Start() error {
+	s.Infof(0, "opening blind store at %v", s.badgerOpts.Dir)
+
+	db, err := badger.Open(s.badgerOpts)
+	if err != nil {
+		return err
+	}
+	s.db = db
+	return nil
+}
+
+// Serve accepts connections on `listener` until the server is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	s.Infof(0, "blind store listening on %v", listener.Addr())
+	return s.grpcServer.Serve(listener)
+}
+
+func (s *Server) Close() {
+	s.grpcServer.Stop()
+	if s.db != nil {
+		err := s.db.Close()
+		if err != nil {
+			s.Errorf("could not close blind store: %v", err)
+		}
+	}
+}
+
+// The server never overwrites or discards what a client has stored: every Put
+// adds a new version of its entry, and a Delete only removes the versions that
+// existed when the prune certificate approving it was first used.  So a client
+// (or anyone who steals its key) can't destroy an entry by writing over it, or
+// by replaying an old certificate against newer history.
+//
+// Entries are laid out as follows:
+//   - v:<len(key)><key><version> holds one version of an entry's value
+//   - m:<key> holds the commitment made by the entry's first Put (see Delete)
+//   - c:<proposal hash> holds the time at which a certificate was first used
+
+const maxKeyLength = 1024
+
+func versionsPrefix(key []byte) []byte {
+	prefix := make([]byte, 4, 4+len(key))
+	copy(prefix, "v:")
+	binary.BigEndian.PutUint16(prefix[2:], uint16(len(key)))
+	return append(prefix, key...)
+}
+
+func versionKey(key []byte, version uint64) []byte {
+	k := versionsPrefix(key)
+	k = append(k, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(k[len(k)-8:], version)
+	return k
+}
+
+func parseVersionKey(k []byte) (key []byte, version uint64, ok bool) {
+	if len(k) < 12 || string(k[:2]) != "v:" {
+		return nil, 0, false
+	}
+	keyLen := int(binary.BigEndian.Uint16(k[2:4]))
+	if len(k) != 4+keyLen+8 {
+		return nil, 0, false
+	}
+	return k[4 : 4+keyLen], binary.BigEndian.Uint64(k[4+keyLen:]), true
+}
+
+func metaKey(key []byte) []byte {
+	return append([]byte("m:"), key...)
+}
+
+func certKey(cert protoprune.PruneCertificate) []byte {
+	hash := cert.Proposal.Hash()
+	return append([]byte("c:"), hash[:]...)
+}
+
+// EntryCommitment binds an entry to the state URI it belongs to without
+// revealing it.  `salt` should be secret to the client until it asks for the
+// entry to be deleted.
+func EntryCommitment(salt types.Hash, stateURI string) types.Hash {
+	return types.HashBytes(append(salt.Bytes(), []byte("commitment:"+stateURI)...))
+}
+
+// Put stores a new version of an entry.  The first Put of a key fixes its
+// commitment, and later Puts must carry the same one.
+func (s *Server) Put(ctx context.Context, req *pb.PutReq) (*pb.PutResp, error) {
+	_, err := s.auth.authenticate(ctx, "Put", req)
+	if err != nil {
+		return nil, err
+	} else if len(req.Key) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing key")
+	} else if len(req.Key) > maxKeyLength {
+		return nil, status.Error(codes.InvalidArgument, "key is too long")
+	} else if len(req.Commitment) != len(types.Hash{}) {
+		return nil, status.Error(codes.InvalidArgument, "bad commitment")
+	}
+
+	err = s.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(metaKey(req.Key))
+		if err == badger.ErrKeyNotFound {
+			err = txn.Set(metaKey(req.Key), req.Commitment)
+			if err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		} else {
+			commitment, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			} else if !bytes.Equal(commitment, req.Commitment) {
+				return status.Error(codes.PermissionDenied, "commitment does not match the entry's")
+			}
+		}
+
+		latest, value, exists, err := latestVersion(txn, req.Key)
+		if err != nil {
+			return err
+		} else if exists && bytes.Equal(value, req.Value) {
+			return nil
+		}
+		version := uint64(time.Now().UnixNano())
+		if exists && version <= latest {
+			version = latest + 1
+		}
+		return txn.Set(versionKey(req.Key, version), req.Value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PutResp{}, nil
+}
+
+func latestVersion(txn *badger.Txn, key []byte) (version uint64, value []byte, exists bool, _ error) {
+	opts := badger.DefaultIteratorOptions
+	opts.Reverse = true
+	opts.Prefix = versionsPrefix(key)
+	iter := txn.NewIterator(opts)
+	defer iter.Close()
+
+	iter.Seek(versionKey(key, math.MaxUint64))
+	if !iter.Valid() {
+		return 0, nil, false, nil
+	}
+	_, version, _ = parseVersionKey(iter.Item().Key())
+	value, err := iter.Item().ValueCopy(nil)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return version, value, true, nil
+}
+
+// Get returns the latest version of an entry.
+func (s *Server) Get(ctx context.Context, req *pb.GetReq) (*pb.GetResp, error) {
+	_, err := s.auth.authenticate(ctx, "Get", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	var exists bool
+	err = s.db.View(func(txn *badger.Txn) error {
+		_, value, exists, err = latestVersion(txn, req.Key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return &pb.GetResp{Value: value}, nil
+}
+
+// Delete removes an entry, but only if the request carries a prune
+// certificate signed by the server's quorum.  The server can't see which tx
+// an entry holds, so it can't check that the entry is part of the history the
+// certificate approves pruning.  What it does check is that:
+//   - the request reveals the salt behind the commitment made when the entry
+//     was first stored, and that commitment is to the certificate's state URI
+//   - the entry's versions predate the first use of the certificate, so that
+//     an old certificate can't be replayed against history written since
+//
+// Versions written after the certificate's first use are kept.
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteReq) (*pb.DeleteResp, error) {
+	client, err := s.auth.authenticate(ctx, "Delete", req)
+	if err != nil {
+		return nil, err
+	} else if req.Certificate == nil {
+		return nil, status.Error(codes.PermissionDenied, "deletes must be approved by a prune certificate")
+	}
+	cert, err := certFromPB(req.Certificate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad prune certificate: %v", err)
+	}
+	salt, err := types.HashFromBytes(req.Salt)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad salt: %v", err)
+	}
+
+	err = s.quorum.Verify(cert)
+	if err != nil {
+		s.Warnf("rejecting delete from %v: %v", client.Hex(), err)
+		return nil, status.Errorf(codes.PermissionDenied, "bad prune certificate: %v", err)
+	}
+
+	err = s.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(metaKey(req.Key))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		commitment, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		expected := EntryCommitment(salt, cert.Proposal.StateURI)
+		if !bytes.Equal(commitment, expected.Bytes()) {
+			s.Warnf("rejecting delete from %v: certificate is for another state URI, or salt is wrong", client.Hex())
+			return status.Error(codes.PermissionDenied, "entry is not covered by the prune certificate")
+		}
+
+		firstUsed, err := certificateFirstUsed(txn, cert)
+		if err != nil {
+			return err
+		}
+
+		var toDelete [][]byte
+		var numKept int
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = versionsPrefix(req.Key)
+		iter := txn.NewIterator(opts)
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			_, version, _ := parseVersionKey(iter.Item().Key())
+			if version < firstUsed {
+				toDelete = append(toDelete, iter.Item().KeyCopy(nil))
+			} else {
+				numKept++
+			}
+		}
+		iter.Close()
+
+		for _, k := range toDelete {
+			err := txn.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+		if numKept == 0 {
+			return txn.Delete(metaKey(req.Key))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeleteResp{}, nil
+}
+
+// certificateFirstUsed returns the time at which the certificate was first
+// used, recording it as now if it hasn't been used before.
+func certificateFirstUsed(txn *badger.Txn, cert protoprune.PruneCertificate) (uint64, error) {
+	item, err := txn.Get(certKey(cert))
+	if err == badger.ErrKeyNotFound {
+		firstUsed := uint64(time.Now().UnixNano())
+		var bs [8]byte
+		binary.BigEndian.PutUint64(bs[:], firstUsed)
+		return firstUsed, txn.Set(certKey(cert), bs[:])
+	} else if err != nil {
+		return 0, err
+	}
+	bs, err := item.ValueCopy(nil)
+	if err != nil {
+		return 0, err
+	} else if len(bs) != 8 {
+		return 0, errors.Errorf("bad certificate record")
+	}
+	return binary.BigEndian.Uint64(bs), nil
+}
+
+// Iterate streams every version of every entry, with each entry's versions
+// in the order they were written.  If the request is for keys only, each key
+// is sent once.
+func (s *Server) Iterate(req *pb.IterateReq, stream pb.BlindStore_IterateServer) error {
+	_, err := s.auth.authenticate(stream.Context(), "Iterate", req)
+	if err != nil {
+		return err
+	}
+
+	return s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte("v:")
+		opts.PrefetchValues = !req.KeysOnly
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		var prevKey []byte
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			item := iter.Item()
+			key, _, ok := parseVersionKey(item.Key())
+			if !ok {
+				continue
+			}
+			var value []byte
+			if req.KeysOnly {
+				// An entry's versions are adjacent
+				if prevKey != nil && bytes.Equal(key, prevKey) {
+					continue
+				}
+				prevKey = append(prevKey[:0], key...)
+			} else {
+				value, err = item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+			}
+			err = stream.Send(&pb.Entry{Key: append([]byte(nil), key...), Value: value})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
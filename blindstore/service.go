@@ -0,0 +1,192 @@
+package blindstore
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"redwood.dev/blindstore/pb"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/types"
+)
+
+// The blind store is a plain key-value store running on separate (possibly
+// off-site) hardware.  It never sees plaintext: values are encrypted by the
+// node before they're sent, and keys are opaque hashes.  The service is
+// described in pb/blindstore.proto.
+
+// Client talks to a remote blind store.
+type Client interface {
+	// Put adds a new version of an entry.  Entries are never overwritten.
+	Put(ctx context.Context, key, value []byte, commitment types.Hash) error
+	// Get returns the latest version of an entry, or errors.Err404 if the key
+	// doesn't exist.
+	Get(ctx context.Context, key []byte) ([]byte, error)
+	// Delete removes an entry, and must be approved by a prune certificate
+	// for the state URI that `salt` opens the entry's commitment to.
+	Delete(ctx context.Context, key []byte, cert protoprune.PruneCertificate, salt types.Hash) error
+	// Iterate calls `fn` with every version of every entry in the store (each
+	// entry's versions oldest first) until `fn` returns an error.
+	Iterate(ctx context.Context, fn func(key, value []byte) error) error
+	// IterateKeys calls `fn` once with each key in the store until `fn`
+	// returns an error.
+	IterateKeys(ctx context.Context, fn func(key []byte) error) error
+	Close() error
+}
+
+type client struct {
+	conn   *grpc.ClientConn
+	client pb.BlindStoreClient
+	signer Signer
+}
+
+// Dial connects to the blind store at `dialAddr`.  Requests are signed by
+// `signer`, whose address must be one of the server's configured clients.  If
+// `tlsCertFile` is empty, the connection is unencrypted (the values it carries
+// are encrypted either way).
+func Dial(dialAddr string, tlsCertFile string, signer Signer) (Client, error) {
+	var opts []grpc.DialOption
+	if tlsCertFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(tlsCertFile, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "while loading blind store TLS cert")
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(dialAddr, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while dialing blind store at %v", dialAddr)
+	}
+	return &client{conn: conn, client: pb.NewBlindStoreClient(conn), signer: signer}, nil
+}
+
+func (c *client) Put(ctx context.Context, key, value []byte, commitment types.Hash) error {
+	req := &pb.PutReq{Key: key, Value: value, Commitment: commitment.Bytes()}
+	ctx, err := signRequest(ctx, c.signer, "Put", req)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Put(ctx, req)
+	return fromStatus(err)
+}
+
+func (c *client) Get(ctx context.Context, key []byte) ([]byte, error) {
+	req := &pb.GetReq{Key: key}
+	ctx, err := signRequest(ctx, c.signer, "Get", req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Get(ctx, req)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return resp.Value, nil
+}
+
+func (c *client) Delete(ctx context.Context, key []byte, cert protoprune.PruneCertificate, salt types.Hash) error {
+	req := &pb.DeleteReq{Key: key, Certificate: certToPB(cert), Salt: salt.Bytes()}
+	ctx, err := signRequest(ctx, c.signer, "Delete", req)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Delete(ctx, req)
+	return fromStatus(err)
+}
+
+func (c *client) Iterate(ctx context.Context, fn func(key, value []byte) error) error {
+	return c.iterate(ctx, false, func(entry *pb.Entry) error {
+		return fn(entry.Key, entry.Value)
+	})
+}
+
+func (c *client) IterateKeys(ctx context.Context, fn func(key []byte) error) error {
+	return c.iterate(ctx, true, func(entry *pb.Entry) error {
+		return fn(entry.Key)
+	})
+}
+
+func (c *client) iterate(ctx context.Context, keysOnly bool, fn func(entry *pb.Entry) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req := &pb.IterateReq{KeysOnly: keysOnly}
+	ctx, err := signRequest(ctx, c.signer, "Iterate", req)
+	if err != nil {
+		return err
+	}
+
+	stream, err := c.client.Iterate(ctx, req)
+	if err != nil {
+		return fromStatus(err)
+	}
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fromStatus(err)
+		}
+		err = fn(entry)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *client) Close() error {
+	return c.conn.Close()
+}
+
+func certToPB(cert protoprune.PruneCertificate) *pb.PruneCertificate {
+	sigs := make([][]byte, len(cert.Signatures))
+	for i, sig := range cert.Signatures {
+		sigs[i] = sig
+	}
+	return &pb.PruneCertificate{
+		StateURI:       cert.Proposal.StateURI,
+		CheckpointTxID: cert.Proposal.CheckpointTxID.Bytes(),
+		StateHash:      cert.Proposal.StateHash.Bytes(),
+		Signatures:     sigs,
+	}
+}
+
+func certFromPB(cert *pb.PruneCertificate) (protoprune.PruneCertificate, error) {
+	stateHash, err := types.HashFromBytes(cert.StateHash)
+	if err != nil {
+		return protoprune.PruneCertificate{}, err
+	}
+	sigs := make([]types.Signature, len(cert.Signatures))
+	for i, sig := range cert.Signatures {
+		sigs[i] = sig
+	}
+	return protoprune.PruneCertificate{
+		Proposal: protoprune.PruneProposal{
+			StateURI:       cert.StateURI,
+			CheckpointTxID: state.VersionFromBytes(cert.CheckpointTxID),
+			StateHash:      stateHash,
+		},
+		Signatures: sigs,
+	}, nil
+}
+
+func fromStatus(err error) error {
+	switch status.Code(err) {
+	case codes.OK:
+		return err
+	case codes.NotFound:
+		return errors.Err404
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return errors.Wrap(errors.Err403, status.Convert(err).Message())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,664 @@
+package blindstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/log"
+	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+// TxStore wraps a local tree.TxStore and mirrors every tx written to it into a
+// remote blind store.  Each tx is encrypted with the keystore's local symmetric
+// key, and is stored under a key derived from that same key, so the blind
+// store learns nothing about the txs it holds (or which state URIs they belong
+// to).  Mirroring happens in the background and is retried until it succeeds.
+// Ops waiting to be mirrored are kept on disk, so they survive restarts, and
+// on startup the store backfills any local txs that the blind store is missing
+// (such as those written before mirroring was enabled).
+//
+// The blind store only deletes entries when shown a certificate from its prune
+// quorum (see protoprune), so a prune is only mirrored to it if it's the one
+// approved by the state URI's latest certificate in `certStore`.  Otherwise the
+// blind store keeps its copy of the pruned history.  Along with the prune, the
+// store mirrors the checkpoint's snapshot and certificate, which it reads from
+// the controller hub (see SetControllerHub), so that Restore can import the
+// checkpoint in place of the pruned history.
+type TxStore struct {
+	tree.TxStore
+	log.Logger
+
+	client        Client
+	keyStore      identity.KeyStore
+	certStore     protoprune.CertificateStore
+	controllerHub tree.ControllerHub
+	queue         *opQueue
+	retryInterval time.Duration
+	chStop        chan struct{}
+	wgDone        sync.WaitGroup
+}
+
+var _ tree.TxStore = (*TxStore)(nil)
+
+// NewTxStore wraps `local`.  Ops that haven't been mirrored yet are queued in
+// a badger DB opened with `queueBadgerOpts`.
+func NewTxStore(local tree.TxStore, client Client, keyStore identity.KeyStore, certStore protoprune.CertificateStore, queueBadgerOpts badger.Options) *TxStore {
+	return &TxStore{
+		TxStore:       local,
+		Logger:        log.NewLogger("blindstore"),
+		client:        client,
+		keyStore:      keyStore,
+		certStore:     certStore,
+		queue:         newOpQueue(queueBadgerOpts),
+		retryInterval: 5 * time.Second,
+		chStop:        make(chan struct{}),
+	}
+}
+
+// SetControllerHub gives the store access to checkpoint snapshots, which it
+// needs in order to mirror prunes.  The hub can't be passed to NewTxStore
+// because it's built on top of the store.
+func (s *TxStore) SetControllerHub(controllerHub tree.ControllerHub) {
+	s.controllerHub = controllerHub
+}
+
+type mirrorOp struct {
+	Key        []byte                      `json:"key"`
+	Value      []byte                      `json:"value"`      // nil for deletes
+	Commitment types.Hash                  `json:"commitment"` // for puts
+	Cert       protoprune.PruneCertificate `json:"cert"`       // approves deletes, and accompanies checkpoints
+	Salt       types.Hash                  `json:"salt"`       // opens the commitment, for deletes
+	Checkpoint bool                        `json:"checkpoint"` // puts the snapshot approved by `Cert`, which is read when the op is applied
+}
+
+// checkpointRecord is a checkpoint tx along with the snapshot of the state as
+// of that tx and the certificate that approved pruning the history before it.
+type checkpointRecord struct {
+	Tx          tree.Tx                     `json:"tx"`
+	Snapshot    interface{}                 `json:"snapshot"`
+	Certificate protoprune.PruneCertificate `json:"certificate"`
+}
+
+// checkpointRecordPrefix distinguishes the plaintext of checkpoint records
+// from that of txs.
+var checkpointRecordPrefix = []byte("redwood-checkpoint:")
+
+// removedRecordPrefix is the plaintext of the entry that replaces a removed
+// tx (see RemoveTx).
+var removedRecordPrefix = []byte("redwood-removed:")
+
+func (s *TxStore) Start() error {
+	err := s.TxStore.Start()
+	if err != nil {
+		return err
+	}
+	err = s.queue.Start()
+	if err != nil {
+		return err
+	}
+	s.wgDone.Add(1)
+	go s.mirrorLoop()
+	return nil
+}
+
+// Close stops the store.  Ops that haven't been mirrored yet stay queued until
+// the store is started again.
+func (s *TxStore) Close() {
+	close(s.chStop)
+	s.wgDone.Wait()
+
+	s.queue.Close()
+	err := s.client.Close()
+	if err != nil {
+		s.Errorf("could not close blind store client: %v", err)
+	}
+	s.TxStore.Close()
+}
+
+func (s *TxStore) AddTx(tx tree.Tx) error {
+	err := s.TxStore.AddTx(tx)
+	if err != nil {
+		return err
+	}
+	op, err := s.putTxOp(tx)
+	if err != nil {
+		return err
+	}
+	return s.queue.Push(op)
+}
+
+func (s *TxStore) putTxOp(tx tree.Tx) (mirrorOp, error) {
+	bs, err := tx.Marshal()
+	if err != nil {
+		return mirrorOp{}, err
+	}
+	op, err := s.putOp(tx.StateURI, s.remoteKey(tx.StateURI, tx.ID), bs)
+	if err != nil {
+		return mirrorOp{}, errors.Wrapf(err, "while encrypting tx %v for blind store", tx.ID.Pretty())
+	}
+	return op, nil
+}
+
+func (s *TxStore) putOp(stateURI string, key []byte, plaintext []byte) (mirrorOp, error) {
+	encrypted, err := s.keyStore.LocalSymEncKey().Encrypt(plaintext)
+	if err != nil {
+		return mirrorOp{}, err
+	}
+	return mirrorOp{
+		Key:        key,
+		Value:      encrypted.Bytes(),
+		Commitment: EntryCommitment(s.remoteSalt(key), stateURI),
+	}, nil
+}
+
+// RemoveTx removes a tx from the local store.  The blind store only deletes
+// entries that a prune certificate covers, so the tx's entry is superseded by
+// a tombstone instead, which RemoteTxs and Restore skip.
+func (s *TxStore) RemoveTx(stateURI string, txID state.Version) error {
+	err := s.TxStore.RemoveTx(stateURI, txID)
+	if err != nil {
+		return err
+	}
+	op, err := s.putOp(stateURI, s.remoteKey(stateURI, txID), append([]byte(nil), removedRecordPrefix...))
+	if err != nil {
+		return errors.Wrapf(err, "while encrypting removal of tx %v for blind store", txID.Pretty())
+	}
+	return s.queue.Push(op)
+}
+
+// PruneTxs prunes the local store, and also the blind store if the prune has
+// been approved by a certificate from the prune quorum.
+func (s *TxStore) PruneTxs(stateURI string, newBase state.Version, txIDs []state.Version) error {
+	err := s.TxStore.PruneTxs(stateURI, newBase, txIDs)
+	if err != nil {
+		return err
+	}
+
+	cert, err := s.certStore.PruneCertificate(stateURI)
+	if errors.Cause(err) == errors.Err404 || (err == nil && cert.Proposal.CheckpointTxID != newBase) {
+		s.Warnf("prune of %v to %v has no quorum certificate, so the blind store will keep the pruned txs", stateURI, newBase.Pretty())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var ops []mirrorOp
+	for _, txID := range txIDs {
+		key := s.remoteKey(stateURI, txID)
+		ops = append(ops, mirrorOp{Key: key, Cert: cert, Salt: s.remoteSalt(key)})
+	}
+
+	// Replace the previous checkpoint.  The delete only removes versions
+	// stored before the certificate was first used, so it doesn't touch the
+	// new one.
+	key := s.remoteCheckpointKey(stateURI)
+	ops = append(ops,
+		mirrorOp{Key: key, Cert: cert, Salt: s.remoteSalt(key)},
+		s.putCheckpointOp(cert),
+	)
+	return s.queue.Push(ops...)
+}
+
+func (s *TxStore) putCheckpointOp(cert protoprune.PruneCertificate) mirrorOp {
+	key := s.remoteCheckpointKey(cert.Proposal.StateURI)
+	return mirrorOp{
+		Key:        key,
+		Cert:       cert,
+		Commitment: EntryCommitment(s.remoteSalt(key), cert.Proposal.StateURI),
+		Checkpoint: true,
+	}
+}
+
+func (s *TxStore) remoteCheckpointKey(stateURI string) []byte {
+	key := s.keyStore.LocalSymEncKey().Bytes()
+	hash := types.HashBytes(append(key, []byte("checkpoint:"+stateURI)...))
+	return hash[:]
+}
+
+// encryptCheckpoint builds the checkpoint record for a certificate.  It
+// returns errors.Err404 if the checkpoint's snapshot is gone or no longer
+// matches the certificate (because a later prune has superseded it).
+func (s *TxStore) encryptCheckpoint(cert protoprune.PruneCertificate) ([]byte, error) {
+	if s.controllerHub == nil {
+		return nil, errors.Wrap(errors.Err404, "blind store has no controller hub to read snapshots from")
+	}
+	stateURI, txID := cert.Proposal.StateURI, cert.Proposal.CheckpointTxID
+
+	tx, err := s.TxStore.FetchTx(stateURI, txID)
+	if err != nil {
+		return nil, err
+	}
+	node, err := s.controllerHub.StateAtVersion(stateURI, &txID)
+	if err != nil {
+		return nil, err
+	}
+	defer node.Close()
+
+	stateHash, err := tree.SnapshotHash(node)
+	if err != nil {
+		return nil, err
+	} else if stateHash != cert.Proposal.StateHash {
+		return nil, errors.Wrapf(errors.Err404, "snapshot for checkpoint %v has changed", txID.Pretty())
+	}
+	snapshot, _, err := node.Value(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := json.Marshal(checkpointRecord{Tx: tx, Snapshot: snapshot, Certificate: cert})
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.keyStore.LocalSymEncKey().Encrypt(append(append([]byte(nil), checkpointRecordPrefix...), bs...))
+	if err != nil {
+		return nil, errors.Wrapf(err, "while encrypting checkpoint %v for blind store", txID.Pretty())
+	}
+	return encrypted.Bytes(), nil
+}
+
+// remoteKey is a keyed hash of the tx's identity, so that the blind store
+// can't link entries to state URIs.
+func (s *TxStore) remoteKey(stateURI string, txID state.Version) []byte {
+	key := s.keyStore.LocalSymEncKey().Bytes()
+	hash := types.HashBytes(append(key, []byte("tx:"+stateURI+":"+txID.Hex())...))
+	return hash[:]
+}
+
+// remoteSalt is the salt for the commitment that binds a blind store entry
+// to its state URI.  It's only revealed when the entry is deleted.
+func (s *TxStore) remoteSalt(remoteKey []byte) types.Hash {
+	key := s.keyStore.LocalSymEncKey().Bytes()
+	return types.HashBytes(append(append(key, []byte("salt:")...), remoteKey...))
+}
+
+func (s *TxStore) mirrorLoop() {
+	defer s.wgDone.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.chStop
+		cancel()
+	}()
+
+	for {
+		err := s.resync(ctx)
+		if err == nil {
+			break
+		}
+		s.Errorf("while resyncing with blind store (retrying in %v): %v", s.retryInterval, err)
+
+		select {
+		case <-s.chStop:
+			return
+		case <-time.After(s.retryInterval):
+		}
+	}
+
+	for {
+		seq, op, exists, err := s.queue.Peek()
+		if err != nil {
+			s.Errorf("while reading blind store op queue (retrying in %v): %v", s.retryInterval, err)
+			select {
+			case <-s.chStop:
+				return
+			case <-time.After(s.retryInterval):
+			}
+			continue
+		} else if !exists {
+			select {
+			case <-s.chStop:
+				return
+			case <-s.queue.Notify():
+			}
+			continue
+		}
+
+		// Ops are retried in place so that writes to the same key stay in order
+		for {
+			err := s.apply(ctx, op)
+			if err == nil {
+				break
+			} else if errors.Cause(err) == errors.Err403 {
+				// Retrying won't help, and would hold up every op behind this one
+				s.Errorf("blind store refused op: %v", err)
+				break
+			}
+			s.Errorf("while mirroring tx to blind store (retrying in %v): %v", s.retryInterval, err)
+
+			select {
+			case <-s.chStop:
+				return
+			case <-time.After(s.retryInterval):
+			}
+		}
+
+		err = s.queue.Remove(seq)
+		if err != nil {
+			s.Errorf("while removing op from blind store op queue: %v", err)
+		}
+	}
+}
+
+func (s *TxStore) apply(ctx context.Context, op mirrorOp) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if op.Checkpoint {
+		value, err := s.encryptCheckpoint(op.Cert)
+		if errors.Cause(err) == errors.Err404 {
+			s.Warnf("not mirroring checkpoint %v (%v): %v", op.Cert.Proposal.CheckpointTxID.Pretty(), op.Cert.Proposal.StateURI, err)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return s.client.Put(ctx, op.Key, value, op.Commitment)
+	} else if op.Value == nil {
+		return s.client.Delete(ctx, op.Key, op.Cert, op.Salt)
+	}
+	return s.client.Put(ctx, op.Key, op.Value, op.Commitment)
+}
+
+// resync queues puts for the local txs, and the current checkpoints, that the
+// blind store doesn't have and that aren't already queued.  This backfills
+// history written before mirroring was enabled, or while an op couldn't be
+// queued.
+func (s *TxStore) resync(ctx context.Context) error {
+	remoteKeys := make(map[string]struct{})
+	err := s.client.IterateKeys(ctx, func(key []byte) error {
+		remoteKeys[string(key)] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "while fetching keys from blind store")
+	}
+	queuedKeys, err := s.queue.Keys()
+	if err != nil {
+		return err
+	}
+	isMissing := func(key []byte) bool {
+		_, isRemote := remoteKeys[string(key)]
+		_, isQueued := queuedKeys[string(key)]
+		return !isRemote && !isQueued
+	}
+
+	stateURIs, err := s.TxStore.KnownStateURIs()
+	if err != nil {
+		return err
+	}
+
+	var ops []mirrorOp
+	for stateURI := range stateURIs {
+		base, err := s.TxStore.HistoryBase(stateURI)
+		if err != nil {
+			return err
+		}
+		exists, err := s.TxStore.TxExists(stateURI, base)
+		if err != nil {
+			return err
+		} else if !exists {
+			// The history was removed
+			continue
+		}
+		if base != tree.GenesisTxID && isMissing(s.remoteCheckpointKey(stateURI)) {
+			cert, err := s.certStore.PruneCertificate(stateURI)
+			if err == nil && cert.Proposal.CheckpointTxID == base {
+				ops = append(ops, s.putCheckpointOp(cert))
+			} else if err != nil && errors.Cause(err) != errors.Err404 {
+				return err
+			}
+		}
+
+		err = func() error {
+			iter := s.TxStore.AllTxsForStateURI(stateURI, base)
+			defer iter.Close()
+			for tx := iter.Next(); tx != nil; tx = iter.Next() {
+				if !isMissing(s.remoteKey(stateURI, tx.ID)) {
+					continue
+				}
+				op, err := s.putTxOp(*tx)
+				if err != nil {
+					return err
+				}
+				ops = append(ops, op)
+			}
+			return iter.Error()
+		}()
+		if err != nil {
+			return errors.Wrapf(err, "while resyncing %v with blind store", stateURI)
+		}
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	s.Infof(0, "backfilling %v entries that the blind store is missing", len(ops))
+	return s.queue.Push(ops...)
+}
+
+// RemoteTxs fetches and decrypts every tx that this node has mirrored to the
+// blind store, taking the latest version of each entry that decrypts.  Entries
+// that can't be decrypted with the local key (for instance, those belonging to
+// other nodes sharing the same store) are skipped, as are removed txs.
+func (s *TxStore) RemoteTxs(ctx context.Context) ([]tree.Tx, error) {
+	txs, _, err := s.remoteEntries(ctx)
+	return txs, err
+}
+
+// remoteEntries is RemoteTxs, but also returns the mirrored checkpoints.
+func (s *TxStore) remoteEntries(ctx context.Context) ([]tree.Tx, []checkpointRecord, error) {
+	key := s.keyStore.LocalSymEncKey()
+
+	// Each entry's latest version wins, and entries are returned in the order
+	// they were first seen
+	var order []string
+	txs := make(map[string]tree.Tx)
+	checkpoints := make(map[string]checkpointRecord)
+	var numSkipped int
+	err := s.client.Iterate(ctx, func(remoteKey, value []byte) error {
+		msg, ok := symEncMsgFromBytes(value)
+		if !ok {
+			numSkipped++
+			return nil
+		}
+		bs, err := key.Decrypt(msg)
+		if err != nil {
+			numSkipped++
+			return nil
+		}
+
+		k := string(remoteKey)
+		_, isTx := txs[k]
+		_, isCheckpoint := checkpoints[k]
+		if !isTx && !isCheckpoint {
+			order = append(order, k)
+		}
+
+		if bytes.HasPrefix(bs, removedRecordPrefix) {
+			delete(txs, k)
+			return nil
+
+		} else if bytes.HasPrefix(bs, checkpointRecordPrefix) {
+			var checkpoint checkpointRecord
+			err = json.Unmarshal(bs[len(checkpointRecordPrefix):], &checkpoint)
+			if err != nil {
+				return err
+			}
+			checkpoints[k] = checkpoint
+			return nil
+		}
+
+		var tx tree.Tx
+		err = tx.Unmarshal(bs)
+		if err != nil {
+			return err
+		}
+		txs[k] = tx
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "while fetching txs from blind store")
+	}
+	if numSkipped > 0 {
+		s.Warnf("skipped %v blind store entries that could not be decrypted", numSkipped)
+	}
+
+	var txList []tree.Tx
+	var checkpointList []checkpointRecord
+	seen := make(map[string]bool, len(order))
+	for _, k := range order {
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if tx, exists := txs[k]; exists {
+			txList = append(txList, tx)
+		} else if checkpoint, exists := checkpoints[k]; exists {
+			checkpointList = append(checkpointList, checkpoint)
+		}
+	}
+	return txList, checkpointList, nil
+}
+
+// symEncMsgFromBytes is crypto.SymEncMsgFromBytes for untrusted input.
+func symEncMsgFromBytes(bs []byte) (crypto.SymEncMsg, bool) {
+	if len(bs) < 16 {
+		return crypto.SymEncMsg{}, false
+	}
+	nonceLen := binary.LittleEndian.Uint64(bs[0:8])
+	if nonceLen > uint64(len(bs)-16) {
+		return crypto.SymEncMsg{}, false
+	}
+	ciphertextLen := binary.LittleEndian.Uint64(bs[8+nonceLen : 16+nonceLen])
+	if ciphertextLen != uint64(len(bs))-16-nonceLen {
+		return crypto.SymEncMsg{}, false
+	}
+	return crypto.SymEncMsgFromBytes(bs), true
+}
+
+// Restore rebuilds the node's trees from the blind store.  State URIs that
+// the node has no history for, and that have been pruned, are bootstrapped
+// from their mirrored checkpoints.  The remaining txs are then replayed
+// through the controller hub, parents first, skipping those that the node
+// already has and those that were pruned before a checkpoint.  It returns the
+// number of checkpoints and txs that were restored.
+//
+// Checkpoints are imported without consulting the prune quorum: the node
+// encrypted them itself, so the blind store can't have forged them.
+func (s *TxStore) Restore(ctx context.Context, controllerHub tree.ControllerHub) (int, error) {
+	txs, checkpoints, err := s.remoteEntries(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	type txKey struct {
+		stateURI string
+		txID     state.Version
+	}
+	byKey := make(map[txKey]tree.Tx, len(txs))
+	for _, tx := range txs {
+		byKey[txKey{tx.StateURI, tx.ID}] = tx
+	}
+
+	var n int
+	pruned := make(map[txKey]bool)
+	for _, checkpoint := range checkpoints {
+		tx := checkpoint.Tx
+
+		leaves, err := s.TxStore.Leaves(tx.StateURI)
+		if err != nil {
+			return n, err
+		} else if len(leaves) == 0 {
+			tx.Status = tree.TxStatusUnknown
+			tx.Children = nil
+			snapshot := state.NewMemoryNodeWithValue(checkpoint.Snapshot)
+			err = controllerHub.ImportCheckpoint(tx, snapshot, checkpoint.Certificate.Proposal.StateHash)
+			if err != nil {
+				return n, errors.Wrapf(err, "while restoring checkpoint %v (%v)", tx.ID.Pretty(), tx.StateURI)
+			}
+			err = s.certStore.SavePruneCertificate(checkpoint.Certificate)
+			if err != nil {
+				return n, err
+			}
+			n++
+		}
+
+		// Pruned history can't be replayed on top of the checkpoint
+		stack := append([]state.Version(nil), tx.Parents...)
+		for len(stack) > 0 {
+			k := txKey{tx.StateURI, stack[len(stack)-1]}
+			stack = stack[:len(stack)-1]
+			if pruned[k] {
+				continue
+			}
+			pruned[k] = true
+			if parent, exists := byKey[k]; exists {
+				stack = append(stack, parent.Parents...)
+			}
+		}
+	}
+
+	for _, tx := range sortParentsFirst(txs) {
+		if pruned[txKey{tx.StateURI, tx.ID}] {
+			continue
+		}
+		exists, err := s.TxStore.TxExists(tx.StateURI, tx.ID)
+		if err != nil {
+			return n, err
+		} else if exists {
+			continue
+		}
+		tx.Status = tree.TxStatusUnknown
+		tx.Children = nil
+		err = controllerHub.AddTx(tx)
+		if err != nil {
+			return n, errors.Wrapf(err, "while restoring tx %v (%v)", tx.ID.Pretty(), tx.StateURI)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// sortParentsFirst orders txs so that each tx comes after any of its parents
+// that are also in the list.
+func sortParentsFirst(txs []tree.Tx) []tree.Tx {
+	type txKey struct {
+		stateURI string
+		txID     state.Version
+	}
+	byKey := make(map[txKey]tree.Tx, len(txs))
+	for _, tx := range txs {
+		byKey[txKey{tx.StateURI, tx.ID}] = tx
+	}
+
+	sorted := make([]tree.Tx, 0, len(txs))
+	visited := make(map[txKey]bool, len(txs))
+	var visit func(tx tree.Tx)
+	visit = func(tx tree.Tx) {
+		k := txKey{tx.StateURI, tx.ID}
+		if visited[k] {
+			return
+		}
+		visited[k] = true
+		for _, parentID := range tx.Parents {
+			if parent, exists := byKey[txKey{tx.StateURI, parentID}]; exists {
+				visit(parent)
+			}
+		}
+		sorted = append(sorted, tx)
+	}
+	for _, tx := range txs {
+		visit(tx)
+	}
+	return sorted
+}
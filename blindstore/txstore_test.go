@@ -0,0 +1,458 @@
+package blindstore_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blindstore"
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/utils/badgerutils"
+)
+
+type certStore map[string]protoprune.PruneCertificate
+
+func (s certStore) PruneCertificate(stateURI string) (protoprune.PruneCertificate, error) {
+	cert, exists := s[stateURI]
+	if !exists {
+		return protoprune.PruneCertificate{}, errors.Err404
+	}
+	return cert, nil
+}
+
+func (s certStore) SavePruneCertificate(cert protoprune.PruneCertificate) error {
+	s[cert.Proposal.StateURI] = cert
+	return nil
+}
+
+func TestTxStore_MirrorAndRestore(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(t.TempDir()), identity.InsecureScryptParams)
+	require.NoError(t, keyStore.Unlock("password", ""))
+	t.Cleanup(func() { keyStore.Close() })
+
+	me, err := keyStore.DefaultPublicIdentity()
+	require.NoError(t, err)
+
+	quorumMember, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	quorum := protoprune.Quorum{Signers: []types.Address{quorumMember.Address()}, Threshold: 1}
+
+	certify := func(t *testing.T, signer blindstore.Signer, proposal protoprune.PruneProposal) protoprune.PruneCertificate {
+		t.Helper()
+		sig, err := signer.SignHash(proposal.Hash())
+		require.NoError(t, err)
+		return protoprune.PruneCertificate{Proposal: proposal, Signatures: []types.Signature{sig}}
+	}
+
+	server, err := blindstore.NewServer(badgerOpts.ForPath(t.TempDir()), "", "", []types.Address{me.Address()}, quorum)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	t.Cleanup(server.Close)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+
+	newNode := func(t *testing.T) (*blindstore.TxStore, tree.ControllerHub, certStore) {
+		t.Helper()
+		dir := t.TempDir()
+
+		client, err := blindstore.Dial(listener.Addr().String(), "", me)
+		require.NoError(t, err)
+
+		certs := make(certStore)
+		txStore := blindstore.NewTxStore(tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs"))), client, keyStore, certs, badgerOpts.ForPath(filepath.Join(dir, "queue")))
+		require.NoError(t, txStore.Start())
+		t.Cleanup(txStore.Close)
+
+		blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+		require.NoError(t, blobStore.Start())
+		t.Cleanup(blobStore.Close)
+
+		statesDir := filepath.Join(dir, "states")
+		require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+		hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+		require.NoError(t, hub.Start())
+		t.Cleanup(func() { hub.Close() })
+		return txStore, hub, certs
+	}
+
+	newTx := func(t *testing.T, id state.Version, parents []state.Version, keypath string, valueJSON string) tree.Tx {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       id,
+			Parents:  parents,
+			From:     me.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		tx.Sig, err = me.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+
+	requireState := func(t *testing.T, hub tree.ControllerHub, expected map[string]interface{}) {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			node, err := hub.StateAtVersion(stateURI, nil)
+			if err != nil {
+				return false
+			}
+			defer node.Close()
+			val, _, err := node.Value(nil, nil)
+			require.NoError(t, err)
+			m, _ := val.(map[string]interface{})
+			return len(m) == len(expected) && m["a"] == expected["a"] && m["b"] == expected["b"]
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	genesis := newTx(t, tree.GenesisTxID, nil, "a", "1")
+	child := newTx(t, state.RandomVersion(), []state.Version{genesis.ID}, "b", "2")
+
+	txStore, hub, certs := newNode(t)
+	require.NoError(t, hub.AddTx(genesis))
+	require.NoError(t, hub.AddTx(child))
+	requireState(t, hub, map[string]interface{}{"a": 1.0, "b": 2.0})
+
+	t.Run("txs are mirrored to the blind store", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			txs, err := txStore.RemoteTxs(context.Background())
+			require.NoError(t, err)
+			if len(txs) != 2 {
+				return false
+			}
+			for _, tx := range txs {
+				if tx.Status != tree.TxStatusValid {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("the blind store only sees opaque entries", func(t *testing.T) {
+		client, err := blindstore.Dial(listener.Addr().String(), "", me)
+		require.NoError(t, err)
+		defer client.Close()
+
+		entries := make(map[string]bool)
+		err = client.Iterate(context.Background(), func(key, value []byte) error {
+			entries[string(key)] = true
+			for _, plaintext := range [][]byte{[]byte(stateURI), genesis.ID[:], child.ID[:], []byte(`"b"`)} {
+				require.False(t, bytes.Contains(key, plaintext))
+				require.False(t, bytes.Contains(value, plaintext))
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+	})
+
+	t.Run("only configured clients may use the blind store", func(t *testing.T) {
+		stranger, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+
+		client, err := blindstore.Dial(listener.Addr().String(), "", stranger)
+		require.NoError(t, err)
+		defer client.Close()
+
+		ctx := context.Background()
+		require.True(t, errors.Cause(client.Put(ctx, []byte("foo"), []byte("bar"), types.Hash{})) == errors.Err403)
+		cert := certify(t, quorumMember, protoprune.PruneProposal{StateURI: stateURI, CheckpointTxID: child.ID})
+		require.True(t, errors.Cause(client.Delete(ctx, []byte("foo"), cert, types.Hash{})) == errors.Err403)
+		_, err = client.Get(ctx, []byte("foo"))
+		require.True(t, errors.Cause(err) == errors.Err403)
+		err = client.Iterate(ctx, func(key, value []byte) error { return nil })
+		require.True(t, errors.Cause(err) == errors.Err403)
+	})
+
+	t.Run("a node can be rebuilt from the blind store", func(t *testing.T) {
+		txStore2, hub2, _ := newNode(t)
+
+		n, err := txStore2.Restore(context.Background(), hub2)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		requireState(t, hub2, map[string]interface{}{"a": 1.0, "b": 2.0})
+		leaves, err := hub2.Leaves(stateURI)
+		require.NoError(t, err)
+		require.Equal(t, []state.Version{child.ID}, leaves)
+	})
+
+	t.Run("deletes require a certificate from the prune quorum", func(t *testing.T) {
+		client, err := blindstore.Dial(listener.Addr().String(), "", me)
+		require.NoError(t, err)
+		defer client.Close()
+
+		ctx := context.Background()
+		salt := types.HashBytes([]byte("salt"))
+		require.NoError(t, client.Put(ctx, []byte("foo"), []byte("bar"), blindstore.EntryCommitment(salt, stateURI)))
+
+		proposal := protoprune.PruneProposal{StateURI: stateURI, CheckpointTxID: child.ID}
+		stranger, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+
+		err = client.Delete(ctx, []byte("foo"), protoprune.PruneCertificate{Proposal: proposal}, salt)
+		require.True(t, errors.Cause(err) == errors.Err403)
+		err = client.Delete(ctx, []byte("foo"), certify(t, stranger, proposal), salt)
+		require.True(t, errors.Cause(err) == errors.Err403)
+		err = client.Delete(ctx, []byte("foo"), certify(t, me, proposal), salt)
+		require.True(t, errors.Cause(err) == errors.Err403)
+
+		// A certificate for another state URI can't delete the entry, nor can
+		// the right certificate without the entry's salt
+		otherProposal := protoprune.PruneProposal{StateURI: "other.uri/xyzzy", CheckpointTxID: child.ID}
+		err = client.Delete(ctx, []byte("foo"), certify(t, quorumMember, otherProposal), salt)
+		require.True(t, errors.Cause(err) == errors.Err403)
+		err = client.Delete(ctx, []byte("foo"), certify(t, quorumMember, proposal), types.HashBytes([]byte("wrong salt")))
+		require.True(t, errors.Cause(err) == errors.Err403)
+
+		value, err := client.Get(ctx, []byte("foo"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("bar"), value)
+
+		require.NoError(t, client.Delete(ctx, []byte("foo"), certify(t, quorumMember, proposal), salt))
+		_, err = client.Get(ctx, []byte("foo"))
+		require.True(t, errors.Cause(err) == errors.Err404)
+
+		// Replaying the certificate doesn't delete entries written since it was used
+		require.NoError(t, client.Put(ctx, []byte("foo"), []byte("baz"), blindstore.EntryCommitment(salt, stateURI)))
+		require.NoError(t, client.Delete(ctx, []byte("foo"), certify(t, quorumMember, proposal), salt))
+		value, err = client.Get(ctx, []byte("foo"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("baz"), value)
+	})
+
+	t.Run("puts can't overwrite entries", func(t *testing.T) {
+		client, err := blindstore.Dial(listener.Addr().String(), "", me)
+		require.NoError(t, err)
+		defer client.Close()
+
+		ctx := context.Background()
+		commitment := blindstore.EntryCommitment(types.HashBytes([]byte("quux salt")), stateURI)
+		require.NoError(t, client.Put(ctx, []byte("quux"), []byte("one"), commitment))
+		require.NoError(t, client.Put(ctx, []byte("quux"), []byte("two"), commitment))
+
+		// A Put with another commitment is refused, so it can't rebind the entry
+		err = client.Put(ctx, []byte("quux"), []byte("three"), blindstore.EntryCommitment(types.HashBytes([]byte("other salt")), stateURI))
+		require.True(t, errors.Cause(err) == errors.Err403)
+
+		value, err := client.Get(ctx, []byte("quux"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("two"), value)
+
+		var versions []string
+		err = client.Iterate(ctx, func(key, value []byte) error {
+			if string(key) == "quux" {
+				versions = append(versions, string(value))
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"one", "two"}, versions)
+	})
+
+	t.Run("prunes are only mirrored once the quorum has approved them", func(t *testing.T) {
+		numRemoteTxs := func() int {
+			txs, err := txStore.RemoteTxs(context.Background())
+			require.NoError(t, err)
+			return len(txs)
+		}
+
+		// Without a certificate, the blind store keeps the pruned tx
+		require.NoError(t, txStore.PruneTxs(stateURI, child.ID, []state.Version{genesis.ID}))
+		require.Never(t, func() bool { return numRemoteTxs() != 2 }, 500*time.Millisecond, 10*time.Millisecond)
+
+		// A certificate for another checkpoint doesn't count either
+		require.NoError(t, certs.SavePruneCertificate(certify(t, quorumMember, protoprune.PruneProposal{StateURI: stateURI, CheckpointTxID: genesis.ID})))
+		require.NoError(t, txStore.PruneTxs(stateURI, child.ID, []state.Version{genesis.ID}))
+		require.Never(t, func() bool { return numRemoteTxs() != 2 }, 500*time.Millisecond, 10*time.Millisecond)
+
+		require.NoError(t, certs.SavePruneCertificate(certify(t, quorumMember, protoprune.PruneProposal{StateURI: stateURI, CheckpointTxID: child.ID})))
+		require.NoError(t, txStore.PruneTxs(stateURI, child.ID, []state.Version{genesis.ID}))
+		require.Eventually(t, func() bool { return numRemoteTxs() == 1 }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("a pruned node can be rebuilt from its mirrored checkpoint", func(t *testing.T) {
+		const stateURI = "foo.bar/pruned"
+
+		newTx := func(t *testing.T, id state.Version, parents []state.Version, checkpoint bool, keypath string, valueJSON string) tree.Tx {
+			t.Helper()
+			tx := tree.Tx{
+				ID:         id,
+				Parents:    parents,
+				From:       me.Address(),
+				StateURI:   stateURI,
+				Checkpoint: checkpoint,
+				Patches:    []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+			}
+			tx.Sig, err = me.SignHash(tx.Hash())
+			require.NoError(t, err)
+			return tx
+		}
+
+		requireValid := func(t *testing.T, hub tree.ControllerHub, txID state.Version) {
+			t.Helper()
+			require.Eventually(t, func() bool {
+				tx, err := hub.FetchTx(stateURI, txID)
+				return err == nil && tx.Status == tree.TxStatusValid
+			}, 5*time.Second, 10*time.Millisecond)
+		}
+
+		var (
+			genesis    = newTx(t, tree.GenesisTxID, nil, false, "a", "1")
+			checkpoint = newTx(t, state.RandomVersion(), []state.Version{genesis.ID}, true, "b", "2")
+			after      = newTx(t, state.RandomVersion(), []state.Version{checkpoint.ID}, false, "c", "3")
+		)
+
+		txStore, hub, certs := newNode(t)
+		txStore.SetControllerHub(hub)
+		require.NoError(t, hub.AddTx(genesis))
+		require.NoError(t, hub.AddTx(checkpoint))
+		requireValid(t, hub, checkpoint.ID)
+
+		node, err := hub.StateAtVersion(stateURI, &checkpoint.ID)
+		require.NoError(t, err)
+		stateHash, err := tree.SnapshotHash(node)
+		node.Close()
+		require.NoError(t, err)
+		cert := certify(t, quorumMember, protoprune.PruneProposal{StateURI: stateURI, CheckpointTxID: checkpoint.ID, StateHash: stateHash})
+		require.NoError(t, certs.SavePruneCertificate(cert))
+
+		numPruned, err := hub.Prune(stateURI, checkpoint.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, numPruned)
+
+		require.NoError(t, hub.AddTx(after))
+		requireValid(t, hub, after.ID)
+
+		// The checkpoint is mirrored before `after`, and the genesis tx is gone
+		require.Eventually(t, func() bool {
+			txs, err := txStore.RemoteTxs(context.Background())
+			require.NoError(t, err)
+			txIDs := state.NewVersionSet(nil)
+			for _, tx := range txs {
+				if tx.StateURI == stateURI {
+					txIDs.Add(tx.ID)
+				}
+			}
+			_, hasCheckpoint := txIDs[checkpoint.ID]
+			_, hasAfter := txIDs[after.ID]
+			return len(txIDs) == 2 && hasCheckpoint && hasAfter
+		}, 5*time.Second, 10*time.Millisecond)
+
+		txStore2, hub2, certs2 := newNode(t)
+		n, err := txStore2.Restore(context.Background(), hub2)
+		require.NoError(t, err)
+		// The checkpoint, `after`, and the tx left over from the earlier subtests
+		require.Equal(t, 3, n)
+		requireValid(t, hub2, after.ID)
+
+		node, err = hub2.StateAtVersion(stateURI, nil)
+		require.NoError(t, err)
+		defer node.Close()
+		val, _, err := node.Value(nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"a": 1.0, "b": 2.0, "c": 3.0}, val)
+
+		leaves, err := hub2.Leaves(stateURI)
+		require.NoError(t, err)
+		require.Equal(t, []state.Version{after.ID}, leaves)
+		base, err := hub2.HistoryBase(stateURI)
+		require.NoError(t, err)
+		require.Equal(t, checkpoint.ID, base)
+
+		restoredCert, err := certs2.PruneCertificate(stateURI)
+		require.NoError(t, err)
+		require.Equal(t, cert, restoredCert)
+	})
+
+	t.Run("local history is backfilled on startup, and queued ops survive restarts", func(t *testing.T) {
+		const stateURI = "foo.bar/backfill"
+		dir := t.TempDir()
+		localOpts := badgerOpts.ForPath(filepath.Join(dir, "txs"))
+		queueOpts := badgerOpts.ForPath(filepath.Join(dir, "queue"))
+
+		tx := tree.Tx{
+			ID:       tree.GenesisTxID,
+			From:     me.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{ValueJSON: []byte(`{"a": 1}`)}},
+			Status:   tree.TxStatusValid,
+		}
+		tx.Sig, err = me.SignHash(tx.Hash())
+		require.NoError(t, err)
+
+		// Write the tx before the node starts mirroring to the blind store
+		local := tree.NewBadgerTxStore(localOpts)
+		require.NoError(t, local.Start())
+		require.NoError(t, local.AddTx(tx))
+		local.Close()
+
+		hasRemoteTx := func() bool {
+			txs, err := txStore.RemoteTxs(context.Background())
+			require.NoError(t, err)
+			for _, remoteTx := range txs {
+				if remoteTx.StateURI == stateURI && remoteTx.ID == tx.ID {
+					return true
+				}
+			}
+			return false
+		}
+		require.False(t, hasRemoteTx())
+
+		startNode := func(t *testing.T, dialAddr string) *blindstore.TxStore {
+			t.Helper()
+			client, err := blindstore.Dial(dialAddr, "", me)
+			require.NoError(t, err)
+			txStore := blindstore.NewTxStore(tree.NewBadgerTxStore(localOpts), client, keyStore, make(certStore), queueOpts)
+			require.NoError(t, txStore.Start())
+			return txStore
+		}
+
+		node := startNode(t, listener.Addr().String())
+		require.Eventually(t, hasRemoteTx, 5*time.Second, 10*time.Millisecond)
+		node.Close()
+
+		// Remove the tx while the blind store is unreachable, and restart
+		// the node once it's back
+		offline, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		offlineAddr := offline.Addr().String()
+		require.NoError(t, offline.Close())
+
+		node = startNode(t, offlineAddr)
+		require.NoError(t, node.RemoveTx(stateURI, tx.ID))
+		node.Close()
+		require.True(t, hasRemoteTx())
+
+		node = startNode(t, listener.Addr().String())
+		defer node.Close()
+		require.Eventually(t, func() bool { return !hasRemoteTx() }, 5*time.Second, 10*time.Millisecond)
+
+		// The removed tx isn't restored
+		txStore2, hub2, _ := newNode(t)
+		_, err = txStore2.Restore(context.Background(), hub2)
+		require.NoError(t, err)
+		exists, err := txStore2.TxExists(stateURI, tx.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
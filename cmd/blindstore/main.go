@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"time"
+
+	"github.com/brynbellomy/klog"
+	"github.com/urfave/cli"
+
+	"redwood.dev/blindstore"
+	"redwood.dev/cmd/cmdutils"
+	"redwood.dev/crypto"
+	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/types"
+	"redwood.dev/utils/badgerutils"
+)
+
+type Config struct {
+	ListenAddr string `json:"listenAddr"`
+	// Clients are the addresses of the nodes allowed to use the store
+	Clients []types.Address `json:"clients"`
+	// PruneQuorum must have signed off on a prune before its txs are deleted
+	PruneQuorum protoprune.Quorum `json:"pruneQuorum"`
+	TLS         struct {
+		CertFile string `json:"certFile"`
+		KeyFile  string `json:"keyFile"`
+	} `json:"tls"`
+	Datastore struct {
+		Path       string                 `json:"path"`
+		Encryption state.EncryptionConfig `json:"encryption"`
+	} `json:"datastore"`
+}
+
+func main() {
+	cliApp := cli.NewApp()
+
+	cliApp.Commands = []cli.Command{
+		{
+			Name: "genconfig",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "config, c",
+					Usage: "path to the config file",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				datastoreKey, err := crypto.NewSymEncKey()
+				if err != nil {
+					return err
+				}
+
+				var cfg Config
+				cfg.ListenAddr = ":21240"
+				cfg.Datastore.Path = "./data"
+				cfg.Datastore.Encryption.Key = datastoreKey.Bytes()
+				cfg.Datastore.Encryption.KeyRotationInterval = 24 * time.Hour
+				configBytes, err := json.MarshalIndent(cfg, "", "    ")
+				if err != nil {
+					return err
+				}
+				return ioutil.WriteFile(c.String("config"), configBytes, 0600)
+			},
+		},
+		{
+			Name: "start",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "config, c",
+					Usage: "path to the config file",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				flagset := flag.NewFlagSet("", flag.ContinueOnError)
+				klog.InitFlags(flagset)
+				flagset.Set("logtostderr", "true")
+				flagset.Set("v", "2")
+				klog.SetFormatter(&klog.FmtConstWidth{
+					FileNameCharWidth: 24,
+					UseColor:          true,
+				})
+
+				configBytes, err := ioutil.ReadFile(c.String("config"))
+				if err != nil {
+					return err
+				}
+
+				var config Config
+				err = json.Unmarshal(configBytes, &config)
+				if err != nil {
+					return err
+				}
+
+				badgerOpts := badgerutils.OptsBuilder{}.WithEncryption(config.Datastore.Encryption.Key, config.Datastore.Encryption.KeyRotationInterval)
+
+				server, err := blindstore.NewServer(badgerOpts.ForPath(config.Datastore.Path), config.TLS.CertFile, config.TLS.KeyFile, config.Clients, config.PruneQuorum)
+				if err != nil {
+					return err
+				}
+				err = server.Start()
+				if err != nil {
+					return err
+				}
+				defer server.Close()
+
+				listener, err := net.Listen("tcp", config.ListenAddr)
+				if err != nil {
+					return err
+				}
+
+				chErr := make(chan error, 1)
+				go func() { chErr <- server.Serve(listener) }()
+
+				select {
+				case err := <-chErr:
+					return err
+				case <-cmdutils.AwaitInterrupt():
+					return nil
+				}
+			},
+		},
+	}
+
+	err := cliApp.Run(os.Args)
+	if err != nil {
+		fmt.Printf("error: %+v\n", err)
+		os.Exit(1)
+	}
+}
@@ -18,7 +18,9 @@ import (
 	"time"
 
 	"github.com/brynbellomy/klog"
+	"google.golang.org/grpc"
 
+	"redwood.dev/blindstore"
 	"redwood.dev/blob"
 	"redwood.dev/errors"
 	"redwood.dev/health"
@@ -33,9 +35,11 @@ import (
 	"redwood.dev/swarm/protoauth"
 	"redwood.dev/swarm/protoblob"
 	"redwood.dev/swarm/protohush"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/swarm/prototree"
 	"redwood.dev/tree"
 	"redwood.dev/types"
+	"redwood.dev/ucan"
 	"redwood.dev/utils"
 	"redwood.dev/utils/badgerutils"
 )
@@ -56,12 +60,16 @@ type App struct {
 	BlobProto           protoblob.BlobProtocol
 	HushProto           protohush.HushProtocol
 	HushProtoStore      protohush.Store
+	PruneProto          protoprune.PruneProtocol
 	TreeProto           prototree.TreeProtocol
 	TreeProtoStore      prototree.Store
 	HTTPTransport       braidhttp.Transport
 	Libp2pTransport     libp2p.Transport
 	HTTPRPCServer       *http.Server
 	HTTPRPCServerConfig rpc.HTTPConfig
+	GRPCServer          *grpc.Server
+	UCANStore           ucan.Store
+	UCANVerifier        *ucan.Verifier
 	SharedStateDB       *state.DBTree
 
 	PeerDB *state.DBTree
@@ -212,6 +220,13 @@ func (app *App) Start() error {
 
 	app.PeerStore = swarm.NewPeerStore(app.PeerDB)
 
+	app.UCANStore, err = ucan.NewStore(app.SharedStateDB)
+	if err != nil {
+		app.Errorf("while opening ucan store: %+v", err)
+		return err
+	}
+	app.UCANVerifier = ucan.NewVerifier(app.KeyStore, app.UCANStore)
+
 	app.BlobStore = blob.NewBadgerStore(badgerOpts.ForPath(cfg.BlobDataRoot()))
 	err = app.BlobStore.Start()
 	if err != nil {
@@ -221,8 +236,28 @@ func (app *App) Start() error {
 
 	// Initialize the tree protocol
 	if cfg.TreeProtocol.Enabled {
+		app.TreeProtoStore, err = prototree.NewStore(app.TreeDB)
+		if err != nil {
+			app.Errorf("while opening prototree store: %+v", err)
+			return err
+		}
+
 		app.TxStore = tree.NewBadgerTxStore(badgerOpts.ForPath(cfg.TxDBRoot()))
 
+		if cfg.BlindStore.Enabled {
+			nodeIdentity, err := app.KeyStore.DefaultPublicIdentity()
+			if err != nil {
+				app.Errorf("while connecting to blind store: %+v", err)
+				return err
+			}
+			client, err := blindstore.Dial(cfg.BlindStore.DialAddr, cfg.BlindStore.TLSCertFile, nodeIdentity)
+			if err != nil {
+				app.Errorf("while connecting to blind store: %+v", err)
+				return err
+			}
+			app.TxStore = blindstore.NewTxStore(app.TxStore, client, app.KeyStore, app.TreeProtoStore, badgerOpts.ForPath(cfg.BlindStoreQueueRoot()))
+		}
+
 		err = app.TxStore.Start()
 		if err != nil {
 			app.Errorf("while opening tx store: %+v", err)
@@ -230,6 +265,9 @@ func (app *App) Start() error {
 		}
 
 		app.ControllerHub = tree.NewControllerHub(cfg.StateDBRoot(), app.TxStore, app.BlobStore, badgerOpts)
+		if txStore, is := app.TxStore.(*blindstore.TxStore); is {
+			txStore.SetControllerHub(app.ControllerHub)
+		}
 		err = app.Process.SpawnChild(context.TODO(), app.ControllerHub)
 		if err != nil {
 			app.Errorf("while starting controller hub: %+v", err)
@@ -298,7 +336,7 @@ func (app *App) Start() error {
 				tlsCertFilename,
 				tlsKeyFilename,
 				tlsCerts,
-				[]byte(cfg.JWTSecret),
+				app.UCANVerifier,
 				cfg.DevMode,
 			)
 			if err != nil {
@@ -331,12 +369,6 @@ func (app *App) Start() error {
 	}
 
 	if cfg.TreeProtocol.Enabled {
-		app.TreeProtoStore, err = prototree.NewStore(app.TreeDB)
-		if err != nil {
-			app.Errorf("while opening prototree store: %+v", err)
-			return err
-		}
-
 		err = app.TreeProtoStore.SetMaxPeersPerSubscription(cfg.TreeProtocol.MaxPeersPerSubscription)
 		if err != nil {
 			app.Errorf("while setting max peers per subscription: %+v", err)
@@ -351,10 +383,24 @@ func (app *App) Start() error {
 			app.KeyStore,
 			app.PeerStore,
 			app.TreeProtoStore,
+			app.UCANVerifier,
+			cfg.PruneProtocol.Quorum,
 		)
 		protocols = append(protocols, app.TreeProto)
 	}
 
+	if cfg.PruneProtocol.Enabled {
+		app.PruneProto = protoprune.NewPruneProtocol(
+			transports,
+			cfg.PruneProtocol.Quorum,
+			app.TreeProtoStore,
+			app.ControllerHub,
+			app.KeyStore,
+			app.PeerStore,
+		)
+		protocols = append(protocols, app.PruneProto)
+	}
+
 	for _, transport := range transports {
 		app.Infof(0, "starting %v", transport.Name())
 		err = app.Process.SpawnChild(nil, transport)
@@ -373,20 +419,29 @@ func (app *App) Start() error {
 	}
 
 	if cfg.HTTPRPC.Enabled {
-		rwRPC := rpc.NewHTTPServer([]byte(cfg.JWTSecret), app.AuthProto, app.BlobProto, app.TreeProto, app.PeerStore, app.KeyStore, app.BlobStore, app.ControllerHub, app.Libp2pTransport)
+		rwRPC := rpc.NewHTTPServer(app.UCANVerifier, app.AuthProto, app.BlobProto, app.TreeProto, app.PeerStore, app.KeyStore, app.BlobStore, app.ControllerHub, app.Libp2pTransport)
 		var server interface{}
 		if cfg.HTTPRPC.Server != nil {
 			server = cfg.HTTPRPC.Server(rwRPC)
 		} else {
 			server = rwRPC
 		}
-		app.HTTPRPCServer, err = rpc.StartHTTPRPC(server, cfg.HTTPRPC, []byte(cfg.JWTSecret))
+		app.HTTPRPCServer, err = rpc.StartHTTPRPC(server, cfg.HTTPRPC, app.UCANVerifier)
 		if err != nil {
 			return err
 		}
 		app.Infof(0, "http rpc server listening on %v", cfg.HTTPRPC.ListenHost)
 	}
 
+	if cfg.GRPCRPC != nil && cfg.GRPCRPC.Enabled {
+		grpcRPC := rpc.NewGRPCServer(app.UCANVerifier, cfg.GRPCRPC.Whitelist, app.TreeProto, app.PeerStore, app.KeyStore, app.BlobStore, app.ControllerHub)
+		app.GRPCServer, err = rpc.StartGRPCRPC(grpcRPC, cfg.GRPCRPC)
+		if err != nil {
+			return err
+		}
+		app.Infof(0, "grpc rpc server listening on %v", cfg.GRPCRPC.ListenHost)
+	}
+
 	for _, bootstrapPeer := range cfg.BootstrapPeers {
 		bootstrapPeer := bootstrapPeer
 		_ = app.Process.Go(nil, "", func(ctx context.Context) {
@@ -489,6 +544,9 @@ func (app *App) Close() error {
 			fmt.Println("error closing HTTP RPC server:", err)
 		}
 	}
+	if app.GRPCServer != nil {
+		app.GRPCServer.Stop()
+	}
 
 	app.Infof(0, "killing keystore")
 	if app.KeyStore != nil {
@@ -11,6 +11,7 @@ import (
 
 	"redwood.dev/errors"
 	"redwood.dev/rpc"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/utils"
 )
 
@@ -20,7 +21,6 @@ type Config struct {
 	BootstrapPeers  []BootstrapPeer `yaml:"BootstrapPeers"`
 	DataRoot        string          `yaml:"DataRoot"`
 	DNSOverHTTPSURL string          `yaml:"DNSOverHTTPSURL"`
-	JWTSecret       string          `yaml:"JWTSecret"`
 	DevMode         bool            `yaml:"-"`
 	Nurse           NurseConfig     `yaml:"Nurse"`
 	KeyStore        KeyStoreConfig  `yaml:"-"`
@@ -28,12 +28,16 @@ type Config struct {
 	Libp2pTransport    Libp2pTransportConfig    `yaml:"Libp2pTransport"`
 	BraidHTTPTransport BraidHTTPTransportConfig `yaml:"BraidHTTPTransport"`
 
-	AuthProtocol AuthProtocolConfig `yaml:"AuthProtocol"`
-	BlobProtocol BlobProtocolConfig `yaml:"BlobProtocol"`
-	HushProtocol HushProtocolConfig `yaml:"HushProtocol"`
-	TreeProtocol TreeProtocolConfig `yaml:"TreeProtocol"`
+	AuthProtocol  AuthProtocolConfig  `yaml:"AuthProtocol"`
+	BlobProtocol  BlobProtocolConfig  `yaml:"BlobProtocol"`
+	HushProtocol  HushProtocolConfig  `yaml:"HushProtocol"`
+	PruneProtocol PruneProtocolConfig `yaml:"PruneProtocol"`
+	TreeProtocol  TreeProtocolConfig  `yaml:"TreeProtocol"`
+
+	BlindStore BlindStoreConfig `yaml:"BlindStore"`
 
 	HTTPRPC *rpc.HTTPConfig `yaml:"HTTPRPC"`
+	GRPCRPC *rpc.GRPCConfig `yaml:"GRPCRPC"`
 
 	configPath string `yaml:"-"`
 }
@@ -100,6 +104,17 @@ type HushProtocolConfig struct {
 	Enabled bool `yaml:"Enabled"`
 }
 
+type BlindStoreConfig struct {
+	Enabled     bool   `yaml:"Enabled"`
+	DialAddr    string `yaml:"DialAddr"`
+	TLSCertFile string `yaml:"TLSCertFile"`
+}
+
+type PruneProtocolConfig struct {
+	Enabled bool              `yaml:"Enabled"`
+	Quorum  protoprune.Quorum `yaml:"Quorum"`
+}
+
 type TreeProtocolConfig struct {
 	Enabled                 bool   `yaml:"Enabled"`
 	MaxPeersPerSubscription uint64 `yaml:"MaxPeersPerSubscription"`
@@ -128,7 +143,6 @@ func DefaultConfig(appName string) Config {
 		},
 		BootstrapPeers: []BootstrapPeer{},
 		DataRoot:       dataRoot,
-		JWTSecret:      utils.RandomString(32),
 		DevMode:        false,
 		Nurse: NurseConfig{
 			Enabled:              true,
@@ -163,14 +177,24 @@ func DefaultConfig(appName string) Config {
 		HushProtocol: HushProtocolConfig{
 			Enabled: true,
 		},
+		PruneProtocol: PruneProtocolConfig{
+			Enabled: false,
+		},
 		TreeProtocol: TreeProtocolConfig{
 			Enabled:                 true,
 			MaxPeersPerSubscription: 4,
 		},
+		BlindStore: BlindStoreConfig{
+			Enabled: false,
+		},
 		HTTPRPC: &rpc.HTTPConfig{
 			Enabled:    false,
 			ListenHost: ":8081",
 		},
+		GRPCRPC: &rpc.GRPCConfig{
+			Enabled:    false,
+			ListenHost: ":8082",
+		},
 	}
 }
 
@@ -277,6 +301,10 @@ func (c *Config) StateDBRoot() string {
 	return filepath.Join(c.DataRoot, "states")
 }
 
+func (c *Config) BlindStoreQueueRoot() string {
+	return filepath.Join(c.DataRoot, "blindstore-queue")
+}
+
 func (c *Config) KeyStoreRoot() string {
 	return filepath.Join(c.DataRoot, "keystore")
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/logrusorgru/aurora/v3"
 	"github.com/olekukonko/tablewriter"
 
+	"redwood.dev/blindstore"
 	"redwood.dev/errors"
 	"redwood.dev/state"
 	"redwood.dev/swarm"
@@ -135,6 +136,18 @@ var defaultREPLCommands = REPLCommands{
 			"sendgroup": CmdHushSendGroupMessage,
 		},
 	},
+	"blindstore": REPLCommand{
+		HelpText: "interact with the remote blind store",
+		Subcommands: REPLCommands{
+			"restore": CmdBlindStoreRestore,
+		},
+	},
+	"prune": REPLCommand{
+		HelpText: "interact with the prune protocol",
+		Subcommands: REPLCommands{
+			"propose": CmdProposePrune,
+		},
+	},
 	"tree": REPLCommand{
 		HelpText: "interact with the tree protocol",
 		Subcommands: REPLCommands{
@@ -144,9 +157,11 @@ var defaultREPLCommands = REPLCommands{
 			"txs": REPLCommand{
 				Subcommands: REPLCommands{
 					"list":      CmdListTxs,
+					"prune":     CmdPruneTxs,
 					"dumpstore": CmdTxStoreDebugPrint,
 				},
 			},
+			"reindex":   CmdRebuildIndices,
 			"subscribe": CmdSubscribe,
 			"dumpstore": CmdTreeStoreDebugPrint,
 			"dumptree":  CmdControllerDebugPrint,
@@ -359,6 +374,83 @@ var (
 		},
 	}
 
+	CmdPruneTxs = REPLCommand{
+		HelpText: "prune the txs before a checkpoint tx for a given state URI",
+		Handler: func(args []string, app *App) error {
+			if len(args) < 2 {
+				return errors.New("requires 2 arguments: txs prune <state URI> <checkpoint tx ID>")
+			}
+			stateURI := args[0]
+			checkpointTxID, err := state.VersionFromHex(args[1])
+			if err != nil {
+				return err
+			}
+
+			numPruned, err := app.ControllerHub.Prune(stateURI, checkpointTxID)
+			if err != nil {
+				return err
+			}
+			app.Successf("pruned %v txs from %v", numPruned, stateURI)
+			return nil
+		},
+	}
+
+	CmdRebuildIndices = REPLCommand{
+		HelpText: "rebuild the indices of a given state URI from scratch",
+		Handler: func(args []string, app *App) error {
+			if len(args) < 1 {
+				return errors.New("requires 1 argument: reindex <state URI>")
+			}
+			stateURI := args[0]
+
+			n, err := app.ControllerHub.RebuildIndices(stateURI)
+			if err != nil {
+				return err
+			}
+			app.Successf("rebuilt %v indices for %v", n, stateURI)
+			return nil
+		},
+	}
+
+	CmdBlindStoreRestore = REPLCommand{
+		HelpText: "rebuild this node's trees from the txs in the remote blind store",
+		Handler: func(args []string, app *App) error {
+			txStore, is := app.TxStore.(*blindstore.TxStore)
+			if !is {
+				return errors.New("blind store is disabled")
+			}
+			n, err := txStore.Restore(context.Background(), app.ControllerHub)
+			if err != nil {
+				return err
+			}
+			app.Successf("restored %v txs from the blind store", n)
+			return nil
+		},
+	}
+
+	CmdProposePrune = REPLCommand{
+		HelpText: "ask the prune quorum to approve pruning a state URI to a checkpoint tx",
+		Handler: func(args []string, app *App) error {
+			if app.PruneProto == nil {
+				return errors.New("prune protocol is disabled")
+			} else if len(args) < 2 {
+				return errors.New("requires 2 arguments: prune propose <state URI> <checkpoint tx ID>")
+			}
+			stateURI := args[0]
+			checkpointTxID, err := state.VersionFromHex(args[1])
+			if err != nil {
+				return err
+			}
+
+			cert, err := app.PruneProto.ProposePrune(context.Background(), stateURI, checkpointTxID)
+			if err != nil {
+				return err
+			}
+			app.Successf("prune of %v approved with %v signatures", stateURI, len(cert.Signatures))
+			return nil
+		},
+	}
+
 	CmdListTxs = REPLCommand{
 		HelpText: "list the txs for a given state URI",
 		Handler: func(args []string, app *App) error {
@@ -23,31 +23,31 @@ require (
 	github.com/ijc25/Gotty v0.0.0-20170406111628-a8b993ba6abd
 	github.com/ipfs/go-cid v0.0.7
 	github.com/ipfs/go-datastore v0.4.5
-	github.com/ipfs/go-ds-badger2 v0.1.1 // indirect
+	github.com/ipfs/go-ds-badger2 v0.1.1
 	github.com/libgit2/git2go v27.10.0+incompatible // indirect
 	github.com/libgit2/git2go/v33 v33.0.4 // indirect
-	github.com/libp2p/go-doh-resolver v0.3.1 // indirect
+	github.com/libp2p/go-doh-resolver v0.3.1
 	github.com/libp2p/go-libp2p v0.14.4
-	github.com/libp2p/go-libp2p-circuit v0.4.0 // indirect
+	github.com/libp2p/go-libp2p-circuit v0.4.0
 	github.com/libp2p/go-libp2p-core v0.8.5
-	github.com/libp2p/go-libp2p-discovery v0.5.0 // indirect
+	github.com/libp2p/go-libp2p-discovery v0.5.0
 	github.com/libp2p/go-libp2p-host v0.1.0
 	github.com/libp2p/go-libp2p-kad-dht v0.11.1
 	github.com/libp2p/go-libp2p-kbucket v0.4.7
 	github.com/libp2p/go-libp2p-metrics v0.1.0
-	github.com/libp2p/go-libp2p-mplex v0.4.1 // indirect
-	github.com/libp2p/go-libp2p-noise v0.2.0 // indirect
+	github.com/libp2p/go-libp2p-mplex v0.4.1
+	github.com/libp2p/go-libp2p-noise v0.2.0
 	github.com/libp2p/go-libp2p-peer v0.2.0
 	github.com/libp2p/go-libp2p-peerstore v0.2.7
 	github.com/libp2p/go-libp2p-protocol v0.1.0
 	github.com/libp2p/go-libp2p-record v0.1.3
-	github.com/libp2p/go-libp2p-routing v0.1.0 // indirect
+	github.com/libp2p/go-libp2p-routing v0.1.0
 	github.com/linode/linodego v1.0.0
 	github.com/logrusorgru/aurora/v3 v3.0.0 // indirect
 	github.com/markbates/pkger v0.17.0
 	github.com/mattn/go-runewidth v0.0.13 // indirect
 	github.com/multiformats/go-multiaddr v0.3.3
-	github.com/multiformats/go-multiaddr-dns v0.3.1 // indirect
+	github.com/multiformats/go-multiaddr-dns v0.3.1
 	github.com/multiformats/go-multihash v0.0.15
 	github.com/olekukonko/tablewriter v0.0.5 // indirect
 	github.com/onsi/gomega v1.10.1
@@ -55,8 +55,10 @@ require (
 	github.com/powerman/rpc-codec v1.2.2
 	github.com/robertkrimen/otto v0.0.0-20210614181706-373ff5438452 // indirect
 	github.com/rs/cors v1.7.0
-	github.com/status-im/doubleratchet v3.0.0+incompatible // indirect
+	github.com/santhosh-tekuri/jsonschema/v5 v5.2.0
+	github.com/status-im/doubleratchet v3.0.0+incompatible
 	github.com/stretchr/testify v1.7.0
+	github.com/tetratelabs/wazero v1.2.1
 	github.com/tyler-smith/go-bip39 v1.0.1-0.20181017060643-dbb3b84ba2ef
 	github.com/urfave/cli v1.22.1
 	github.com/yhat/wsutil v0.0.0-20170731153501-1d66fa95c997
@@ -32,7 +32,7 @@ export default function createRPCClient({ endpoint }: { endpoint: string }): RPC
         rpcFetch: (method: string, params?: {[key: string]: any}) => rpcFetch(endpoint, method, params),
 
         ucan: async function() {
-            return (await rpcFetch(endpoint, 'RPC.Ucan')).JWT
+            return (await rpcFetch(endpoint, 'RPC.Ucan')).UCAN
         },
 
         subscribe: async function ({ stateURI, keypath, txs, states }: RPCSubscribeParams) {
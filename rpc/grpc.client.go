@@ -0,0 +1,409 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/rpc/pb"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/swarm/braidhttp"
+	"redwood.dev/swarm/libp2p"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+type GRPCClient struct {
+	conn   *grpc.ClientConn
+	client pb.TrustedRPCClient
+	ucan   string
+	ucanMu sync.RWMutex
+}
+
+// DialGRPC connects to the gRPC RPC server at `dialAddr`.  If `tlsCertFile` is
+// empty, the connection is unencrypted.
+func DialGRPC(dialAddr string, tlsCertFile string) (*GRPCClient, error) {
+	c := &GRPCClient{}
+
+	opts := []grpc.DialOption{
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(c.withUCAN(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(c.withUCAN(ctx), desc, cc, method, opts...)
+		}),
+	}
+	if tlsCertFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(tlsCertFile, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "while loading gRPC TLS cert")
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(dialAddr, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while dialing gRPC RPC server at %v", dialAddr)
+	}
+	c.conn = conn
+	c.client = pb.NewTrustedRPCClient(conn)
+	return c, nil
+}
+
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *GRPCClient) withUCAN(ctx context.Context) context.Context {
+	c.ucanMu.RLock()
+	defer c.ucanMu.RUnlock()
+	if c.ucan == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.ucan)
+}
+
+// SetUCAN sets the UCAN sent with every call, for instance one delegated by
+// a whitelisted address.  Authorize sets it automatically.
+func (c *GRPCClient) SetUCAN(token string) {
+	c.ucanMu.Lock()
+	defer c.ucanMu.Unlock()
+	c.ucan = token
+}
+
+func (c *GRPCClient) Authorize(ctx context.Context, signingKeypair *crypto.SigKeypair) error {
+	challengeResp, err := c.client.Authorize(ctx, &pb.AuthorizeReq{})
+	if err != nil {
+		return err
+	}
+
+	sig, err := signingKeypair.SignHash(types.HashBytes(challengeResp.Challenge))
+	if err != nil {
+		return err
+	}
+
+	ucanResp, err := c.client.Authorize(ctx, &pb.AuthorizeReq{Challenge: challengeResp.Challenge, Response: sig})
+	if err != nil {
+		return err
+	}
+	c.SetUCAN(ucanResp.Ucan)
+	return nil
+}
+
+type GRPCStateSubscription struct {
+	stream   pb.TrustedRPC_SubscribeToStatesClient
+	cancel   context.CancelFunc
+	stateURI string
+	keypath  state.Keypath
+}
+
+// Read blocks until the next state update arrives.  It returns the value at
+// the subscription's keypath, along with the tree's leaves.
+func (sub *GRPCStateSubscription) Read() (interface{}, []state.Version, error) {
+	packet, err := sub.stream.Recv()
+	if err != nil {
+		return nil, nil, err
+	}
+	return stateFromPacket(packet)
+}
+
+// ID returns the server's ID for the subscription, waiting for the server to
+// send it if necessary.
+func (sub *GRPCStateSubscription) ID() (string, error) {
+	return grpcSubscriptionID(sub.stream)
+}
+
+func (sub *GRPCStateSubscription) Close() error {
+	sub.cancel()
+	return nil
+}
+
+func (c *GRPCClient) SubscribeStates(ctx context.Context, stateURI string, keypath state.Keypath) (*GRPCStateSubscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.SubscribeToStates(ctx, &pb.SubscribeToStatesReq{StateURI: stateURI, Keypath: keypath.String()})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &GRPCStateSubscription{stream, cancel, stateURI, keypath}, nil
+}
+
+// UnsubscribeStates asks the server to end the given subscription.  Other
+// subscriptions to the same state URI, including the caller's, stay open.
+func (c *GRPCClient) UnsubscribeStates(ctx context.Context, sub *GRPCStateSubscription) error {
+	id, err := sub.ID()
+	if err != nil {
+		return err
+	}
+	_, err = c.client.UnsubscribeFromStates(ctx, &pb.UnsubscribeFromStatesReq{StateURI: sub.stateURI, Keypath: sub.keypath.String(), SubscriptionID: id})
+	return err
+}
+
+type GRPCTxSubscription struct {
+	stream   pb.TrustedRPC_SubscribeToTxsClient
+	cancel   context.CancelFunc
+	stateURI string
+}
+
+// Read blocks until the next tx arrives.
+func (sub *GRPCTxSubscription) Read() (tree.Tx, error) {
+	packet, err := sub.stream.Recv()
+	if err != nil {
+		return tree.Tx{}, err
+	}
+	return txFromPacket(packet)
+}
+
+// ID returns the server's ID for the subscription, waiting for the server to
+// send it if necessary.
+func (sub *GRPCTxSubscription) ID() (string, error) {
+	return grpcSubscriptionID(sub.stream)
+}
+
+func (sub *GRPCTxSubscription) Close() error {
+	sub.cancel()
+	return nil
+}
+
+func (c *GRPCClient) SubscribeTxs(ctx context.Context, stateURI string) (*GRPCTxSubscription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.SubscribeToTxs(ctx, &pb.SubscribeToTxsReq{StateURI: stateURI})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &GRPCTxSubscription{stream, cancel, stateURI}, nil
+}
+
+// UnsubscribeTxs asks the server to end the given subscription.  Other
+// subscriptions to the same state URI, including the caller's, stay open.
+func (c *GRPCClient) UnsubscribeTxs(ctx context.Context, sub *GRPCTxSubscription) error {
+	id, err := sub.ID()
+	if err != nil {
+		return err
+	}
+	_, err = c.client.UnsubscribeFromTxs(ctx, &pb.UnsubscribeFromTxsReq{StateURI: sub.stateURI, SubscriptionID: id})
+	return err
+}
+
+func grpcSubscriptionID(stream grpc.ClientStream) (string, error) {
+	md, err := stream.Header()
+	if err != nil {
+		return "", err
+	}
+	ids := md.Get(grpcSubscriptionIDHeader)
+	if len(ids) == 0 {
+		return "", errors.New("the server didn't send a subscription ID")
+	}
+	return ids[0], nil
+}
+
+// GetState returns the value at `keypath` as of `version` (or the current
+// version, if it's nil), along with the tree's leaves.
+func (c *GRPCClient) GetState(ctx context.Context, stateURI string, keypath state.Keypath, version *state.Version) (interface{}, []state.Version, error) {
+	req := &pb.GetStateReq{StateURI: stateURI, Keypath: keypath.String()}
+	if version != nil {
+		req.Version = version.Bytes()
+	}
+	packet, err := c.client.GetState(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stateFromPacket(packet)
+}
+
+// SendTx sends a tx to be signed by the node's default public identity.  If
+// the tx has no ID, the node assigns one.  If it has no parents, the tree's
+// current leaves are used.
+func (c *GRPCClient) SendTx(ctx context.Context, tx tree.Tx) (state.Version, error) {
+	req := &pb.SendTxReq{StateURI: tx.StateURI, Attachment: tx.Attachment}
+	if tx.ID != (state.Version{}) {
+		req.Id = tx.ID.Bytes()
+	}
+	for _, parent := range tx.Parents {
+		req.Parents = append(req.Parents, parent.Bytes())
+	}
+	for _, patch := range tx.Patches {
+		req.Patches = append(req.Patches, patch.String())
+	}
+
+	resp, err := c.client.SendTx(ctx, req)
+	if err != nil {
+		return state.Version{}, err
+	}
+	return state.VersionFromBytes(resp.Id), nil
+}
+
+func (c *GRPCClient) StoreBlob(ctx context.Context, reader io.Reader) (sha1 types.Hash, sha3 types.Hash, err error) {
+	stream, err := c.client.AddRef(ctx)
+	if err != nil {
+		return types.Hash{}, types.Hash{}, err
+	}
+
+	buf := make([]byte, grpcRefPacketSize)
+	for {
+		n, err := io.ReadFull(reader, buf)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = stream.Send(&pb.RefPacket{Data: buf[:n], End: true})
+			if err != nil {
+				return types.Hash{}, types.Hash{}, err
+			}
+			break
+		} else if err != nil {
+			return types.Hash{}, types.Hash{}, err
+		}
+		err = stream.Send(&pb.RefPacket{Data: buf[:n]})
+		if err != nil {
+			return types.Hash{}, types.Hash{}, err
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return types.Hash{}, types.Hash{}, err
+	}
+	sha1, err = types.HashFromBytes(resp.Sha1Hash)
+	if err != nil {
+		return types.Hash{}, types.Hash{}, err
+	}
+	sha3, err = types.HashFromBytes(resp.Sha3Hash)
+	if err != nil {
+		return types.Hash{}, types.Hash{}, err
+	}
+	return sha1, sha3, nil
+}
+
+func (c *GRPCClient) FetchBlob(ctx context.Context, blobID blob.ID) (io.ReadCloser, error) {
+	req := &pb.FetchRefReq{}
+	switch blobID.HashAlg {
+	case types.SHA1:
+		req.Hash = &pb.FetchRefReq_Sha1{Sha1: blobID.Hash.Bytes()[:20]}
+	case types.SHA3:
+		req.Hash = &pb.FetchRefReq_Sha3{Sha3: blobID.Hash.Bytes()}
+	default:
+		return nil, errors.Errorf("unknown hash algorithm %v", blobID.HashAlg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.FetchRef(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer cancel()
+		for {
+			packet, err := stream.Recv()
+			if err == io.EOF {
+				pw.CloseWithError(io.ErrUnexpectedEOF)
+				return
+			} else if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			_, err = pw.Write(packet.Data)
+			if err != nil {
+				return
+			}
+			if packet.End {
+				pw.Close()
+				return
+			}
+		}
+	}()
+	return pr, nil
+}
+
+func (c *GRPCClient) AddPeer(ctx context.Context, dialInfo swarm.PeerDialInfo) error {
+	transport, err := transportToProtobuf(dialInfo.TransportName)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.AddPeer(ctx, &pb.AddPeerReq{Transport: transport, DialAddrs: []string{dialInfo.DialAddr}})
+	return err
+}
+
+func (c *GRPCClient) DropPeerWithDialInfo(ctx context.Context, dialInfo swarm.PeerDialInfo) error {
+	transport, err := transportToProtobuf(dialInfo.TransportName)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.DropPeer(ctx, &pb.DropPeerReq{
+		Identifier: &pb.DropPeerReq_PeerDialInfo_{
+			PeerDialInfo: &pb.DropPeerReq_PeerDialInfo{Transport: transport, DialAddrs: []string{dialInfo.DialAddr}},
+		},
+	})
+	return err
+}
+
+func (c *GRPCClient) DropPeersWithAddress(ctx context.Context, address types.Address) error {
+	_, err := c.client.DropPeer(ctx, &pb.DropPeerReq{
+		Identifier: &pb.DropPeerReq_Address{Address: address.Bytes()},
+	})
+	return err
+}
+
+func stateFromPacket(packet *pb.StatePacket) (interface{}, []state.Version, error) {
+	var val interface{}
+	if len(packet.State) > 0 {
+		err := json.Unmarshal(packet.State[0].Value, &val)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	var leaves []state.Version
+	for _, leaf := range packet.Leaves {
+		leaves = append(leaves, state.VersionFromBytes(leaf))
+	}
+	return val, leaves, nil
+}
+
+func txFromPacket(packet *pb.TxPacket) (tree.Tx, error) {
+	tx := tree.Tx{
+		ID:         state.VersionFromBytes(packet.Id),
+		StateURI:   packet.StateURI,
+		From:       types.AddressFromBytes(packet.From),
+		Sig:        types.Signature(packet.Sig),
+		Attachment: packet.Attachment,
+		Status:     tree.TxStatus(packet.Status),
+	}
+	for _, parent := range packet.Parents {
+		tx.Parents = append(tx.Parents, state.VersionFromBytes(parent))
+	}
+	for _, child := range packet.Children {
+		tx.Children = append(tx.Children, state.VersionFromBytes(child))
+	}
+	for _, patchStr := range packet.Patches {
+		var patch tree.Patch
+		err := patch.UnmarshalText([]byte(patchStr))
+		if err != nil {
+			return tree.Tx{}, err
+		}
+		tx.Patches = append(tx.Patches, patch)
+	}
+	return tx, nil
+}
+
+func transportToProtobuf(transportName string) (pb.Transport, error) {
+	switch transportName {
+	case libp2p.TransportName:
+		return pb.LIBP2P, nil
+	case braidhttp.TransportName:
+		return pb.HTTPS, nil
+	default:
+		return 0, errors.Errorf("unknown transport %v", transportName)
+	}
+}
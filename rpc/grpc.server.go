@@ -0,0 +1,631 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"redwood.dev/blob"
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/log"
+	"redwood.dev/rpc/pb"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/swarm/braidhttp"
+	"redwood.dev/swarm/libp2p"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/ucan"
+)
+
+type GRPCConfig struct {
+	Enabled     bool                `json:"enabled"     yaml:"Enabled"`
+	ListenHost  string              `json:"listenHost"  yaml:"ListenHost"`
+	TLSCertFile string              `json:"tlsCertFile" yaml:"TLSCertFile"`
+	TLSKeyFile  string              `json:"tlsKeyFile"  yaml:"TLSKeyFile"`
+	Whitelist   HTTPWhitelistConfig `json:"whitelist"   yaml:"Whitelist"`
+}
+
+func StartGRPCRPC(server *GRPCServer, config *GRPCConfig) (*grpc.Server, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(server.unaryInterceptor),
+		grpc.StreamInterceptor(server.streamInterceptor),
+	}
+	if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while loading gRPC TLS cert")
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	listener, err := net.Listen("tcp", config.ListenHost)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterTrustedRPCServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+
+	return grpcServer, nil
+}
+
+// GRPCServer implements the TrustedRPC service described in pb/grpc.proto.
+// When the whitelist is enabled, every call other than Authorize must carry
+// a UCAN in its "authorization" metadata that grants the capability the call
+// requires (see grpcRequiredCapability), exactly as with the HTTP RPC server.
+type GRPCServer struct {
+	log.Logger
+	whitelist        *whitelistMiddleware
+	whitelistEnabled bool
+	treeProto        prototree.TreeProtocol
+	peerStore        swarm.PeerStore
+	keyStore         identity.KeyStore
+	blobStore        blob.Store
+	controllerHub    tree.ControllerHub
+
+	subscriptions   map[string]grpcSubscription
+	subscriptionsMu sync.Mutex
+}
+
+type grpcSubscriptionKey struct {
+	stateURI         string
+	keypath          string
+	subscriptionType prototree.SubscriptionType
+}
+
+// grpcSubscription is an open subscription stream, which can be ended by an
+// Unsubscribe call carrying its ID.
+type grpcSubscription struct {
+	key    grpcSubscriptionKey
+	cancel context.CancelFunc
+}
+
+// grpcSubscriptionIDHeader is the header in which each subscription stream's
+// ID is sent to the client.
+const grpcSubscriptionIDHeader = "subscription-id"
+
+var _ pb.TrustedRPCServer = (*GRPCServer)(nil)
+
+func NewGRPCServer(
+	ucanVerifier *ucan.Verifier,
+	whitelist HTTPWhitelistConfig,
+	treeProto prototree.TreeProtocol,
+	peerStore swarm.PeerStore,
+	keyStore identity.KeyStore,
+	blobStore blob.Store,
+	controllerHub tree.ControllerHub,
+) *GRPCServer {
+	return &GRPCServer{
+		Logger:           log.NewLogger("grpc rpc"),
+		whitelist:        NewWhitelistMiddleware(whitelist.PermittedAddrs, ucanVerifier, nil),
+		whitelistEnabled: whitelist.Enabled,
+		treeProto:        treeProto,
+		peerStore:        peerStore,
+		keyStore:         keyStore,
+		blobStore:        blobStore,
+		controllerHub:    controllerHub,
+		subscriptions:    make(map[string]grpcSubscription),
+	}
+}
+
+const (
+	grpcAuthorizeMethod = "/trustedrpc.TrustedRPC/Authorize"
+	grpcRefPacketSize   = 64 * 1024
+)
+
+func (s *GRPCServer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if info.FullMethod != grpcAuthorizeMethod {
+		var err error
+		ctx, err = s.checkAuthorization(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = checkCapability(ctx, grpcRequiredCapability(req))
+		if err != nil {
+			return nil, toStatus(err)
+		}
+	}
+	resp, err := handler(ctx, req)
+	return resp, toStatus(err)
+}
+
+func (s *GRPCServer) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.checkAuthorization(stream.Context())
+	if err != nil {
+		return err
+	}
+	return toStatus(handler(srv, &authorizedServerStream{stream, ctx}))
+}
+
+// authorizedServerStream checks each message the client sends against the
+// capabilities of the UCAN attached to its context.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authorizedServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *authorizedServerStream) RecvMsg(msg interface{}) error {
+	err := stream.ServerStream.RecvMsg(msg)
+	if err != nil {
+		return err
+	}
+	return toStatus(checkCapability(stream.ctx, grpcRequiredCapability(msg)))
+}
+
+// checkAuthorization verifies the UCAN in the call's metadata and returns a
+// context carrying it.
+func (s *GRPCServer) checkAuthorization(ctx context.Context) (context.Context, error) {
+	if !s.whitelistEnabled {
+		return ctx, nil
+	}
+	var authHeader string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("authorization"); len(vals) > 0 {
+			authHeader = vals[0]
+		}
+	}
+	u, httpStatus, err := s.whitelist.checkUCAN(authHeader)
+	if err != nil {
+		return nil, status.Error(httpStatusToCode(httpStatus), err.Error())
+	}
+	return ucan.NewContext(ctx, u), nil
+}
+
+// grpcRequiredCapability is the gRPC equivalent of ScopedArgs.
+func grpcRequiredCapability(req interface{}) ucan.Capability {
+	switch req := req.(type) {
+	case *pb.SubscribeToStatesReq:
+		return readCapability(req.StateURI, state.Keypath(req.Keypath))
+	case *pb.UnsubscribeFromStatesReq:
+		return readCapability(req.StateURI, state.Keypath(req.Keypath))
+	case *pb.SubscribeToTxsReq:
+		return readCapability(req.StateURI, nil)
+	case *pb.UnsubscribeFromTxsReq:
+		return readCapability(req.StateURI, nil)
+	case *pb.GetStateReq:
+		return readCapability(req.StateURI, state.Keypath(req.Keypath))
+	case *pb.SendTxReq:
+		return writeCapability(req.StateURI)
+	default:
+		return ucan.AdminCapability
+	}
+}
+
+func (s *GRPCServer) Authorize(ctx context.Context, req *pb.AuthorizeReq) (*pb.AuthorizeResp, error) {
+	if len(req.Response) == 0 {
+		// Wants challenge
+		challenge, err := s.whitelist.newChallenge()
+		if err != nil {
+			return nil, err
+		}
+		return &pb.AuthorizeResp{Challenge: challenge}, nil
+	}
+
+	// Has challenge response
+	if len(req.Challenge) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "must provide challenge")
+	}
+	u, httpStatus, err := s.whitelist.redeemChallenge(req.Challenge, req.Response)
+	if err != nil {
+		return nil, status.Error(httpStatusToCode(httpStatus), err.Error())
+	}
+	return &pb.AuthorizeResp{Ucan: u.String()}, nil
+}
+
+// SetHDMnemonic is not supported: the keystore's mnemonic is fixed when the
+// keystore is first unlocked.
+func (s *GRPCServer) SetHDMnemonic(ctx context.Context, req *pb.SetHDMnemonicReq) (*pb.SetHDMnemonicResp, error) {
+	return nil, status.Error(codes.Unimplemented, "the mnemonic can only be set when the keystore is created")
+}
+
+func (s *GRPCServer) SubscribeToStates(req *pb.SubscribeToStatesReq, stream pb.TrustedRPC_SubscribeToStatesServer) error {
+	key := grpcSubscriptionKey{req.StateURI, req.Keypath, prototree.SubscriptionType_States}
+	return s.subscribe(stream, key, func(msg prototree.SubscriptionMsg) error {
+		if msg.State == nil {
+			return nil
+		}
+		packet, err := statePacket(state.Keypath(req.Keypath), msg.State, msg.Leaves)
+		if err != nil {
+			return err
+		}
+		return stream.Send(packet)
+	})
+}
+
+func (s *GRPCServer) UnsubscribeFromStates(ctx context.Context, req *pb.UnsubscribeFromStatesReq) (*pb.UnsubscribeFromStatesResp, error) {
+	err := s.unsubscribe(req.SubscriptionID, req.StateURI, prototree.SubscriptionType_States)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UnsubscribeFromStatesResp{}, nil
+}
+
+func (s *GRPCServer) SubscribeToTxs(req *pb.SubscribeToTxsReq, stream pb.TrustedRPC_SubscribeToTxsServer) error {
+	key := grpcSubscriptionKey{req.StateURI, "", prototree.SubscriptionType_Txs}
+	return s.subscribe(stream, key, func(msg prototree.SubscriptionMsg) error {
+		if msg.Tx == nil {
+			return nil
+		}
+		return stream.Send(txPacket(*msg.Tx))
+	})
+}
+
+func (s *GRPCServer) UnsubscribeFromTxs(ctx context.Context, req *pb.UnsubscribeFromTxsReq) (*pb.UnsubscribeFromTxsResp, error) {
+	// Tx subscriptions always cover the whole tree, so the keypath is ignored
+	err := s.unsubscribe(req.SubscriptionID, req.StateURI, prototree.SubscriptionType_Txs)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UnsubscribeFromTxsResp{}, nil
+}
+
+// subscribe opens an in-process subscription and passes its messages to `fn`
+// until the client goes away, `fn` fails, or the subscription is ended by an
+// Unsubscribe call.  The stream's subscription ID is sent to the client in its
+// header before any messages.
+func (s *GRPCServer) subscribe(stream grpc.ServerStream, key grpcSubscriptionKey, fn func(msg prototree.SubscriptionMsg) error) error {
+	if s.treeProto == nil {
+		return errors.ErrUnsupported
+	} else if key.stateURI == "" {
+		return status.Error(codes.InvalidArgument, "missing stateURI")
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	sub, err := s.treeProto.InProcessSubscription(ctx, key.stateURI, key.subscriptionType, state.Keypath(key.keypath), nil)
+	if err != nil {
+		return errors.Wrapf(err, "while subscribing to %v", key.stateURI)
+	}
+	defer sub.Close()
+
+	id := types.RandomID().Hex()
+
+	s.subscriptionsMu.Lock()
+	s.subscriptions[id] = grpcSubscription{key: key, cancel: cancel}
+	s.subscriptionsMu.Unlock()
+
+	defer func() {
+		s.subscriptionsMu.Lock()
+		defer s.subscriptionsMu.Unlock()
+		delete(s.subscriptions, id)
+	}()
+
+	err = stream.SendHeader(metadata.Pairs(grpcSubscriptionIDHeader, id))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		sub.Close()
+	}()
+
+	for {
+		msg, err := sub.Read()
+		if errors.Cause(err) == errors.ErrClosed {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		err = fn(msg)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// unsubscribe ends the subscription stream with the given ID.  The stream
+// must have been opened for the given state URI, as that's what the caller's
+// capabilities were checked against.
+func (s *GRPCServer) unsubscribe(id string, stateURI string, subscriptionType prototree.SubscriptionType) error {
+	if id == "" {
+		return status.Error(codes.InvalidArgument, "missing subscriptionID")
+	}
+
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+
+	sub, exists := s.subscriptions[id]
+	if !exists || sub.key.stateURI != stateURI || sub.key.subscriptionType != subscriptionType {
+		return errors.Err404
+	}
+	sub.cancel()
+	return nil
+}
+
+func (s *GRPCServer) GetState(ctx context.Context, req *pb.GetStateReq) (*pb.StatePacket, error) {
+	if s.controllerHub == nil {
+		return nil, errors.ErrUnsupported
+	}
+
+	var version *state.Version
+	if len(req.Version) > 0 {
+		v := state.VersionFromBytes(req.Version)
+		version = &v
+	}
+
+	node, err := s.controllerHub.StateAtVersion(req.StateURI, version)
+	if err != nil {
+		return nil, err
+	}
+	defer node.Close()
+
+	leaves, err := s.controllerHub.Leaves(req.StateURI)
+	if err != nil {
+		return nil, err
+	}
+	keypath := state.Keypath(req.Keypath)
+	return statePacket(keypath, node.NodeAt(keypath, nil), leaves)
+}
+
+func (s *GRPCServer) SendTx(ctx context.Context, req *pb.SendTxReq) (*pb.SendTxResp, error) {
+	if s.treeProto == nil {
+		return nil, errors.ErrUnsupported
+	}
+
+	tx := tree.Tx{
+		ID:         state.RandomVersion(),
+		StateURI:   req.StateURI,
+		Attachment: req.Attachment,
+	}
+	if len(req.Id) > 0 {
+		tx.ID = state.VersionFromBytes(req.Id)
+	}
+	for _, parent := range req.Parents {
+		tx.Parents = append(tx.Parents, state.VersionFromBytes(parent))
+	}
+	for _, patchStr := range req.Patches {
+		var patch tree.Patch
+		err := patch.UnmarshalText([]byte(patchStr))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		tx.Patches = append(tx.Patches, patch)
+	}
+
+	err := s.treeProto.SendTx(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SendTxResp{StateURI: tx.StateURI, Id: tx.ID.Bytes()}, nil
+}
+
+func (s *GRPCServer) AddRef(stream pb.TrustedRPC_AddRefServer) error {
+	if s.blobStore == nil {
+		return errors.ErrUnsupported
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			packet, err := stream.Recv()
+			if err == io.EOF {
+				pw.Close()
+				return
+			} else if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			_, err = pw.Write(packet.Data)
+			if err != nil {
+				return
+			}
+			if packet.End {
+				pw.Close()
+				return
+			}
+		}
+	}()
+
+	sha1, sha3, err := s.blobStore.StoreBlob(pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	return stream.SendAndClose(&pb.AddRefResp{
+		Sha1Hash: sha1.Bytes()[:20],
+		Sha3Hash: sha3.Bytes(),
+	})
+}
+
+func (s *GRPCServer) FetchRef(req *pb.FetchRefReq, stream pb.TrustedRPC_FetchRefServer) error {
+	if s.blobStore == nil {
+		return errors.ErrUnsupported
+	}
+
+	var blobID blob.ID
+	var err error
+	switch hash := req.Hash.(type) {
+	case *pb.FetchRefReq_Sha1:
+		blobID.HashAlg = types.SHA1
+		blobID.Hash, err = types.HashFromBytes(hash.Sha1)
+	case *pb.FetchRefReq_Sha3:
+		blobID.HashAlg = types.SHA3
+		blobID.Hash, err = types.HashFromBytes(hash.Sha3)
+	default:
+		return status.Error(codes.InvalidArgument, "missing hash")
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reader, _, err := s.blobStore.BlobReader(blobID)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	buf := make([]byte, grpcRefPacketSize)
+	for {
+		n, err := io.ReadFull(reader, buf)
+		if err == io.EOF {
+			return stream.Send(&pb.RefPacket{End: true})
+		} else if err == io.ErrUnexpectedEOF {
+			return stream.Send(&pb.RefPacket{Data: buf[:n], End: true})
+		} else if err != nil {
+			return err
+		}
+		err = stream.Send(&pb.RefPacket{Data: buf[:n]})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *GRPCServer) AddPeer(ctx context.Context, req *pb.AddPeerReq) (*pb.AddPeerResp, error) {
+	if s.peerStore == nil {
+		return nil, errors.ErrUnsupported
+	}
+	transportName, err := transportNameFromProtobuf(req.Transport)
+	if err != nil {
+		return nil, err
+	}
+	for _, dialAddr := range req.DialAddrs {
+		s.peerStore.AddDialInfo(swarm.PeerDialInfo{TransportName: transportName, DialAddr: dialAddr}, "")
+	}
+	return &pb.AddPeerResp{}, nil
+}
+
+func (s *GRPCServer) DropPeer(ctx context.Context, req *pb.DropPeerReq) (*pb.DropPeerResp, error) {
+	if s.peerStore == nil {
+		return nil, errors.ErrUnsupported
+	}
+
+	var deviceUniqueIDs []string
+	switch identifier := req.Identifier.(type) {
+	case *pb.DropPeerReq_PeerDialInfo_:
+		transportName, err := transportNameFromProtobuf(identifier.PeerDialInfo.Transport)
+		if err != nil {
+			return nil, err
+		}
+		for _, dialAddr := range identifier.PeerDialInfo.DialAddrs {
+			endpoint := s.peerStore.PeerEndpoint(swarm.PeerDialInfo{TransportName: transportName, DialAddr: dialAddr})
+			if endpoint != nil && endpoint.DeviceUniqueID() != "" {
+				deviceUniqueIDs = append(deviceUniqueIDs, endpoint.DeviceUniqueID())
+			}
+		}
+	case *pb.DropPeerReq_Address:
+		for _, peer := range s.peerStore.PeersWithAddress(types.AddressFromBytes(identifier.Address)) {
+			deviceUniqueIDs = append(deviceUniqueIDs, peer.DeviceUniqueID())
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "missing identifier")
+	}
+
+	err := s.peerStore.RemovePeers(deviceUniqueIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DropPeerResp{}, nil
+}
+
+func statePacket(keypath state.Keypath, node state.Node, leaves []state.Version) (*pb.StatePacket, error) {
+	val, exists, err := node.Value(nil, nil)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		val = nil
+	}
+	valueJSON, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+
+	packet := &pb.StatePacket{
+		State: []*pb.KeypathValue{{Keypath: keypath.String(), Value: valueJSON}},
+	}
+	for _, leaf := range leaves {
+		packet.Leaves = append(packet.Leaves, leaf.Bytes())
+	}
+	return packet, nil
+}
+
+func txPacket(tx tree.Tx) *pb.TxPacket {
+	packet := &pb.TxPacket{
+		StateURI:   tx.StateURI,
+		Id:         tx.ID.Bytes(),
+		From:       tx.From.Bytes(),
+		Sig:        tx.Sig,
+		Attachment: tx.Attachment,
+		Status:     pb.TxPacket_TxStatus(tx.Status),
+		Hash:       tx.Hash().Bytes(),
+	}
+	for _, parent := range tx.Parents {
+		packet.Parents = append(packet.Parents, parent.Bytes())
+	}
+	for _, child := range tx.Children {
+		packet.Children = append(packet.Children, child.Bytes())
+	}
+	for _, patch := range tx.Patches {
+		packet.Patches = append(packet.Patches, patch.String())
+	}
+	return packet
+}
+
+func transportNameFromProtobuf(transport pb.Transport) (string, error) {
+	switch transport {
+	case pb.LIBP2P:
+		return libp2p.TransportName, nil
+	case pb.HTTPS:
+		return braidhttp.TransportName, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unknown transport %v", transport)
+	}
+}
+
+func httpStatusToCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus maps the errors returned by the node's subsystems onto gRPC status
+// codes.  Errors that already carry a status are passed through.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	} else if _, is := status.FromError(err); is {
+		return err
+	}
+	switch errors.Cause(err) {
+	case errors.Err404, tree.ErrNoController:
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Err403:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.ErrUnsupported:
+		return status.Error(codes.Unimplemented, err.Error())
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
@@ -0,0 +1,335 @@
+package rpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/identity"
+	"redwood.dev/internal/testutils"
+	"redwood.dev/rpc"
+	"redwood.dev/rpc/pb"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	hushmocks "redwood.dev/swarm/protohush/mocks"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/ucan"
+	"redwood.dev/utils/badgerutils"
+)
+
+type testNode struct {
+	hub          tree.ControllerHub
+	txStore      tree.TxStore
+	keyStore     identity.KeyStore
+	blobStore    blob.Store
+	peerStore    swarm.PeerStore
+	treeProto    prototree.TreeProtocol
+	ucanVerifier *ucan.Verifier
+}
+
+func setupTestNode(t *testing.T) testNode {
+	t.Helper()
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(filepath.Join(dir, "keystore")), identity.InsecureScryptParams)
+	require.NoError(t, keyStore.Unlock("password", ""))
+	t.Cleanup(func() { keyStore.Close() })
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	ucanStore, err := ucan.NewStore(testutils.SetupDBTree(t))
+	require.NoError(t, err)
+	ucanVerifier := ucan.NewVerifier(keyStore, ucanStore)
+
+	peerStore := swarm.NewPeerStore(testutils.SetupDBTree(t))
+
+	store, err := prototree.NewStore(testutils.SetupDBTree(t))
+	require.NoError(t, err)
+
+	hushProto := new(hushmocks.HushProtocol)
+	hushProto.On("OnGroupMessageEncrypted", prototree.ProtocolName, mock.Anything).Return()
+	hushProto.On("OnGroupMessageDecrypted", prototree.ProtocolName, mock.Anything).Return()
+
+	treeProto := prototree.NewTreeProtocol(nil, hushProto, hub, txStore, keyStore, peerStore, store, ucanVerifier, protoprune.Quorum{})
+	require.NoError(t, treeProto.Start())
+	t.Cleanup(func() { treeProto.Close() })
+
+	return testNode{
+		hub:          hub,
+		txStore:      txStore,
+		keyStore:     keyStore,
+		blobStore:    blobStore,
+		peerStore:    peerStore,
+		treeProto:    treeProto,
+		ucanVerifier: ucanVerifier,
+	}
+}
+
+type grpcTestNode struct {
+	testNode
+	dialAddr string
+}
+
+func setupGRPCTestNode(t *testing.T, whitelist rpc.HTTPWhitelistConfig) grpcTestNode {
+	t.Helper()
+
+	node := setupTestNode(t)
+
+	// Find a free port for the server to listen on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dialAddr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	server := rpc.NewGRPCServer(node.ucanVerifier, whitelist, node.treeProto, node.peerStore, node.keyStore, node.blobStore, node.hub)
+	grpcServer, err := rpc.StartGRPCRPC(server, &rpc.GRPCConfig{Enabled: true, ListenHost: dialAddr})
+	require.NoError(t, err)
+	t.Cleanup(grpcServer.Stop)
+
+	return grpcTestNode{testNode: node, dialAddr: dialAddr}
+}
+
+func dialGRPCTestNode(t *testing.T, node grpcTestNode) *rpc.GRPCClient {
+	t.Helper()
+	client, err := rpc.DialGRPC(node.dialAddr, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestGRPC_RoundTrip(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	node := setupGRPCTestNode(t, rpc.HTTPWhitelistConfig{})
+	client := dialGRPCTestNode(t, node)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	txID, err := client.SendTx(ctx, tree.Tx{
+		ID:       tree.GenesisTxID,
+		StateURI: stateURI,
+		Patches:  []tree.Patch{{ValueJSON: []byte(`{"messages": ["hi"]}`)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, tree.GenesisTxID, txID)
+
+	requireState := func(t *testing.T, keypath state.Keypath, expected interface{}, expectedLeaf state.Version) {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			val, leaves, err := client.GetState(ctx, stateURI, keypath, nil)
+			if status.Code(err) == codes.NotFound {
+				return false
+			}
+			require.NoError(t, err)
+			return len(leaves) == 1 && leaves[0] == expectedLeaf && reflect.DeepEqual(val, expected)
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	requireState(t, state.Keypath("messages"), []interface{}{"hi"}, tree.GenesisTxID)
+
+	sub, err := client.SubscribeTxs(ctx, stateURI)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	txID, err = client.SendTx(ctx, tree.Tx{
+		StateURI: stateURI,
+		Patches:  []tree.Patch{{Keypath: state.Keypath("messages"), ValueJSON: []byte(`["hi", "bye"]`)}},
+	})
+	require.NoError(t, err)
+
+	for {
+		tx, err := sub.Read()
+		require.NoError(t, err)
+		if tx.ID != txID {
+			continue
+		}
+		require.Equal(t, stateURI, tx.StateURI)
+		require.Equal(t, []state.Version{tree.GenesisTxID}, tx.Parents)
+		require.Equal(t, []tree.Patch{{Keypath: state.Keypath("messages"), ValueJSON: []byte(`["hi", "bye"]`)}}, tx.Patches)
+		break
+	}
+	requireState(t, state.Keypath("messages"), []interface{}{"hi", "bye"}, txID)
+}
+
+func TestGRPC_Whitelist(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	mallory, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	node := setupGRPCTestNode(t, rpc.HTTPWhitelistConfig{Enabled: true, PermittedAddrs: []types.Address{alice.Address()}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("calls without a ucan are rejected", func(t *testing.T) {
+		client := dialGRPCTestNode(t, node)
+		_, _, err := client.GetState(ctx, stateURI, nil, nil)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("addresses that aren't whitelisted can't authorize", func(t *testing.T) {
+		client := dialGRPCTestNode(t, node)
+		err := client.Authorize(ctx, mallory)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("whitelisted addresses can authorize", func(t *testing.T) {
+		client := dialGRPCTestNode(t, node)
+		require.NoError(t, client.Authorize(ctx, alice))
+		_, err := client.SendTx(ctx, tree.Tx{
+			ID:       tree.GenesisTxID,
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{ValueJSON: []byte(`{"messages": ["hi"]}`)}},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("ucans are bearer tokens", func(t *testing.T) {
+		// The whitelist middleware documents why RPC ucans don't require proof
+		// that the caller holds the audience's key
+		client := dialGRPCTestNode(t, node)
+		u, err := node.ucanVerifier.Issue(mallory.Address(), []ucan.Capability{{StateURI: stateURI, Ability: ucan.AbilityRead}}, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		client.SetUCAN(u.String())
+
+		_, _, err = client.GetState(ctx, stateURI, nil, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("streams are rejected if the ucan lacks the required capability", func(t *testing.T) {
+		client := dialGRPCTestNode(t, node)
+		u, err := node.ucanVerifier.Issue(alice.Address(), []ucan.Capability{{StateURI: "foo.bar/other", Ability: ucan.AbilityRead}}, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		client.SetUCAN(u.String())
+
+		sub, err := client.SubscribeTxs(ctx, stateURI)
+		require.NoError(t, err)
+		defer sub.Close()
+		_, err = sub.Read()
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		// Unary calls are checked too
+		_, err = client.SendTx(ctx, tree.Tx{StateURI: "foo.bar/other"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestGRPC_Unsubscribe(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	node := setupGRPCTestNode(t, rpc.HTTPWhitelistConfig{})
+	alice := dialGRPCTestNode(t, node)
+	bob := dialGRPCTestNode(t, node)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := alice.SendTx(ctx, tree.Tx{
+		ID:       tree.GenesisTxID,
+		StateURI: stateURI,
+		Patches:  []tree.Patch{{ValueJSON: []byte(`{"messages": ["hi"]}`)}},
+	})
+	require.NoError(t, err)
+
+	aliceSub, err := alice.SubscribeTxs(ctx, stateURI)
+	require.NoError(t, err)
+	defer aliceSub.Close()
+	aliceSub2, err := alice.SubscribeTxs(ctx, stateURI)
+	require.NoError(t, err)
+	defer aliceSub2.Close()
+	bobSub, err := bob.SubscribeTxs(ctx, stateURI)
+	require.NoError(t, err)
+	defer bobSub.Close()
+
+	aliceID, err := aliceSub.ID()
+	require.NoError(t, err)
+	aliceID2, err := aliceSub2.ID()
+	require.NoError(t, err)
+	bobID, err := bobSub.ID()
+	require.NoError(t, err)
+	require.NotEqual(t, aliceID, aliceID2)
+	require.NotEqual(t, aliceID, bobID)
+
+	t.Run("only the given subscription is ended", func(t *testing.T) {
+		require.NoError(t, alice.UnsubscribeTxs(ctx, aliceSub))
+
+		// The history may have been sent before the subscription ended
+		for {
+			tx, err := aliceSub.Read()
+			if err != nil {
+				require.Equal(t, io.EOF, err)
+				break
+			}
+			require.Equal(t, tree.GenesisTxID, tx.ID)
+		}
+
+		txID, err := alice.SendTx(ctx, tree.Tx{
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath("messages"), ValueJSON: []byte(`["hi", "bye"]`)}},
+		})
+		require.NoError(t, err)
+
+		for _, sub := range []*rpc.GRPCTxSubscription{aliceSub2, bobSub} {
+			for {
+				tx, err := sub.Read()
+				require.NoError(t, err)
+				if tx.ID == txID {
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("subscriptions can only be ended through their own state URI", func(t *testing.T) {
+		conn, err := grpc.Dial(node.dialAddr, grpc.WithInsecure())
+		require.NoError(t, err)
+		defer conn.Close()
+		client := pb.NewTrustedRPCClient(conn)
+
+		_, err = client.UnsubscribeFromTxs(ctx, &pb.UnsubscribeFromTxsReq{StateURI: "foo.bar/other", SubscriptionID: bobID})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		_, err = client.UnsubscribeFromStates(ctx, &pb.UnsubscribeFromStatesReq{StateURI: stateURI, SubscriptionID: bobID})
+		require.Equal(t, codes.NotFound, status.Code(err))
+		_, err = client.UnsubscribeFromTxs(ctx, &pb.UnsubscribeFromTxsReq{StateURI: stateURI})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unknown subscriptions aren't found", func(t *testing.T) {
+		err := alice.UnsubscribeTxs(ctx, aliceSub)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -1,23 +1,35 @@
 package rpc
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/powerman/rpc-codec/jsonrpc2"
 
 	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/tree"
 	"redwood.dev/types"
 	"redwood.dev/utils"
 )
 
 type HTTPClient struct {
-	dialAddr   string
-	rpcClient  *jsonrpc2.Client
-	httpClient *utils.HTTPClient
-	jwt        string
+	dialAddr        string
+	rpcClient       *jsonrpc2.Client
+	httpClient      *utils.HTTPClient
+	streamingClient *utils.HTTPClient
+	ucan            string
 }
 
 func NewHTTPClient(dialAddr string) *HTTPClient {
@@ -25,13 +37,14 @@ func NewHTTPClient(dialAddr string) *HTTPClient {
 
 	var c *HTTPClient
 	c = &HTTPClient{
-		dialAddr:   dialAddr,
-		httpClient: httpClient,
+		dialAddr:        dialAddr,
+		httpClient:      httpClient,
+		streamingClient: utils.MakeHTTPClient(0, 30*time.Second, nil, nil),
 		rpcClient: jsonrpc2.NewCustomHTTPClient(
 			dialAddr,
 			jsonrpc2.DoerFunc(func(req *http.Request) (*http.Response, error) {
-				if len(c.jwt) > 0 {
-					req.Header.Set("Authorization", "Bearer "+c.jwt)
+				if len(c.ucan) > 0 {
+					req.Header.Set("Authorization", "Bearer "+c.ucan)
 				}
 				return httpClient.Do(req)
 			}),
@@ -41,6 +54,8 @@ func NewHTTPClient(dialAddr string) *HTTPClient {
 }
 
 func (c *HTTPClient) Close() error {
+	c.httpClient.Close()
+	c.streamingClient.Close()
 	return c.rpcClient.Close()
 }
 
@@ -87,21 +102,136 @@ func (c *HTTPClient) Authorize(signingKeypair *crypto.SigKeypair) error {
 	}
 	defer resp.Body.Close()
 
-	var jwtResp struct {
-		JWT string `json:"jwt"`
+	var ucanResp struct {
+		UCAN string `json:"ucan"`
 	}
-	err = json.NewDecoder(resp.Body).Decode(&jwtResp)
+	err = json.NewDecoder(resp.Body).Decode(&ucanResp)
 	if err != nil {
 		return err
 	}
-	c.jwt = jwtResp.JWT
+	c.ucan = ucanResp.UCAN
 	return nil
 }
 
+// SetUCAN sets the UCAN sent with every request, for instance one delegated
+// by a whitelisted address.  Authorize sets it automatically.
+func (c *HTTPClient) SetUCAN(token string) {
+	c.ucan = token
+}
+
+func (c *HTTPClient) Ucan(args UcanArgs) (string, error) {
+	var resp UcanResponse
+	err := c.rpcClient.Call("RPC.Ucan", args, &resp)
+	return resp.UCAN, err
+}
+
+func (c *HTTPClient) RevokeUcan(args RevokeUcanArgs) error {
+	return c.rpcClient.Call("RPC.RevokeUcan", args, nil)
+}
+
 func (c *HTTPClient) Subscribe(args SubscribeArgs) error {
 	return c.rpcClient.Call("RPC.Subscribe", args, nil)
 }
 
+// OpenSubscription opens a streaming subscription to a state URI.  Unlike
+// Subscribe, the messages are delivered to the caller.  Closing the returned
+// subscription ends it.
+func (c *HTTPClient) OpenSubscription(args SubscribeArgs) (prototree.ReadableSubscription, error) {
+	u, err := url.Parse(c.dialAddr)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, HTTPSubscriptionPath)
+
+	var subscriptionType prototree.SubscriptionType
+	if args.Txs {
+		subscriptionType |= prototree.SubscriptionType_Txs
+	}
+	if args.States {
+		subscriptionType |= prototree.SubscriptionType_States
+	}
+	subTypeBytes, err := subscriptionType.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("state_uri", args.StateURI)
+	query.Set("subscription_type", string(subTypeBytes))
+	if args.Keypath != "" {
+		query.Set("keypath", args.Keypath)
+	}
+	if opts := args.FetchHistoryOpts; opts != nil {
+		query.Set("fetch_history", "true")
+		if opts.FromTxID != (state.Version{}) {
+			query.Set("from_tx", opts.FromTxID.Hex())
+		}
+		if opts.ToTxID != (state.Version{}) {
+			query.Set("to_tx", opts.ToTxID.Hex())
+		}
+		if len(opts.KnownLeaves) > 0 {
+			leafStrs := make([]string, len(opts.KnownLeaves))
+			for i, leaf := range opts.KnownLeaves {
+				leafStrs[i] = leaf.Hex()
+			}
+			query.Set("known_leaves", strings.Join(leafStrs, ","))
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if len(c.ucan) > 0 {
+		req.Header.Set("Authorization", "Bearer "+c.ucan)
+	}
+
+	resp, err := c.streamingClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while subscribing to %v", args.StateURI)
+	} else if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, errors.Errorf("while subscribing to %v: (%v) %v", args.StateURI, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return &httpClientSubscription{stream: resp.Body, reader: bufio.NewReader(resp.Body)}, nil
+}
+
+type httpClientSubscription struct {
+	stream io.ReadCloser
+	reader *bufio.Reader
+}
+
+var _ prototree.ReadableSubscription = (*httpClientSubscription)(nil)
+
+func (sub *httpClientSubscription) Read() (prototree.SubscriptionMsg, error) {
+	for {
+		bs, err := sub.reader.ReadBytes(byte('\n'))
+		if err != nil {
+			return prototree.SubscriptionMsg{}, err
+		}
+		bs = bytes.Trim(bs, "\n ")
+		if !bytes.HasPrefix(bs, []byte("data: ")) {
+			continue
+		}
+		bs = bytes.TrimPrefix(bs, []byte("data: "))
+
+		var msg prototree.SubscriptionMsg
+		err = json.Unmarshal(bs, &msg)
+		if err != nil {
+			return prototree.SubscriptionMsg{}, err
+		}
+		return msg, nil
+	}
+}
+
+func (sub *httpClientSubscription) Close() error {
+	return sub.stream.Close()
+}
+
 func (c *HTTPClient) Identities() ([]Identity, error) {
 	var resp IdentitiesResponse
 	return resp.Identities, c.rpcClient.Call("RPC.Identities", nil, &resp)
@@ -120,11 +250,74 @@ func (c *HTTPClient) KnownStateURIs() ([]string, error) {
 	return resp.StateURIs, c.rpcClient.Call("RPC.KnownStateURIs", nil, &resp)
 }
 
+func (c *HTTPClient) StateAtVersion(args StateAtVersionArgs) (interface{}, error) {
+	var resp StateAtVersionResponse
+	err := c.rpcClient.Call("RPC.StateAtVersion", args, &resp)
+	return resp.State, err
+}
+
+func (c *HTTPClient) FetchTx(args FetchTxArgs) (tree.Tx, error) {
+	var resp FetchTxResponse
+	err := c.rpcClient.Call("RPC.FetchTx", args, &resp)
+	return resp.Tx, err
+}
+
+func (c *HTTPClient) FetchTxs(args FetchTxsArgs) ([]tree.Tx, error) {
+	var resp FetchTxsResponse
+	err := c.rpcClient.Call("RPC.FetchTxs", args, &resp)
+	return resp.Txs, err
+}
+
+func (c *HTTPClient) Leaves(args LeavesArgs) ([]state.Version, error) {
+	var resp LeavesResponse
+	err := c.rpcClient.Call("RPC.Leaves", args, &resp)
+	return resp.Leaves, err
+}
+
+func (c *HTTPClient) QueryIndex(args QueryIndexArgs) (interface{}, error) {
+	var resp QueryIndexResponse
+	err := c.rpcClient.Call("RPC.QueryIndex", args, &resp)
+	return resp.Result, err
+}
+
+func (c *HTTPClient) SearchIndex(args SearchIndexArgs) ([]interface{}, error) {
+	var resp SearchIndexResponse
+	err := c.rpcClient.Call("RPC.SearchIndex", args, &resp)
+	return resp.Results, err
+}
+
+func (c *HTTPClient) SearchText(args SearchTextArgs) ([]interface{}, error) {
+	var resp SearchTextResponse
+	err := c.rpcClient.Call("RPC.SearchText", args, &resp)
+	return resp.Results, err
+}
+
+func (c *HTTPClient) Mempool(args MempoolArgs) ([]tree.Tx, error) {
+	var resp MempoolResponse
+	err := c.rpcClient.Call("RPC.Mempool", args, &resp)
+	return resp.Txs, err
+}
+
 func (c *HTTPClient) SendTx(args SendTxArgs) error {
 	return c.rpcClient.Call("RPC.SendTx", args, nil)
 }
 
+func (c *HTTPClient) PruneTxs(args PruneTxsArgs) (int, error) {
+	var resp PruneTxsResponse
+	return resp.NumPruned, c.rpcClient.Call("RPC.PruneTxs", args, &resp)
+}
+
+func (c *HTTPClient) UpdatePrivateTreeMembers(args UpdatePrivateTreeMembersArgs) error {
+	return c.rpcClient.Call("RPC.UpdatePrivateTreeMembers", args, nil)
+}
+
 func (c *HTTPClient) StoreBlob(args StoreBlobArgs) (StoreBlobResponse, error) {
 	var resp StoreBlobResponse
 	return resp, c.rpcClient.Call("RPC.StoreBlob", args, &resp)
 }
+
+func (c *HTTPClient) FetchBlob(args FetchBlobArgs) ([]byte, error) {
+	var resp FetchBlobResponse
+	err := c.rpcClient.Call("RPC.FetchBlob", args, &resp)
+	return resp.Blob, err
+}
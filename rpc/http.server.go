@@ -3,14 +3,14 @@ package rpc
 import (
 	"bytes"
 	"context"
-	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gorilla/rpc/v2"
 	"github.com/gorilla/rpc/v2/json2"
 
@@ -27,6 +27,7 @@ import (
 	"redwood.dev/swarm/prototree"
 	"redwood.dev/tree"
 	"redwood.dev/types"
+	"redwood.dev/ucan"
 	"redwood.dev/utils"
 )
 
@@ -44,7 +45,7 @@ type HTTPWhitelistConfig struct {
 	PermittedAddrs []types.Address `json:"permittedAddrs" yaml:"PermittedAddrs"`
 }
 
-func StartHTTPRPC(svc interface{}, config *HTTPConfig, jwtSecret []byte) (*http.Server, error) {
+func StartHTTPRPC(svc interface{}, config *HTTPConfig, ucanVerifier *ucan.Verifier) (*http.Server, error) {
 	if config == nil || !config.Enabled {
 		return nil, nil
 	}
@@ -57,21 +58,73 @@ func StartHTTPRPC(svc interface{}, config *HTTPConfig, jwtSecret []byte) (*http.
 		server := rpc.NewServer()
 		server.RegisterCodec(json2.NewCodec(), "application/json")
 		server.RegisterService(svc, "RPC")
+		server.RegisterValidateRequestFunc(checkRequiredCapability)
 
-		httpServer.Handler = server
+		mux := http.NewServeMux()
+		mux.Handle("/", server)
+		if subServer, is := svc.(subscriptionServer); is {
+			mux.HandleFunc(HTTPSubscriptionPath, subServer.ServeSubscription)
+		}
+
+		httpServer.Handler = mux
 		if config.Whitelist.Enabled {
-			httpServer.Handler = NewWhitelistMiddleware(config.Whitelist.PermittedAddrs, jwtSecret, server)
+			httpServer.Handler = NewWhitelistMiddleware(config.Whitelist.PermittedAddrs, ucanVerifier, mux)
 		}
 		httpServer.Handler = utils.UnrestrictedCors(httpServer.Handler)
-		httpServer.ListenAndServe()
+		if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+			httpServer.ListenAndServeTLS(config.TLSCertFile, config.TLSKeyFile)
+		} else {
+			httpServer.ListenAndServe()
+		}
 	}()
 
 	return httpServer, nil
 }
 
+// HTTPSubscriptionPath is the path of the server-sent events endpoint that
+// streams subscription messages to RPC clients (see HTTPServer.ServeSubscription).
+const HTTPSubscriptionPath = "/subscribe"
+
+type subscriptionServer interface {
+	ServeSubscription(w http.ResponseWriter, r *http.Request)
+}
+
+// ScopedArgs is implemented by the args of RPC methods that only touch a single
+// state URI.  When the whitelist is enabled, the caller's UCAN must grant the
+// capability they return.  Every other method requires ucan.AdminCapability.
+type ScopedArgs interface {
+	RequiredCapability() ucan.Capability
+}
+
+func checkRequiredCapability(info *rpc.RequestInfo, args interface{}) error {
+	required := ucan.AdminCapability
+	if args, is := args.(ScopedArgs); is {
+		required = args.RequiredCapability()
+	}
+	return checkCapability(info.Request.Context(), required)
+}
+
+// checkCapability checks the UCAN that the whitelist middleware attached to the
+// request.  If there isn't one, the whitelist is disabled and anything goes.
+func checkCapability(ctx context.Context, required ucan.Capability) error {
+	u, exists := ucan.FromContext(ctx)
+	if !exists || u.Grants(required) {
+		return nil
+	}
+	return errors.Wrapf(errors.Err403, "ucan does not grant %v on %v", required.Ability, required.StateURI)
+}
+
+func readCapability(stateURI string, keypath state.Keypath) ucan.Capability {
+	return ucan.Capability{StateURI: stateURI, Keypath: keypath, Ability: ucan.AbilityRead}
+}
+
+func writeCapability(stateURI string) ucan.Capability {
+	return ucan.Capability{StateURI: stateURI, Ability: ucan.AbilityWrite}
+}
+
 type HTTPServer struct {
 	log.Logger
-	jwtSecret       []byte
+	ucanVerifier    *ucan.Verifier
 	authProto       protoauth.AuthProtocol
 	blobProto       protoblob.BlobProtocol
 	treeProto       prototree.TreeProtocol
@@ -83,7 +136,7 @@ type HTTPServer struct {
 }
 
 func NewHTTPServer(
-	jwtSecret []byte,
+	ucanVerifier *ucan.Verifier,
 	authProto protoauth.AuthProtocol,
 	blobProto protoblob.BlobProtocol,
 	treeProto prototree.TreeProtocol,
@@ -95,7 +148,7 @@ func NewHTTPServer(
 ) *HTTPServer {
 	return &HTTPServer{
 		Logger:          log.NewLogger("http rpc"),
-		jwtSecret:       jwtSecret,
+		ucanVerifier:    ucanVerifier,
 		authProto:       authProto,
 		blobProto:       blobProto,
 		treeProto:       treeProto,
@@ -108,31 +161,80 @@ func NewHTTPServer(
 }
 
 type (
-	UcanArgs     struct{}
+	UcanArgs struct {
+		// Audience defaults to the node's default identity
+		Audience *types.Address
+		// Capabilities defaults to ucan.AdminCapability
+		Capabilities []ucan.Capability
+		// Lifetime, in seconds, defaults to DefaultUCANLifetime
+		Lifetime uint64
+	}
 	UcanResponse struct {
-		JWT string
+		UCAN string
 	}
 )
 
+const DefaultUCANLifetime = 24 * time.Hour
+
+// Ucan issues a UCAN signed by the node's default identity, which can be
+// presented to this node's RPC servers and braidhttp transport.
 func (s *HTTPServer) Ucan(r *http.Request, args *UcanArgs, resp *UcanResponse) error {
-	identity, err := s.keyStore.DefaultPublicIdentity()
+	if s.ucanVerifier == nil {
+		return errors.ErrUnsupported
+	}
+
+	var audience types.Address
+	if args.Audience != nil {
+		audience = *args.Audience
+	} else {
+		identity, err := s.keyStore.DefaultPublicIdentity()
+		if err != nil {
+			return err
+		}
+		audience = identity.Address()
+	}
+
+	capabilities := args.Capabilities
+	if len(capabilities) == 0 {
+		capabilities = []ucan.Capability{ucan.AdminCapability}
+	}
+
+	lifetime := DefaultUCANLifetime
+	if args.Lifetime > 0 {
+		lifetime = time.Duration(args.Lifetime) * time.Second
+	}
+
+	u, err := s.ucanVerifier.Issue(audience, capabilities, time.Now().Add(lifetime))
 	if err != nil {
 		return err
 	}
+	resp.UCAN = u.String()
+	return nil
+}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"address": identity.Address().Hex(),
-		"nbf":     time.Date(2015, 10, 10, 12, 0, 0, 0, time.UTC).Unix(),
-	})
+type (
+	RevokeUcanArgs struct {
+		// UCAN is revoked by this node, which must have issued it or one of
+		// its proofs.
+		UCAN string
+		// Revocation, if set, is a revocation signed by someone else.
+		Revocation *ucan.Revocation
+	}
+	RevokeUcanResponse struct{}
+)
 
-	// Sign and get the complete encoded token as a string using the secret
-	jwtTokenString, err := jwtToken.SignedString(s.jwtSecret)
+func (s *HTTPServer) RevokeUcan(r *http.Request, args *RevokeUcanArgs, resp *RevokeUcanResponse) error {
+	if s.ucanVerifier == nil {
+		return errors.ErrUnsupported
+	}
+	if args.Revocation != nil {
+		return s.ucanVerifier.AddRevocation(*args.Revocation)
+	}
+	u, err := ucan.Parse(args.UCAN)
 	if err != nil {
 		return err
 	}
-
-	resp.JWT = jwtTokenString
-	return nil
+	return s.ucanVerifier.Revoke(u)
 }
 
 type (
@@ -141,10 +243,18 @@ type (
 		Txs      bool
 		States   bool
 		Keypath  string
+
+		// FetchHistoryOpts, if non-nil, causes the tree's history to be
+		// replayed.  It's only used by HTTPClient.OpenSubscription.
+		FetchHistoryOpts *prototree.FetchHistoryOpts `json:"-"`
 	}
 	SubscribeResponse struct{}
 )
 
+func (args SubscribeArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, state.Keypath(args.Keypath))
+}
+
 func (s *HTTPServer) Subscribe(r *http.Request, args *SubscribeArgs, resp *SubscribeResponse) (err error) {
 	if s.treeProto == nil {
 		return errors.ErrUnsupported
@@ -169,6 +279,117 @@ func (s *HTTPServer) Subscribe(r *http.Request, args *SubscribeArgs, resp *Subsc
 	return nil
 }
 
+// ServeSubscription streams a subscription to the caller as server-sent events,
+// one JSON-encoded prototree.SubscriptionMsg per event.  The request's query
+// parameters are:
+//   - state_uri (required)
+//   - subscription_type (required): "txs", "states", or "txs,states"
+//   - keypath
+//   - fetch_history: if "true", the tree's txs are replayed before any new ones,
+//     limited by from_tx, to_tx, and known_leaves (a comma-separated list of
+//     versions), exactly as with prototree.FetchHistoryOpts
+func (s *HTTPServer) ServeSubscription(w http.ResponseWriter, r *http.Request) {
+	if s.treeProto == nil {
+		http.Error(w, errors.ErrUnsupported.Error(), http.StatusNotImplemented)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	type request struct {
+		StateURI     string                     `query:"state_uri"         required:"true"`
+		Keypath      state.Keypath              `query:"keypath"`
+		SubType      prototree.SubscriptionType `query:"subscription_type" required:"true"`
+		FetchHistory bool                       `query:"fetch_history"`
+		FromTxID     state.Version              `query:"from_tx"`
+		ToTxID       state.Version              `query:"to_tx"`
+		KnownLeaves  versionList                `query:"known_leaves"`
+	}
+
+	var req request
+	err := utils.UnmarshalHTTPRequest(&req, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = checkCapability(r.Context(), readCapability(req.StateURI, req.Keypath))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var fetchHistoryOpts *prototree.FetchHistoryOpts
+	if req.FetchHistory {
+		fetchHistoryOpts = &prototree.FetchHistoryOpts{
+			FromTxID:    req.FromTxID,
+			ToTxID:      req.ToTxID,
+			KnownLeaves: req.KnownLeaves,
+		}
+	}
+
+	sub, err := s.treeProto.InProcessSubscription(r.Context(), req.StateURI, req.SubType, req.Keypath, fetchHistoryOpts)
+	if errors.Cause(err) == errors.Err403 {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer sub.Close()
+
+	go func() {
+		<-r.Context().Done()
+		sub.Close()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	for {
+		msg, err := sub.Read()
+		if err != nil {
+			return
+		}
+
+		bs, err := json.Marshal(msg)
+		if err != nil {
+			s.Errorf("while marshaling subscription message: %v", err)
+			return
+		}
+
+		_, err = w.Write([]byte("data: " + string(bs) + "\n\n"))
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+type versionList []state.Version
+
+func (l *versionList) UnmarshalText(text []byte) error {
+	*l = nil
+	for _, s := range strings.Split(string(text), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		version, err := state.VersionFromHex(s)
+		if err != nil {
+			return errors.Errorf("bad version list: '%v'", string(text))
+		}
+		*l = append(*l, version)
+	}
+	return nil
+}
+
 type (
 	IdentitiesArgs     struct{}
 	IdentitiesResponse struct {
@@ -294,6 +515,280 @@ func (s *HTTPServer) KnownStateURIs(r *http.Request, args *KnownStateURIsArgs, r
 	return nil
 }
 
+type (
+	StateAtVersionArgs struct {
+		StateURI string
+		Keypath  string
+		Version  *state.Version
+	}
+	StateAtVersionResponse struct {
+		State interface{}
+	}
+)
+
+func (args StateAtVersionArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, state.Keypath(args.Keypath))
+}
+
+func (s *HTTPServer) StateAtVersion(r *http.Request, args *StateAtVersionArgs, resp *StateAtVersionResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	node, err := s.controllerHub.StateAtVersion(args.StateURI, args.Version)
+	if err != nil {
+		return err
+	}
+	defer node.Close()
+
+	val, _, err := node.Value(state.Keypath(args.Keypath), nil)
+	if err != nil {
+		return err
+	}
+	resp.State = val
+	return nil
+}
+
+type (
+	FetchTxArgs struct {
+		StateURI string
+		TxID     state.Version
+	}
+	FetchTxResponse struct {
+		Tx tree.Tx
+	}
+)
+
+func (args FetchTxArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, nil)
+}
+
+func (s *HTTPServer) FetchTx(r *http.Request, args *FetchTxArgs, resp *FetchTxResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	tx, err := s.controllerHub.FetchTx(args.StateURI, args.TxID)
+	if err != nil {
+		return err
+	}
+	resp.Tx = tx
+	return nil
+}
+
+type (
+	FetchTxsArgs struct {
+		StateURI string
+		FromTxID state.Version
+	}
+	FetchTxsResponse struct {
+		Txs []tree.Tx
+	}
+)
+
+func (args FetchTxsArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, nil)
+}
+
+func (s *HTTPServer) FetchTxs(r *http.Request, args *FetchTxsArgs, resp *FetchTxsResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	iter := s.controllerHub.FetchTxs(args.StateURI, args.FromTxID)
+	defer iter.Close()
+
+	for {
+		tx := iter.Next()
+		if iter.Error() != nil {
+			return iter.Error()
+		} else if tx == nil {
+			break
+		}
+		resp.Txs = append(resp.Txs, *tx)
+	}
+	return nil
+}
+
+type (
+	LeavesArgs struct {
+		StateURI string
+	}
+	LeavesResponse struct {
+		Leaves []state.Version
+	}
+)
+
+func (args LeavesArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, nil)
+}
+
+func (s *HTTPServer) Leaves(r *http.Request, args *LeavesArgs, resp *LeavesResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	leaves, err := s.controllerHub.Leaves(args.StateURI)
+	if err != nil {
+		return err
+	}
+	resp.Leaves = leaves
+	return nil
+}
+
+type (
+	QueryIndexArgs struct {
+		StateURI   string
+		Version    *state.Version
+		Keypath    string
+		IndexName  string
+		QueryParam string
+		Range      *state.Range
+	}
+	QueryIndexResponse struct {
+		Result interface{}
+	}
+)
+
+func (args QueryIndexArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, state.Keypath(args.Keypath))
+}
+
+func (s *HTTPServer) QueryIndex(r *http.Request, args *QueryIndexArgs, resp *QueryIndexResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	node, err := s.controllerHub.QueryIndex(
+		args.StateURI,
+		args.Version,
+		state.Keypath(args.Keypath),
+		state.Keypath(args.IndexName),
+		state.Keypath(args.QueryParam),
+		args.Range,
+	)
+	if err != nil {
+		return err
+	}
+	defer node.Close()
+
+	val, _, err := node.Value(nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Result = val
+	return nil
+}
+
+type (
+	SearchIndexArgs struct {
+		StateURI  string
+		Version   *state.Version
+		Keypath   string
+		IndexName string
+		Args      []interface{}
+		Prefix    interface{}
+		Start     interface{}
+		End       interface{}
+		Range     *state.Range
+	}
+	SearchIndexResponse struct {
+		Results []interface{}
+	}
+)
+
+func (args SearchIndexArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, state.Keypath(args.Keypath))
+}
+
+func (s *HTTPServer) SearchIndex(r *http.Request, args *SearchIndexArgs, resp *SearchIndexResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	node, err := s.controllerHub.SearchIndex(
+		args.StateURI,
+		args.Version,
+		state.Keypath(args.Keypath),
+		state.Keypath(args.IndexName),
+		tree.IndexQuery{Args: args.Args, Prefix: args.Prefix, Start: args.Start, End: args.End},
+		args.Range,
+	)
+	if err != nil {
+		return err
+	}
+	defer node.Close()
+
+	val, _, err := node.Value(nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Results, _ = val.([]interface{})
+	return nil
+}
+
+type (
+	SearchTextArgs struct {
+		StateURI  string
+		Version   *state.Version
+		Keypath   string
+		IndexName string
+		Query     string
+		Range     *state.Range
+	}
+	SearchTextResponse struct {
+		Results []interface{}
+	}
+)
+
+func (args SearchTextArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, state.Keypath(args.Keypath))
+}
+
+func (s *HTTPServer) SearchText(r *http.Request, args *SearchTextArgs, resp *SearchTextResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	node, err := s.controllerHub.SearchText(
+		args.StateURI,
+		args.Version,
+		state.Keypath(args.Keypath),
+		state.Keypath(args.IndexName),
+		args.Query,
+		args.Range,
+	)
+	if err != nil {
+		return err
+	}
+	defer node.Close()
+
+	val, _, err := node.Value(nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Results, _ = val.([]interface{})
+	return nil
+}
+
+type (
+	MempoolArgs struct {
+		StateURI string
+	}
+	MempoolResponse struct {
+		Txs []tree.Tx
+	}
+)
+
+func (args MempoolArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, nil)
+}
+
+func (s *HTTPServer) Mempool(r *http.Request, args *MempoolArgs, resp *MempoolResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	txs, err := s.controllerHub.Mempool(args.StateURI)
+	if err != nil {
+		return err
+	}
+	resp.Txs = txs
+	return nil
+}
+
 type (
 	SendTxArgs struct {
 		Tx tree.Tx
@@ -301,6 +796,10 @@ type (
 	SendTxResponse struct{}
 )
 
+func (args SendTxArgs) RequiredCapability() ucan.Capability {
+	return writeCapability(args.Tx.StateURI)
+}
+
 func (s *HTTPServer) SendTx(r *http.Request, args *SendTxArgs, resp *SendTxResponse) error {
 	if s.treeProto == nil {
 		return errors.ErrUnsupported
@@ -308,6 +807,30 @@ func (s *HTTPServer) SendTx(r *http.Request, args *SendTxArgs, resp *SendTxRespo
 	return s.treeProto.SendTx(context.Background(), args.Tx)
 }
 
+// PruneTxsArgs isn't ScopedArgs, as pruning deletes history that every reader
+// of the state URI relies on, so it requires ucan.AdminCapability.
+type (
+	PruneTxsArgs struct {
+		StateURI       string
+		CheckpointTxID state.Version
+	}
+	PruneTxsResponse struct {
+		NumPruned int
+	}
+)
+
+func (s *HTTPServer) PruneTxs(r *http.Request, args *PruneTxsArgs, resp *PruneTxsResponse) error {
+	if s.controllerHub == nil {
+		return errors.ErrUnsupported
+	}
+	numPruned, err := s.controllerHub.Prune(args.StateURI, args.CheckpointTxID)
+	if err != nil {
+		return err
+	}
+	resp.NumPruned = numPruned
+	return nil
+}
+
 type (
 	StoreBlobArgs struct {
 		Blob []byte
@@ -328,6 +851,30 @@ func (s *HTTPServer) StoreBlob(r *http.Request, args *StoreBlobArgs, resp *Store
 	return nil
 }
 
+type (
+	FetchBlobArgs struct {
+		BlobID blob.ID
+	}
+	FetchBlobResponse struct {
+		Blob []byte
+	}
+)
+
+func (s *HTTPServer) FetchBlob(r *http.Request, args *FetchBlobArgs, resp *FetchBlobResponse) error {
+	reader, _, err := s.blobStore.BlobReader(args.BlobID)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	bs, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	resp.Blob = bs
+	return nil
+}
+
 type (
 	PrivateTreeMembersArgs struct {
 		StateURI string
@@ -337,6 +884,10 @@ type (
 	}
 )
 
+func (args PrivateTreeMembersArgs) RequiredCapability() ucan.Capability {
+	return readCapability(args.StateURI, nil)
+}
+
 func (s *HTTPServer) PrivateTreeMembers(r *http.Request, args *PrivateTreeMembersArgs, resp *PrivateTreeMembersResponse) error {
 	if s.controllerHub == nil {
 		return errors.ErrUnsupported
@@ -361,6 +912,26 @@ func (s *HTTPServer) PrivateTreeMembers(r *http.Request, args *PrivateTreeMember
 	return nil
 }
 
+type (
+	UpdatePrivateTreeMembersArgs struct {
+		StateURI string
+		Add      []types.Address
+		Remove   []types.Address
+	}
+	UpdatePrivateTreeMembersResponse struct{}
+)
+
+func (args UpdatePrivateTreeMembersArgs) RequiredCapability() ucan.Capability {
+	return writeCapability(args.StateURI)
+}
+
+func (s *HTTPServer) UpdatePrivateTreeMembers(r *http.Request, args *UpdatePrivateTreeMembersArgs, resp *UpdatePrivateTreeMembersResponse) error {
+	if s.treeProto == nil {
+		return errors.ErrUnsupported
+	}
+	return s.treeProto.UpdateMembers(context.Background(), args.StateURI, args.Add, args.Remove)
+}
+
 type (
 	PeersArgs struct {
 		StateURI string
@@ -413,22 +984,35 @@ func (s *HTTPServer) Peers(r *http.Request, args *PeersArgs, resp *PeersResponse
 	return nil
 }
 
+// whitelistMiddleware only lets through requests carrying a valid UCAN.  A
+// whitelisted address can obtain one by signing a challenge (the AUTHORIZE
+// method), and may then delegate some or all of its capabilities to others.
+//
+// Unlike the braidhttp transport, which only accepts a UCAN from an audience
+// that has proven it holds the audience's key, the RPC servers (HTTP and gRPC)
+// accept UCANs as bearer tokens: whoever presents one gets the capabilities it
+// grants.  The braidhttp rule exists because the transport treats a UCAN's
+// audience as the caller's identity, stores the UCAN as that identity's
+// credential, and checks state URI ACLs against it.  The RPC servers do none of
+// that: they only check that the UCAN grants the capability that each call
+// requires, and txs sent through them are signed by the node's own identities
+// regardless of who sent them.  A leaked RPC UCAN therefore grants exactly its
+// capabilities and nothing more, like an API key, so the RPC servers should be
+// served over TLS (or only on loopback) and UCANs delegated with short
+// lifetimes.
 type whitelistMiddleware struct {
 	permittedAddrs          map[types.Address]struct{}
 	nextHandler             http.Handler
-	jwtSecret               []byte
+	ucanVerifier            *ucan.Verifier
 	pendingAuthorizations   map[string]struct{}
 	pendingAuthorizationsMu sync.Mutex
 }
 
-func NewWhitelistMiddleware(permittedAddrs []types.Address, jwtSecret []byte, nextHandler http.Handler) *whitelistMiddleware {
-	if len(jwtSecret) == 0 {
-		jwtSecret = make([]byte, 64)
-		_, err := rand.Read(jwtSecret)
-		if err != nil {
-			panic(err)
-		}
-	}
+// WhitelistUCANLifetime is the lifetime of the UCANs issued in exchange for
+// a signed challenge.
+const WhitelistUCANLifetime = 24 * time.Hour
+
+func NewWhitelistMiddleware(permittedAddrs []types.Address, ucanVerifier *ucan.Verifier, nextHandler http.Handler) *whitelistMiddleware {
 	paddrs := make(map[types.Address]struct{}, len(permittedAddrs))
 	for _, addr := range permittedAddrs {
 		paddrs[addr] = struct{}{}
@@ -436,7 +1020,7 @@ func NewWhitelistMiddleware(permittedAddrs []types.Address, jwtSecret []byte, ne
 	return &whitelistMiddleware{
 		permittedAddrs:        paddrs,
 		nextHandler:           nextHandler,
-		jwtSecret:             jwtSecret,
+		ucanVerifier:          ucanVerifier,
 		pendingAuthorizations: make(map[string]struct{}),
 	}
 }
@@ -446,17 +1030,12 @@ func (mw *whitelistMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		responseHex := r.Header.Get("Response")
 		if responseHex == "" {
 			// Wants challenge
-			challenge, err := protoauth.GenerateChallengeMsg()
+			challenge, err := mw.newChallenge()
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
 
-			mw.pendingAuthorizationsMu.Lock()
-			defer mw.pendingAuthorizationsMu.Unlock()
-
-			mw.pendingAuthorizations[string(challenge)] = struct{}{}
-
 			challengeHex := hex.EncodeToString(challenge)
 
 			utils.RespondJSON(w, struct {
@@ -477,70 +1056,89 @@ func (mw *whitelistMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request)
 				return
 			}
 
-			mw.pendingAuthorizationsMu.Lock()
-			defer mw.pendingAuthorizationsMu.Unlock()
-			_, exists := mw.pendingAuthorizations[string(challenge)]
-			if !exists {
-				http.Error(w, "no pending authorization", http.StatusBadRequest)
-				return
-			}
-
 			sig, err := hex.DecodeString(responseHex)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
 
-			sigpubkey, err := crypto.RecoverSigningPubkey(types.HashBytes(challenge), sig)
+			u, status, err := mw.redeemChallenge(challenge, sig)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
-
-			delete(mw.pendingAuthorizations, string(challenge)) // @@TODO: expiration/garbage collection for failed auths
-
-			jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-				"address": sigpubkey.Address().Hex(),
-				"nbf":     time.Date(2015, 10, 10, 12, 0, 0, 0, time.UTC).Unix(),
-			})
-
-			// Sign and get the complete encoded token as a string using the secret
-			jwtTokenString, err := jwtToken.SignedString(mw.jwtSecret)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				http.Error(w, err.Error(), status)
 				return
 			}
 
 			utils.RespondJSON(w, struct {
-				JWT string `json:"jwt"`
-			}{jwtTokenString})
+				UCAN string `json:"ucan"`
+			}{u.String()})
 		}
 
 	} else {
-		claims, exists, err := utils.ParseJWT(r.Header.Get("Authorization"), mw.jwtSecret)
-		if err != nil {
-			http.Error(w, "bad Authorization header", http.StatusBadRequest)
-			return
-		} else if !exists {
-			http.Error(w, "no JWT present", http.StatusForbidden)
-			return
-		}
-		addrHex, ok := claims["address"].(string)
-		if !ok {
-			http.Error(w, "jwt does not contain 'address' claim", http.StatusBadRequest)
-			return
-		}
-		addr, err := types.AddressFromHex(addrHex)
+		u, status, err := mw.checkUCAN(r.Header.Get("Authorization"))
 		if err != nil {
-			http.Error(w, "jwt 'address' claim contains invalid data", http.StatusBadRequest)
-			return
-		}
-		_, exists = mw.permittedAddrs[addr]
-		if !exists {
-			http.Error(w, "nope", http.StatusForbidden)
+			http.Error(w, err.Error(), status)
 			return
 		}
+		mw.nextHandler.ServeHTTP(w, r.WithContext(ucan.NewContext(r.Context(), u)))
+	}
+}
+
+func (mw *whitelistMiddleware) newChallenge() (protoauth.ChallengeMsg, error) {
+	challenge, err := protoauth.GenerateChallengeMsg()
+	if err != nil {
+		return nil, err
+	}
+
+	mw.pendingAuthorizationsMu.Lock()
+	defer mw.pendingAuthorizationsMu.Unlock()
 
-		mw.nextHandler.ServeHTTP(w, r)
+	mw.pendingAuthorizations[string(challenge)] = struct{}{}
+	return challenge, nil
+}
+
+// redeemChallenge exchanges a challenge signed by a whitelisted address for a
+// UCAN granting it full access.  If it fails, it also returns the HTTP status
+// code describing the failure.
+func (mw *whitelistMiddleware) redeemChallenge(challenge, sig []byte) (*ucan.UCAN, int, error) {
+	mw.pendingAuthorizationsMu.Lock()
+	defer mw.pendingAuthorizationsMu.Unlock()
+	_, exists := mw.pendingAuthorizations[string(challenge)]
+	if !exists {
+		return nil, http.StatusBadRequest, errors.New("no pending authorization")
+	}
+
+	sigpubkey, err := crypto.RecoverSigningPubkey(types.HashBytes(challenge), sig)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	delete(mw.pendingAuthorizations, string(challenge)) // @@TODO: expiration/garbage collection for failed auths
+
+	_, exists = mw.permittedAddrs[sigpubkey.Address()]
+	if !exists {
+		return nil, http.StatusForbidden, errors.New("nope")
+	}
+
+	u, err := mw.ucanVerifier.Issue(sigpubkey.Address(), []ucan.Capability{ucan.AdminCapability}, time.Now().Add(WhitelistUCANLifetime))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return u, http.StatusOK, nil
+}
+
+// checkUCAN verifies the UCAN in the given Authorization header.  If it's
+// invalid, it also returns the HTTP status code describing the failure.  The
+// UCAN is a bearer token, so its audience isn't checked (see
+// whitelistMiddleware).
+func (mw *whitelistMiddleware) checkUCAN(authHeader string) (*ucan.UCAN, int, error) {
+	if authHeader == "" {
+		return nil, http.StatusForbidden, errors.New("no UCAN present")
+	} else if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, http.StatusBadRequest, errors.New("bad Authorization header")
+	}
+	u, err := mw.ucanVerifier.Verify(authHeader)
+	if err != nil {
+		return nil, http.StatusForbidden, err
 	}
+	return u, http.StatusOK, nil
 }
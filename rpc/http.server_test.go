@@ -0,0 +1,186 @@
+package rpc_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	rwrpc "redwood.dev/rpc"
+	"redwood.dev/state"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/tree"
+	"redwood.dev/ucan"
+)
+
+func TestCheckRequiredCapability(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	requestWith := func(capabilities ...ucan.Capability) *rpc.RequestInfo {
+		req := httptest.NewRequest("POST", "/", nil)
+		u := &ucan.UCAN{Payload: ucan.Payload{Capabilities: capabilities}}
+		return &rpc.RequestInfo{Request: req.WithContext(ucan.NewContext(req.Context(), u))}
+	}
+	writer := requestWith(ucan.Capability{StateURI: stateURI, Ability: ucan.AbilityWrite})
+	admin := requestWith(ucan.AdminCapability)
+
+	t.Run("scoped methods accept a ucan for the state URI", func(t *testing.T) {
+		args := &rwrpc.SendTxArgs{}
+		args.Tx.StateURI = stateURI
+		require.NoError(t, rwrpc.CheckRequiredCapability(writer, args))
+	})
+
+	t.Run("pruning requires the admin capability", func(t *testing.T) {
+		args := &rwrpc.PruneTxsArgs{StateURI: stateURI, CheckpointTxID: state.RandomVersion()}
+		err := rwrpc.CheckRequiredCapability(writer, args)
+		require.True(t, errors.Cause(err) == errors.Err403)
+		require.NoError(t, rwrpc.CheckRequiredCapability(admin, args))
+	})
+}
+
+func TestHTTPServer_ServeSubscription(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	node := setupTestNode(t)
+	server := rwrpc.NewHTTPServer(node.ucanVerifier, nil, nil, node.treeProto, node.peerStore, node.keyStore, node.blobStore, node.hub, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(rwrpc.HTTPSubscriptionPath, server.ServeSubscription)
+	httpServer := httptest.NewServer(mux)
+	t.Cleanup(httpServer.Close)
+
+	client := rwrpc.NewHTTPClient(httpServer.URL)
+	t.Cleanup(func() { client.Close() })
+
+	addTx := func(t *testing.T, id state.Version, parents []state.Version, patchStr string) tree.Tx {
+		t.Helper()
+		var patch tree.Patch
+		require.NoError(t, patch.UnmarshalText([]byte(patchStr)))
+		tx := tree.Tx{
+			ID:       id,
+			Parents:  parents,
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{patch},
+		}
+		tx.Sig, err = alice.SignHash(tx.Hash())
+		require.NoError(t, err)
+
+		require.NoError(t, node.hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := node.txStore.FetchTx(stateURI, tx.ID)
+			return err == nil && tx.Status == tree.TxStatusValid
+		}, 5*time.Second, 10*time.Millisecond)
+		return tx
+	}
+
+	readMsg := func(t *testing.T, sub prototree.ReadableSubscription) prototree.SubscriptionMsg {
+		t.Helper()
+		chMsg := make(chan prototree.SubscriptionMsg, 1)
+		chErr := make(chan error, 1)
+		go func() {
+			msg, err := sub.Read()
+			if err != nil {
+				chErr <- err
+				return
+			}
+			chMsg <- msg
+		}()
+		select {
+		case msg := <-chMsg:
+			return msg
+		case err := <-chErr:
+			t.Fatalf("error reading subscription: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out reading subscription")
+		}
+		return prototree.SubscriptionMsg{}
+	}
+
+	genesis := addTx(t, tree.GenesisTxID, nil, ` = {"messages": ["hi"]}`)
+	second := addTx(t, state.RandomVersion(), []state.Version{genesis.ID}, `.messages[1:1] = ["yo"]`)
+
+	t.Run("history is replayed before new txs", func(t *testing.T) {
+		sub, err := client.OpenSubscription(rwrpc.SubscribeArgs{
+			StateURI:         stateURI,
+			Txs:              true,
+			States:           true,
+			FetchHistoryOpts: &prototree.FetchHistoryOpts{},
+		})
+		require.NoError(t, err)
+		defer sub.Close()
+
+		for _, expected := range []tree.Tx{genesis, second} {
+			msg := readMsg(t, sub)
+			require.Equal(t, stateURI, msg.StateURI)
+			require.NotNil(t, msg.Tx)
+			require.Equal(t, expected.ID, msg.Tx.ID)
+			require.Equal(t, expected.Patches, msg.Tx.Patches)
+		}
+
+		requireState := func(t *testing.T, msg prototree.SubscriptionMsg, leaf state.Version, expected interface{}) {
+			t.Helper()
+			require.NotNil(t, msg.State)
+			require.Equal(t, []state.Version{leaf}, msg.Leaves)
+			val, exists, err := msg.State.Value(state.Keypath("messages"), nil)
+			require.NoError(t, err)
+			require.True(t, exists)
+			require.Equal(t, expected, val)
+		}
+
+		// The replayed history is followed by the current state
+		msg := readMsg(t, sub)
+		require.Nil(t, msg.Tx)
+		requireState(t, msg, second.ID, []interface{}{"hi", "yo"})
+
+		third := addTx(t, state.RandomVersion(), []state.Version{second.ID}, `.messages[2:2] = ["sup"]`)
+
+		msg = readMsg(t, sub)
+		require.NotNil(t, msg.Tx)
+		require.Equal(t, third.ID, msg.Tx.ID)
+		require.Equal(t, []state.Version{second.ID}, msg.Tx.Parents)
+		require.Equal(t, third.Patches, msg.Tx.Patches)
+		requireState(t, msg, third.ID, []interface{}{"hi", "yo", "sup"})
+	})
+
+	t.Run("history is limited by from_tx", func(t *testing.T) {
+		sub, err := client.OpenSubscription(rwrpc.SubscribeArgs{
+			StateURI:         stateURI,
+			Txs:              true,
+			FetchHistoryOpts: &prototree.FetchHistoryOpts{FromTxID: second.ID},
+		})
+		require.NoError(t, err)
+		defer sub.Close()
+
+		msg := readMsg(t, sub)
+		require.NotNil(t, msg.Tx)
+		require.Equal(t, second.ID, msg.Tx.ID)
+	})
+
+	t.Run("bad requests are rejected", func(t *testing.T) {
+		_, err := client.OpenSubscription(rwrpc.SubscribeArgs{Txs: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "(400)")
+	})
+
+	t.Run("response writers that can't stream get a 500", func(t *testing.T) {
+		req := httptest.NewRequest("GET", rwrpc.HTTPSubscriptionPath+"?state_uri="+stateURI+"&subscription_type=txs", nil)
+		recorder := httptest.NewRecorder()
+		server.ServeSubscription(nonFlushingResponseWriter{recorder}, req)
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+// nonFlushingResponseWriter hides the http.Flusher implementation of the
+// ResponseWriter it wraps.
+type nonFlushingResponseWriter struct {
+	http.ResponseWriter
+}
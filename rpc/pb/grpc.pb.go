@@ -0,0 +1,8939 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: grpc.proto
+
+package pb
+
+import (
+	bytes "bytes"
+	context "context"
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strconv "strconv"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type Transport int32
+
+const (
+	LIBP2P Transport = 0
+	HTTPS  Transport = 1
+)
+
+var Transport_name = map[int32]string{
+	0: "LIBP2P",
+	1: "HTTPS",
+}
+
+var Transport_value = map[string]int32{
+	"LIBP2P": 0,
+	"HTTPS":  1,
+}
+
+func (Transport) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{0}
+}
+
+type TxPacket_TxStatus int32
+
+const (
+	UNKNOWN    TxPacket_TxStatus = 0
+	IN_MEMPOOL TxPacket_TxStatus = 1
+	INVALID    TxPacket_TxStatus = 2
+	VALID      TxPacket_TxStatus = 3
+)
+
+var TxPacket_TxStatus_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "IN_MEMPOOL",
+	2: "INVALID",
+	3: "VALID",
+}
+
+var TxPacket_TxStatus_value = map[string]int32{
+	"UNKNOWN":    0,
+	"IN_MEMPOOL": 1,
+	"INVALID":    2,
+	"VALID":      3,
+}
+
+func (TxPacket_TxStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{10, 0}
+}
+
+// An empty AuthorizeReq asks for a challenge.  Signing the challenge and
+// sending it back with the signature yields a UCAN, which must be sent with
+// every other call as "authorization" metadata ("Bearer <ucan>").  UCANs
+// obtained elsewhere (for example, delegated by a whitelisted address) are
+// accepted too.  UCANs are bearer tokens here: unlike the braidhttp transport,
+// the server doesn't require the caller to prove that it holds the UCAN's
+// audience key, since it never treats the audience as the caller's identity
+// (see rpc.whitelistMiddleware).
+type AuthorizeReq struct {
+	Challenge []byte `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Response  []byte `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (m *AuthorizeReq) Reset()      { *m = AuthorizeReq{} }
+func (*AuthorizeReq) ProtoMessage() {}
+func (*AuthorizeReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{0}
+}
+func (m *AuthorizeReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AuthorizeReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AuthorizeReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AuthorizeReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizeReq.Merge(m, src)
+}
+func (m *AuthorizeReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *AuthorizeReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizeReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthorizeReq proto.InternalMessageInfo
+
+func (m *AuthorizeReq) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *AuthorizeReq) GetResponse() []byte {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+type AuthorizeResp struct {
+	Challenge []byte `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Ucan      string `protobuf:"bytes,2,opt,name=ucan,proto3" json:"ucan,omitempty"`
+}
+
+func (m *AuthorizeResp) Reset()      { *m = AuthorizeResp{} }
+func (*AuthorizeResp) ProtoMessage() {}
+func (*AuthorizeResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{1}
+}
+func (m *AuthorizeResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AuthorizeResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AuthorizeResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AuthorizeResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizeResp.Merge(m, src)
+}
+func (m *AuthorizeResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *AuthorizeResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizeResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthorizeResp proto.InternalMessageInfo
+
+func (m *AuthorizeResp) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *AuthorizeResp) GetUcan() string {
+	if m != nil {
+		return m.Ucan
+	}
+	return ""
+}
+
+type SetHDMnemonicReq struct {
+	Mnemonic string `protobuf:"bytes,1,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+}
+
+func (m *SetHDMnemonicReq) Reset()      { *m = SetHDMnemonicReq{} }
+func (*SetHDMnemonicReq) ProtoMessage() {}
+func (*SetHDMnemonicReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{2}
+}
+func (m *SetHDMnemonicReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SetHDMnemonicReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SetHDMnemonicReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SetHDMnemonicReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetHDMnemonicReq.Merge(m, src)
+}
+func (m *SetHDMnemonicReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *SetHDMnemonicReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetHDMnemonicReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetHDMnemonicReq proto.InternalMessageInfo
+
+func (m *SetHDMnemonicReq) GetMnemonic() string {
+	if m != nil {
+		return m.Mnemonic
+	}
+	return ""
+}
+
+type SetHDMnemonicResp struct {
+}
+
+func (m *SetHDMnemonicResp) Reset()      { *m = SetHDMnemonicResp{} }
+func (*SetHDMnemonicResp) ProtoMessage() {}
+func (*SetHDMnemonicResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{3}
+}
+func (m *SetHDMnemonicResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SetHDMnemonicResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SetHDMnemonicResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SetHDMnemonicResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetHDMnemonicResp.Merge(m, src)
+}
+func (m *SetHDMnemonicResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *SetHDMnemonicResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetHDMnemonicResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetHDMnemonicResp proto.InternalMessageInfo
+
+type SubscribeToStatesReq struct {
+	StateURI string `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+	Keypath  string `protobuf:"bytes,2,opt,name=keypath,proto3" json:"keypath,omitempty"`
+}
+
+func (m *SubscribeToStatesReq) Reset()      { *m = SubscribeToStatesReq{} }
+func (*SubscribeToStatesReq) ProtoMessage() {}
+func (*SubscribeToStatesReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{4}
+}
+func (m *SubscribeToStatesReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SubscribeToStatesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SubscribeToStatesReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SubscribeToStatesReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeToStatesReq.Merge(m, src)
+}
+func (m *SubscribeToStatesReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *SubscribeToStatesReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeToStatesReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeToStatesReq proto.InternalMessageInfo
+
+func (m *SubscribeToStatesReq) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+func (m *SubscribeToStatesReq) GetKeypath() string {
+	if m != nil {
+		return m.Keypath
+	}
+	return ""
+}
+
+type StatePacket struct {
+	State  []*KeypathValue `protobuf:"bytes,1,rep,name=state,proto3" json:"state,omitempty"`
+	Leaves [][]byte        `protobuf:"bytes,2,rep,name=leaves,proto3" json:"leaves,omitempty"`
+}
+
+func (m *StatePacket) Reset()      { *m = StatePacket{} }
+func (*StatePacket) ProtoMessage() {}
+func (*StatePacket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{5}
+}
+func (m *StatePacket) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StatePacket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StatePacket.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StatePacket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatePacket.Merge(m, src)
+}
+func (m *StatePacket) XXX_Size() int {
+	return m.Size()
+}
+func (m *StatePacket) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatePacket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatePacket proto.InternalMessageInfo
+
+func (m *StatePacket) GetState() []*KeypathValue {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (m *StatePacket) GetLeaves() [][]byte {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+type KeypathValue struct {
+	Keypath string `protobuf:"bytes,1,opt,name=keypath,proto3" json:"keypath,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *KeypathValue) Reset()      { *m = KeypathValue{} }
+func (*KeypathValue) ProtoMessage() {}
+func (*KeypathValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{6}
+}
+func (m *KeypathValue) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *KeypathValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_KeypathValue.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *KeypathValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeypathValue.Merge(m, src)
+}
+func (m *KeypathValue) XXX_Size() int {
+	return m.Size()
+}
+func (m *KeypathValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeypathValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeypathValue proto.InternalMessageInfo
+
+func (m *KeypathValue) GetKeypath() string {
+	if m != nil {
+		return m.Keypath
+	}
+	return ""
+}
+
+func (m *KeypathValue) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// Each subscription stream's ID is sent to the client in the stream's
+// "subscription-id" header.  Unsubscribing ends only the stream with that ID,
+// which must have been opened for the same stateURI.
+type UnsubscribeFromStatesReq struct {
+	StateURI       string `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+	Keypath        string `protobuf:"bytes,2,opt,name=keypath,proto3" json:"keypath,omitempty"`
+	SubscriptionID string `protobuf:"bytes,3,opt,name=subscriptionID,proto3" json:"subscriptionID,omitempty"`
+}
+
+func (m *UnsubscribeFromStatesReq) Reset()      { *m = UnsubscribeFromStatesReq{} }
+func (*UnsubscribeFromStatesReq) ProtoMessage() {}
+func (*UnsubscribeFromStatesReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{7}
+}
+func (m *UnsubscribeFromStatesReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UnsubscribeFromStatesReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UnsubscribeFromStatesReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UnsubscribeFromStatesReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsubscribeFromStatesReq.Merge(m, src)
+}
+func (m *UnsubscribeFromStatesReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *UnsubscribeFromStatesReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsubscribeFromStatesReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsubscribeFromStatesReq proto.InternalMessageInfo
+
+func (m *UnsubscribeFromStatesReq) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+func (m *UnsubscribeFromStatesReq) GetKeypath() string {
+	if m != nil {
+		return m.Keypath
+	}
+	return ""
+}
+
+func (m *UnsubscribeFromStatesReq) GetSubscriptionID() string {
+	if m != nil {
+		return m.SubscriptionID
+	}
+	return ""
+}
+
+type UnsubscribeFromStatesResp struct {
+}
+
+func (m *UnsubscribeFromStatesResp) Reset()      { *m = UnsubscribeFromStatesResp{} }
+func (*UnsubscribeFromStatesResp) ProtoMessage() {}
+func (*UnsubscribeFromStatesResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{8}
+}
+func (m *UnsubscribeFromStatesResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UnsubscribeFromStatesResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UnsubscribeFromStatesResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UnsubscribeFromStatesResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsubscribeFromStatesResp.Merge(m, src)
+}
+func (m *UnsubscribeFromStatesResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *UnsubscribeFromStatesResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsubscribeFromStatesResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsubscribeFromStatesResp proto.InternalMessageInfo
+
+type SubscribeToTxsReq struct {
+	StateURI string `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+}
+
+func (m *SubscribeToTxsReq) Reset()      { *m = SubscribeToTxsReq{} }
+func (*SubscribeToTxsReq) ProtoMessage() {}
+func (*SubscribeToTxsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{9}
+}
+func (m *SubscribeToTxsReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SubscribeToTxsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SubscribeToTxsReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SubscribeToTxsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeToTxsReq.Merge(m, src)
+}
+func (m *SubscribeToTxsReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *SubscribeToTxsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeToTxsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeToTxsReq proto.InternalMessageInfo
+
+func (m *SubscribeToTxsReq) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+type TxPacket struct {
+	StateURI   string            `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+	Id         []byte            `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Parents    [][]byte          `protobuf:"bytes,3,rep,name=parents,proto3" json:"parents,omitempty"`
+	Children   [][]byte          `protobuf:"bytes,4,rep,name=children,proto3" json:"children,omitempty"`
+	From       []byte            `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	Sig        []byte            `protobuf:"bytes,6,opt,name=sig,proto3" json:"sig,omitempty"`
+	Patches    []string          `protobuf:"bytes,7,rep,name=patches,proto3" json:"patches,omitempty"`
+	Recipients [][]byte          `protobuf:"bytes,8,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	Attachment []byte            `protobuf:"bytes,9,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	Status     TxPacket_TxStatus `protobuf:"varint,10,opt,name=status,proto3,enum=trustedrpc.TxPacket_TxStatus" json:"status,omitempty"`
+	Hash       []byte            `protobuf:"bytes,11,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *TxPacket) Reset()      { *m = TxPacket{} }
+func (*TxPacket) ProtoMessage() {}
+func (*TxPacket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{10}
+}
+func (m *TxPacket) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TxPacket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TxPacket.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TxPacket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxPacket.Merge(m, src)
+}
+func (m *TxPacket) XXX_Size() int {
+	return m.Size()
+}
+func (m *TxPacket) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxPacket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxPacket proto.InternalMessageInfo
+
+func (m *TxPacket) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+func (m *TxPacket) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *TxPacket) GetParents() [][]byte {
+	if m != nil {
+		return m.Parents
+	}
+	return nil
+}
+
+func (m *TxPacket) GetChildren() [][]byte {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+func (m *TxPacket) GetFrom() []byte {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *TxPacket) GetSig() []byte {
+	if m != nil {
+		return m.Sig
+	}
+	return nil
+}
+
+func (m *TxPacket) GetPatches() []string {
+	if m != nil {
+		return m.Patches
+	}
+	return nil
+}
+
+func (m *TxPacket) GetRecipients() [][]byte {
+	if m != nil {
+		return m.Recipients
+	}
+	return nil
+}
+
+func (m *TxPacket) GetAttachment() []byte {
+	if m != nil {
+		return m.Attachment
+	}
+	return nil
+}
+
+func (m *TxPacket) GetStatus() TxPacket_TxStatus {
+	if m != nil {
+		return m.Status
+	}
+	return UNKNOWN
+}
+
+func (m *TxPacket) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+// See UnsubscribeFromStatesReq.
+type UnsubscribeFromTxsReq struct {
+	StateURI       string `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+	Keypath        string `protobuf:"bytes,2,opt,name=keypath,proto3" json:"keypath,omitempty"`
+	SubscriptionID string `protobuf:"bytes,3,opt,name=subscriptionID,proto3" json:"subscriptionID,omitempty"`
+}
+
+func (m *UnsubscribeFromTxsReq) Reset()      { *m = UnsubscribeFromTxsReq{} }
+func (*UnsubscribeFromTxsReq) ProtoMessage() {}
+func (*UnsubscribeFromTxsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{11}
+}
+func (m *UnsubscribeFromTxsReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UnsubscribeFromTxsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UnsubscribeFromTxsReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UnsubscribeFromTxsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsubscribeFromTxsReq.Merge(m, src)
+}
+func (m *UnsubscribeFromTxsReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *UnsubscribeFromTxsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsubscribeFromTxsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsubscribeFromTxsReq proto.InternalMessageInfo
+
+func (m *UnsubscribeFromTxsReq) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+func (m *UnsubscribeFromTxsReq) GetKeypath() string {
+	if m != nil {
+		return m.Keypath
+	}
+	return ""
+}
+
+func (m *UnsubscribeFromTxsReq) GetSubscriptionID() string {
+	if m != nil {
+		return m.SubscriptionID
+	}
+	return ""
+}
+
+type UnsubscribeFromTxsResp struct {
+}
+
+func (m *UnsubscribeFromTxsResp) Reset()      { *m = UnsubscribeFromTxsResp{} }
+func (*UnsubscribeFromTxsResp) ProtoMessage() {}
+func (*UnsubscribeFromTxsResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{12}
+}
+func (m *UnsubscribeFromTxsResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UnsubscribeFromTxsResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UnsubscribeFromTxsResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UnsubscribeFromTxsResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsubscribeFromTxsResp.Merge(m, src)
+}
+func (m *UnsubscribeFromTxsResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *UnsubscribeFromTxsResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsubscribeFromTxsResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsubscribeFromTxsResp proto.InternalMessageInfo
+
+type GetStateReq struct {
+	StateURI string `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+	Keypath  string `protobuf:"bytes,2,opt,name=keypath,proto3" json:"keypath,omitempty"`
+	Version  []byte `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *GetStateReq) Reset()      { *m = GetStateReq{} }
+func (*GetStateReq) ProtoMessage() {}
+func (*GetStateReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{13}
+}
+func (m *GetStateReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetStateReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetStateReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetStateReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateReq.Merge(m, src)
+}
+func (m *GetStateReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetStateReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateReq proto.InternalMessageInfo
+
+func (m *GetStateReq) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+func (m *GetStateReq) GetKeypath() string {
+	if m != nil {
+		return m.Keypath
+	}
+	return ""
+}
+
+func (m *GetStateReq) GetVersion() []byte {
+	if m != nil {
+		return m.Version
+	}
+	return nil
+}
+
+type SendTxReq struct {
+	StateURI   string   `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+	Id         []byte   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Parents    [][]byte `protobuf:"bytes,3,rep,name=parents,proto3" json:"parents,omitempty"`
+	Patches    []string `protobuf:"bytes,4,rep,name=patches,proto3" json:"patches,omitempty"`
+	Attachment []byte   `protobuf:"bytes,5,opt,name=attachment,proto3" json:"attachment,omitempty"`
+}
+
+func (m *SendTxReq) Reset()      { *m = SendTxReq{} }
+func (*SendTxReq) ProtoMessage() {}
+func (*SendTxReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{14}
+}
+func (m *SendTxReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SendTxReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SendTxReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SendTxReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendTxReq.Merge(m, src)
+}
+func (m *SendTxReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *SendTxReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendTxReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendTxReq proto.InternalMessageInfo
+
+func (m *SendTxReq) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+func (m *SendTxReq) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *SendTxReq) GetParents() [][]byte {
+	if m != nil {
+		return m.Parents
+	}
+	return nil
+}
+
+func (m *SendTxReq) GetPatches() []string {
+	if m != nil {
+		return m.Patches
+	}
+	return nil
+}
+
+func (m *SendTxReq) GetAttachment() []byte {
+	if m != nil {
+		return m.Attachment
+	}
+	return nil
+}
+
+type SendTxResp struct {
+	StateURI string `protobuf:"bytes,1,opt,name=stateURI,proto3" json:"stateURI,omitempty"`
+	Id       []byte `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *SendTxResp) Reset()      { *m = SendTxResp{} }
+func (*SendTxResp) ProtoMessage() {}
+func (*SendTxResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{15}
+}
+func (m *SendTxResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SendTxResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SendTxResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SendTxResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendTxResp.Merge(m, src)
+}
+func (m *SendTxResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *SendTxResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendTxResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendTxResp proto.InternalMessageInfo
+
+func (m *SendTxResp) GetStateURI() string {
+	if m != nil {
+		return m.StateURI
+	}
+	return ""
+}
+
+func (m *SendTxResp) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+type RefPacket struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	End  bool   `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (m *RefPacket) Reset()      { *m = RefPacket{} }
+func (*RefPacket) ProtoMessage() {}
+func (*RefPacket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{16}
+}
+func (m *RefPacket) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RefPacket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RefPacket.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RefPacket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefPacket.Merge(m, src)
+}
+func (m *RefPacket) XXX_Size() int {
+	return m.Size()
+}
+func (m *RefPacket) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefPacket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefPacket proto.InternalMessageInfo
+
+func (m *RefPacket) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *RefPacket) GetEnd() bool {
+	if m != nil {
+		return m.End
+	}
+	return false
+}
+
+type AddRefResp struct {
+	Sha1Hash []byte `protobuf:"bytes,1,opt,name=sha1Hash,proto3" json:"sha1Hash,omitempty"`
+	Sha3Hash []byte `protobuf:"bytes,2,opt,name=sha3Hash,proto3" json:"sha3Hash,omitempty"`
+}
+
+func (m *AddRefResp) Reset()      { *m = AddRefResp{} }
+func (*AddRefResp) ProtoMessage() {}
+func (*AddRefResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{17}
+}
+func (m *AddRefResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AddRefResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AddRefResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AddRefResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddRefResp.Merge(m, src)
+}
+func (m *AddRefResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *AddRefResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddRefResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddRefResp proto.InternalMessageInfo
+
+func (m *AddRefResp) GetSha1Hash() []byte {
+	if m != nil {
+		return m.Sha1Hash
+	}
+	return nil
+}
+
+func (m *AddRefResp) GetSha3Hash() []byte {
+	if m != nil {
+		return m.Sha3Hash
+	}
+	return nil
+}
+
+type FetchRefReq struct {
+	// Types that are valid to be assigned to Hash:
+	//	*FetchRefReq_Sha1
+	//	*FetchRefReq_Sha3
+	Hash isFetchRefReq_Hash `protobuf_oneof:"hash"`
+}
+
+func (m *FetchRefReq) Reset()      { *m = FetchRefReq{} }
+func (*FetchRefReq) ProtoMessage() {}
+func (*FetchRefReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{18}
+}
+func (m *FetchRefReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FetchRefReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FetchRefReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FetchRefReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchRefReq.Merge(m, src)
+}
+func (m *FetchRefReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *FetchRefReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchRefReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchRefReq proto.InternalMessageInfo
+
+type isFetchRefReq_Hash interface {
+	isFetchRefReq_Hash()
+	Equal(interface{}) bool
+	VerboseEqual(interface{}) error
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type FetchRefReq_Sha1 struct {
+	Sha1 []byte `protobuf:"bytes,1,opt,name=sha1,proto3,oneof" json:"sha1,omitempty"`
+}
+type FetchRefReq_Sha3 struct {
+	Sha3 []byte `protobuf:"bytes,2,opt,name=sha3,proto3,oneof" json:"sha3,omitempty"`
+}
+
+func (*FetchRefReq_Sha1) isFetchRefReq_Hash() {}
+func (*FetchRefReq_Sha3) isFetchRefReq_Hash() {}
+
+func (m *FetchRefReq) GetHash() isFetchRefReq_Hash {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *FetchRefReq) GetSha1() []byte {
+	if x, ok := m.GetHash().(*FetchRefReq_Sha1); ok {
+		return x.Sha1
+	}
+	return nil
+}
+
+func (m *FetchRefReq) GetSha3() []byte {
+	if x, ok := m.GetHash().(*FetchRefReq_Sha3); ok {
+		return x.Sha3
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*FetchRefReq) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*FetchRefReq_Sha1)(nil),
+		(*FetchRefReq_Sha3)(nil),
+	}
+}
+
+type AddPeerReq struct {
+	Transport Transport `protobuf:"varint,1,opt,name=transport,proto3,enum=trustedrpc.Transport" json:"transport,omitempty"`
+	DialAddrs []string  `protobuf:"bytes,2,rep,name=dialAddrs,proto3" json:"dialAddrs,omitempty"`
+}
+
+func (m *AddPeerReq) Reset()      { *m = AddPeerReq{} }
+func (*AddPeerReq) ProtoMessage() {}
+func (*AddPeerReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{19}
+}
+func (m *AddPeerReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AddPeerReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AddPeerReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AddPeerReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddPeerReq.Merge(m, src)
+}
+func (m *AddPeerReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *AddPeerReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddPeerReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddPeerReq proto.InternalMessageInfo
+
+func (m *AddPeerReq) GetTransport() Transport {
+	if m != nil {
+		return m.Transport
+	}
+	return LIBP2P
+}
+
+func (m *AddPeerReq) GetDialAddrs() []string {
+	if m != nil {
+		return m.DialAddrs
+	}
+	return nil
+}
+
+type AddPeerResp struct {
+}
+
+func (m *AddPeerResp) Reset()      { *m = AddPeerResp{} }
+func (*AddPeerResp) ProtoMessage() {}
+func (*AddPeerResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{20}
+}
+func (m *AddPeerResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AddPeerResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AddPeerResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AddPeerResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddPeerResp.Merge(m, src)
+}
+func (m *AddPeerResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *AddPeerResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddPeerResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddPeerResp proto.InternalMessageInfo
+
+type DropPeerReq struct {
+	// Types that are valid to be assigned to Identifier:
+	//	*DropPeerReq_PeerDialInfo_
+	//	*DropPeerReq_Address
+	Identifier isDropPeerReq_Identifier `protobuf_oneof:"identifier"`
+}
+
+func (m *DropPeerReq) Reset()      { *m = DropPeerReq{} }
+func (*DropPeerReq) ProtoMessage() {}
+func (*DropPeerReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{21}
+}
+func (m *DropPeerReq) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DropPeerReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DropPeerReq.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DropPeerReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropPeerReq.Merge(m, src)
+}
+func (m *DropPeerReq) XXX_Size() int {
+	return m.Size()
+}
+func (m *DropPeerReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropPeerReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropPeerReq proto.InternalMessageInfo
+
+type isDropPeerReq_Identifier interface {
+	isDropPeerReq_Identifier()
+	Equal(interface{}) bool
+	VerboseEqual(interface{}) error
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type DropPeerReq_PeerDialInfo_ struct {
+	PeerDialInfo *DropPeerReq_PeerDialInfo `protobuf:"bytes,1,opt,name=peerDialInfo,proto3,oneof" json:"peerDialInfo,omitempty"`
+}
+type DropPeerReq_Address struct {
+	Address []byte `protobuf:"bytes,2,opt,name=address,proto3,oneof" json:"address,omitempty"`
+}
+
+func (*DropPeerReq_PeerDialInfo_) isDropPeerReq_Identifier() {}
+func (*DropPeerReq_Address) isDropPeerReq_Identifier()       {}
+
+func (m *DropPeerReq) GetIdentifier() isDropPeerReq_Identifier {
+	if m != nil {
+		return m.Identifier
+	}
+	return nil
+}
+
+func (m *DropPeerReq) GetPeerDialInfo() *DropPeerReq_PeerDialInfo {
+	if x, ok := m.GetIdentifier().(*DropPeerReq_PeerDialInfo_); ok {
+		return x.PeerDialInfo
+	}
+	return nil
+}
+
+func (m *DropPeerReq) GetAddress() []byte {
+	if x, ok := m.GetIdentifier().(*DropPeerReq_Address); ok {
+		return x.Address
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*DropPeerReq) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*DropPeerReq_PeerDialInfo_)(nil),
+		(*DropPeerReq_Address)(nil),
+	}
+}
+
+type DropPeerReq_PeerDialInfo struct {
+	Transport Transport `protobuf:"varint,1,opt,name=transport,proto3,enum=trustedrpc.Transport" json:"transport,omitempty"`
+	DialAddrs []string  `protobuf:"bytes,2,rep,name=dialAddrs,proto3" json:"dialAddrs,omitempty"`
+}
+
+func (m *DropPeerReq_PeerDialInfo) Reset()      { *m = DropPeerReq_PeerDialInfo{} }
+func (*DropPeerReq_PeerDialInfo) ProtoMessage() {}
+func (*DropPeerReq_PeerDialInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{21, 0}
+}
+func (m *DropPeerReq_PeerDialInfo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DropPeerReq_PeerDialInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DropPeerReq_PeerDialInfo.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DropPeerReq_PeerDialInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropPeerReq_PeerDialInfo.Merge(m, src)
+}
+func (m *DropPeerReq_PeerDialInfo) XXX_Size() int {
+	return m.Size()
+}
+func (m *DropPeerReq_PeerDialInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropPeerReq_PeerDialInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropPeerReq_PeerDialInfo proto.InternalMessageInfo
+
+func (m *DropPeerReq_PeerDialInfo) GetTransport() Transport {
+	if m != nil {
+		return m.Transport
+	}
+	return LIBP2P
+}
+
+func (m *DropPeerReq_PeerDialInfo) GetDialAddrs() []string {
+	if m != nil {
+		return m.DialAddrs
+	}
+	return nil
+}
+
+type DropPeerResp struct {
+}
+
+func (m *DropPeerResp) Reset()      { *m = DropPeerResp{} }
+func (*DropPeerResp) ProtoMessage() {}
+func (*DropPeerResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bedfbfc9b54e5600, []int{22}
+}
+func (m *DropPeerResp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DropPeerResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DropPeerResp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DropPeerResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropPeerResp.Merge(m, src)
+}
+func (m *DropPeerResp) XXX_Size() int {
+	return m.Size()
+}
+func (m *DropPeerResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropPeerResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropPeerResp proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterEnum("trustedrpc.Transport", Transport_name, Transport_value)
+	proto.RegisterEnum("trustedrpc.TxPacket_TxStatus", TxPacket_TxStatus_name, TxPacket_TxStatus_value)
+	proto.RegisterType((*AuthorizeReq)(nil), "trustedrpc.AuthorizeReq")
+	proto.RegisterType((*AuthorizeResp)(nil), "trustedrpc.AuthorizeResp")
+	proto.RegisterType((*SetHDMnemonicReq)(nil), "trustedrpc.SetHDMnemonicReq")
+	proto.RegisterType((*SetHDMnemonicResp)(nil), "trustedrpc.SetHDMnemonicResp")
+	proto.RegisterType((*SubscribeToStatesReq)(nil), "trustedrpc.SubscribeToStatesReq")
+	proto.RegisterType((*StatePacket)(nil), "trustedrpc.StatePacket")
+	proto.RegisterType((*KeypathValue)(nil), "trustedrpc.KeypathValue")
+	proto.RegisterType((*UnsubscribeFromStatesReq)(nil), "trustedrpc.UnsubscribeFromStatesReq")
+	proto.RegisterType((*UnsubscribeFromStatesResp)(nil), "trustedrpc.UnsubscribeFromStatesResp")
+	proto.RegisterType((*SubscribeToTxsReq)(nil), "trustedrpc.SubscribeToTxsReq")
+	proto.RegisterType((*TxPacket)(nil), "trustedrpc.TxPacket")
+	proto.RegisterType((*UnsubscribeFromTxsReq)(nil), "trustedrpc.UnsubscribeFromTxsReq")
+	proto.RegisterType((*UnsubscribeFromTxsResp)(nil), "trustedrpc.UnsubscribeFromTxsResp")
+	proto.RegisterType((*GetStateReq)(nil), "trustedrpc.GetStateReq")
+	proto.RegisterType((*SendTxReq)(nil), "trustedrpc.SendTxReq")
+	proto.RegisterType((*SendTxResp)(nil), "trustedrpc.SendTxResp")
+	proto.RegisterType((*RefPacket)(nil), "trustedrpc.RefPacket")
+	proto.RegisterType((*AddRefResp)(nil), "trustedrpc.AddRefResp")
+	proto.RegisterType((*FetchRefReq)(nil), "trustedrpc.FetchRefReq")
+	proto.RegisterType((*AddPeerReq)(nil), "trustedrpc.AddPeerReq")
+	proto.RegisterType((*AddPeerResp)(nil), "trustedrpc.AddPeerResp")
+	proto.RegisterType((*DropPeerReq)(nil), "trustedrpc.DropPeerReq")
+	proto.RegisterType((*DropPeerReq_PeerDialInfo)(nil), "trustedrpc.DropPeerReq.PeerDialInfo")
+	proto.RegisterType((*DropPeerResp)(nil), "trustedrpc.DropPeerResp")
+}
+
+func init() { proto.RegisterFile("grpc.proto", fileDescriptor_bedfbfc9b54e5600) }
+
+var fileDescriptor_bedfbfc9b54e5600 = []byte{
+	// 1164 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x4b, 0x6f, 0xdb, 0x46,
+	0x10, 0x16, 0x25, 0x5b, 0x8f, 0x91, 0x2c, 0x28, 0x5b, 0xdb, 0x61, 0xd8, 0x98, 0x50, 0x17, 0x6d,
+	0x61, 0x14, 0xa8, 0xec, 0xc8, 0x28, 0xfa, 0x40, 0x5f, 0x72, 0xd5, 0x44, 0x6a, 0x64, 0x5b, 0xa0,
+	0xe4, 0x14, 0x68, 0x51, 0x04, 0x14, 0xb9, 0x92, 0x88, 0x48, 0xe4, 0x66, 0x97, 0x72, 0xdd, 0x9e,
+	0x7a, 0xef, 0xa5, 0x3f, 0xa3, 0x3f, 0xa5, 0x47, 0x1f, 0x73, 0x8c, 0x65, 0xb4, 0xe8, 0x31, 0xc7,
+	0x1e, 0x0b, 0x2e, 0x49, 0x89, 0x94, 0x65, 0x3b, 0x7d, 0xe4, 0xb6, 0xf3, 0xfa, 0xe6, 0xdb, 0xe1,
+	0xec, 0x0c, 0x01, 0x06, 0x8c, 0x1a, 0x15, 0xca, 0x1c, 0xd7, 0x41, 0xe0, 0xb2, 0x09, 0x77, 0x89,
+	0xc9, 0xa8, 0xa1, 0xbc, 0x3b, 0xb0, 0xdc, 0xe1, 0xa4, 0x57, 0x31, 0x9c, 0xf1, 0xce, 0xc0, 0x19,
+	0x38, 0x3b, 0xc2, 0xa5, 0x37, 0xe9, 0x0b, 0x49, 0x08, 0xe2, 0xe4, 0x87, 0xe2, 0x06, 0x14, 0x6a,
+	0x13, 0x77, 0xe8, 0x30, 0xeb, 0x47, 0xa2, 0x91, 0xa7, 0xe8, 0x2e, 0xe4, 0x8c, 0xa1, 0x3e, 0x1a,
+	0x11, 0x7b, 0x40, 0x64, 0xa9, 0x2c, 0x6d, 0x17, 0xb4, 0xb9, 0x02, 0x29, 0x90, 0x65, 0x84, 0x53,
+	0xc7, 0xe6, 0x44, 0x4e, 0x0a, 0xe3, 0x4c, 0xc6, 0x35, 0x58, 0x8b, 0x20, 0x71, 0x7a, 0x03, 0x14,
+	0x82, 0x95, 0x89, 0xa1, 0xdb, 0x02, 0x26, 0xa7, 0x89, 0x33, 0xae, 0x40, 0xa9, 0x43, 0xdc, 0x46,
+	0xfd, 0xc0, 0x26, 0x63, 0xc7, 0xb6, 0x0c, 0x8f, 0x90, 0x02, 0xd9, 0x71, 0x20, 0x0a, 0x90, 0x9c,
+	0x36, 0x93, 0xf1, 0x6b, 0x70, 0x6b, 0xc1, 0x9f, 0x53, 0xdc, 0x82, 0xf5, 0xce, 0xa4, 0xc7, 0x0d,
+	0x66, 0xf5, 0x48, 0xd7, 0xe9, 0xb8, 0xba, 0x4b, 0x78, 0x00, 0xc4, 0x3d, 0xe1, 0x58, 0x6b, 0x86,
+	0x40, 0xa1, 0x8c, 0x64, 0xc8, 0x3c, 0x21, 0x3f, 0x50, 0xdd, 0x1d, 0x06, 0x7c, 0x42, 0x11, 0x1f,
+	0x43, 0x5e, 0x40, 0xb4, 0x75, 0xe3, 0x09, 0x71, 0x51, 0x05, 0x56, 0x45, 0x90, 0x2c, 0x95, 0x53,
+	0xdb, 0xf9, 0xaa, 0x5c, 0x99, 0x57, 0xbe, 0xf2, 0xd0, 0x0f, 0x79, 0xa4, 0x8f, 0x26, 0x44, 0xf3,
+	0xdd, 0xd0, 0x26, 0xa4, 0x47, 0x44, 0x3f, 0x21, 0x5c, 0x4e, 0x96, 0x53, 0xdb, 0x05, 0x2d, 0x90,
+	0xf0, 0xa7, 0x50, 0x88, 0xba, 0x47, 0x09, 0x48, 0x31, 0x02, 0x68, 0x1d, 0x56, 0x4f, 0x3c, 0x97,
+	0xa0, 0xde, 0xbe, 0x80, 0x4f, 0x41, 0x3e, 0xb6, 0x79, 0x78, 0xcd, 0xfb, 0xcc, 0x19, 0xff, 0xc7,
+	0x8b, 0xa2, 0xb7, 0xa1, 0x18, 0xe0, 0x51, 0xd7, 0x72, 0xec, 0x66, 0x5d, 0x4e, 0x09, 0x87, 0x05,
+	0x2d, 0x7e, 0x1d, 0xee, 0x5c, 0x91, 0x99, 0x53, 0xbc, 0x03, 0xb7, 0x22, 0xb5, 0xef, 0x9e, 0xde,
+	0xc4, 0x07, 0xff, 0x95, 0x84, 0x6c, 0xf7, 0x34, 0x28, 0xee, 0x75, 0xc4, 0x8b, 0x90, 0xb4, 0xcc,
+	0xa0, 0x06, 0x49, 0xcb, 0xf4, 0x2e, 0x42, 0x75, 0x46, 0x6c, 0x97, 0xcb, 0x29, 0x51, 0xd9, 0x50,
+	0xf4, 0x50, 0x8c, 0xa1, 0x35, 0x32, 0x19, 0xb1, 0xe5, 0x15, 0x61, 0x9a, 0xc9, 0x5e, 0xd3, 0xf5,
+	0x99, 0x33, 0x96, 0x57, 0x05, 0x8e, 0x38, 0xa3, 0x12, 0xa4, 0xb8, 0x35, 0x90, 0xd3, 0x42, 0xe5,
+	0x1d, 0x7d, 0x6c, 0xd7, 0x18, 0x12, 0x2e, 0x67, 0xca, 0x29, 0xaf, 0x48, 0x81, 0x88, 0x54, 0x00,
+	0x46, 0x0c, 0x8b, 0x5a, 0x22, 0x71, 0x56, 0xa0, 0x47, 0x34, 0x9e, 0x5d, 0x77, 0x5d, 0xdd, 0x18,
+	0x8e, 0x89, 0xed, 0xca, 0x39, 0x01, 0x19, 0xd1, 0xa0, 0xf7, 0x20, 0xed, 0xdd, 0x68, 0xc2, 0x65,
+	0x28, 0x4b, 0xdb, 0xc5, 0xea, 0x56, 0xb4, 0x7f, 0xc2, 0x3a, 0x54, 0xba, 0xa7, 0x1d, 0xe1, 0xa4,
+	0x05, 0xce, 0x1e, 0xed, 0xa1, 0xce, 0x87, 0x72, 0xde, 0xa7, 0xed, 0x9d, 0xf1, 0x67, 0x90, 0x0d,
+	0xfd, 0x50, 0x1e, 0x32, 0xc7, 0x87, 0x0f, 0x0f, 0x8f, 0xbe, 0x3e, 0x2c, 0x25, 0x50, 0x11, 0xa0,
+	0x79, 0xf8, 0xf8, 0xe0, 0xcb, 0x83, 0xf6, 0xd1, 0x51, 0xab, 0x24, 0x79, 0xc6, 0xe6, 0xe1, 0xa3,
+	0x5a, 0xab, 0x59, 0x2f, 0x25, 0x51, 0x0e, 0x56, 0xfd, 0x63, 0x0a, 0x4f, 0x60, 0x63, 0xe1, 0x43,
+	0xde, 0xfc, 0xbd, 0xfe, 0x87, 0xfe, 0x91, 0x61, 0x73, 0x59, 0x5a, 0x4e, 0xf1, 0x77, 0x90, 0x7f,
+	0x40, 0x5c, 0xd1, 0x4d, 0xff, 0x9e, 0x86, 0x0c, 0x99, 0x13, 0xc2, 0xb8, 0xe5, 0xd8, 0x22, 0x7f,
+	0x41, 0x0b, 0x45, 0xfc, 0xb3, 0x04, 0xb9, 0x0e, 0xb1, 0xcd, 0xee, 0xe9, 0x4d, 0xe8, 0x2f, 0xdf,
+	0x6b, 0x91, 0x4e, 0x59, 0xb9, 0xd4, 0x29, 0x91, 0x4e, 0x58, 0x5d, 0xec, 0x04, 0xfc, 0x01, 0x40,
+	0x48, 0x86, 0xd3, 0x7f, 0xc2, 0x06, 0xdf, 0x83, 0x9c, 0x46, 0xfa, 0xc1, 0x93, 0x41, 0xb0, 0x62,
+	0xea, 0xae, 0x1e, 0x8c, 0x57, 0x71, 0xf6, 0x1a, 0x9a, 0xd8, 0x7e, 0x44, 0x56, 0xf3, 0x8e, 0xb8,
+	0x0e, 0x50, 0x33, 0x4d, 0x8d, 0xf4, 0x67, 0xc9, 0x86, 0xfa, 0xbd, 0x86, 0xd7, 0x51, 0x7e, 0xdc,
+	0x4c, 0x0e, 0x6c, 0x7b, 0xc2, 0x96, 0x9c, 0xd9, 0x84, 0x8c, 0x6b, 0x90, 0xbf, 0x4f, 0x5c, 0x63,
+	0x28, 0x70, 0x9e, 0xa2, 0x75, 0x58, 0xf1, 0xc2, 0x7c, 0x88, 0x46, 0x42, 0x13, 0x52, 0xa0, 0xdd,
+	0x93, 0x93, 0x11, 0xed, 0xde, 0x7e, 0xda, 0x6f, 0x60, 0xfc, 0x58, 0x10, 0x69, 0x13, 0xc2, 0x3c,
+	0x84, 0x3d, 0xc8, 0xb9, 0x4c, 0xb7, 0x39, 0x75, 0x98, 0x2b, 0x60, 0x8a, 0xd5, 0x8d, 0xd8, 0x83,
+	0x08, 0x8d, 0xda, 0xdc, 0xcf, 0xdb, 0x2a, 0xa6, 0xa5, 0x8f, 0x6a, 0xa6, 0xc9, 0xfc, 0xa1, 0x9a,
+	0xd3, 0xe6, 0x0a, 0xbc, 0x06, 0xf9, 0x59, 0x02, 0x4e, 0xf1, 0x1f, 0x12, 0xe4, 0xeb, 0xcc, 0xa1,
+	0x61, 0xc6, 0xaf, 0xa0, 0x40, 0x09, 0x61, 0x75, 0x4b, 0x1f, 0x35, 0xed, 0xbe, 0x23, 0x92, 0xe6,
+	0xab, 0x6f, 0x46, 0x93, 0x46, 0xdc, 0x2b, 0xed, 0x88, 0x6f, 0x23, 0xa1, 0xc5, 0x62, 0x91, 0x02,
+	0x19, 0xdd, 0x34, 0x19, 0xe1, 0x7c, 0x76, 0xd9, 0x50, 0xa1, 0xe8, 0x50, 0x88, 0xc6, 0xbe, 0x82,
+	0x9b, 0xee, 0x17, 0x00, 0x2c, 0x93, 0xd8, 0xae, 0xd5, 0xb7, 0x08, 0xc3, 0x45, 0x28, 0xcc, 0x89,
+	0x73, 0xfa, 0x0e, 0x86, 0xdc, 0x0c, 0x13, 0x01, 0xa4, 0x5b, 0xcd, 0xfd, 0x76, 0xb5, 0x5d, 0x4a,
+	0x78, 0x03, 0xa0, 0xd1, 0xed, 0xb6, 0x3b, 0x25, 0xa9, 0xfa, 0x7b, 0x1a, 0xa0, 0xeb, 0x73, 0xd0,
+	0xda, 0x5f, 0xa0, 0xcf, 0x21, 0x37, 0xdb, 0xdf, 0x28, 0xb6, 0xd8, 0xa2, 0x3f, 0x08, 0xca, 0x9d,
+	0x2b, 0x2c, 0x9c, 0xa2, 0x16, 0xac, 0xc5, 0xd6, 0x31, 0xba, 0x1b, 0xf5, 0x5d, 0xdc, 0xec, 0xca,
+	0xd6, 0x35, 0x56, 0x4e, 0x51, 0x3b, 0xb6, 0x4b, 0xfc, 0x25, 0x83, 0xca, 0xb1, 0x98, 0x25, 0x6b,
+	0x5e, 0xb9, 0x1d, 0xf3, 0x98, 0xaf, 0xee, 0x5d, 0x09, 0x99, 0x97, 0x26, 0x5e, 0x80, 0x1a, 0x6b,
+	0x80, 0xab, 0xf6, 0xaa, 0xf2, 0xd6, 0x4b, 0x78, 0x71, 0x8a, 0x1e, 0x40, 0x31, 0xbe, 0x03, 0xd1,
+	0xd6, 0x15, 0xa4, 0xfd, 0x79, 0xab, 0xac, 0x2f, 0x5b, 0x02, 0xbb, 0x12, 0xfa, 0x16, 0xd0, 0xe5,
+	0x49, 0x89, 0xde, 0xb8, 0x86, 0x45, 0x00, 0x88, 0x6f, 0x72, 0xe1, 0x14, 0x7d, 0x0c, 0xd9, 0x70,
+	0xd8, 0xa2, 0x58, 0xc9, 0x22, 0x23, 0xf8, 0xca, 0x5a, 0xa2, 0xf7, 0x21, 0xed, 0x4f, 0x2f, 0xb4,
+	0x11, 0xff, 0x88, 0xc1, 0x78, 0x55, 0x36, 0x97, 0xa9, 0x39, 0x45, 0x1f, 0x42, 0xda, 0x9f, 0x44,
+	0xf1, 0xc0, 0xd9, 0x40, 0x8b, 0x07, 0xce, 0x87, 0xd6, 0xb6, 0xe4, 0x31, 0x0e, 0xc7, 0x4f, 0x9c,
+	0x71, 0x64, 0x28, 0x29, 0xcb, 0x51, 0x77, 0x25, 0xf4, 0x11, 0x64, 0x82, 0xc1, 0x80, 0x16, 0x53,
+	0x04, 0xaf, 0x5d, 0xb9, 0xbd, 0x54, 0xcf, 0x29, 0xfa, 0x04, 0xb2, 0xe1, 0xe3, 0x8a, 0x67, 0x8e,
+	0xcc, 0x0a, 0x45, 0x5e, 0x6e, 0xe0, 0x74, 0xbf, 0x75, 0x76, 0xae, 0x26, 0x9e, 0x9d, 0xab, 0x89,
+	0xe7, 0xe7, 0xaa, 0xf4, 0xe2, 0x5c, 0x95, 0x7e, 0x9a, 0xaa, 0xd2, 0xaf, 0x53, 0x55, 0xfa, 0x6d,
+	0xaa, 0x4a, 0x67, 0x53, 0x55, 0x7a, 0x3e, 0x55, 0xa5, 0x3f, 0xa7, 0x6a, 0xe2, 0xc5, 0x54, 0x95,
+	0x7e, 0xb9, 0x50, 0x13, 0x67, 0x17, 0x6a, 0xe2, 0xd9, 0x85, 0x9a, 0xf8, 0x06, 0x31, 0x62, 0x7e,
+	0xef, 0x38, 0x66, 0xc5, 0x24, 0x27, 0x3b, 0x8c, 0x1a, 0x3b, 0xb4, 0xd7, 0x4b, 0x8b, 0xff, 0xf6,
+	0xbd, 0xbf, 0x07, 0x00, 0x4a, 0x11, 0x71, 0xe4, 0x00, 0x0c, 0x00, 0x00,
+}
+
+func (x Transport) String() string {
+	s, ok := Transport_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (x TxPacket_TxStatus) String() string {
+	s, ok := TxPacket_TxStatus_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (this *AuthorizeReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*AuthorizeReq)
+	if !ok {
+		that2, ok := that.(AuthorizeReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *AuthorizeReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *AuthorizeReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *AuthorizeReq but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return fmt.Errorf("Challenge this(%v) Not Equal that(%v)", this.Challenge, that1.Challenge)
+	}
+	if !bytes.Equal(this.Response, that1.Response) {
+		return fmt.Errorf("Response this(%v) Not Equal that(%v)", this.Response, that1.Response)
+	}
+	return nil
+}
+func (this *AuthorizeReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AuthorizeReq)
+	if !ok {
+		that2, ok := that.(AuthorizeReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return false
+	}
+	if !bytes.Equal(this.Response, that1.Response) {
+		return false
+	}
+	return true
+}
+func (this *AuthorizeResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*AuthorizeResp)
+	if !ok {
+		that2, ok := that.(AuthorizeResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *AuthorizeResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *AuthorizeResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *AuthorizeResp but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return fmt.Errorf("Challenge this(%v) Not Equal that(%v)", this.Challenge, that1.Challenge)
+	}
+	if this.Ucan != that1.Ucan {
+		return fmt.Errorf("Ucan this(%v) Not Equal that(%v)", this.Ucan, that1.Ucan)
+	}
+	return nil
+}
+func (this *AuthorizeResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AuthorizeResp)
+	if !ok {
+		that2, ok := that.(AuthorizeResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return false
+	}
+	if this.Ucan != that1.Ucan {
+		return false
+	}
+	return true
+}
+func (this *SetHDMnemonicReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*SetHDMnemonicReq)
+	if !ok {
+		that2, ok := that.(SetHDMnemonicReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SetHDMnemonicReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SetHDMnemonicReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SetHDMnemonicReq but is not nil && this == nil")
+	}
+	if this.Mnemonic != that1.Mnemonic {
+		return fmt.Errorf("Mnemonic this(%v) Not Equal that(%v)", this.Mnemonic, that1.Mnemonic)
+	}
+	return nil
+}
+func (this *SetHDMnemonicReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SetHDMnemonicReq)
+	if !ok {
+		that2, ok := that.(SetHDMnemonicReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Mnemonic != that1.Mnemonic {
+		return false
+	}
+	return true
+}
+func (this *SetHDMnemonicResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*SetHDMnemonicResp)
+	if !ok {
+		that2, ok := that.(SetHDMnemonicResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SetHDMnemonicResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SetHDMnemonicResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SetHDMnemonicResp but is not nil && this == nil")
+	}
+	return nil
+}
+func (this *SetHDMnemonicResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SetHDMnemonicResp)
+	if !ok {
+		that2, ok := that.(SetHDMnemonicResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *SubscribeToStatesReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*SubscribeToStatesReq)
+	if !ok {
+		that2, ok := that.(SubscribeToStatesReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SubscribeToStatesReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SubscribeToStatesReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SubscribeToStatesReq but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	if this.Keypath != that1.Keypath {
+		return fmt.Errorf("Keypath this(%v) Not Equal that(%v)", this.Keypath, that1.Keypath)
+	}
+	return nil
+}
+func (this *SubscribeToStatesReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SubscribeToStatesReq)
+	if !ok {
+		that2, ok := that.(SubscribeToStatesReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	if this.Keypath != that1.Keypath {
+		return false
+	}
+	return true
+}
+func (this *StatePacket) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*StatePacket)
+	if !ok {
+		that2, ok := that.(StatePacket)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StatePacket")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StatePacket but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StatePacket but is not nil && this == nil")
+	}
+	if len(this.State) != len(that1.State) {
+		return fmt.Errorf("State this(%v) Not Equal that(%v)", len(this.State), len(that1.State))
+	}
+	for i := range this.State {
+		if !this.State[i].Equal(that1.State[i]) {
+			return fmt.Errorf("State this[%v](%v) Not Equal that[%v](%v)", i, this.State[i], i, that1.State[i])
+		}
+	}
+	if len(this.Leaves) != len(that1.Leaves) {
+		return fmt.Errorf("Leaves this(%v) Not Equal that(%v)", len(this.Leaves), len(that1.Leaves))
+	}
+	for i := range this.Leaves {
+		if !bytes.Equal(this.Leaves[i], that1.Leaves[i]) {
+			return fmt.Errorf("Leaves this[%v](%v) Not Equal that[%v](%v)", i, this.Leaves[i], i, that1.Leaves[i])
+		}
+	}
+	return nil
+}
+func (this *StatePacket) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*StatePacket)
+	if !ok {
+		that2, ok := that.(StatePacket)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.State) != len(that1.State) {
+		return false
+	}
+	for i := range this.State {
+		if !this.State[i].Equal(that1.State[i]) {
+			return false
+		}
+	}
+	if len(this.Leaves) != len(that1.Leaves) {
+		return false
+	}
+	for i := range this.Leaves {
+		if !bytes.Equal(this.Leaves[i], that1.Leaves[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *KeypathValue) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*KeypathValue)
+	if !ok {
+		that2, ok := that.(KeypathValue)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *KeypathValue")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *KeypathValue but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *KeypathValue but is not nil && this == nil")
+	}
+	if this.Keypath != that1.Keypath {
+		return fmt.Errorf("Keypath this(%v) Not Equal that(%v)", this.Keypath, that1.Keypath)
+	}
+	if !bytes.Equal(this.Value, that1.Value) {
+		return fmt.Errorf("Value this(%v) Not Equal that(%v)", this.Value, that1.Value)
+	}
+	return nil
+}
+func (this *KeypathValue) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*KeypathValue)
+	if !ok {
+		that2, ok := that.(KeypathValue)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Keypath != that1.Keypath {
+		return false
+	}
+	if !bytes.Equal(this.Value, that1.Value) {
+		return false
+	}
+	return true
+}
+func (this *UnsubscribeFromStatesReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*UnsubscribeFromStatesReq)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromStatesReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *UnsubscribeFromStatesReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *UnsubscribeFromStatesReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *UnsubscribeFromStatesReq but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	if this.Keypath != that1.Keypath {
+		return fmt.Errorf("Keypath this(%v) Not Equal that(%v)", this.Keypath, that1.Keypath)
+	}
+	if this.SubscriptionID != that1.SubscriptionID {
+		return fmt.Errorf("SubscriptionID this(%v) Not Equal that(%v)", this.SubscriptionID, that1.SubscriptionID)
+	}
+	return nil
+}
+func (this *UnsubscribeFromStatesReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*UnsubscribeFromStatesReq)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromStatesReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	if this.Keypath != that1.Keypath {
+		return false
+	}
+	if this.SubscriptionID != that1.SubscriptionID {
+		return false
+	}
+	return true
+}
+func (this *UnsubscribeFromStatesResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*UnsubscribeFromStatesResp)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromStatesResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *UnsubscribeFromStatesResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *UnsubscribeFromStatesResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *UnsubscribeFromStatesResp but is not nil && this == nil")
+	}
+	return nil
+}
+func (this *UnsubscribeFromStatesResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*UnsubscribeFromStatesResp)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromStatesResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *SubscribeToTxsReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*SubscribeToTxsReq)
+	if !ok {
+		that2, ok := that.(SubscribeToTxsReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SubscribeToTxsReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SubscribeToTxsReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SubscribeToTxsReq but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	return nil
+}
+func (this *SubscribeToTxsReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SubscribeToTxsReq)
+	if !ok {
+		that2, ok := that.(SubscribeToTxsReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	return true
+}
+func (this *TxPacket) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*TxPacket)
+	if !ok {
+		that2, ok := that.(TxPacket)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *TxPacket")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *TxPacket but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *TxPacket but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return fmt.Errorf("Id this(%v) Not Equal that(%v)", this.Id, that1.Id)
+	}
+	if len(this.Parents) != len(that1.Parents) {
+		return fmt.Errorf("Parents this(%v) Not Equal that(%v)", len(this.Parents), len(that1.Parents))
+	}
+	for i := range this.Parents {
+		if !bytes.Equal(this.Parents[i], that1.Parents[i]) {
+			return fmt.Errorf("Parents this[%v](%v) Not Equal that[%v](%v)", i, this.Parents[i], i, that1.Parents[i])
+		}
+	}
+	if len(this.Children) != len(that1.Children) {
+		return fmt.Errorf("Children this(%v) Not Equal that(%v)", len(this.Children), len(that1.Children))
+	}
+	for i := range this.Children {
+		if !bytes.Equal(this.Children[i], that1.Children[i]) {
+			return fmt.Errorf("Children this[%v](%v) Not Equal that[%v](%v)", i, this.Children[i], i, that1.Children[i])
+		}
+	}
+	if !bytes.Equal(this.From, that1.From) {
+		return fmt.Errorf("From this(%v) Not Equal that(%v)", this.From, that1.From)
+	}
+	if !bytes.Equal(this.Sig, that1.Sig) {
+		return fmt.Errorf("Sig this(%v) Not Equal that(%v)", this.Sig, that1.Sig)
+	}
+	if len(this.Patches) != len(that1.Patches) {
+		return fmt.Errorf("Patches this(%v) Not Equal that(%v)", len(this.Patches), len(that1.Patches))
+	}
+	for i := range this.Patches {
+		if this.Patches[i] != that1.Patches[i] {
+			return fmt.Errorf("Patches this[%v](%v) Not Equal that[%v](%v)", i, this.Patches[i], i, that1.Patches[i])
+		}
+	}
+	if len(this.Recipients) != len(that1.Recipients) {
+		return fmt.Errorf("Recipients this(%v) Not Equal that(%v)", len(this.Recipients), len(that1.Recipients))
+	}
+	for i := range this.Recipients {
+		if !bytes.Equal(this.Recipients[i], that1.Recipients[i]) {
+			return fmt.Errorf("Recipients this[%v](%v) Not Equal that[%v](%v)", i, this.Recipients[i], i, that1.Recipients[i])
+		}
+	}
+	if !bytes.Equal(this.Attachment, that1.Attachment) {
+		return fmt.Errorf("Attachment this(%v) Not Equal that(%v)", this.Attachment, that1.Attachment)
+	}
+	if this.Status != that1.Status {
+		return fmt.Errorf("Status this(%v) Not Equal that(%v)", this.Status, that1.Status)
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return fmt.Errorf("Hash this(%v) Not Equal that(%v)", this.Hash, that1.Hash)
+	}
+	return nil
+}
+func (this *TxPacket) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TxPacket)
+	if !ok {
+		that2, ok := that.(TxPacket)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
+	}
+	if len(this.Parents) != len(that1.Parents) {
+		return false
+	}
+	for i := range this.Parents {
+		if !bytes.Equal(this.Parents[i], that1.Parents[i]) {
+			return false
+		}
+	}
+	if len(this.Children) != len(that1.Children) {
+		return false
+	}
+	for i := range this.Children {
+		if !bytes.Equal(this.Children[i], that1.Children[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.From, that1.From) {
+		return false
+	}
+	if !bytes.Equal(this.Sig, that1.Sig) {
+		return false
+	}
+	if len(this.Patches) != len(that1.Patches) {
+		return false
+	}
+	for i := range this.Patches {
+		if this.Patches[i] != that1.Patches[i] {
+			return false
+		}
+	}
+	if len(this.Recipients) != len(that1.Recipients) {
+		return false
+	}
+	for i := range this.Recipients {
+		if !bytes.Equal(this.Recipients[i], that1.Recipients[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.Attachment, that1.Attachment) {
+		return false
+	}
+	if this.Status != that1.Status {
+		return false
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return false
+	}
+	return true
+}
+func (this *UnsubscribeFromTxsReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*UnsubscribeFromTxsReq)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromTxsReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *UnsubscribeFromTxsReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *UnsubscribeFromTxsReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *UnsubscribeFromTxsReq but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	if this.Keypath != that1.Keypath {
+		return fmt.Errorf("Keypath this(%v) Not Equal that(%v)", this.Keypath, that1.Keypath)
+	}
+	if this.SubscriptionID != that1.SubscriptionID {
+		return fmt.Errorf("SubscriptionID this(%v) Not Equal that(%v)", this.SubscriptionID, that1.SubscriptionID)
+	}
+	return nil
+}
+func (this *UnsubscribeFromTxsReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*UnsubscribeFromTxsReq)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromTxsReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	if this.Keypath != that1.Keypath {
+		return false
+	}
+	if this.SubscriptionID != that1.SubscriptionID {
+		return false
+	}
+	return true
+}
+func (this *UnsubscribeFromTxsResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*UnsubscribeFromTxsResp)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromTxsResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *UnsubscribeFromTxsResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *UnsubscribeFromTxsResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *UnsubscribeFromTxsResp but is not nil && this == nil")
+	}
+	return nil
+}
+func (this *UnsubscribeFromTxsResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*UnsubscribeFromTxsResp)
+	if !ok {
+		that2, ok := that.(UnsubscribeFromTxsResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *GetStateReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*GetStateReq)
+	if !ok {
+		that2, ok := that.(GetStateReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *GetStateReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *GetStateReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *GetStateReq but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	if this.Keypath != that1.Keypath {
+		return fmt.Errorf("Keypath this(%v) Not Equal that(%v)", this.Keypath, that1.Keypath)
+	}
+	if !bytes.Equal(this.Version, that1.Version) {
+		return fmt.Errorf("Version this(%v) Not Equal that(%v)", this.Version, that1.Version)
+	}
+	return nil
+}
+func (this *GetStateReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetStateReq)
+	if !ok {
+		that2, ok := that.(GetStateReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	if this.Keypath != that1.Keypath {
+		return false
+	}
+	if !bytes.Equal(this.Version, that1.Version) {
+		return false
+	}
+	return true
+}
+func (this *SendTxReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*SendTxReq)
+	if !ok {
+		that2, ok := that.(SendTxReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SendTxReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SendTxReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SendTxReq but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return fmt.Errorf("Id this(%v) Not Equal that(%v)", this.Id, that1.Id)
+	}
+	if len(this.Parents) != len(that1.Parents) {
+		return fmt.Errorf("Parents this(%v) Not Equal that(%v)", len(this.Parents), len(that1.Parents))
+	}
+	for i := range this.Parents {
+		if !bytes.Equal(this.Parents[i], that1.Parents[i]) {
+			return fmt.Errorf("Parents this[%v](%v) Not Equal that[%v](%v)", i, this.Parents[i], i, that1.Parents[i])
+		}
+	}
+	if len(this.Patches) != len(that1.Patches) {
+		return fmt.Errorf("Patches this(%v) Not Equal that(%v)", len(this.Patches), len(that1.Patches))
+	}
+	for i := range this.Patches {
+		if this.Patches[i] != that1.Patches[i] {
+			return fmt.Errorf("Patches this[%v](%v) Not Equal that[%v](%v)", i, this.Patches[i], i, that1.Patches[i])
+		}
+	}
+	if !bytes.Equal(this.Attachment, that1.Attachment) {
+		return fmt.Errorf("Attachment this(%v) Not Equal that(%v)", this.Attachment, that1.Attachment)
+	}
+	return nil
+}
+func (this *SendTxReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SendTxReq)
+	if !ok {
+		that2, ok := that.(SendTxReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
+	}
+	if len(this.Parents) != len(that1.Parents) {
+		return false
+	}
+	for i := range this.Parents {
+		if !bytes.Equal(this.Parents[i], that1.Parents[i]) {
+			return false
+		}
+	}
+	if len(this.Patches) != len(that1.Patches) {
+		return false
+	}
+	for i := range this.Patches {
+		if this.Patches[i] != that1.Patches[i] {
+			return false
+		}
+	}
+	if !bytes.Equal(this.Attachment, that1.Attachment) {
+		return false
+	}
+	return true
+}
+func (this *SendTxResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*SendTxResp)
+	if !ok {
+		that2, ok := that.(SendTxResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SendTxResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SendTxResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SendTxResp but is not nil && this == nil")
+	}
+	if this.StateURI != that1.StateURI {
+		return fmt.Errorf("StateURI this(%v) Not Equal that(%v)", this.StateURI, that1.StateURI)
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return fmt.Errorf("Id this(%v) Not Equal that(%v)", this.Id, that1.Id)
+	}
+	return nil
+}
+func (this *SendTxResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SendTxResp)
+	if !ok {
+		that2, ok := that.(SendTxResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.StateURI != that1.StateURI {
+		return false
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
+	}
+	return true
+}
+func (this *RefPacket) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*RefPacket)
+	if !ok {
+		that2, ok := that.(RefPacket)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *RefPacket")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *RefPacket but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *RefPacket but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return fmt.Errorf("Data this(%v) Not Equal that(%v)", this.Data, that1.Data)
+	}
+	if this.End != that1.End {
+		return fmt.Errorf("End this(%v) Not Equal that(%v)", this.End, that1.End)
+	}
+	return nil
+}
+func (this *RefPacket) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*RefPacket)
+	if !ok {
+		that2, ok := that.(RefPacket)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.End != that1.End {
+		return false
+	}
+	return true
+}
+func (this *AddRefResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*AddRefResp)
+	if !ok {
+		that2, ok := that.(AddRefResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *AddRefResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *AddRefResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *AddRefResp but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Sha1Hash, that1.Sha1Hash) {
+		return fmt.Errorf("Sha1Hash this(%v) Not Equal that(%v)", this.Sha1Hash, that1.Sha1Hash)
+	}
+	if !bytes.Equal(this.Sha3Hash, that1.Sha3Hash) {
+		return fmt.Errorf("Sha3Hash this(%v) Not Equal that(%v)", this.Sha3Hash, that1.Sha3Hash)
+	}
+	return nil
+}
+func (this *AddRefResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AddRefResp)
+	if !ok {
+		that2, ok := that.(AddRefResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Sha1Hash, that1.Sha1Hash) {
+		return false
+	}
+	if !bytes.Equal(this.Sha3Hash, that1.Sha3Hash) {
+		return false
+	}
+	return true
+}
+func (this *FetchRefReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*FetchRefReq)
+	if !ok {
+		that2, ok := that.(FetchRefReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *FetchRefReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *FetchRefReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *FetchRefReq but is not nil && this == nil")
+	}
+	if that1.Hash == nil {
+		if this.Hash != nil {
+			return fmt.Errorf("this.Hash != nil && that1.Hash == nil")
+		}
+	} else if this.Hash == nil {
+		return fmt.Errorf("this.Hash == nil && that1.Hash != nil")
+	} else if err := this.Hash.VerboseEqual(that1.Hash); err != nil {
+		return err
+	}
+	return nil
+}
+func (this *FetchRefReq_Sha1) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*FetchRefReq_Sha1)
+	if !ok {
+		that2, ok := that.(FetchRefReq_Sha1)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *FetchRefReq_Sha1")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *FetchRefReq_Sha1 but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *FetchRefReq_Sha1 but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Sha1, that1.Sha1) {
+		return fmt.Errorf("Sha1 this(%v) Not Equal that(%v)", this.Sha1, that1.Sha1)
+	}
+	return nil
+}
+func (this *FetchRefReq_Sha3) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*FetchRefReq_Sha3)
+	if !ok {
+		that2, ok := that.(FetchRefReq_Sha3)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *FetchRefReq_Sha3")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *FetchRefReq_Sha3 but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *FetchRefReq_Sha3 but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Sha3, that1.Sha3) {
+		return fmt.Errorf("Sha3 this(%v) Not Equal that(%v)", this.Sha3, that1.Sha3)
+	}
+	return nil
+}
+func (this *FetchRefReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*FetchRefReq)
+	if !ok {
+		that2, ok := that.(FetchRefReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Hash == nil {
+		if this.Hash != nil {
+			return false
+		}
+	} else if this.Hash == nil {
+		return false
+	} else if !this.Hash.Equal(that1.Hash) {
+		return false
+	}
+	return true
+}
+func (this *FetchRefReq_Sha1) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*FetchRefReq_Sha1)
+	if !ok {
+		that2, ok := that.(FetchRefReq_Sha1)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Sha1, that1.Sha1) {
+		return false
+	}
+	return true
+}
+func (this *FetchRefReq_Sha3) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*FetchRefReq_Sha3)
+	if !ok {
+		that2, ok := that.(FetchRefReq_Sha3)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Sha3, that1.Sha3) {
+		return false
+	}
+	return true
+}
+func (this *AddPeerReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*AddPeerReq)
+	if !ok {
+		that2, ok := that.(AddPeerReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *AddPeerReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *AddPeerReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *AddPeerReq but is not nil && this == nil")
+	}
+	if this.Transport != that1.Transport {
+		return fmt.Errorf("Transport this(%v) Not Equal that(%v)", this.Transport, that1.Transport)
+	}
+	if len(this.DialAddrs) != len(that1.DialAddrs) {
+		return fmt.Errorf("DialAddrs this(%v) Not Equal that(%v)", len(this.DialAddrs), len(that1.DialAddrs))
+	}
+	for i := range this.DialAddrs {
+		if this.DialAddrs[i] != that1.DialAddrs[i] {
+			return fmt.Errorf("DialAddrs this[%v](%v) Not Equal that[%v](%v)", i, this.DialAddrs[i], i, that1.DialAddrs[i])
+		}
+	}
+	return nil
+}
+func (this *AddPeerReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AddPeerReq)
+	if !ok {
+		that2, ok := that.(AddPeerReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Transport != that1.Transport {
+		return false
+	}
+	if len(this.DialAddrs) != len(that1.DialAddrs) {
+		return false
+	}
+	for i := range this.DialAddrs {
+		if this.DialAddrs[i] != that1.DialAddrs[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *AddPeerResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*AddPeerResp)
+	if !ok {
+		that2, ok := that.(AddPeerResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *AddPeerResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *AddPeerResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *AddPeerResp but is not nil && this == nil")
+	}
+	return nil
+}
+func (this *AddPeerResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AddPeerResp)
+	if !ok {
+		that2, ok := that.(AddPeerResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *DropPeerReq) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*DropPeerReq)
+	if !ok {
+		that2, ok := that.(DropPeerReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DropPeerReq")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DropPeerReq but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DropPeerReq but is not nil && this == nil")
+	}
+	if that1.Identifier == nil {
+		if this.Identifier != nil {
+			return fmt.Errorf("this.Identifier != nil && that1.Identifier == nil")
+		}
+	} else if this.Identifier == nil {
+		return fmt.Errorf("this.Identifier == nil && that1.Identifier != nil")
+	} else if err := this.Identifier.VerboseEqual(that1.Identifier); err != nil {
+		return err
+	}
+	return nil
+}
+func (this *DropPeerReq_PeerDialInfo_) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*DropPeerReq_PeerDialInfo_)
+	if !ok {
+		that2, ok := that.(DropPeerReq_PeerDialInfo_)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DropPeerReq_PeerDialInfo_")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DropPeerReq_PeerDialInfo_ but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DropPeerReq_PeerDialInfo_ but is not nil && this == nil")
+	}
+	if !this.PeerDialInfo.Equal(that1.PeerDialInfo) {
+		return fmt.Errorf("PeerDialInfo this(%v) Not Equal that(%v)", this.PeerDialInfo, that1.PeerDialInfo)
+	}
+	return nil
+}
+func (this *DropPeerReq_Address) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*DropPeerReq_Address)
+	if !ok {
+		that2, ok := that.(DropPeerReq_Address)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DropPeerReq_Address")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DropPeerReq_Address but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DropPeerReq_Address but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Address, that1.Address) {
+		return fmt.Errorf("Address this(%v) Not Equal that(%v)", this.Address, that1.Address)
+	}
+	return nil
+}
+func (this *DropPeerReq) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DropPeerReq)
+	if !ok {
+		that2, ok := that.(DropPeerReq)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Identifier == nil {
+		if this.Identifier != nil {
+			return false
+		}
+	} else if this.Identifier == nil {
+		return false
+	} else if !this.Identifier.Equal(that1.Identifier) {
+		return false
+	}
+	return true
+}
+func (this *DropPeerReq_PeerDialInfo_) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DropPeerReq_PeerDialInfo_)
+	if !ok {
+		that2, ok := that.(DropPeerReq_PeerDialInfo_)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.PeerDialInfo.Equal(that1.PeerDialInfo) {
+		return false
+	}
+	return true
+}
+func (this *DropPeerReq_Address) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DropPeerReq_Address)
+	if !ok {
+		that2, ok := that.(DropPeerReq_Address)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Address, that1.Address) {
+		return false
+	}
+	return true
+}
+func (this *DropPeerReq_PeerDialInfo) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*DropPeerReq_PeerDialInfo)
+	if !ok {
+		that2, ok := that.(DropPeerReq_PeerDialInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DropPeerReq_PeerDialInfo")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DropPeerReq_PeerDialInfo but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DropPeerReq_PeerDialInfo but is not nil && this == nil")
+	}
+	if this.Transport != that1.Transport {
+		return fmt.Errorf("Transport this(%v) Not Equal that(%v)", this.Transport, that1.Transport)
+	}
+	if len(this.DialAddrs) != len(that1.DialAddrs) {
+		return fmt.Errorf("DialAddrs this(%v) Not Equal that(%v)", len(this.DialAddrs), len(that1.DialAddrs))
+	}
+	for i := range this.DialAddrs {
+		if this.DialAddrs[i] != that1.DialAddrs[i] {
+			return fmt.Errorf("DialAddrs this[%v](%v) Not Equal that[%v](%v)", i, this.DialAddrs[i], i, that1.DialAddrs[i])
+		}
+	}
+	return nil
+}
+func (this *DropPeerReq_PeerDialInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DropPeerReq_PeerDialInfo)
+	if !ok {
+		that2, ok := that.(DropPeerReq_PeerDialInfo)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Transport != that1.Transport {
+		return false
+	}
+	if len(this.DialAddrs) != len(that1.DialAddrs) {
+		return false
+	}
+	for i := range this.DialAddrs {
+		if this.DialAddrs[i] != that1.DialAddrs[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *DropPeerResp) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*DropPeerResp)
+	if !ok {
+		that2, ok := that.(DropPeerResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *DropPeerResp")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *DropPeerResp but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *DropPeerResp but is not nil && this == nil")
+	}
+	return nil
+}
+func (this *DropPeerResp) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DropPeerResp)
+	if !ok {
+		that2, ok := that.(DropPeerResp)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *AuthorizeReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.AuthorizeReq{")
+	s = append(s, "Challenge: "+fmt.Sprintf("%#v", this.Challenge)+",\n")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AuthorizeResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.AuthorizeResp{")
+	s = append(s, "Challenge: "+fmt.Sprintf("%#v", this.Challenge)+",\n")
+	s = append(s, "Ucan: "+fmt.Sprintf("%#v", this.Ucan)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SetHDMnemonicReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&pb.SetHDMnemonicReq{")
+	s = append(s, "Mnemonic: "+fmt.Sprintf("%#v", this.Mnemonic)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SetHDMnemonicResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&pb.SetHDMnemonicResp{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SubscribeToStatesReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.SubscribeToStatesReq{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "Keypath: "+fmt.Sprintf("%#v", this.Keypath)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StatePacket) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.StatePacket{")
+	if this.State != nil {
+		s = append(s, "State: "+fmt.Sprintf("%#v", this.State)+",\n")
+	}
+	s = append(s, "Leaves: "+fmt.Sprintf("%#v", this.Leaves)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *KeypathValue) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.KeypathValue{")
+	s = append(s, "Keypath: "+fmt.Sprintf("%#v", this.Keypath)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *UnsubscribeFromStatesReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&pb.UnsubscribeFromStatesReq{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "Keypath: "+fmt.Sprintf("%#v", this.Keypath)+",\n")
+	s = append(s, "SubscriptionID: "+fmt.Sprintf("%#v", this.SubscriptionID)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *UnsubscribeFromStatesResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&pb.UnsubscribeFromStatesResp{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SubscribeToTxsReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&pb.SubscribeToTxsReq{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TxPacket) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 15)
+	s = append(s, "&pb.TxPacket{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Parents: "+fmt.Sprintf("%#v", this.Parents)+",\n")
+	s = append(s, "Children: "+fmt.Sprintf("%#v", this.Children)+",\n")
+	s = append(s, "From: "+fmt.Sprintf("%#v", this.From)+",\n")
+	s = append(s, "Sig: "+fmt.Sprintf("%#v", this.Sig)+",\n")
+	s = append(s, "Patches: "+fmt.Sprintf("%#v", this.Patches)+",\n")
+	s = append(s, "Recipients: "+fmt.Sprintf("%#v", this.Recipients)+",\n")
+	s = append(s, "Attachment: "+fmt.Sprintf("%#v", this.Attachment)+",\n")
+	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
+	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *UnsubscribeFromTxsReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&pb.UnsubscribeFromTxsReq{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "Keypath: "+fmt.Sprintf("%#v", this.Keypath)+",\n")
+	s = append(s, "SubscriptionID: "+fmt.Sprintf("%#v", this.SubscriptionID)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *UnsubscribeFromTxsResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&pb.UnsubscribeFromTxsResp{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *GetStateReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&pb.GetStateReq{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "Keypath: "+fmt.Sprintf("%#v", this.Keypath)+",\n")
+	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SendTxReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&pb.SendTxReq{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Parents: "+fmt.Sprintf("%#v", this.Parents)+",\n")
+	s = append(s, "Patches: "+fmt.Sprintf("%#v", this.Patches)+",\n")
+	s = append(s, "Attachment: "+fmt.Sprintf("%#v", this.Attachment)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SendTxResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.SendTxResp{")
+	s = append(s, "StateURI: "+fmt.Sprintf("%#v", this.StateURI)+",\n")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *RefPacket) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.RefPacket{")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AddRefResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.AddRefResp{")
+	s = append(s, "Sha1Hash: "+fmt.Sprintf("%#v", this.Sha1Hash)+",\n")
+	s = append(s, "Sha3Hash: "+fmt.Sprintf("%#v", this.Sha3Hash)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *FetchRefReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.FetchRefReq{")
+	if this.Hash != nil {
+		s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *FetchRefReq_Sha1) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&pb.FetchRefReq_Sha1{` +
+		`Sha1:` + fmt.Sprintf("%#v", this.Sha1) + `}`}, ", ")
+	return s
+}
+func (this *FetchRefReq_Sha3) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&pb.FetchRefReq_Sha3{` +
+		`Sha3:` + fmt.Sprintf("%#v", this.Sha3) + `}`}, ", ")
+	return s
+}
+func (this *AddPeerReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.AddPeerReq{")
+	s = append(s, "Transport: "+fmt.Sprintf("%#v", this.Transport)+",\n")
+	s = append(s, "DialAddrs: "+fmt.Sprintf("%#v", this.DialAddrs)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AddPeerResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&pb.AddPeerResp{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DropPeerReq) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.DropPeerReq{")
+	if this.Identifier != nil {
+		s = append(s, "Identifier: "+fmt.Sprintf("%#v", this.Identifier)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DropPeerReq_PeerDialInfo_) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&pb.DropPeerReq_PeerDialInfo_{` +
+		`PeerDialInfo:` + fmt.Sprintf("%#v", this.PeerDialInfo) + `}`}, ", ")
+	return s
+}
+func (this *DropPeerReq_Address) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&pb.DropPeerReq_Address{` +
+		`Address:` + fmt.Sprintf("%#v", this.Address) + `}`}, ", ")
+	return s
+}
+func (this *DropPeerReq_PeerDialInfo) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&pb.DropPeerReq_PeerDialInfo{")
+	s = append(s, "Transport: "+fmt.Sprintf("%#v", this.Transport)+",\n")
+	s = append(s, "DialAddrs: "+fmt.Sprintf("%#v", this.DialAddrs)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DropPeerResp) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&pb.DropPeerResp{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringGrpc(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TrustedRPCClient is the client API for TrustedRPC service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TrustedRPCClient interface {
+	//
+	// Authorization (see rpc.NewWhitelistMiddleware)
+	//
+	Authorize(ctx context.Context, in *AuthorizeReq, opts ...grpc.CallOption) (*AuthorizeResp, error)
+	//
+	// Fundamental primitives
+	//
+	SetHDMnemonic(ctx context.Context, in *SetHDMnemonicReq, opts ...grpc.CallOption) (*SetHDMnemonicResp, error)
+	SubscribeToStates(ctx context.Context, in *SubscribeToStatesReq, opts ...grpc.CallOption) (TrustedRPC_SubscribeToStatesClient, error)
+	UnsubscribeFromStates(ctx context.Context, in *UnsubscribeFromStatesReq, opts ...grpc.CallOption) (*UnsubscribeFromStatesResp, error)
+	SubscribeToTxs(ctx context.Context, in *SubscribeToTxsReq, opts ...grpc.CallOption) (TrustedRPC_SubscribeToTxsClient, error)
+	UnsubscribeFromTxs(ctx context.Context, in *UnsubscribeFromTxsReq, opts ...grpc.CallOption) (*UnsubscribeFromTxsResp, error)
+	GetState(ctx context.Context, in *GetStateReq, opts ...grpc.CallOption) (*StatePacket, error)
+	SendTx(ctx context.Context, in *SendTxReq, opts ...grpc.CallOption) (*SendTxResp, error)
+	AddRef(ctx context.Context, opts ...grpc.CallOption) (TrustedRPC_AddRefClient, error)
+	FetchRef(ctx context.Context, in *FetchRefReq, opts ...grpc.CallOption) (TrustedRPC_FetchRefClient, error)
+	AddPeer(ctx context.Context, in *AddPeerReq, opts ...grpc.CallOption) (*AddPeerResp, error)
+	DropPeer(ctx context.Context, in *DropPeerReq, opts ...grpc.CallOption) (*DropPeerResp, error)
+}
+
+type trustedRPCClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTrustedRPCClient(cc *grpc.ClientConn) TrustedRPCClient {
+	return &trustedRPCClient{cc}
+}
+
+func (c *trustedRPCClient) Authorize(ctx context.Context, in *AuthorizeReq, opts ...grpc.CallOption) (*AuthorizeResp, error) {
+	out := new(AuthorizeResp)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/Authorize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustedRPCClient) SetHDMnemonic(ctx context.Context, in *SetHDMnemonicReq, opts ...grpc.CallOption) (*SetHDMnemonicResp, error) {
+	out := new(SetHDMnemonicResp)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/SetHDMnemonic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustedRPCClient) SubscribeToStates(ctx context.Context, in *SubscribeToStatesReq, opts ...grpc.CallOption) (TrustedRPC_SubscribeToStatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TrustedRPC_serviceDesc.Streams[0], "/trustedrpc.TrustedRPC/SubscribeToStates", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trustedRPCSubscribeToStatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrustedRPC_SubscribeToStatesClient interface {
+	Recv() (*StatePacket, error)
+	grpc.ClientStream
+}
+
+type trustedRPCSubscribeToStatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *trustedRPCSubscribeToStatesClient) Recv() (*StatePacket, error) {
+	m := new(StatePacket)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trustedRPCClient) UnsubscribeFromStates(ctx context.Context, in *UnsubscribeFromStatesReq, opts ...grpc.CallOption) (*UnsubscribeFromStatesResp, error) {
+	out := new(UnsubscribeFromStatesResp)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/UnsubscribeFromStates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustedRPCClient) SubscribeToTxs(ctx context.Context, in *SubscribeToTxsReq, opts ...grpc.CallOption) (TrustedRPC_SubscribeToTxsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TrustedRPC_serviceDesc.Streams[1], "/trustedrpc.TrustedRPC/SubscribeToTxs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trustedRPCSubscribeToTxsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrustedRPC_SubscribeToTxsClient interface {
+	Recv() (*TxPacket, error)
+	grpc.ClientStream
+}
+
+type trustedRPCSubscribeToTxsClient struct {
+	grpc.ClientStream
+}
+
+func (x *trustedRPCSubscribeToTxsClient) Recv() (*TxPacket, error) {
+	m := new(TxPacket)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trustedRPCClient) UnsubscribeFromTxs(ctx context.Context, in *UnsubscribeFromTxsReq, opts ...grpc.CallOption) (*UnsubscribeFromTxsResp, error) {
+	out := new(UnsubscribeFromTxsResp)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/UnsubscribeFromTxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustedRPCClient) GetState(ctx context.Context, in *GetStateReq, opts ...grpc.CallOption) (*StatePacket, error) {
+	out := new(StatePacket)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/GetState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustedRPCClient) SendTx(ctx context.Context, in *SendTxReq, opts ...grpc.CallOption) (*SendTxResp, error) {
+	out := new(SendTxResp)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/SendTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustedRPCClient) AddRef(ctx context.Context, opts ...grpc.CallOption) (TrustedRPC_AddRefClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TrustedRPC_serviceDesc.Streams[2], "/trustedrpc.TrustedRPC/AddRef", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trustedRPCAddRefClient{stream}
+	return x, nil
+}
+
+type TrustedRPC_AddRefClient interface {
+	Send(*RefPacket) error
+	CloseAndRecv() (*AddRefResp, error)
+	grpc.ClientStream
+}
+
+type trustedRPCAddRefClient struct {
+	grpc.ClientStream
+}
+
+func (x *trustedRPCAddRefClient) Send(m *RefPacket) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *trustedRPCAddRefClient) CloseAndRecv() (*AddRefResp, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(AddRefResp)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trustedRPCClient) FetchRef(ctx context.Context, in *FetchRefReq, opts ...grpc.CallOption) (TrustedRPC_FetchRefClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TrustedRPC_serviceDesc.Streams[3], "/trustedrpc.TrustedRPC/FetchRef", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trustedRPCFetchRefClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrustedRPC_FetchRefClient interface {
+	Recv() (*RefPacket, error)
+	grpc.ClientStream
+}
+
+type trustedRPCFetchRefClient struct {
+	grpc.ClientStream
+}
+
+func (x *trustedRPCFetchRefClient) Recv() (*RefPacket, error) {
+	m := new(RefPacket)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trustedRPCClient) AddPeer(ctx context.Context, in *AddPeerReq, opts ...grpc.CallOption) (*AddPeerResp, error) {
+	out := new(AddPeerResp)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/AddPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustedRPCClient) DropPeer(ctx context.Context, in *DropPeerReq, opts ...grpc.CallOption) (*DropPeerResp, error) {
+	out := new(DropPeerResp)
+	err := c.cc.Invoke(ctx, "/trustedrpc.TrustedRPC/DropPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrustedRPCServer is the server API for TrustedRPC service.
+type TrustedRPCServer interface {
+	//
+	// Authorization (see rpc.NewWhitelistMiddleware)
+	//
+	Authorize(context.Context, *AuthorizeReq) (*AuthorizeResp, error)
+	//
+	// Fundamental primitives
+	//
+	SetHDMnemonic(context.Context, *SetHDMnemonicReq) (*SetHDMnemonicResp, error)
+	SubscribeToStates(*SubscribeToStatesReq, TrustedRPC_SubscribeToStatesServer) error
+	UnsubscribeFromStates(context.Context, *UnsubscribeFromStatesReq) (*UnsubscribeFromStatesResp, error)
+	SubscribeToTxs(*SubscribeToTxsReq, TrustedRPC_SubscribeToTxsServer) error
+	UnsubscribeFromTxs(context.Context, *UnsubscribeFromTxsReq) (*UnsubscribeFromTxsResp, error)
+	GetState(context.Context, *GetStateReq) (*StatePacket, error)
+	SendTx(context.Context, *SendTxReq) (*SendTxResp, error)
+	AddRef(TrustedRPC_AddRefServer) error
+	FetchRef(*FetchRefReq, TrustedRPC_FetchRefServer) error
+	AddPeer(context.Context, *AddPeerReq) (*AddPeerResp, error)
+	DropPeer(context.Context, *DropPeerReq) (*DropPeerResp, error)
+}
+
+// UnimplementedTrustedRPCServer can be embedded to have forward compatible implementations.
+type UnimplementedTrustedRPCServer struct {
+}
+
+func (*UnimplementedTrustedRPCServer) Authorize(ctx context.Context, req *AuthorizeReq) (*AuthorizeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+}
+func (*UnimplementedTrustedRPCServer) SetHDMnemonic(ctx context.Context, req *SetHDMnemonicReq) (*SetHDMnemonicResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHDMnemonic not implemented")
+}
+func (*UnimplementedTrustedRPCServer) SubscribeToStates(req *SubscribeToStatesReq, srv TrustedRPC_SubscribeToStatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToStates not implemented")
+}
+func (*UnimplementedTrustedRPCServer) UnsubscribeFromStates(ctx context.Context, req *UnsubscribeFromStatesReq) (*UnsubscribeFromStatesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsubscribeFromStates not implemented")
+}
+func (*UnimplementedTrustedRPCServer) SubscribeToTxs(req *SubscribeToTxsReq, srv TrustedRPC_SubscribeToTxsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToTxs not implemented")
+}
+func (*UnimplementedTrustedRPCServer) UnsubscribeFromTxs(ctx context.Context, req *UnsubscribeFromTxsReq) (*UnsubscribeFromTxsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsubscribeFromTxs not implemented")
+}
+func (*UnimplementedTrustedRPCServer) GetState(ctx context.Context, req *GetStateReq) (*StatePacket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetState not implemented")
+}
+func (*UnimplementedTrustedRPCServer) SendTx(ctx context.Context, req *SendTxReq) (*SendTxResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendTx not implemented")
+}
+func (*UnimplementedTrustedRPCServer) AddRef(srv TrustedRPC_AddRefServer) error {
+	return status.Errorf(codes.Unimplemented, "method AddRef not implemented")
+}
+func (*UnimplementedTrustedRPCServer) FetchRef(req *FetchRefReq, srv TrustedRPC_FetchRefServer) error {
+	return status.Errorf(codes.Unimplemented, "method FetchRef not implemented")
+}
+func (*UnimplementedTrustedRPCServer) AddPeer(ctx context.Context, req *AddPeerReq) (*AddPeerResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPeer not implemented")
+}
+func (*UnimplementedTrustedRPCServer) DropPeer(ctx context.Context, req *DropPeerReq) (*DropPeerResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropPeer not implemented")
+}
+
+func RegisterTrustedRPCServer(s *grpc.Server, srv TrustedRPCServer) {
+	s.RegisterService(&_TrustedRPC_serviceDesc, srv)
+}
+
+func _TrustedRPC_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).Authorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/Authorize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).Authorize(ctx, req.(*AuthorizeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustedRPC_SetHDMnemonic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetHDMnemonicReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).SetHDMnemonic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/SetHDMnemonic",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).SetHDMnemonic(ctx, req.(*SetHDMnemonicReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustedRPC_SubscribeToStates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToStatesReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrustedRPCServer).SubscribeToStates(m, &trustedRPCSubscribeToStatesServer{stream})
+}
+
+type TrustedRPC_SubscribeToStatesServer interface {
+	Send(*StatePacket) error
+	grpc.ServerStream
+}
+
+type trustedRPCSubscribeToStatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *trustedRPCSubscribeToStatesServer) Send(m *StatePacket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrustedRPC_UnsubscribeFromStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsubscribeFromStatesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).UnsubscribeFromStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/UnsubscribeFromStates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).UnsubscribeFromStates(ctx, req.(*UnsubscribeFromStatesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustedRPC_SubscribeToTxs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToTxsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrustedRPCServer).SubscribeToTxs(m, &trustedRPCSubscribeToTxsServer{stream})
+}
+
+type TrustedRPC_SubscribeToTxsServer interface {
+	Send(*TxPacket) error
+	grpc.ServerStream
+}
+
+type trustedRPCSubscribeToTxsServer struct {
+	grpc.ServerStream
+}
+
+func (x *trustedRPCSubscribeToTxsServer) Send(m *TxPacket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrustedRPC_UnsubscribeFromTxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsubscribeFromTxsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).UnsubscribeFromTxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/UnsubscribeFromTxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).UnsubscribeFromTxs(ctx, req.(*UnsubscribeFromTxsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustedRPC_GetState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).GetState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/GetState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).GetState(ctx, req.(*GetStateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustedRPC_SendTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTxReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).SendTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/SendTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).SendTx(ctx, req.(*SendTxReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustedRPC_AddRef_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TrustedRPCServer).AddRef(&trustedRPCAddRefServer{stream})
+}
+
+type TrustedRPC_AddRefServer interface {
+	SendAndClose(*AddRefResp) error
+	Recv() (*RefPacket, error)
+	grpc.ServerStream
+}
+
+type trustedRPCAddRefServer struct {
+	grpc.ServerStream
+}
+
+func (x *trustedRPCAddRefServer) SendAndClose(m *AddRefResp) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *trustedRPCAddRefServer) Recv() (*RefPacket, error) {
+	m := new(RefPacket)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TrustedRPC_FetchRef_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchRefReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrustedRPCServer).FetchRef(m, &trustedRPCFetchRefServer{stream})
+}
+
+type TrustedRPC_FetchRefServer interface {
+	Send(*RefPacket) error
+	grpc.ServerStream
+}
+
+type trustedRPCFetchRefServer struct {
+	grpc.ServerStream
+}
+
+func (x *trustedRPCFetchRefServer) Send(m *RefPacket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrustedRPC_AddPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPeerReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).AddPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/AddPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).AddPeer(ctx, req.(*AddPeerReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustedRPC_DropPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropPeerReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustedRPCServer).DropPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trustedrpc.TrustedRPC/DropPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustedRPCServer).DropPeer(ctx, req.(*DropPeerReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _TrustedRPC_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "trustedrpc.TrustedRPC",
+	HandlerType: (*TrustedRPCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Authorize",
+			Handler:    _TrustedRPC_Authorize_Handler,
+		},
+		{
+			MethodName: "SetHDMnemonic",
+			Handler:    _TrustedRPC_SetHDMnemonic_Handler,
+		},
+		{
+			MethodName: "UnsubscribeFromStates",
+			Handler:    _TrustedRPC_UnsubscribeFromStates_Handler,
+		},
+		{
+			MethodName: "UnsubscribeFromTxs",
+			Handler:    _TrustedRPC_UnsubscribeFromTxs_Handler,
+		},
+		{
+			MethodName: "GetState",
+			Handler:    _TrustedRPC_GetState_Handler,
+		},
+		{
+			MethodName: "SendTx",
+			Handler:    _TrustedRPC_SendTx_Handler,
+		},
+		{
+			MethodName: "AddPeer",
+			Handler:    _TrustedRPC_AddPeer_Handler,
+		},
+		{
+			MethodName: "DropPeer",
+			Handler:    _TrustedRPC_DropPeer_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeToStates",
+			Handler:       _TrustedRPC_SubscribeToStates_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeToTxs",
+			Handler:       _TrustedRPC_SubscribeToTxs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "AddRef",
+			Handler:       _TrustedRPC_AddRef_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FetchRef",
+			Handler:       _TrustedRPC_FetchRef_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpc.proto",
+}
+
+func (m *AuthorizeReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AuthorizeReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AuthorizeReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Response) > 0 {
+		i -= len(m.Response)
+		copy(dAtA[i:], m.Response)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Response)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Challenge) > 0 {
+		i -= len(m.Challenge)
+		copy(dAtA[i:], m.Challenge)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Challenge)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AuthorizeResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AuthorizeResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AuthorizeResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Ucan) > 0 {
+		i -= len(m.Ucan)
+		copy(dAtA[i:], m.Ucan)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Ucan)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Challenge) > 0 {
+		i -= len(m.Challenge)
+		copy(dAtA[i:], m.Challenge)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Challenge)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SetHDMnemonicReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SetHDMnemonicReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SetHDMnemonicReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Mnemonic) > 0 {
+		i -= len(m.Mnemonic)
+		copy(dAtA[i:], m.Mnemonic)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Mnemonic)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SetHDMnemonicResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SetHDMnemonicResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SetHDMnemonicResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *SubscribeToStatesReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SubscribeToStatesReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SubscribeToStatesReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Keypath) > 0 {
+		i -= len(m.Keypath)
+		copy(dAtA[i:], m.Keypath)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Keypath)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StatePacket) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StatePacket) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StatePacket) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Leaves) > 0 {
+		for iNdEx := len(m.Leaves) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Leaves[iNdEx])
+			copy(dAtA[i:], m.Leaves[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.Leaves[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.State) > 0 {
+		for iNdEx := len(m.State) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.State[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGrpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *KeypathValue) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KeypathValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *KeypathValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Keypath) > 0 {
+		i -= len(m.Keypath)
+		copy(dAtA[i:], m.Keypath)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Keypath)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UnsubscribeFromStatesReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UnsubscribeFromStatesReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UnsubscribeFromStatesReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SubscriptionID) > 0 {
+		i -= len(m.SubscriptionID)
+		copy(dAtA[i:], m.SubscriptionID)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.SubscriptionID)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Keypath) > 0 {
+		i -= len(m.Keypath)
+		copy(dAtA[i:], m.Keypath)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Keypath)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UnsubscribeFromStatesResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UnsubscribeFromStatesResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UnsubscribeFromStatesResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *SubscribeToTxsReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SubscribeToTxsReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SubscribeToTxsReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TxPacket) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxPacket) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TxPacket) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.Status != 0 {
+		i = encodeVarintGrpc(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.Attachment) > 0 {
+		i -= len(m.Attachment)
+		copy(dAtA[i:], m.Attachment)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Attachment)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Recipients) > 0 {
+		for iNdEx := len(m.Recipients) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Recipients[iNdEx])
+			copy(dAtA[i:], m.Recipients[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.Recipients[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.Patches) > 0 {
+		for iNdEx := len(m.Patches) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Patches[iNdEx])
+			copy(dAtA[i:], m.Patches[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.Patches[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.Sig) > 0 {
+		i -= len(m.Sig)
+		copy(dAtA[i:], m.Sig)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Sig)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.From) > 0 {
+		i -= len(m.From)
+		copy(dAtA[i:], m.From)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.From)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Children) > 0 {
+		for iNdEx := len(m.Children) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Children[iNdEx])
+			copy(dAtA[i:], m.Children[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.Children[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Parents) > 0 {
+		for iNdEx := len(m.Parents) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Parents[iNdEx])
+			copy(dAtA[i:], m.Parents[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.Parents[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UnsubscribeFromTxsReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UnsubscribeFromTxsReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UnsubscribeFromTxsReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.SubscriptionID) > 0 {
+		i -= len(m.SubscriptionID)
+		copy(dAtA[i:], m.SubscriptionID)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.SubscriptionID)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Keypath) > 0 {
+		i -= len(m.Keypath)
+		copy(dAtA[i:], m.Keypath)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Keypath)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *UnsubscribeFromTxsResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UnsubscribeFromTxsResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UnsubscribeFromTxsResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *GetStateReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetStateReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetStateReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Version) > 0 {
+		i -= len(m.Version)
+		copy(dAtA[i:], m.Version)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Version)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Keypath) > 0 {
+		i -= len(m.Keypath)
+		copy(dAtA[i:], m.Keypath)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Keypath)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SendTxReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SendTxReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SendTxReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Attachment) > 0 {
+		i -= len(m.Attachment)
+		copy(dAtA[i:], m.Attachment)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Attachment)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Patches) > 0 {
+		for iNdEx := len(m.Patches) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Patches[iNdEx])
+			copy(dAtA[i:], m.Patches[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.Patches[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Parents) > 0 {
+		for iNdEx := len(m.Parents) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Parents[iNdEx])
+			copy(dAtA[i:], m.Parents[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.Parents[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SendTxResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SendTxResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SendTxResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.StateURI) > 0 {
+		i -= len(m.StateURI)
+		copy(dAtA[i:], m.StateURI)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.StateURI)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RefPacket) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RefPacket) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RefPacket) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.End {
+		i--
+		if m.End {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AddRefResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AddRefResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AddRefResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sha3Hash) > 0 {
+		i -= len(m.Sha3Hash)
+		copy(dAtA[i:], m.Sha3Hash)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Sha3Hash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Sha1Hash) > 0 {
+		i -= len(m.Sha1Hash)
+		copy(dAtA[i:], m.Sha1Hash)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Sha1Hash)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FetchRefReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchRefReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FetchRefReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Hash != nil {
+		{
+			size := m.Hash.Size()
+			i -= size
+			if _, err := m.Hash.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FetchRefReq_Sha1) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FetchRefReq_Sha1) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Sha1 != nil {
+		i -= len(m.Sha1)
+		copy(dAtA[i:], m.Sha1)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Sha1)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+func (m *FetchRefReq_Sha3) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FetchRefReq_Sha3) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Sha3 != nil {
+		i -= len(m.Sha3)
+		copy(dAtA[i:], m.Sha3)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Sha3)))
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+func (m *AddPeerReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AddPeerReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AddPeerReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.DialAddrs) > 0 {
+		for iNdEx := len(m.DialAddrs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.DialAddrs[iNdEx])
+			copy(dAtA[i:], m.DialAddrs[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.DialAddrs[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Transport != 0 {
+		i = encodeVarintGrpc(dAtA, i, uint64(m.Transport))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AddPeerResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AddPeerResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AddPeerResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *DropPeerReq) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DropPeerReq) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DropPeerReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Identifier != nil {
+		{
+			size := m.Identifier.Size()
+			i -= size
+			if _, err := m.Identifier.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DropPeerReq_PeerDialInfo_) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DropPeerReq_PeerDialInfo_) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.PeerDialInfo != nil {
+		{
+			size, err := m.PeerDialInfo.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGrpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+func (m *DropPeerReq_Address) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DropPeerReq_Address) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Address != nil {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintGrpc(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+func (m *DropPeerReq_PeerDialInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DropPeerReq_PeerDialInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DropPeerReq_PeerDialInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.DialAddrs) > 0 {
+		for iNdEx := len(m.DialAddrs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.DialAddrs[iNdEx])
+			copy(dAtA[i:], m.DialAddrs[iNdEx])
+			i = encodeVarintGrpc(dAtA, i, uint64(len(m.DialAddrs[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Transport != 0 {
+		i = encodeVarintGrpc(dAtA, i, uint64(m.Transport))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DropPeerResp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DropPeerResp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DropPeerResp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintGrpc(dAtA []byte, offset int, v uint64) int {
+	offset -= sovGrpc(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *AuthorizeReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Challenge)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Response)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *AuthorizeResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Challenge)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Ucan)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *SetHDMnemonicReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Mnemonic)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *SetHDMnemonicResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SubscribeToStatesReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Keypath)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *StatePacket) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.State) > 0 {
+		for _, e := range m.State {
+			l = e.Size()
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	if len(m.Leaves) > 0 {
+		for _, b := range m.Leaves {
+			l = len(b)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *KeypathValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Keypath)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *UnsubscribeFromStatesReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Keypath)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.SubscriptionID)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *UnsubscribeFromStatesResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SubscribeToTxsReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *TxPacket) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	if len(m.Parents) > 0 {
+		for _, b := range m.Parents {
+			l = len(b)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	if len(m.Children) > 0 {
+		for _, b := range m.Children {
+			l = len(b)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	l = len(m.From)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Sig)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	if len(m.Patches) > 0 {
+		for _, s := range m.Patches {
+			l = len(s)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	if len(m.Recipients) > 0 {
+		for _, b := range m.Recipients {
+			l = len(b)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	l = len(m.Attachment)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	if m.Status != 0 {
+		n += 1 + sovGrpc(uint64(m.Status))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *UnsubscribeFromTxsReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Keypath)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.SubscriptionID)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *UnsubscribeFromTxsResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *GetStateReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Keypath)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *SendTxReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	if len(m.Parents) > 0 {
+		for _, b := range m.Parents {
+			l = len(b)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	if len(m.Patches) > 0 {
+		for _, s := range m.Patches {
+			l = len(s)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	l = len(m.Attachment)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *SendTxResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateURI)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *RefPacket) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	if m.End {
+		n += 2
+	}
+	return n
+}
+
+func (m *AddRefResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Sha1Hash)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	l = len(m.Sha3Hash)
+	if l > 0 {
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+
+func (m *FetchRefReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Hash != nil {
+		n += m.Hash.Size()
+	}
+	return n
+}
+
+func (m *FetchRefReq_Sha1) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sha1 != nil {
+		l = len(m.Sha1)
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+func (m *FetchRefReq_Sha3) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sha3 != nil {
+		l = len(m.Sha3)
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+func (m *AddPeerReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Transport != 0 {
+		n += 1 + sovGrpc(uint64(m.Transport))
+	}
+	if len(m.DialAddrs) > 0 {
+		for _, s := range m.DialAddrs {
+			l = len(s)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AddPeerResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *DropPeerReq) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Identifier != nil {
+		n += m.Identifier.Size()
+	}
+	return n
+}
+
+func (m *DropPeerReq_PeerDialInfo_) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PeerDialInfo != nil {
+		l = m.PeerDialInfo.Size()
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+func (m *DropPeerReq_Address) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Address != nil {
+		l = len(m.Address)
+		n += 1 + l + sovGrpc(uint64(l))
+	}
+	return n
+}
+func (m *DropPeerReq_PeerDialInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Transport != 0 {
+		n += 1 + sovGrpc(uint64(m.Transport))
+	}
+	if len(m.DialAddrs) > 0 {
+		for _, s := range m.DialAddrs {
+			l = len(s)
+			n += 1 + l + sovGrpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DropPeerResp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func sovGrpc(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozGrpc(x uint64) (n int) {
+	return sovGrpc(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *AuthorizeReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AuthorizeReq{`,
+		`Challenge:` + fmt.Sprintf("%v", this.Challenge) + `,`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AuthorizeResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AuthorizeResp{`,
+		`Challenge:` + fmt.Sprintf("%v", this.Challenge) + `,`,
+		`Ucan:` + fmt.Sprintf("%v", this.Ucan) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SetHDMnemonicReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SetHDMnemonicReq{`,
+		`Mnemonic:` + fmt.Sprintf("%v", this.Mnemonic) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SetHDMnemonicResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SetHDMnemonicResp{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SubscribeToStatesReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SubscribeToStatesReq{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`Keypath:` + fmt.Sprintf("%v", this.Keypath) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StatePacket) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForState := "[]*KeypathValue{"
+	for _, f := range this.State {
+		repeatedStringForState += strings.Replace(f.String(), "KeypathValue", "KeypathValue", 1) + ","
+	}
+	repeatedStringForState += "}"
+	s := strings.Join([]string{`&StatePacket{`,
+		`State:` + repeatedStringForState + `,`,
+		`Leaves:` + fmt.Sprintf("%v", this.Leaves) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *KeypathValue) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&KeypathValue{`,
+		`Keypath:` + fmt.Sprintf("%v", this.Keypath) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UnsubscribeFromStatesReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UnsubscribeFromStatesReq{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`Keypath:` + fmt.Sprintf("%v", this.Keypath) + `,`,
+		`SubscriptionID:` + fmt.Sprintf("%v", this.SubscriptionID) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UnsubscribeFromStatesResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UnsubscribeFromStatesResp{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SubscribeToTxsReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SubscribeToTxsReq{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TxPacket) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TxPacket{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Parents:` + fmt.Sprintf("%v", this.Parents) + `,`,
+		`Children:` + fmt.Sprintf("%v", this.Children) + `,`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Sig:` + fmt.Sprintf("%v", this.Sig) + `,`,
+		`Patches:` + fmt.Sprintf("%v", this.Patches) + `,`,
+		`Recipients:` + fmt.Sprintf("%v", this.Recipients) + `,`,
+		`Attachment:` + fmt.Sprintf("%v", this.Attachment) + `,`,
+		`Status:` + fmt.Sprintf("%v", this.Status) + `,`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UnsubscribeFromTxsReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UnsubscribeFromTxsReq{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`Keypath:` + fmt.Sprintf("%v", this.Keypath) + `,`,
+		`SubscriptionID:` + fmt.Sprintf("%v", this.SubscriptionID) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UnsubscribeFromTxsResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UnsubscribeFromTxsResp{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetStateReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetStateReq{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`Keypath:` + fmt.Sprintf("%v", this.Keypath) + `,`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SendTxReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SendTxReq{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Parents:` + fmt.Sprintf("%v", this.Parents) + `,`,
+		`Patches:` + fmt.Sprintf("%v", this.Patches) + `,`,
+		`Attachment:` + fmt.Sprintf("%v", this.Attachment) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SendTxResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SendTxResp{`,
+		`StateURI:` + fmt.Sprintf("%v", this.StateURI) + `,`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RefPacket) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RefPacket{`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`End:` + fmt.Sprintf("%v", this.End) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AddRefResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AddRefResp{`,
+		`Sha1Hash:` + fmt.Sprintf("%v", this.Sha1Hash) + `,`,
+		`Sha3Hash:` + fmt.Sprintf("%v", this.Sha3Hash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FetchRefReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FetchRefReq{`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FetchRefReq_Sha1) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FetchRefReq_Sha1{`,
+		`Sha1:` + fmt.Sprintf("%v", this.Sha1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FetchRefReq_Sha3) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FetchRefReq_Sha3{`,
+		`Sha3:` + fmt.Sprintf("%v", this.Sha3) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AddPeerReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AddPeerReq{`,
+		`Transport:` + fmt.Sprintf("%v", this.Transport) + `,`,
+		`DialAddrs:` + fmt.Sprintf("%v", this.DialAddrs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AddPeerResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AddPeerResp{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DropPeerReq) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DropPeerReq{`,
+		`Identifier:` + fmt.Sprintf("%v", this.Identifier) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DropPeerReq_PeerDialInfo_) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DropPeerReq_PeerDialInfo_{`,
+		`PeerDialInfo:` + strings.Replace(fmt.Sprintf("%v", this.PeerDialInfo), "DropPeerReq_PeerDialInfo", "DropPeerReq_PeerDialInfo", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DropPeerReq_Address) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DropPeerReq_Address{`,
+		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DropPeerReq_PeerDialInfo) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DropPeerReq_PeerDialInfo{`,
+		`Transport:` + fmt.Sprintf("%v", this.Transport) + `,`,
+		`DialAddrs:` + fmt.Sprintf("%v", this.DialAddrs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DropPeerResp) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DropPeerResp{`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringGrpc(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *AuthorizeReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AuthorizeReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AuthorizeReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Challenge", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Challenge = append(m.Challenge[:0], dAtA[iNdEx:postIndex]...)
+			if m.Challenge == nil {
+				m.Challenge = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Response = append(m.Response[:0], dAtA[iNdEx:postIndex]...)
+			if m.Response == nil {
+				m.Response = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AuthorizeResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AuthorizeResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AuthorizeResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Challenge", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Challenge = append(m.Challenge[:0], dAtA[iNdEx:postIndex]...)
+			if m.Challenge == nil {
+				m.Challenge = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ucan", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ucan = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetHDMnemonicReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetHDMnemonicReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetHDMnemonicReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mnemonic", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mnemonic = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetHDMnemonicResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetHDMnemonicResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetHDMnemonicResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SubscribeToStatesReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubscribeToStatesReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubscribeToStatesReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keypath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keypath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StatePacket) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StatePacket: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StatePacket: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.State = append(m.State, &KeypathValue{})
+			if err := m.State[len(m.State)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leaves", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Leaves = append(m.Leaves, make([]byte, postIndex-iNdEx))
+			copy(m.Leaves[len(m.Leaves)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *KeypathValue) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: KeypathValue: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: KeypathValue: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keypath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keypath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UnsubscribeFromStatesReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UnsubscribeFromStatesReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UnsubscribeFromStatesReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keypath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keypath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SubscriptionID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SubscriptionID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UnsubscribeFromStatesResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UnsubscribeFromStatesResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UnsubscribeFromStatesResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SubscribeToTxsReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SubscribeToTxsReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SubscribeToTxsReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxPacket) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxPacket: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxPacket: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Parents", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Parents = append(m.Parents, make([]byte, postIndex-iNdEx))
+			copy(m.Parents[len(m.Parents)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Children", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Children = append(m.Children, make([]byte, postIndex-iNdEx))
+			copy(m.Children[len(m.Children)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.From = append(m.From[:0], dAtA[iNdEx:postIndex]...)
+			if m.From == nil {
+				m.From = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sig", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sig = append(m.Sig[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sig == nil {
+				m.Sig = []byte{}
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Patches", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Patches = append(m.Patches, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipients", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipients = append(m.Recipients, make([]byte, postIndex-iNdEx))
+			copy(m.Recipients[len(m.Recipients)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attachment", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attachment = append(m.Attachment[:0], dAtA[iNdEx:postIndex]...)
+			if m.Attachment == nil {
+				m.Attachment = []byte{}
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= TxPacket_TxStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UnsubscribeFromTxsReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UnsubscribeFromTxsReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UnsubscribeFromTxsReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keypath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keypath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SubscriptionID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SubscriptionID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UnsubscribeFromTxsResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UnsubscribeFromTxsResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UnsubscribeFromTxsResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetStateReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetStateReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetStateReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keypath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keypath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = append(m.Version[:0], dAtA[iNdEx:postIndex]...)
+			if m.Version == nil {
+				m.Version = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SendTxReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SendTxReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SendTxReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Parents", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Parents = append(m.Parents, make([]byte, postIndex-iNdEx))
+			copy(m.Parents[len(m.Parents)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Patches", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Patches = append(m.Patches, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attachment", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attachment = append(m.Attachment[:0], dAtA[iNdEx:postIndex]...)
+			if m.Attachment == nil {
+				m.Attachment = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SendTxResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SendTxResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SendTxResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateURI", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateURI = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RefPacket) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RefPacket: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RefPacket: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.End = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AddRefResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AddRefResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AddRefResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha1Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sha1Hash = append(m.Sha1Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sha1Hash == nil {
+				m.Sha1Hash = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha3Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sha3Hash = append(m.Sha3Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sha3Hash == nil {
+				m.Sha3Hash = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FetchRefReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FetchRefReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FetchRefReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha1", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := make([]byte, postIndex-iNdEx)
+			copy(v, dAtA[iNdEx:postIndex])
+			m.Hash = &FetchRefReq_Sha1{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sha3", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := make([]byte, postIndex-iNdEx)
+			copy(v, dAtA[iNdEx:postIndex])
+			m.Hash = &FetchRefReq_Sha3{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AddPeerReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AddPeerReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AddPeerReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Transport", wireType)
+			}
+			m.Transport = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Transport |= Transport(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DialAddrs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DialAddrs = append(m.DialAddrs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AddPeerResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AddPeerResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AddPeerResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DropPeerReq) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DropPeerReq: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DropPeerReq: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerDialInfo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &DropPeerReq_PeerDialInfo{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Identifier = &DropPeerReq_PeerDialInfo_{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := make([]byte, postIndex-iNdEx)
+			copy(v, dAtA[iNdEx:postIndex])
+			m.Identifier = &DropPeerReq_Address{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DropPeerReq_PeerDialInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerDialInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerDialInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Transport", wireType)
+			}
+			m.Transport = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Transport |= Transport(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DialAddrs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DialAddrs = append(m.DialAddrs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DropPeerResp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DropPeerResp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DropPeerResp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGrpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGrpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipGrpc(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowGrpc
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowGrpc
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthGrpc
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupGrpc
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthGrpc
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthGrpc        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowGrpc          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupGrpc = fmt.Errorf("proto: unexpected end of group")
+)
@@ -42,3 +42,15 @@ func (s VersionSet) Copy() VersionSet {
 	}
 	return set
 }
+
+func (s VersionSet) Equal(other VersionSet) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for x := range s {
+		if _, exists := other[x]; !exists {
+			return false
+		}
+	}
+	return true
+}
@@ -10,6 +10,8 @@ import (
 	"math"
 	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -148,6 +150,46 @@ func (t *VersionedDBTree) makeIndexKeyPrefix(version Version, keypath Keypath, i
 	return bytes.Join([][]byte{[]byte("i"), version[:], keypath, indexName, []byte{}}, []byte(":"))
 }
 
+func (t *VersionedDBTree) makeIndexRefsKeyPrefix(version Version, keypath Keypath, indexName Keypath) []byte {
+	// r:<version>:<keypath>:<indexName>:
+	return bytes.Join([][]byte{[]byte("r"), version[:], keypath, indexName, []byte{}}, []byte(":"))
+}
+
+// indexRefsAtVersion returns the refs of an index, which record the index key
+// that each child was last indexed under so that its old entry can be found
+// and removed when it changes.  They share the index's transaction.
+func (t *VersionedDBTree) indexRefsAtVersion(index *DBNode, version Version, keypath Keypath, indexName Keypath) *DBNode {
+	return &DBNode{
+		tx:             index.tx,
+		keyPrefix:      t.makeIndexRefsKeyPrefix(version, keypath, indexName),
+		activeIterator: index.activeIterator,
+	}
+}
+
+func (t *VersionedDBTree) makeIndexLayoutKey(version Version, keypath Keypath, indexName Keypath) []byte {
+	// l:<version>:<keypath>:<indexName>:
+	return bytes.Join([][]byte{[]byte("l"), version[:], keypath, indexName, []byte{}}, []byte(":"))
+}
+
+func (t *VersionedDBTree) makeIndexContributionsKeyPrefix(version Version, keypath Keypath, indexName Keypath) []byte {
+	// c:<version>:<keypath>:<indexName>:
+	return bytes.Join([][]byte{[]byte("c"), version[:], keypath, indexName, []byte{}}, []byte(":"))
+}
+
+// indexContributionsAtVersion returns the contributions of an aggregate
+// index, which record the value that each child contributes to each of its
+// groups (i.e., <group>/<childKey>), so that a group's aggregate can be
+// recomputed when its members change.  Indices of maps whose Indexer doesn't
+// group children keep their members here in the same way.  They share the
+// index's transaction.
+func (t *VersionedDBTree) indexContributionsAtVersion(index *DBNode, version Version, keypath Keypath, indexName Keypath) *DBNode {
+	return &DBNode{
+		tx:             index.tx,
+		keyPrefix:      t.makeIndexContributionsKeyPrefix(version, keypath, indexName),
+		activeIterator: index.activeIterator,
+	}
+}
+
 func (t *VersionedDBTree) StateAtVersion(version *Version, mutable bool) *DBNode {
 	if version == nil {
 		version = &CurrentVersion
@@ -1714,6 +1756,14 @@ func (t *VersionedDBTree) CopyVersion(dstVersion, srcVersion Version) error {
 	})
 }
 
+// DeleteVersion removes a copy of the state made with CopyVersion.
+func (t *VersionedDBTree) DeleteVersion(version Version) error {
+	if version == CurrentVersion {
+		return errors.New("cannot delete the current version")
+	}
+	return t.db.DropPrefix(t.makeStateKeyPrefix(version))
+}
+
 func (n *DBNode) MarshalJSON() ([]byte, error) {
 	v, _, err := n.Value(nil, nil)
 	if err != nil {
@@ -1830,29 +1880,374 @@ type Indexer interface {
 	IndexNode(relKeypath Keypath, state Node) (Keypath, Node, error)
 }
 
+// A MultiIndexer indexes each child under any number of index keys rather than
+// a single one, with a separate value under each (e.g., an inverted index of
+// the terms in each child).  The index trees use its IndexEntries method
+// instead of IndexNode.
+type MultiIndexer interface {
+	Indexer
+	IndexEntries(relKeypath Keypath, state Node) (map[string]interface{}, error)
+}
+
+// An Aggregator summarizes the children of a node (e.g., by counting them)
+// rather than re-keying them.  Its IndexEntries method returns the groups that
+// a child belongs to and the value that it contributes to each, and the index
+// holds the Aggregate of each group's values under the group's key.  An
+// aggregator that isn't Grouped puts every child into the group "", whose
+// aggregate is the root of the index.
+type Aggregator interface {
+	MultiIndexer
+	Grouped() bool
+	Aggregate(values []interface{}) (interface{}, error)
+}
+
+// A GroupingIndexer indexes the children of a map under their index key and
+// then their own key (i.e., <indexKey>/<childKey>), so that children that
+// share an index key don't clobber one another.  Other Indexers keep the
+// original layout, in which each index key holds a single child: the last one,
+// in key order, that's indexed under it.  MultiIndexers always group.
+type GroupingIndexer interface {
+	Indexer
+	GroupsChildren() bool
+}
+
+// GroupsChildren returns true if the indexer's indices of maps are grouped by
+// child key (see GroupingIndexer).
+func GroupsChildren(indexer Indexer) bool {
+	if _, is := indexer.(MultiIndexer); is {
+		return true
+	} else if grouping, is := indexer.(GroupingIndexer); is {
+		return grouping.GroupsChildren()
+	}
+	return false
+}
+
+// indexLayoutVersion is stamped on each index when it's built.  It must be
+// bumped whenever the layout of the index trees changes, so that indices built
+// by older code are rebuilt rather than updated in place (see IndexIsCurrent).
+//
+// Version 1 added the refs and members that let indices of maps be updated
+// incrementally, and restored the original single-child layout for indexers
+// that aren't GroupingIndexers.
+const indexLayoutVersion = 1
+
 func prettyJSON(x interface{}) string {
 	j, _ := json.MarshalIndent(x, "", "    ")
 	return string(j)
 }
 
-func (t *VersionedDBTree) BuildIndex(version *Version, node Node, indexName Keypath, indexer Indexer) (err error) {
+// BuildIndex builds the named index of the node at the given keypath from
+// scratch, discarding anything that was previously indexed there.
+//
+// The children of a map are indexed under the key returned by the Indexer.  If
+// the Indexer groups children (see GroupingIndexer), each is indexed under its
+// own key beneath that (i.e., <indexKey>/<childKey>).  Otherwise, every child
+// is recorded in the index's members, grouped that way, and the index holds
+// the last member of each group.  The children of a slice are grouped into a
+// slice under each index key, in their original order, except when the
+// Indexer is a MultiIndexer, in which case they're indexed like the children
+// of a map whose keys are their (decimal) indices.
+func (t *VersionedDBTree) BuildIndex(version *Version, keypath Keypath, node Node, indexName Keypath, indexer Indexer) (err error) {
 	defer errors.Annotate(&err, "BuildIndex")
 
-	// @@TODO: ensure NodeType is map or slice
-	// @@TODO: don't use a map[][] to count children, put it in Badger
-	index := t.IndexAtVersion(version, node.Keypath(), indexName, true)
+	if version == nil {
+		version = &CurrentVersion
+	}
+
+	err = t.DeleteIndex(version, keypath, indexName)
+	if err != nil {
+		return err
+	}
+
+	index := t.IndexAtVersion(version, keypath, indexName, true)
 	defer index.Close()
 
-	iter := node.ChildIterator(nil, true, 10)
-	defer iter.Close()
+	err = index.Set(nil, nil, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	err = index.tx.Set(t.makeIndexLayoutKey(*version, keypath, indexName), []byte{indexLayoutVersion})
+	if err != nil {
+		return err
+	}
 
-	rootNodeType, _, _, err := node.NodeInfo(nil)
+	if aggregator, is := indexer.(Aggregator); is {
+		err = t.buildAggregate(index, *version, keypath, indexName, node, aggregator)
+		if err != nil {
+			return err
+		}
+		return index.Save()
+	}
+
+	nodeType, _, _, err := node.NodeInfo(nil)
+	if errors.Cause(err) == errors.Err404 {
+		return index.Save()
+	} else if err != nil {
+		return err
+	}
+
+	switch nodeType {
+	case NodeTypeMap:
+		refs := t.indexRefsAtVersion(index, *version, keypath, indexName)
+		err = refs.Set(nil, nil, map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		if GroupsChildren(indexer) {
+			err = t.indexMapChildren(index, refs, node, indexer, childKeysOf(node), nil)
+			break
+		}
+		members := t.indexContributionsAtVersion(index, *version, keypath, indexName)
+		err = members.Set(nil, nil, map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		groups := make(map[string]bool)
+		err = t.indexMapChildren(members, refs, node, indexer, childKeysOf(node), groups)
+		if err != nil {
+			return err
+		}
+		err = t.flattenGroups(index, members, groups)
+	case NodeTypeSlice:
+		if multiIndexer, is := indexer.(MultiIndexer); is {
+			err = t.indexSliceChildrenMulti(index, node, multiIndexer, nil)
+		} else {
+			err = t.indexSliceChildren(index, node, indexer)
+		}
+	}
 	if err != nil {
-		t.Error(err)
 		return err
 	}
+	return index.Save()
+}
+
+// UpdateIndex re-indexes the given children of the node at the given keypath
+// after they've been added, changed, or removed.  Indices of slices are
+// rebuilt from scratch, as inserting or removing an element renumbers the
+// ones after it, as are indices that haven't been built yet or were built with
+// an older layout.
+func (t *VersionedDBTree) UpdateIndex(version *Version, keypath Keypath, node Node, indexName Keypath, indexer Indexer, childKeys []Keypath) (err error) {
+	defer errors.Annotate(&err, "UpdateIndex")
+
+	if version == nil {
+		version = &CurrentVersion
+	}
+
+	nodeType, _, _, err := node.NodeInfo(nil)
+	if err != nil && errors.Cause(err) != errors.Err404 {
+		return err
+	} else if nodeType == NodeTypeSlice {
+		return t.BuildIndex(version, keypath, node, indexName, indexer)
+	}
+
+	index := t.IndexAtVersion(version, keypath, indexName, true)
+	defer index.Close()
+
+	current, err := t.IndexIsCurrent(version, keypath, indexName)
+	if err != nil {
+		return err
+	} else if !current {
+		return t.BuildIndex(version, keypath, node, indexName, indexer)
+	}
+
+	refs := t.indexRefsAtVersion(index, *version, keypath, indexName)
+	if aggregator, is := indexer.(Aggregator); is {
+		contributions := t.indexContributionsAtVersion(index, *version, keypath, indexName)
+		groups := make(map[string]bool)
+		err = t.indexMapChildren(contributions, refs, node, indexer, childKeys, groups)
+		if err != nil {
+			return err
+		}
+		err = t.aggregateGroups(index, contributions, aggregator, groups)
+	} else if GroupsChildren(indexer) {
+		err = t.indexMapChildren(index, refs, node, indexer, childKeys, nil)
+	} else {
+		members := t.indexContributionsAtVersion(index, *version, keypath, indexName)
+		groups := make(map[string]bool)
+		err = t.indexMapChildren(members, refs, node, indexer, childKeys, groups)
+		if err != nil {
+			return err
+		}
+		err = t.flattenGroups(index, members, groups)
+	}
+	if err != nil {
+		return err
+	}
+	return index.Save()
+}
+
+// DeleteIndex removes the named index of the node at the given keypath.
+func (t *VersionedDBTree) DeleteIndex(version *Version, keypath Keypath, indexName Keypath) error {
+	if version == nil {
+		version = &CurrentVersion
+	}
+	return t.db.DropPrefix(
+		t.makeIndexKeyPrefix(*version, keypath, indexName),
+		t.makeIndexRefsKeyPrefix(*version, keypath, indexName),
+		t.makeIndexContributionsKeyPrefix(*version, keypath, indexName),
+		t.makeIndexLayoutKey(*version, keypath, indexName),
+	)
+}
+
+// IndexIsCurrent returns true if the named index has been built with the
+// current layout.  Indices that were built by older code (or not at all) need
+// to be rebuilt from scratch before they can be read or updated.
+func (t *VersionedDBTree) IndexIsCurrent(version *Version, keypath Keypath, indexName Keypath) (bool, error) {
+	if version == nil {
+		version = &CurrentVersion
+	}
+	var current bool
+	err := t.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(t.makeIndexLayoutKey(*version, keypath, indexName))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			current = len(val) == 1 && val[0] == indexLayoutVersion
+			return nil
+		})
+	})
+	return current, err
+}
+
+// IndexMembersAtVersion returns the children indexed under each key of the
+// named index, grouped by key (i.e., <indexKey>/<childKey>).  For indices of
+// maps whose Indexer doesn't group children, these are the members that each
+// key's single entry is chosen from.  For all others, it's the index itself.
+func (t *VersionedDBTree) IndexMembersAtVersion(version *Version, keypath Keypath, indexName Keypath) (*DBNode, error) {
+	if version == nil {
+		version = &CurrentVersion
+	}
+	index := t.IndexAtVersion(version, keypath, indexName, false)
+	members := t.indexContributionsAtVersion(index, *version, keypath, indexName)
+
+	exists, err := members.Exists(nil)
+	if err != nil {
+		index.Close()
+		return nil, err
+	} else if exists {
+		return members, nil
+	}
+	return index, nil
+}
+
+// indexMapChildren re-indexes the given children of a map.  If touched isn't
+// nil, the index keys that were added to or removed from are recorded in it.
+func (t *VersionedDBTree) indexMapChildren(index, refs *DBNode, node Node, indexer Indexer, childKeys []Keypath, touched map[string]bool) error {
+	for _, childKey := range childKeys {
+		oldIndexKeys, exists, err := refs.SliceValue(childKey)
+		if err != nil {
+			return err
+		} else if exists {
+			for _, x := range oldIndexKeys {
+				oldIndexKey := Keypath(x.(string))
+				if touched != nil {
+					touched[string(oldIndexKey)] = true
+				}
+
+				// Don't leave empty index keys behind
+				oldKeypath := oldIndexKey.Push(childKey)
+				_, _, length, err := index.NodeInfo(oldIndexKey)
+				if err != nil {
+					return err
+				} else if length <= 1 {
+					oldKeypath = oldIndexKey
+				}
+				err = index.Delete(oldKeypath, nil)
+				if err != nil {
+					return err
+				}
+			}
+			err = refs.Delete(childKey, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		exists, err = node.Exists(childKey)
+		if err != nil {
+			return err
+		} else if !exists {
+			continue
+		}
+
+		entries, err := indexEntries(indexer, childKey, node.NodeAt(childKey, nil))
+		if err != nil {
+			return err
+		} else if len(entries) == 0 {
+			continue
+		}
+
+		indexKeys := make([]string, 0, len(entries))
+		for indexKey := range entries {
+			indexKeys = append(indexKeys, indexKey)
+		}
+		sort.Strings(indexKeys)
+
+		refVal := make([]interface{}, len(indexKeys))
+		for i, indexKey := range indexKeys {
+			// Setting a keypath whose parent doesn't exist yet would leave the
+			// index's root map with the wrong length
+			exists, err = index.Exists(Keypath(indexKey))
+			if err != nil {
+				return err
+			} else if exists {
+				err = index.Set(Keypath(indexKey).Push(childKey), nil, entries[indexKey])
+			} else {
+				err = index.Set(Keypath(indexKey), nil, map[string]interface{}{string(childKey): entries[indexKey]})
+			}
+			if err != nil {
+				return err
+			}
+			refVal[i] = indexKey
+			if touched != nil {
+				touched[indexKey] = true
+			}
+		}
+		err = refs.Set(childKey, nil, refVal)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexEntries returns the index keys of a child and the value to store under
+// each of them.
+func indexEntries(indexer Indexer, childKey Keypath, childNode Node) (map[string]interface{}, error) {
+	if multiIndexer, is := indexer.(MultiIndexer); is {
+		return multiIndexer.IndexEntries(childKey, childNode)
+	}
+
+	indexKey, indexNode, err := indexer.IndexNode(childKey, childNode)
+	if err != nil {
+		return nil, err
+	} else if indexKey == nil || indexNode == nil {
+		return nil, nil
+	}
+	return map[string]interface{}{string(indexKey): indexNode}, nil
+}
+
+func childKeysOf(node Node) []Keypath {
+	iter := node.ChildIterator(nil, false, 10)
+	defer iter.Close()
+
+	var childKeys []Keypath
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		childKeys = append(childKeys, iter.Node().Keypath().RelativeTo(node.Keypath()).Copy())
+	}
+	return childKeys
+}
+
+func (t *VersionedDBTree) indexSliceChildren(index *DBNode, node Node, indexer Indexer) error {
+	var indexKeys []Keypath
+	groups := make(map[string][]interface{})
+
+	iter := node.ChildIterator(nil, true, 10)
+	defer iter.Close()
 
-	children := make(map[string]map[string]struct{})
 	for iter.Rewind(); iter.Valid(); iter.Next() {
 		childNode := iter.Node()
 		relKeypath := childNode.Keypath().RelativeTo(node.Keypath())
@@ -1864,53 +2259,185 @@ func (t *VersionedDBTree) BuildIndex(version *Version, node Node, indexName Keyp
 			continue
 		}
 
-		if _, exists := children[string(indexKey)]; !exists {
-			children[string(indexKey)] = make(map[string]struct{})
+		val, _, err := indexNode.Value(nil, nil)
+		if err != nil {
+			return err
 		}
-		children[string(indexKey)][string(relKeypath.Part(0))] = struct{}{}
-
-		if rootNodeType == NodeTypeSlice {
-			// If it's a slice, we have to renumber its children
-			newIdx := uint64(len(children[string(indexKey)])) - 1
-			_, rest := relKeypath.Shift()
-			relKeypath = rest.Unshift(EncodeSliceIndex(newIdx))
-
-		} else if rootNodeType == NodeTypeMap {
-			// If it's a map, we have to replace the root key with the indexKey
-			_, rest := relKeypath.Shift()
-			relKeypath = rest.Unshift(indexKey)
+		if _, exists := groups[string(indexKey)]; !exists {
+			indexKeys = append(indexKeys, indexKey.Copy())
 		}
+		groups[string(indexKey)] = append(groups[string(indexKey)], val)
+	}
 
-		err = index.Set(relKeypath, nil, indexNode)
+	for _, indexKey := range indexKeys {
+		err := index.Set(indexKey, nil, groups[string(indexKey)])
 		if err != nil {
-			t.Error(err)
 			return err
 		}
 	}
+	return nil
+}
 
-	// Set the index's node types to the type of the original keypath being indexed
-	for indexKey, child := range children {
-		encoded, err := encodeNode(rootNodeType, 0, uint64(len(child)), nil)
+func (t *VersionedDBTree) indexSliceChildrenMulti(index *DBNode, node Node, indexer MultiIndexer, touched map[string]bool) error {
+	groups := make(map[string]map[string]interface{})
+
+	iter := node.ChildIterator(nil, true, 10)
+	defer iter.Close()
+
+	var i uint64
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		childNode := iter.Node()
+		relKeypath := childNode.Keypath().RelativeTo(node.Keypath())
+
+		entries, err := indexer.IndexEntries(relKeypath, childNode)
 		if err != nil {
 			return err
 		}
+		for indexKey, val := range entries {
+			if _, exists := groups[indexKey]; !exists {
+				groups[indexKey] = make(map[string]interface{})
+			}
+			groups[indexKey][strconv.FormatUint(i, 10)] = val
+		}
+		i++
+	}
 
-		err = index.tx.Set(index.addKeyPrefix(Keypath(indexKey)), encoded)
+	for indexKey, group := range groups {
+		err := index.Set(Keypath(indexKey), nil, group)
 		if err != nil {
 			return err
 		}
+		if touched != nil {
+			touched[indexKey] = true
+		}
 	}
+	return nil
+}
 
-	// Set the root value of the index to a NodeTypeMap
-	encoded, err := encodeNode(NodeTypeMap, 0, 0, nil)
+func (t *VersionedDBTree) buildAggregate(index *DBNode, version Version, keypath Keypath, indexName Keypath, node Node, aggregator Aggregator) error {
+	contributions := t.indexContributionsAtVersion(index, version, keypath, indexName)
+	err := contributions.Set(nil, nil, map[string]interface{}{})
 	if err != nil {
 		return err
 	}
-	err = index.tx.Set(index.addKeyPrefix(nil), encoded)
+
+	groups := make(map[string]bool)
+	if !aggregator.Grouped() {
+		groups[""] = true
+	}
+
+	nodeType, _, _, err := node.NodeInfo(nil)
+	if errors.Cause(err) == errors.Err404 {
+		return t.aggregateGroups(index, contributions, aggregator, groups)
+	} else if err != nil {
+		return err
+	}
+
+	switch nodeType {
+	case NodeTypeMap:
+		refs := t.indexRefsAtVersion(index, version, keypath, indexName)
+		err = refs.Set(nil, nil, map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		err = t.indexMapChildren(contributions, refs, node, aggregator, childKeysOf(node), groups)
+	case NodeTypeSlice:
+		err = t.indexSliceChildrenMulti(contributions, node, aggregator, groups)
+	}
 	if err != nil {
 		return err
 	}
-	return index.Save()
+	return t.aggregateGroups(index, contributions, aggregator, groups)
+}
+
+// aggregateGroups recomputes the aggregates of the given groups from their
+// members' contributions.  Groups without any members are removed, except for
+// the group "", which is the root of the index.
+func (t *VersionedDBTree) aggregateGroups(index, contributions *DBNode, aggregator Aggregator, groups map[string]bool) error {
+	for group := range groups {
+		exists, err := contributions.Exists(Keypath(group))
+		if err != nil {
+			return err
+		}
+
+		var values []interface{}
+		if exists {
+			iter := contributions.ChildIterator(Keypath(group), true, 10)
+			for iter.Rewind(); iter.Valid(); iter.Next() {
+				val, _, err := iter.Node().Value(nil, nil)
+				if err != nil {
+					iter.Close()
+					return err
+				}
+				values = append(values, val)
+			}
+			iter.Close()
+		}
+
+		if len(values) == 0 && group != "" {
+			exists, err := index.Exists(Keypath(group))
+			if err != nil {
+				return err
+			} else if exists {
+				err = index.Delete(Keypath(group), nil)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		aggregate, err := aggregator.Aggregate(values)
+		if err != nil {
+			return err
+		}
+		err = index.Set(Keypath(group), nil, aggregate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenGroups sets each of the given index keys to the last of its members,
+// in key order.  Keys without any members are removed.
+func (t *VersionedDBTree) flattenGroups(index, members *DBNode, groups map[string]bool) error {
+	for group := range groups {
+		var (
+			val    interface{}
+			exists bool
+		)
+		iter := members.ChildIterator(Keypath(group), true, 10)
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			var err error
+			val, _, err = iter.Node().Value(nil, nil)
+			if err != nil {
+				iter.Close()
+				return err
+			}
+			exists = true
+		}
+		iter.Close()
+
+		if exists {
+			err := index.Set(Keypath(group), nil, val)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		exists, err := index.Exists(Keypath(group))
+		if err != nil {
+			return err
+		} else if exists {
+			err = index.Delete(Keypath(group), nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func (n *DBNode) scanChildrenForward(
@@ -1027,3 +1027,237 @@ func update(t *state.VersionedDBTree, v *state.Version, fn func(*state.DBNode) e
 	}
 	return nil
 }
+
+type fieldIndexer struct {
+	field state.Keypath
+}
+
+func (i fieldIndexer) IndexNode(relKeypath state.Keypath, node state.Node) (state.Keypath, state.Node, error) {
+	val, exists, err := node.StringValue(i.field)
+	if err != nil || !exists {
+		return nil, nil, err
+	}
+	return state.Keypath(val), node, nil
+}
+
+type groupingFieldIndexer struct {
+	fieldIndexer
+}
+
+func (i groupingFieldIndexer) GroupsChildren() bool {
+	return true
+}
+
+func TestVersionedDBTree_Index(t *testing.T) {
+	db := testutils.SetupVersionedDBTree(t)
+	defer db.DeleteDB()
+
+	indexer := fieldIndexer{field: state.Keypath("author")}
+	indexName := state.Keypath("author")
+
+	set := func(t *testing.T, keypath state.Keypath, val interface{}) {
+		t.Helper()
+		node := db.StateAtVersion(nil, true)
+		defer node.Close()
+		require.NoError(t, node.Set(keypath, nil, val))
+		require.NoError(t, node.Save())
+	}
+
+	requireIndex := func(t *testing.T, keypath state.Keypath, expected interface{}) {
+		t.Helper()
+		index := db.IndexAtVersion(nil, keypath, indexName, false)
+		defer index.Close()
+		val, exists, err := index.Value(nil, nil)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, expected, val)
+	}
+
+	buildIndex := func(t *testing.T, keypath state.Keypath, indexer state.Indexer) {
+		t.Helper()
+		node := db.StateAtVersion(nil, false)
+		defer node.Close()
+		require.NoError(t, db.BuildIndex(nil, keypath, node.NodeAt(keypath, nil), indexName, indexer))
+	}
+
+	updateIndex := func(t *testing.T, keypath state.Keypath, indexer state.Indexer, childKeys ...string) {
+		t.Helper()
+		var keys []state.Keypath
+		for _, k := range childKeys {
+			keys = append(keys, state.Keypath(k))
+		}
+		node := db.StateAtVersion(nil, false)
+		defer node.Close()
+		require.NoError(t, db.UpdateIndex(nil, keypath, node.NodeAt(keypath, nil), indexName, indexer, keys))
+	}
+
+	deleteChildren := func(t *testing.T, keypath state.Keypath, childKeys ...string) {
+		t.Helper()
+		node := db.StateAtVersion(nil, true)
+		defer node.Close()
+		for _, k := range childKeys {
+			require.NoError(t, node.Delete(keypath.Push(state.Keypath(k)), nil))
+		}
+		require.NoError(t, node.Save())
+	}
+
+	requireMembers := func(t *testing.T, keypath state.Keypath, expected interface{}) {
+		t.Helper()
+		members, err := db.IndexMembersAtVersion(nil, keypath, indexName)
+		require.NoError(t, err)
+		defer members.Close()
+		val, exists, err := members.Value(nil, nil)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, expected, val)
+	}
+
+	messages := M{
+		"a": M{"author": "alice", "text": "hi"},
+		"b": M{"author": "bob", "text": "yo"},
+		"c": M{"author": "alice", "text": "sup"},
+		"d": M{"text": "anonymous"},
+	}
+
+	t.Run("maps", func(t *testing.T) {
+		keypath := state.Keypath("messages")
+		set(t, keypath, messages)
+
+		current, err := db.IndexIsCurrent(nil, keypath, indexName)
+		require.NoError(t, err)
+		require.False(t, current)
+
+		// Each index key holds the last child (in key order) that's indexed
+		// under it
+		buildIndex(t, keypath, indexer)
+		requireIndex(t, keypath, M{
+			"alice": M{"author": "alice", "text": "sup"},
+			"bob":   M{"author": "bob", "text": "yo"},
+		})
+		requireMembers(t, keypath, M{
+			"alice": M{"a": M{"author": "alice", "text": "hi"}, "c": M{"author": "alice", "text": "sup"}},
+			"bob":   M{"b": M{"author": "bob", "text": "yo"}},
+		})
+
+		current, err = db.IndexIsCurrent(nil, keypath, indexName)
+		require.NoError(t, err)
+		require.True(t, current)
+
+		set(t, keypath.Push(state.Keypath("b/author")), "alice")
+		set(t, keypath.Push(state.Keypath("e")), M{"author": "carol", "text": "new"})
+		updateIndex(t, keypath, indexer, "b", "e")
+		requireIndex(t, keypath, M{
+			"alice": M{"author": "alice", "text": "sup"},
+			"carol": M{"author": "carol", "text": "new"},
+		})
+
+		// Removing the child that a key holds falls back to the next one
+		deleteChildren(t, keypath, "c", "e")
+		updateIndex(t, keypath, indexer, "c", "e")
+		requireIndex(t, keypath, M{
+			"alice": M{"author": "alice", "text": "yo"},
+		})
+		requireMembers(t, keypath, M{
+			"alice": M{"a": M{"author": "alice", "text": "hi"}, "b": M{"author": "alice", "text": "yo"}},
+		})
+
+		require.NoError(t, db.DeleteIndex(nil, keypath, indexName))
+		exists, err := db.IndexAtVersion(nil, keypath, indexName, false).Exists(nil)
+		require.NoError(t, err)
+		require.False(t, exists)
+		current, err = db.IndexIsCurrent(nil, keypath, indexName)
+		require.NoError(t, err)
+		require.False(t, current)
+	})
+
+	t.Run("maps with a grouping indexer", func(t *testing.T) {
+		keypath := state.Keypath("threads")
+		indexer := groupingFieldIndexer{fieldIndexer{field: state.Keypath("author")}}
+		set(t, keypath, messages)
+
+		buildIndex(t, keypath, indexer)
+		requireIndex(t, keypath, M{
+			"alice": M{"a": M{"author": "alice", "text": "hi"}, "c": M{"author": "alice", "text": "sup"}},
+			"bob":   M{"b": M{"author": "bob", "text": "yo"}},
+		})
+
+		set(t, keypath.Push(state.Keypath("b/author")), "alice")
+		set(t, keypath.Push(state.Keypath("e")), M{"author": "carol", "text": "new"})
+		updateIndex(t, keypath, indexer, "b", "e")
+		requireIndex(t, keypath, M{
+			"alice": M{
+				"a": M{"author": "alice", "text": "hi"},
+				"b": M{"author": "alice", "text": "yo"},
+				"c": M{"author": "alice", "text": "sup"},
+			},
+			"carol": M{"e": M{"author": "carol", "text": "new"}},
+		})
+
+		deleteChildren(t, keypath, "a", "e")
+		updateIndex(t, keypath, indexer, "a", "e")
+		requireIndex(t, keypath, M{
+			"alice": M{
+				"b": M{"author": "alice", "text": "yo"},
+				"c": M{"author": "alice", "text": "sup"},
+			},
+		})
+		requireMembers(t, keypath, M{
+			"alice": M{
+				"b": M{"author": "alice", "text": "yo"},
+				"c": M{"author": "alice", "text": "sup"},
+			},
+		})
+	})
+
+	t.Run("indices built with an older layout are rebuilt", func(t *testing.T) {
+		keypath := state.Keypath("old")
+		set(t, keypath, messages)
+
+		// An index in the grouped layout, without a layout stamp or refs
+		index := db.IndexAtVersion(nil, keypath, indexName, true)
+		require.NoError(t, index.Set(nil, nil, M{
+			"alice": M{"a": M{"author": "alice", "text": "hi"}, "c": M{"author": "alice", "text": "sup"}},
+			"bob":   M{"b": M{"author": "bob", "text": "yo"}},
+		}))
+		require.NoError(t, index.Save())
+		index.Close()
+
+		set(t, keypath.Push(state.Keypath("e")), M{"author": "carol", "text": "new"})
+		updateIndex(t, keypath, indexer, "e")
+		requireIndex(t, keypath, M{
+			"alice": M{"author": "alice", "text": "sup"},
+			"bob":   M{"author": "bob", "text": "yo"},
+			"carol": M{"author": "carol", "text": "new"},
+		})
+
+		current, err := db.IndexIsCurrent(nil, keypath, indexName)
+		require.NoError(t, err)
+		require.True(t, current)
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		keypath := state.Keypath("list")
+		set(t, keypath, S{
+			M{"author": "alice", "text": "hi"},
+			M{"author": "bob", "text": "yo"},
+			M{"author": "alice", "text": "sup"},
+		})
+
+		node := db.StateAtVersion(nil, false)
+		require.NoError(t, db.BuildIndex(nil, keypath, node.NodeAt(keypath, nil), indexName, indexer))
+		node.Close()
+		requireIndex(t, keypath, M{
+			"alice": S{M{"author": "alice", "text": "hi"}, M{"author": "alice", "text": "sup"}},
+			"bob":   S{M{"author": "bob", "text": "yo"}},
+		})
+
+		set(t, keypath.PushIndex(0).Push(state.Keypath("author")), "bob")
+		node = db.StateAtVersion(nil, false)
+		require.NoError(t, db.UpdateIndex(nil, keypath, node.NodeAt(keypath, nil), indexName, indexer, []state.Keypath{state.EncodeSliceIndex(0)}))
+		node.Close()
+		requireIndex(t, keypath, M{
+			"alice": S{M{"author": "alice", "text": "sup"}},
+			"bob":   S{M{"author": "bob", "text": "hi"}, M{"author": "bob", "text": "yo"}},
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package braidhttp
+
+var MergePatchToPatches = mergePatchToPatches
+
+// HTTPSubscriptions returns the number of HTTP subscriptions to the given
+// state URI that the transport is tracking, across every session.
+func HTTPSubscriptions(tpt interface{}, stateURI string) int {
+	t := tpt.(*transport)
+	t.httpSubscriptionsMu.Lock()
+	defer t.httpSubscriptionsMu.Unlock()
+
+	var n int
+	for _, subs := range t.httpSubscriptions {
+		n += len(subs[stateURI])
+	}
+	return n
+}
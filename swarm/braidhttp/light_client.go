@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
@@ -24,6 +25,7 @@ import (
 	"redwood.dev/errors"
 	"redwood.dev/state"
 	"redwood.dev/swarm"
+	"redwood.dev/swarm/prototree"
 	"redwood.dev/tree"
 	"redwood.dev/types"
 	"redwood.dev/utils"
@@ -123,7 +125,7 @@ func (c *LightClient) Subscribe(ctx context.Context, stateURI string) (chan Mayb
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	req.Header.Set("Subscribe", "true")
+	req.Header.Set("Subscribe", prototree.SubscriptionType_Txs.String())
 	req.Header.Set("State-URI", stateURI)
 
 	resp, err := client.Do(req)
@@ -132,12 +134,13 @@ func (c *LightClient) Subscribe(ctx context.Context, stateURI string) (chan Mayb
 	} else if resp.StatusCode != 200 {
 		return nil, errors.Errorf("error subscribing: (%v) %v", resp.StatusCode, resp.Status)
 	}
-	defer resp.Body.Close()
 
 	ch := make(chan MaybeTx)
 	go func() {
 		defer close(ch)
+		defer resp.Body.Close()
 
+		r := bufio.NewReader(resp.Body)
 		for {
 			select {
 			case <-ctx.Done():
@@ -145,27 +148,63 @@ func (c *LightClient) Subscribe(ctx context.Context, stateURI string) (chan Mayb
 			default:
 			}
 
-			var tx tree.Tx
-			r := bufio.NewReader(resp.Body)
 			bs, err := r.ReadBytes(byte('\n'))
-			if err != nil {
+			if err == io.EOF {
+				// The server ended the subscription (i.e., we called Unsubscribe)
+				return
+			} else if err != nil {
 				ch <- MaybeTx{Err: err}
-				continue
+				return
 			}
+
+			// Messages are sent using HTTP's SSE format
+			bs = bytes.TrimPrefix(bs, []byte("data: "))
 			bs = bytes.Trim(bs, "\n ")
+			if len(bs) == 0 {
+				continue
+			}
 
-			err = json.Unmarshal(bs, &tx)
+			var msg prototree.SubscriptionMsg
+			err = json.Unmarshal(bs, &msg)
 			if err != nil {
 				ch <- MaybeTx{Err: err}
 				continue
+			} else if msg.Tx == nil {
+				continue
 			}
 
-			ch <- MaybeTx{Tx: &tx}
+			ch <- MaybeTx{Tx: msg.Tx}
 		}
 	}()
 	return ch, nil
 }
 
+// Unsubscribe ends this client's subscription to the given state URI.  The
+// server identifies the subscription by the session cookie it issued when we
+// subscribed.
+func (c *LightClient) Unsubscribe(stateURI string) error {
+	client := c.client()
+
+	req, err := http.NewRequest("FORGET", c.dialAddr, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("State-URI", stateURI)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return errors.Err404
+	} else if resp.StatusCode != 200 {
+		return errors.Errorf("error unsubscribing: (%v) %v", resp.StatusCode, resp.Status)
+	}
+	return nil
+}
+
 func (c *LightClient) FetchTx(stateURI string, txID state.Version) (*tree.Tx, error) {
 	client := c.client()
 	req, err := http.NewRequest("GET", c.dialAddr+"/__tx/"+txID.Hex(), nil)
@@ -193,6 +232,70 @@ func (c *LightClient) FetchTx(stateURI string, txID state.Version) (*tree.Tx, er
 	return &tx, nil
 }
 
+// FetchTxSpan fetches the txs connecting `parents` to `version` (or to the
+// peer's current leaves, if `version` is nil) in topological order.  Clients
+// with no history can pass the genesis tx ID as the only parent, which fetches
+// the entire history (including the genesis tx itself).
+func (c *LightClient) FetchTxSpan(stateURI string, parents []state.Version, version *state.Version) ([]tree.Tx, error) {
+	if len(parents) == 0 {
+		return nil, errors.New("a span needs at least one parent")
+	}
+
+	req, err := http.NewRequest("GET", c.dialAddr, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if stateURI != "" {
+		req.Header.Set("State-URI", stateURI)
+	}
+	parentStrs := make([]string, len(parents))
+	for i, parentID := range parents {
+		parentStrs[i] = parentID.Hex()
+	}
+	req.Header.Set("Parents", strings.Join(parentStrs, ","))
+	if version != nil {
+		req.Header.Set("Version", version.Hex())
+	}
+
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, errors.Err404
+	} else if resp.StatusCode != 200 {
+		return nil, errors.Errorf("error fetching span: (%v) %v", resp.StatusCode, resp.Status)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if mediaType != "multipart/mixed" {
+		return nil, errors.Errorf("error fetching span: unexpected Content-Type '%v'", mediaType)
+	}
+
+	var txs []tree.Tx
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return txs, nil
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		var tx tree.Tx
+		err = json.NewDecoder(part).Decode(&tx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		txs = append(txs, tx)
+	}
+}
+
 type HeadResponse struct {
 	StateURI       string
 	Parents        []state.Version
@@ -8,6 +8,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"redwood.dev/blob"
@@ -50,7 +51,7 @@ func (p *peerConn) EnsureConnected(ctx context.Context) error {
 	return nil
 }
 
-func (p *peerConn) Subscribe(ctx context.Context, stateURI string) (_ prototree.ReadableSubscription, err error) {
+func (p *peerConn) Subscribe(ctx context.Context, stateURI string, keypath state.Keypath) (_ prototree.ReadableSubscription, err error) {
 	defer func() { p.UpdateConnStats(err == nil) }()
 
 	if p.DialInfo().DialAddr == "" {
@@ -62,6 +63,9 @@ func (p *peerConn) Subscribe(ctx context.Context, stateURI string) (_ prototree.
 		return nil, err
 	}
 	req.Header.Set("State-URI", stateURI)
+	if len(keypath) > 0 {
+		req.Header.Set("Keypath", keypath.String())
+	}
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Connection", "keep-alive")
@@ -72,6 +76,19 @@ func (p *peerConn) Subscribe(ctx context.Context, stateURI string) (_ prototree.
 	}
 	req.Header.Set("Subscribe", string(subTypeBytes))
 
+	// Send our leaves so that the peer only sends the txs we're missing
+	leaves, err := p.t.controllerHub.Leaves(stateURI)
+	if err != nil {
+		return nil, err
+	}
+	if len(leaves) > 0 {
+		leafStrs := make([]string, len(leaves))
+		for i, leafID := range leaves {
+			leafStrs[i] = leafID.Hex()
+		}
+		req.Header.Set("Known-Leaves", strings.Join(leafStrs, ","))
+	}
+
 	resp, err := p.t.doRequest(req)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error subscribing to peer (%v) (state URI: %v)", p.DialInfo().DialAddr, stateURI)
@@ -182,6 +199,54 @@ func (p *peerConn) AnnounceP2PStateURI(ctx context.Context, stateURI string) (er
 	return nil
 }
 
+func (p *peerConn) FetchTxs(ctx context.Context, stateURI string, txIDs []state.Version) (_ prototree.FetchTxsResponse, err error) {
+	defer func() { p.UpdateConnStats(err == nil) }()
+
+	if p.DialInfo().DialAddr == "" {
+		return prototree.FetchTxsResponse{}, errors.New("peer has no DialAddr")
+	}
+
+	txIDStrs := make([]string, len(txIDs))
+	for i, txID := range txIDs {
+		txIDStrs[i] = txID.Hex()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(p.DialInfo().DialAddr, "/")+"/__txs", nil)
+	if err != nil {
+		return prototree.FetchTxsResponse{}, err
+	}
+	req.Header.Set("State-URI", stateURI)
+	req.Header.Set("Tx-IDs", strings.Join(txIDStrs, ","))
+
+	resp, err := p.doRequest(req)
+	if err != nil {
+		return prototree.FetchTxsResponse{}, errors.Wrapf(err, "error fetching txs from peer (%v)", p.DialInfo().DialAddr)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return prototree.FetchTxsResponse{}, errors.Errorf("error fetching txs from peer (%v): %v", p.DialInfo().DialAddr, resp.Status)
+	}
+
+	var fetchTxsResp prototree.FetchTxsResponse
+	err = json.NewDecoder(resp.Body).Decode(&fetchTxsResp)
+	if err != nil {
+		return prototree.FetchTxsResponse{}, errors.WithStack(err)
+	}
+	return fetchTxsResp, nil
+}
+
+func (p *peerConn) RespondFetchTxs(resp prototree.FetchTxsResponse) (err error) {
+	defer func() { p.UpdateConnStats(err == nil) }()
+
+	err = json.NewEncoder(p.stream.Writer).Encode(resp)
+	if err != nil {
+		http.Error(p.stream.Writer.(http.ResponseWriter), err.Error(), http.StatusInternalServerError)
+		return err
+	}
+	return nil
+}
+
 func (p *peerConn) ChallengeIdentity(challengeMsg protoauth.ChallengeMsg) (err error) {
 	defer errors.AddStack(&err)
 	defer func() { p.UpdateConnStats(err == nil) }()
@@ -13,14 +13,18 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
+	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httputil"
+	"net/textproto"
 	"net/url"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/multierr"
@@ -41,6 +45,7 @@ import (
 	"redwood.dev/tree"
 	"redwood.dev/tree/nelson"
 	"redwood.dev/types"
+	"redwood.dev/ucan"
 	"redwood.dev/utils"
 )
 
@@ -70,7 +75,7 @@ type transport struct {
 	tlsKeyFilename  string
 	tlsCerts        []tls.Certificate
 	cookieJar       http.CookieJar
-	jwtSecret       []byte
+	ucanVerifier    *ucan.Verifier
 	devMode         bool
 
 	srv        *http.Server
@@ -79,6 +84,9 @@ type transport struct {
 
 	pendingAuthorizations map[types.ID][]byte
 
+	httpSubscriptions   map[types.ID]map[string]map[*httpWritableSubscription]struct{} // map[sessionID]map[stateURI]
+	httpSubscriptionsMu sync.Mutex
+
 	treeACL   prototree.ACL
 	peerStore swarm.PeerStore
 	keyStore  identity.KeyStore
@@ -102,7 +110,7 @@ func NewTransport(
 	peerStore swarm.PeerStore,
 	tlsCertFilename, tlsKeyFilename string,
 	tlsCerts []tls.Certificate,
-	jwtSecret []byte,
+	ucanVerifier *ucan.Verifier,
 	devMode bool,
 ) (*transport, error) {
 	t := &transport{
@@ -115,14 +123,16 @@ func NewTransport(
 		tlsCertFilename:       tlsCertFilename,
 		tlsKeyFilename:        tlsKeyFilename,
 		tlsCerts:              tlsCerts,
-		jwtSecret:             jwtSecret,
+		ucanVerifier:          ucanVerifier,
 		devMode:               devMode,
 		pendingAuthorizations: make(map[types.ID][]byte),
+		httpSubscriptions:     make(map[types.ID]map[string]map[*httpWritableSubscription]struct{}),
 		ownURLs:               ownURLs,
 		keyStore:              keyStore,
 		blobStore:             blobStore,
 		peerStore:             peerStore,
 	}
+	t.treeACL = prototree.DefaultACL{ControllerHub: controllerHub, UCANVerifier: ucanVerifier}
 	return t, nil
 }
 
@@ -248,12 +258,18 @@ func (t *transport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	address, err := t.addressFromRequest(r)
-	if err != nil {
+	address, u, err := t.addressFromRequest(r)
+	if errors.Cause(err) == errors.Err403 {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
 		t.Errorf("err: %v", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if u != nil {
+		r = r.WithContext(ucan.NewContext(r.Context(), u))
+	}
 
 	duID := sessionID.Hex()
 	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
@@ -310,25 +326,34 @@ func (t *transport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				// @@TODO: this is hacky
 				t.serveRedwoodJS(w, r)
 			} else if strings.HasPrefix(r.URL.Path, "/__tx/") {
-				t.serveGetTx(w, r)
+				t.serveGetTx(w, r, address)
+			} else if r.URL.Path == "/__txs" {
+				t.serveFetchTxs(w, r, peerConn)
+			} else if r.Header.Get("Parents") != "" {
+				t.serveGetSpan(w, r, address)
 			} else {
-				t.serveGetState(w, r)
+				t.serveGetState(w, r, address)
 			}
 		}
 
 	case "POST":
 		if r.Header.Get("Blob") == "true" {
 			t.servePostBlob(w, r)
+		} else {
+			t.servePostTx(w, r, peerConn, address)
 		}
 
 	case "ACK":
 		t.serveAck(w, r, peerConn)
 
-	case "PUT":
+	case "FORGET":
+		t.serveForget(w, r, sessionID)
+
+	case "PUT", "PATCH":
 		if r.Header.Get("Private") == "true" {
 			t.servePostPrivateTx(w, r, peerConn)
 		} else {
-			t.servePostTx(w, r, peerConn)
+			t.servePostTx(w, r, peerConn, address)
 		}
 
 	default:
@@ -362,44 +387,81 @@ func (t *transport) storeAltSvcHeaderPeers(h http.Header) {
 	}
 }
 
-func (t *transport) addressFromRequest(r *http.Request) (types.Address, error) {
+// addressFromRequest determines who is making the request from the cookie set
+// after they answered an identity challenge.  A UCAN may also be presented, but
+// only by its audience, which proves its key with that same challenge.
+// Presented UCANs are stored as credentials so that the tree protocol's ACL can
+// take them into account.
+func (t *transport) addressFromRequest(r *http.Request) (types.Address, *ucan.UCAN, error) {
 	type request struct {
-		JWT string `header:"Authorization" query:"ucan"`
+		UCAN string `header:"Authorization" query:"ucan"`
 	}
 	var req request
 	err := utils.UnmarshalHTTPRequest(&req, r)
 	if err != nil {
-		return types.Address{}, err
+		return types.Address{}, nil, err
 	}
 
-	claims, exists, err := utils.ParseJWT(req.JWT, t.jwtSecret)
-	if err != nil {
-		return types.Address{}, err
-	} else if !exists {
-		claims, exists, err = utils.ParseJWT(req.JWT, t.jwtSecret)
+	// The "address" cookie is only set once the client has signed one of our
+	// identity challenges (see serveChallengeIdentityCheckResponse)
+	var address types.Address
+	addressBytes, err := t.signedCookie(r, "address")
+	if err == nil {
+		address = types.AddressFromBytes(addressBytes)
+	} else if errors.Cause(err) != errors.Err404 {
+		return types.Address{}, nil, err
+	}
+
+	if req.UCAN != "" {
+		if t.ucanVerifier == nil {
+			return types.Address{}, nil, errors.Wrap(errors.Err403, "ucans are not supported")
+		}
+		u, err := ucan.Parse(req.UCAN)
 		if err != nil {
-			return types.Address{}, err
+			return types.Address{}, nil, errors.Wrapf(errors.Err403, "bad ucan: %v", err)
 		}
-	}
-	if exists {
-		addrHex, ok := claims["address"].(string)
-		if !ok {
-			return types.Address{}, errors.Errorf("jwt does not contain 'address' claim")
+		// A ucan is a bearer token, so whoever presents it must also prove
+		// that they hold its audience's key before we treat them as its audience
+		if address.IsZero() || address != u.Audience() {
+			return types.Address{}, nil, errors.Wrapf(errors.Err403, "ucan audience %v has not been authorized (AUTHORIZE first)", u.Audience().Hex())
 		}
-		addr, err := types.AddressFromHex(addrHex)
+		u, err = t.ucanVerifier.SaveCredential(req.UCAN)
 		if err != nil {
-			return types.Address{}, errors.Wrapf(err, "jwt 'address' claim contains invalid data")
+			return types.Address{}, nil, errors.Wrapf(errors.Err403, "bad ucan: %v", err)
 		}
-		return addr, nil
+		return u.Audience(), u, nil
 	}
+	return address, nil, nil
+}
 
-	addressBytes, err := t.signedCookie(r, "address")
-	if errors.Cause(err) == errors.Err404 {
-		return types.Address{}, nil
+// checkCapability responds with a 403 and returns false if the request carries
+// a UCAN that doesn't grant the given capability.  Requests without a UCAN are
+// subject only to the tree's own access rules.
+func (t *transport) checkCapability(w http.ResponseWriter, r *http.Request, capability ucan.Capability) bool {
+	u, exists := ucan.FromContext(r.Context())
+	if !exists || u.Grants(capability) {
+		return true
+	}
+	http.Error(w, fmt.Sprintf("ucan does not grant %v on %v", capability.Ability, capability.StateURI), http.StatusForbidden)
+	return false
+}
+
+// checkReadAccess enforces the state URI's ACL (its declared policy as well
+// as any read rules set by its validators), exactly as the tree protocol does
+// for subscriptions and fetches.
+func (t *transport) checkReadAccess(w http.ResponseWriter, stateURI string, keypath state.Keypath, address types.Address) bool {
+	allowed, err := t.treeACL.HasReadAccess(stateURI, keypath, types.NewAddressSet([]types.Address{address}))
+	if errors.Cause(err) == tree.ErrNoController {
+		http.Error(w, fmt.Sprintf("not found: %v", err), http.StatusNotFound)
+		return false
 	} else if err != nil {
-		return types.Address{}, err
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	} else if !allowed {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
 	}
-	return types.AddressFromBytes(addressBytes), nil
+	return true
 }
 
 func (t *transport) serveHeadRequest(w http.ResponseWriter, r *http.Request) {
@@ -517,10 +579,12 @@ func (t *transport) serveChallengeIdentityCheckResponse(w http.ResponseWriter, r
 
 func (t *transport) serveHTTPSubscription(w http.ResponseWriter, r *http.Request, sessionID types.ID, address types.Address) {
 	type request struct {
-		StateURI string                     `header:"State-URI" query:"state_uri"         required:"true"`
-		Keypath  state.Keypath              `header:"Keypath"   query:"keypath"`
-		SubType  prototree.SubscriptionType `header:"Subscribe" query:"subscription_type" required:"true"`
-		FromTxID *state.Version             `header:"From-Tx"   query:"from_tx"`
+		StateURI    string                     `header:"State-URI"    query:"state_uri"         required:"true"`
+		Keypath     state.Keypath              `header:"Keypath"      query:"keypath"`
+		SubType     prototree.SubscriptionType `header:"Subscribe"    query:"subscription_type" required:"true"`
+		FromTxID    *state.Version             `header:"From-Tx"      query:"from_tx"`
+		ToTxID      *state.Version             `header:"To-Tx"        query:"to_tx"`
+		KnownLeaves versionsHeader             `header:"Known-Leaves" query:"known_leaves"`
 	}
 
 	var req request
@@ -535,11 +599,18 @@ func (t *transport) serveHTTPSubscription(w http.ResponseWriter, r *http.Request
 		req.StateURI = t.defaultStateURI
 	}
 
+	if !t.checkCapability(w, r, ucan.Capability{StateURI: req.StateURI, Keypath: req.Keypath, Ability: ucan.AbilityRead}) {
+		return
+	}
+
 	t.Infof(0, "incoming http subscription (address: %v, state uri: %v)", address, req.StateURI)
 
-	var fetchHistoryOpts prototree.FetchHistoryOpts
+	fetchHistoryOpts := prototree.FetchHistoryOpts{KnownLeaves: req.KnownLeaves}
 	if req.FromTxID != nil {
-		fetchHistoryOpts = prototree.FetchHistoryOpts{FromTxID: *req.FromTxID}
+		fetchHistoryOpts.FromTxID = *req.FromTxID
+	}
+	if req.ToTxID != nil {
+		fetchHistoryOpts.ToTxID = *req.ToTxID
 	}
 
 	subRequest := prototree.SubscriptionRequest{
@@ -549,8 +620,10 @@ func (t *transport) serveHTTPSubscription(w http.ResponseWriter, r *http.Request
 		FetchHistoryOpts: &fetchHistoryOpts,
 		Addresses:        types.NewAddressSet([]types.Address{address}),
 	}
+	var sub *httpWritableSubscription
 	chSubClosed, err := t.HandleWritableSubscriptionOpened(subRequest, func() (prototree.WritableSubscriptionImpl, error) {
-		return newHTTPWritableSubscription(req.StateURI, w, r), nil
+		sub = newHTTPWritableSubscription(req.StateURI, w, r)
+		return sub, nil
 	})
 	if errors.Cause(err) == errors.Err403 {
 		http.Error(w, err.Error(), http.StatusForbidden)
@@ -563,6 +636,9 @@ func (t *transport) serveHTTPSubscription(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	t.trackHTTPSubscription(sessionID, sub)
+	defer t.untrackHTTPSubscription(sessionID, sub)
+
 	// Block until the subscription is canceled so that net/http doesn't close the connection
 	select {
 	case <-chSubClosed:
@@ -570,12 +646,85 @@ func (t *transport) serveHTTPSubscription(w http.ResponseWriter, r *http.Request
 	}
 }
 
+func (t *transport) trackHTTPSubscription(sessionID types.ID, sub *httpWritableSubscription) {
+	t.httpSubscriptionsMu.Lock()
+	defer t.httpSubscriptionsMu.Unlock()
+
+	if _, exists := t.httpSubscriptions[sessionID]; !exists {
+		t.httpSubscriptions[sessionID] = make(map[string]map[*httpWritableSubscription]struct{})
+	}
+	if _, exists := t.httpSubscriptions[sessionID][sub.stateURI]; !exists {
+		t.httpSubscriptions[sessionID][sub.stateURI] = make(map[*httpWritableSubscription]struct{})
+	}
+	t.httpSubscriptions[sessionID][sub.stateURI][sub] = struct{}{}
+}
+
+func (t *transport) untrackHTTPSubscription(sessionID types.ID, sub *httpWritableSubscription) {
+	t.httpSubscriptionsMu.Lock()
+	defer t.httpSubscriptionsMu.Unlock()
+
+	delete(t.httpSubscriptions[sessionID][sub.stateURI], sub)
+	if len(t.httpSubscriptions[sessionID][sub.stateURI]) == 0 {
+		delete(t.httpSubscriptions[sessionID], sub.stateURI)
+	}
+	if len(t.httpSubscriptions[sessionID]) == 0 {
+		delete(t.httpSubscriptions, sessionID)
+	}
+}
+
+// serveForget ends the requesting session's HTTP subscriptions to a state URI.
+// The session is identified by its sessionid cookie.
+func (t *transport) serveForget(w http.ResponseWriter, r *http.Request, sessionID types.ID) {
+	type request struct {
+		StateURI string `header:"State-URI" query:"state_uri"`
+	}
+
+	var req request
+	err := utils.UnmarshalHTTPRequest(&req, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.StateURI == "" {
+		req.StateURI = t.defaultStateURI
+	}
+
+	subs := func() []*httpWritableSubscription {
+		t.httpSubscriptionsMu.Lock()
+		defer t.httpSubscriptionsMu.Unlock()
+
+		var subs []*httpWritableSubscription
+		for sub := range t.httpSubscriptions[sessionID][req.StateURI] {
+			subs = append(subs, sub)
+		}
+		return subs
+	}()
+	if len(subs) == 0 {
+		http.Error(w, "no subscription to forget", http.StatusNotFound)
+		return
+	}
+
+	t.Infof(0, "forgetting http subscription (session: %v, state uri: %v)", sessionID, req.StateURI)
+
+	// Closing a subscription unblocks serveHTTPSubscription, which untracks it
+	for _, sub := range subs {
+		err = sub.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 func (t *transport) serveWSSubscription(w http.ResponseWriter, r *http.Request, sessionID types.ID, address types.Address) {
 	type request struct {
-		StateURI string                     `header:"State-URI" query:"state_uri"         required:"true"`
-		Keypath  state.Keypath              `header:"Keypath"   query:"keypath"`
-		SubType  prototree.SubscriptionType `header:"Subscribe" query:"subscription_type" required:"true"`
-		FromTxID *state.Version             `header:"From-Tx"   query:"from_tx"`
+		StateURI    string                     `header:"State-URI"    query:"state_uri"         required:"true"`
+		Keypath     state.Keypath              `header:"Keypath"      query:"keypath"`
+		SubType     prototree.SubscriptionType `header:"Subscribe"    query:"subscription_type" required:"true"`
+		FromTxID    *state.Version             `header:"From-Tx"      query:"from_tx"`
+		ToTxID      *state.Version             `header:"To-Tx"        query:"to_tx"`
+		KnownLeaves versionsHeader             `header:"Known-Leaves" query:"known_leaves"`
 	}
 
 	var req request
@@ -589,9 +738,16 @@ func (t *transport) serveWSSubscription(w http.ResponseWriter, r *http.Request,
 		req.StateURI = t.defaultStateURI
 	}
 
-	var fetchHistoryOpts prototree.FetchHistoryOpts
+	if !t.checkCapability(w, r, ucan.Capability{StateURI: req.StateURI, Keypath: req.Keypath, Ability: ucan.AbilityRead}) {
+		return
+	}
+
+	fetchHistoryOpts := prototree.FetchHistoryOpts{KnownLeaves: req.KnownLeaves}
 	if req.FromTxID != nil {
-		fetchHistoryOpts = prototree.FetchHistoryOpts{FromTxID: *req.FromTxID}
+		fetchHistoryOpts.FromTxID = *req.FromTxID
+	}
+	if req.ToTxID != nil {
+		fetchHistoryOpts.ToTxID = *req.ToTxID
 	}
 
 	subRequest := prototree.SubscriptionRequest{
@@ -630,7 +786,7 @@ func (t *transport) serveRedwoodJS(w http.ResponseWriter, r *http.Request) {
 	http.ServeContent(w, r, "./redwood.js", time.Now(), bytes.NewReader(redwoodjs.BrowserSrc))
 }
 
-func (t *transport) serveGetTx(w http.ResponseWriter, r *http.Request) {
+func (t *transport) serveGetTx(w http.ResponseWriter, r *http.Request, address types.Address) {
 	type request struct {
 		StateURI string `header:"State-URI" query:"state_uri" required:"true"`
 	}
@@ -642,6 +798,12 @@ func (t *transport) serveGetTx(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !t.checkCapability(w, r, ucan.Capability{StateURI: req.StateURI, Ability: ucan.AbilityRead}) {
+		return
+	} else if !t.checkReadAccess(w, req.StateURI, nil, address) {
+		return
+	}
+
 	parts := strings.Split(r.URL.Path[1:], "/")
 	txIDStr := parts[1]
 	txID, err := state.VersionFromHex(txIDStr)
@@ -659,6 +821,117 @@ func (t *transport) serveGetTx(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, tx)
 }
 
+// serveFetchTxs responds with the requested txs (at most
+// prototree.MaxFetchTxs), skipping any that we don't have.  Peers use it to
+// fill in the missing parents of txs that they've received.
+func (t *transport) serveFetchTxs(w http.ResponseWriter, r *http.Request, peerConn *peerConn) {
+	type request struct {
+		StateURI string         `header:"State-URI" query:"state_uri" required:"true"`
+		TxIDs    versionsHeader `header:"Tx-IDs"    required:"true"`
+	}
+
+	var req request
+	err := utils.UnmarshalHTTPRequest(&req, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !t.checkCapability(w, r, ucan.Capability{StateURI: req.StateURI, Ability: ucan.AbilityRead}) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	t.HandleFetchTxsReceived(req.StateURI, req.TxIDs, peerConn)
+}
+
+// Span GET: responds with the txs connecting the `Parents` to `Version` (or to
+// the current leaves) in topological order, as a multipart/mixed stream with
+// one JSON-encoded tx per part.  A genesis parent fetches the entire history.
+func (t *transport) serveGetSpan(w http.ResponseWriter, r *http.Request, address types.Address) {
+	type request struct {
+		StateURI string         `header:"State-URI" query:"state_uri"`
+		Parents  versionsHeader `header:"Parents"   required:"true"`
+		Version  state.Version  `header:"Version"`
+	}
+
+	var req request
+	err := utils.UnmarshalHTTPRequest(&req, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.StateURI == "" {
+		req.StateURI = t.defaultStateURI
+	}
+
+	if !t.checkCapability(w, r, ucan.Capability{StateURI: req.StateURI, Ability: ucan.AbilityRead}) {
+		return
+	}
+
+	if !t.checkReadAccess(w, req.StateURI, nil, address) {
+		return
+	}
+
+	// A genesis parent means that the requester has no history (every tx
+	// descends from genesis, so it never excludes anything else)
+	var rng tree.TxRange
+	for _, parentID := range req.Parents {
+		if parentID != tree.GenesisTxID {
+			rng.KnownLeaves = append(rng.KnownLeaves, parentID)
+		}
+	}
+	rng.ToTxID = req.Version
+
+	iter := tree.FetchTxRange(t.controllerHub, req.StateURI, rng)
+	defer iter.Close()
+
+	// Pull the first tx before writing anything so that we can still respond
+	// with an error status
+	tx := iter.Next()
+	if errors.Cause(iter.Error()) == errors.Err404 {
+		http.Error(w, fmt.Sprintf("not found: %v", iter.Error()), http.StatusNotFound)
+		return
+	} else if iter.Error() != nil {
+		http.Error(w, iter.Error().Error(), http.StatusInternalServerError)
+		return
+	}
+
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	t.addParentsHeader(req.StateURI, w)
+
+	for ; tx != nil; tx = iter.Next() {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Type", "application/json")
+		h.Set("Version", tx.ID.Hex())
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			t.Errorf("while writing span: %v", err)
+			return
+		}
+		err = json.NewEncoder(part).Encode(tx)
+		if err != nil {
+			t.Errorf("while writing span: %v", err)
+			return
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	if iter.Error() != nil {
+		// Leave the stream unterminated so that the client sees the failure
+		t.Errorf("while writing span: %v", iter.Error())
+		return
+	}
+
+	err = mw.Close()
+	if err != nil {
+		t.Errorf("while writing span: %v", err)
+	}
+}
+
 type keypathAndRangePath struct {
 	Keypath state.Keypath
 	Range   *state.Range
@@ -722,7 +995,30 @@ func (h *httpRangeHeader) UnmarshalHTTPHeader(header string) error {
 	return nil
 }
 
-func (t *transport) serveGetState(w http.ResponseWriter, r *http.Request) {
+// versionsHeader is a comma-separated list of tx IDs (i.e. "Known-Leaves: abcd...,ef01...")
+type versionsHeader []state.Version
+
+func (h *versionsHeader) UnmarshalHTTPHeader(header string) error {
+	return h.UnmarshalText([]byte(header))
+}
+
+func (h *versionsHeader) UnmarshalText(text []byte) error {
+	*h = nil
+	for _, s := range strings.Split(string(text), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		version, err := state.VersionFromHex(s)
+		if err != nil {
+			return errors.Errorf("bad version list: '%v'", string(text))
+		}
+		*h = append(*h, version)
+	}
+	return nil
+}
+
+func (t *transport) serveGetState(w http.ResponseWriter, r *http.Request, address types.Address) {
 	type request struct {
 		StateURI        string              `header:"State-URI" query:"state_uri"`
 		Version         *state.Version      `header:"Version"`
@@ -749,6 +1045,25 @@ func (t *transport) serveGetState(w http.ResponseWriter, r *http.Request) {
 		rng = req.KeypathAndRange.Range
 	}
 
+	if !t.checkCapability(w, r, ucan.Capability{StateURI: req.StateURI, Keypath: req.KeypathAndRange.Keypath, Ability: ucan.AbilityRead}) {
+		return
+	}
+
+	if !t.checkReadAccess(w, req.StateURI, req.KeypathAndRange.Keypath, address) {
+		return
+	}
+
+	if indexName, query := parseIndexParams(r); indexName != "" {
+		if search := r.URL.Query().Get("search"); search != "" {
+			node, err := t.controllerHub.SearchText(req.StateURI, req.Version, req.KeypathAndRange.Keypath, state.Keypath(indexName), search, rng)
+			t.serveIndexResults(w, req.StateURI, node, err)
+		} else {
+			node, err := t.controllerHub.SearchIndex(req.StateURI, req.Version, req.KeypathAndRange.Keypath, state.Keypath(indexName), query, rng)
+			t.serveIndexResults(w, req.StateURI, node, err)
+		}
+		return
+	}
+
 	var node state.Node
 	var anyMissing bool
 
@@ -839,6 +1154,31 @@ func (t *transport) serveGetState(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (t *transport) serveIndexResults(w http.ResponseWriter, stateURI string, node state.Node, err error) {
+	if errors.Cause(err) == errors.Err404 || errors.Cause(err) == tree.ErrNoController {
+		http.Error(w, fmt.Sprintf("not found: %+v", err), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("error: %+v", err), http.StatusBadRequest)
+		return
+	}
+	defer node.Close()
+
+	val, _, err := node.Value(nil, nil)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: %+v", err), http.StatusInternalServerError)
+		return
+	}
+
+	t.addParentsHeader(stateURI, w)
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(val)
+	if err != nil {
+		t.Errorf("error writing index search results: %v", err)
+	}
+}
+
 func (t *transport) serveAck(w http.ResponseWriter, r *http.Request, peerConn *peerConn) {
 	defer r.Body.Close()
 
@@ -907,7 +1247,7 @@ func (t *transport) servePostBlob(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, StoreBlobResponse{SHA1: sha1Hash, SHA3: sha3Hash})
 }
 
-func (t *transport) servePostTx(w http.ResponseWriter, r *http.Request, peerConn *peerConn) {
+func (t *transport) servePostTx(w http.ResponseWriter, r *http.Request, peerConn *peerConn, address types.Address) {
 	type request struct {
 		StateURI   string
 		Signature  types.Signature
@@ -916,6 +1256,13 @@ func (t *transport) servePostTx(w http.ResponseWriter, r *http.Request, peerConn
 		Checkpoint bool
 	}
 
+	// Braid txs are signed by their senders.  Anything else is a traditional
+	// HTTP write that we have to turn into a tx ourselves.
+	if r.Header.Get("Signature") == "" && r.Header.Get("Patch-Type") != "braid" {
+		t.serveTraditionalTx(w, r, address)
+		return
+	}
+
 	t.Infof(0, "incoming tx")
 
 	var err error
@@ -1044,11 +1391,290 @@ func (t *transport) servePostTx(w http.ResponseWriter, r *http.Request, peerConn
 	tx.From = pubkey.Address()
 	////////////////////////////////
 
+	// Signed txs aren't rate limited: peers replicate txs to us this way, and
+	// we can't tell their relays apart from txs that a client has signed
+	// itself (see tree.RateLimiter)
 	t.Process.Go(nil, "HandleTxReceived", func(ctx context.Context) {
 		t.HandleTxReceived(tx, peerConn)
 	})
 }
 
+// serveTraditionalTx converts a plain JSON write from an authenticated client
+// into a tx.  The tx is signed with the client's identity, so this only works
+// for clients authenticated as one of the identities held by our keystore (for
+// example, an app sharing the node's mnemonic).  A UCAN can narrow what such a
+// client may write, but doesn't let any other identity write this way: other
+// clients must sign their own txs and send them as Braid PUTs.
+//   - PUT replaces the value at the keypath with the body
+//   - PATCH applies the body to the value at the keypath as a JSON merge patch (RFC 7396)
+//   - POST appends the body to the list at the keypath
+//
+// PATCH and POST are applied to the value as of the Parents header.  That's
+// only possible if the parents are the current leaves, or a single version
+// whose state we've kept (i.e., a checkpoint).  Otherwise, the write is
+// rejected with a 409 so that the client can retry it against the current
+// leaves.  If the Version or Parents headers are missing, we assign them
+// (using the current leaves as the parents).
+func (t *transport) serveTraditionalTx(w http.ResponseWriter, r *http.Request, address types.Address) {
+	type request struct {
+		StateURI string              `header:"State-URI" query:"state_uri"`
+		Version  *state.Version      `header:"Version"`
+		Parents  versionsHeader      `header:"Parents"`
+		Keypath  keypathAndRangePath `path:""`
+	}
+
+	var req request
+	err := utils.UnmarshalHTTPRequest(&req, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if req.Keypath.Range != nil {
+		http.Error(w, "ranges are not supported", http.StatusBadRequest)
+		return
+	}
+
+	if req.StateURI == "" {
+		req.StateURI = t.defaultStateURI
+	}
+
+	if address.IsZero() {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	} else if !t.checkCapability(w, r, ucan.Capability{StateURI: req.StateURI, Keypath: req.Keypath.Keypath, Ability: ucan.AbilityWrite}) {
+		return
+	}
+	canSign, err := t.keyStore.IdentityExists(address)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if !canSign {
+		http.Error(w, fmt.Sprintf("cannot sign txs for %v (sign the tx yourself and send a Braid PUT)", address), http.StatusForbidden)
+		return
+	}
+
+	bodyJSON, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var body interface{}
+	err = json.Unmarshal(bodyJSON, &body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("bad JSON body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	keypath := req.Keypath.Keypath.Normalized()
+
+	leaves, err := t.controllerHub.Leaves(req.StateURI)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	parents := []state.Version(req.Parents)
+	if len(parents) == 0 {
+		parents = leaves
+	}
+
+	var current interface{}
+	if r.Method != "PUT" {
+		var available bool
+		current, available, err = t.valueAtParents(req.StateURI, keypath, parents, leaves)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		} else if !available {
+			leafStrs := make([]string, len(leaves))
+			for i, leaf := range leaves {
+				leafStrs[i] = leaf.Hex()
+			}
+			w.Header().Set("Parents", strings.Join(leafStrs, ","))
+			http.Error(w, "the state at the given parents is not available, retry against the current leaves", http.StatusConflict)
+			return
+		}
+	}
+
+	var patches []tree.Patch
+	switch r.Method {
+	case "PUT":
+		patches = []tree.Patch{{Keypath: keypath, ValueJSON: bodyJSON}}
+
+	case "PATCH":
+		patches, err = mergePatchToPatches(keypath, current, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	case "POST":
+		var length uint64
+		if current != nil {
+			list, isList := current.([]interface{})
+			if !isList {
+				http.Error(w, fmt.Sprintf("cannot append to %T", current), http.StatusConflict)
+				return
+			}
+			length = uint64(len(list))
+		}
+		valueJSON, err := json.Marshal([]interface{}{body})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if current == nil {
+			patches = []tree.Patch{{Keypath: keypath, ValueJSON: valueJSON}}
+		} else {
+			patches = []tree.Patch{{Keypath: keypath, Range: &state.Range{Start: length, End: length}, ValueJSON: valueJSON}}
+		}
+	}
+
+	if len(patches) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var txID state.Version
+	if req.Version != nil {
+		txID = *req.Version
+	} else if len(parents) == 0 {
+		txID = tree.GenesisTxID
+	} else {
+		txID = state.RandomVersion()
+	}
+
+	tx := tree.Tx{
+		ID:       txID,
+		Parents:  parents,
+		From:     address,
+		StateURI: req.StateURI,
+		Patches:  patches,
+	}
+	tx.Sig, err = t.keyStore.SignHash(address, tx.Hash())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Infof(0, "incoming traditional %v (state uri: %v, tx: %v)", r.Method, req.StateURI, tx.ID.Pretty())
+
+	err = t.controllerHub.CheckRateLimits(tx)
+	if errors.Cause(err) == tree.ErrRateLimited {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = t.controllerHub.AddTx(tx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	parentStrs := make([]string, len(tx.Parents))
+	for i, parentID := range tx.Parents {
+		parentStrs[i] = parentID.Hex()
+	}
+	w.Header().Set("Version", tx.ID.Hex())
+	w.Header().Set("Parents", strings.Join(parentStrs, ","))
+}
+
+// valueAtParents returns the JSON value at the given keypath in the state as
+// of the given parents, or nil if it doesn't exist.  The state is only
+// available if the parents are the current leaves (in any order), or a single
+// version whose state we've kept.
+func (t *transport) valueAtParents(stateURI string, keypath state.Keypath, parents, leaves []state.Version) (_ interface{}, available bool, _ error) {
+	var version *state.Version
+	if !state.NewVersionSet(parents).Equal(state.NewVersionSet(leaves)) {
+		if len(parents) != 1 {
+			return nil, false, nil
+		}
+		version = &parents[0]
+	}
+
+	node, err := t.controllerHub.StateAtVersion(stateURI, version)
+	if errors.Cause(err) == tree.ErrNoController {
+		return nil, version == nil, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	defer node.Close()
+
+	if version != nil {
+		// Only checkpoints' states are kept
+		exists, err := node.Exists(nil)
+		if err != nil {
+			return nil, false, err
+		} else if !exists {
+			return nil, false, nil
+		}
+	}
+
+	val, exists, err := node.Value(keypath, nil)
+	if errors.Cause(err) == errors.Err404 || (err == nil && !exists) {
+		return nil, true, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+// mergePatchToPatches converts a JSON merge patch into the patches that apply
+// it to the current value at the given keypath.
+func mergePatchToPatches(keypath state.Keypath, current interface{}, mergePatch interface{}) ([]tree.Patch, error) {
+	patchMap, patchIsMap := mergePatch.(map[string]interface{})
+	currentMap, currentIsMap := current.(map[string]interface{})
+
+	if !patchIsMap || !currentIsMap {
+		// Anything other than an object merged into an object replaces the value entirely
+		valueJSON, err := json.Marshal(withoutNulls(mergePatch))
+		if err != nil {
+			return nil, err
+		}
+		return []tree.Patch{{Keypath: keypath, ValueJSON: valueJSON}}, nil
+	}
+
+	keys := make([]string, 0, len(patchMap))
+	for key := range patchMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var patches []tree.Patch
+	for _, key := range keys {
+		val := patchMap[key]
+		if val == nil {
+			if _, exists := currentMap[key]; exists {
+				patches = append(patches, tree.Patch{Keypath: keypath.Push(state.Keypath(key)), ValueJSON: []byte("null")})
+			}
+			continue
+		}
+		subpatches, err := mergePatchToPatches(keypath.Push(state.Keypath(key)), currentMap[key], val)
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, subpatches...)
+	}
+	return patches, nil
+}
+
+// withoutNulls strips null members from objects, which a merge patch treats
+// as deletions.
+func withoutNulls(val interface{}) interface{} {
+	asMap, isMap := val.(map[string]interface{})
+	if !isMap {
+		return val
+	}
+	stripped := make(map[string]interface{}, len(asMap))
+	for key, v := range asMap {
+		if v != nil {
+			stripped[key] = withoutNulls(v)
+		}
+	}
+	return stripped
+}
+
 func (t *transport) servePostPrivateTx(w http.ResponseWriter, r *http.Request, peerConn *peerConn) {
 	t.Infof(0, "incoming private tx")
 
@@ -0,0 +1,699 @@
+package braidhttp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/internal/testutils"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/swarm/braidhttp"
+	hushmocks "redwood.dev/swarm/protohush/mocks"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/ucan"
+	"redwood.dev/utils/badgerutils"
+)
+
+type testNode struct {
+	hub          tree.ControllerHub
+	txStore      tree.TxStore
+	keyStore     identity.KeyStore
+	ucanVerifier *ucan.Verifier
+	transport    braidhttpTransport
+	treeProto    prototree.TreeProtocol
+}
+
+type braidhttpTransport interface {
+	http.Handler
+	swarm.Transport
+}
+
+func setupTestNode(t *testing.T) testNode {
+	t.Helper()
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(filepath.Join(dir, "keys")), identity.InsecureScryptParams)
+	require.NoError(t, keyStore.Unlock("password", ""))
+	t.Cleanup(func() { keyStore.Close() })
+
+	ucanStore, err := ucan.NewStore(testutils.SetupDBTree(t))
+	require.NoError(t, err)
+	ucanVerifier := ucan.NewVerifier(keyStore, ucanStore)
+
+	peerStore := swarm.NewPeerStore(testutils.SetupDBTree(t))
+
+	transport, err := braidhttp.NewTransport("127.0.0.1:0", "127.0.0.1:0", types.NewStringSet(nil), "", hub, keyStore, blobStore, peerStore, "", "", nil, ucanVerifier, false)
+	require.NoError(t, err)
+	require.NoError(t, transport.Start())
+	t.Cleanup(func() { transport.Close() })
+
+	hushProto := new(hushmocks.HushProtocol)
+	hushProto.On("OnGroupMessageEncrypted", prototree.ProtocolName, mock.Anything).Return()
+	hushProto.On("OnGroupMessageDecrypted", prototree.ProtocolName, mock.Anything).Return()
+
+	treeStore, err := prototree.NewStore(testutils.SetupDBTree(t))
+	require.NoError(t, err)
+
+	treeProto := prototree.NewTreeProtocol([]swarm.Transport{transport}, hushProto, hub, txStore, keyStore, peerStore, treeStore, ucanVerifier, protoprune.Quorum{})
+	require.NoError(t, treeProto.Start())
+	t.Cleanup(func() { treeProto.Close() })
+
+	return testNode{hub: hub, txStore: txStore, keyStore: keyStore, ucanVerifier: ucanVerifier, transport: transport, treeProto: treeProto}
+}
+
+func (n testNode) addTx(t *testing.T, tx tree.Tx) {
+	t.Helper()
+	require.NoError(t, n.hub.AddTx(tx))
+	require.Eventually(t, func() bool {
+		tx, err := n.txStore.FetchTx(tx.StateURI, tx.ID)
+		return err == nil && tx.Status == tree.TxStatusValid
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (n testNode) sendGenesis(t *testing.T, stateURI string, sender *crypto.SigKeypair, valueJSON string) {
+	t.Helper()
+	tx := tree.Tx{
+		ID:       tree.GenesisTxID,
+		From:     sender.Address(),
+		StateURI: stateURI,
+		Patches:  []tree.Patch{{ValueJSON: []byte(valueJSON)}},
+	}
+	var err error
+	tx.Sig, err = sender.SignHash(tx.Hash())
+	require.NoError(t, err)
+
+	require.NoError(t, n.hub.AddTx(tx))
+	require.Eventually(t, func() bool {
+		tx, err := n.txStore.FetchTx(stateURI, tx.ID)
+		require.NoError(t, err)
+		return tx.Status == tree.TxStatusValid
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// client holds the cookies that the transport sets, like a browser would.
+type client struct {
+	node    testNode
+	cookies map[string]*http.Cookie
+}
+
+func (c *client) do(t *testing.T, method, stateURI, path string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	return c.doWithBody(t, method, stateURI, path, headers, "")
+}
+
+func (c *client) doWithBody(t *testing.T, method, stateURI, path string, headers map[string]string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if stateURI != "" {
+		req.Header.Set("State-URI", stateURI)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	c.node.transport.ServeHTTP(w, req)
+
+	if c.cookies == nil {
+		c.cookies = make(map[string]*http.Cookie)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		c.cookies[cookie.Name] = cookie
+	}
+	return w
+}
+
+// authorize answers the transport's identity challenge as the given identity.
+func (c *client) authorize(t *testing.T, identity *crypto.SigKeypair) {
+	t.Helper()
+	w := c.do(t, "AUTHORIZE", "", "/", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	challengeHex, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	challenge, err := hex.DecodeString(string(challengeHex))
+	require.NoError(t, err)
+
+	sig, err := identity.SignHash(types.HashBytes(challenge))
+	require.NoError(t, err)
+	w = c.do(t, "AUTHORIZE", "", "/", map[string]string{"Response": hex.EncodeToString(sig)})
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTransport_ReadACL(t *testing.T) {
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	node := setupTestNode(t)
+	anon := &client{node: node}
+
+	requireReadStatus := func(t *testing.T, stateURI string, expected int) {
+		t.Helper()
+		require.Equal(t, expected, anon.do(t, "GET", stateURI, "/messages", nil).Code, "state")
+		require.Equal(t, expected, anon.do(t, "GET", stateURI, "/__tx/"+tree.GenesisTxID.Hex(), nil).Code, "tx")
+		require.Equal(t, expected, anon.do(t, "GET", stateURI, "/", map[string]string{"Parents": state.Version{}.Hex()}).Code, "span")
+	}
+
+	t.Run("public state URIs are readable by anyone", func(t *testing.T) {
+		const stateURI = "foo.bar/public"
+		node.sendGenesis(t, stateURI, alice, `{"messages": ["hi"]}`)
+		require.Equal(t, http.StatusOK, anon.do(t, "GET", stateURI, "/messages", nil).Code)
+		require.Equal(t, http.StatusOK, anon.do(t, "GET", stateURI, "/__tx/"+tree.GenesisTxID.Hex(), nil).Code)
+	})
+
+	t.Run("private state URIs aren't readable by non-members", func(t *testing.T) {
+		const stateURI = "foo.bar/private"
+		node.sendGenesis(t, stateURI, alice, `{"ACL": {"Content-Type": "acl/private"}, "Members": {"`+alice.Address().Hex()+`": true}, "messages": ["hi"]}`)
+		requireReadStatus(t, stateURI, http.StatusForbidden)
+	})
+
+	t.Run("allowlisted state URIs aren't readable by others", func(t *testing.T) {
+		const stateURI = "foo.bar/allowlist"
+		node.sendGenesis(t, stateURI, alice, `{"ACL": {"Content-Type": "acl/allowlist", "readers": ["`+alice.Address().Hex()+`"], "writers": ["`+alice.Address().Hex()+`"]}, "messages": ["hi"]}`)
+		requireReadStatus(t, stateURI, http.StatusForbidden)
+	})
+}
+
+func TestTransport_UCANProofOfPossession(t *testing.T) {
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	mallory, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	const stateURI = "foo.bar/allowlist"
+
+	node := setupTestNode(t)
+	node.sendGenesis(t, stateURI, alice, `{"ACL": {"Content-Type": "acl/allowlist", "readers": ["`+alice.Address().Hex()+`"], "writers": ["`+alice.Address().Hex()+`"]}, "messages": ["hi"]}`)
+
+	readMessages := ucan.Capability{StateURI: stateURI, Keypath: state.Keypath("messages"), Ability: ucan.AbilityRead}
+	aliceToken, err := node.ucanVerifier.Issue(alice.Address(), []ucan.Capability{readMessages}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	get := func(t *testing.T, c *client, token *ucan.UCAN) int {
+		t.Helper()
+		return c.do(t, "GET", stateURI, "/messages", map[string]string{"Authorization": "Bearer " + token.String()}).Code
+	}
+
+	t.Run("ucans are accepted from their audience", func(t *testing.T) {
+		c := &client{node: node}
+		c.authorize(t, alice)
+		require.Equal(t, http.StatusOK, get(t, c, aliceToken))
+	})
+
+	t.Run("ucans are rejected from clients that haven't authorized", func(t *testing.T) {
+		c := &client{node: node}
+		require.Equal(t, http.StatusForbidden, get(t, c, aliceToken))
+	})
+
+	t.Run("ucans are rejected from anyone but their audience", func(t *testing.T) {
+		c := &client{node: node}
+		c.authorize(t, mallory)
+		require.Equal(t, http.StatusForbidden, get(t, c, aliceToken))
+	})
+
+	t.Run("ucans that grant nothing are rejected", func(t *testing.T) {
+		c := &client{node: node}
+		c.authorize(t, mallory)
+		selfIssued, err := ucan.Issue(mallory, mallory.Address(), nil, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, get(t, c, selfIssued))
+	})
+}
+
+func TestMergePatchToPatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    string
+		mergePatch string
+		expected   []string
+	}{
+		{"nested objects are merged", `{"a": 1, "b": {"c": 2}}`, `{"b": {"d": 3}}`, []string{`.b.d = 3`}},
+		{"nulls delete existing keys", `{"a": 1, "b": 2}`, `{"a": null, "c": 3}`, []string{`.a = null`, `.c = 3`}},
+		{"nulls for missing keys are dropped", `{"a": 1}`, `{"b": null}`, nil},
+		{"non-objects replace the value", `{"a": [1, 2]}`, `{"a": [3]}`, []string{`.a = [3]`}},
+		{"objects replacing non-objects have their nulls stripped", `{"a": 1}`, `{"a": {"b": null, "c": 2}}`, []string{`.a = {"c":2}`}},
+		{"patches onto missing values replace them", `null`, `{"a": {"b": null}}`, []string{` = {"a":{}}`}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var current, mergePatch interface{}
+			require.NoError(t, json.Unmarshal([]byte(test.current), &current))
+			require.NoError(t, json.Unmarshal([]byte(test.mergePatch), &mergePatch))
+
+			patches, err := braidhttp.MergePatchToPatches(nil, current, mergePatch)
+			require.NoError(t, err)
+
+			var patchStrs []string
+			for _, patch := range patches {
+				patchStrs = append(patchStrs, patch.String())
+			}
+			require.Equal(t, test.expected, patchStrs)
+		})
+	}
+}
+
+func TestTransport_TraditionalWrites(t *testing.T) {
+	node := setupTestNode(t)
+
+	ident, err := node.keyStore.DefaultPublicIdentity()
+	require.NoError(t, err)
+
+	c := &client{node: node}
+	c.authorize(t, ident.SigKeypair)
+
+	write := func(t *testing.T, method, stateURI, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := c.doWithBody(t, method, stateURI, path, headers, body)
+		if w.Code == http.StatusOK {
+			txID, err := state.VersionFromHex(w.Header().Get("Version"))
+			require.NoError(t, err)
+			require.Eventually(t, func() bool {
+				tx, err := node.txStore.FetchTx(stateURI, txID)
+				return err == nil && tx.Status == tree.TxStatusValid
+			}, 5*time.Second, 10*time.Millisecond)
+		}
+		return w
+	}
+
+	requireState := func(t *testing.T, stateURI string, expected string) {
+		t.Helper()
+		w := c.do(t, "GET", stateURI, "/", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, expected, w.Body.String())
+	}
+
+	tests := []struct {
+		name     string
+		genesis  string
+		method   string
+		path     string
+		body     string
+		status   int
+		expected string
+	}{
+		{"PUT replaces the value", `{"a": 1, "b": {"c": 2}}`, "PUT", "/b", `{"d": 3}`, http.StatusOK, `{"a": 1, "b": {"d": 3}}`},
+		{"PATCH merges into the value", `{"a": 1, "b": {"c": 2}}`, "PATCH", "/b", `{"d": 3}`, http.StatusOK, `{"a": 1, "b": {"c": 2, "d": 3}}`},
+		{"PATCH deletes keys set to null", `{"a": 1, "b": {"c": 2}}`, "PATCH", "/", `{"a": null, "b": {"c": null, "d": 3}}`, http.StatusOK, `{"b": {"d": 3}}`},
+		{"PATCH that changes nothing is a no-op", `{"a": 1}`, "PATCH", "/", `{"b": null}`, http.StatusNoContent, `{"a": 1}`},
+		{"POST appends to the list", `{"messages": ["hi"]}`, "POST", "/messages", `"bye"`, http.StatusOK, `{"messages": ["hi", "bye"]}`},
+		{"POST to a missing value creates the list", `{}`, "POST", "/messages", `{"text": "hi"}`, http.StatusOK, `{"messages": [{"text": "hi"}]}`},
+		{"POST to a non-list is a conflict", `{"messages": "hi"}`, "POST", "/messages", `"bye"`, http.StatusConflict, `{"messages": "hi"}`},
+	}
+
+	for i, test := range tests {
+		stateURI := fmt.Sprintf("foo.bar/traditional-%v", i)
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			node.sendGenesis(t, stateURI, ident.SigKeypair, test.genesis)
+			w := write(t, test.method, stateURI, test.path, test.body, nil)
+			require.Equal(t, test.status, w.Code, w.Body.String())
+			requireState(t, stateURI, test.expected)
+		})
+	}
+
+	t.Run("PATCH and POST are based on the state at the given parents", func(t *testing.T) {
+		const stateURI = "foo.bar/traditional-parents"
+		node.sendGenesis(t, stateURI, ident.SigKeypair, `{"messages": []}`)
+
+		checkpoint := tree.Tx{
+			ID:         state.RandomVersion(),
+			Parents:    []state.Version{tree.GenesisTxID},
+			From:       ident.Address(),
+			StateURI:   stateURI,
+			Patches:    []tree.Patch{{Keypath: state.Keypath("messages"), ValueJSON: []byte(`["hi"]`)}},
+			Checkpoint: true,
+		}
+		checkpoint.Sig, err = ident.SigKeypair.SignHash(checkpoint.Hash())
+		require.NoError(t, err)
+		node.addTx(t, checkpoint)
+
+		w := write(t, "POST", stateURI, "/messages", `"bye"`, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		leaf := w.Header().Get("Version")
+		requireState(t, stateURI, `{"messages": ["hi", "bye"]}`)
+
+		// The checkpoint's state is kept, so the POST appends to its list
+		// rather than to the current one
+		w = write(t, "POST", stateURI, "/messages", `"hello"`, map[string]string{"Parents": checkpoint.ID.Hex()})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Equal(t, checkpoint.ID.Hex(), w.Header().Get("Parents"))
+		txID, err := state.VersionFromHex(w.Header().Get("Version"))
+		require.NoError(t, err)
+		tx, err := node.txStore.FetchTx(stateURI, txID)
+		require.NoError(t, err)
+		require.Equal(t, &state.Range{Start: 1, End: 1}, tx.Patches[0].Range)
+		leaves, err := node.hub.Leaves(stateURI)
+		require.NoError(t, err)
+
+		// The genesis state isn't kept, so a PATCH based on it is rejected
+		w = write(t, "PATCH", stateURI, "/", `{"messages": null}`, map[string]string{"Parents": tree.GenesisTxID.Hex()})
+		require.Equal(t, http.StatusConflict, w.Code)
+		parents := strings.Split(w.Header().Get("Parents"), ",")
+		require.Len(t, parents, len(leaves))
+		require.Contains(t, parents, leaf)
+	})
+
+	t.Run("identities outside of the keystore can't write this way", func(t *testing.T) {
+		const stateURI = "foo.bar/traditional-outsider"
+		node.sendGenesis(t, stateURI, ident.SigKeypair, `{"a": 1}`)
+
+		mallory, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+		outsider := &client{node: node}
+		outsider.authorize(t, mallory)
+
+		w := outsider.doWithBody(t, "PUT", stateURI, "/a", nil, `2`)
+		require.Equal(t, http.StatusForbidden, w.Code)
+		requireState(t, stateURI, `{"a": 1}`)
+	})
+}
+
+func TestLightClient_Unsubscribe(t *testing.T) {
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	bob, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	node := setupTestNode(t)
+	server := httptest.NewServer(node.transport)
+	t.Cleanup(server.Close)
+
+	const stateURI = "foo.bar/forget"
+	node.sendGenesis(t, stateURI, alice, `{"messages": []}`)
+
+	aliceClient, err := braidhttp.NewLightClient(server.URL, alice, nil, false)
+	require.NoError(t, err)
+	bobClient, err := braidhttp.NewLightClient(server.URL, bob, nil, false)
+	require.NoError(t, err)
+
+	// The tree protocol stops awaiting a subscription's closure once it has
+	// removed it from its writable subscriptions
+	treeProtoIsAwaiting := func() bool {
+		for _, name := range node.treeProto.ProcessTree()["goroutines"].([]string) {
+			if strings.HasPrefix(name, "await close "+braidhttp.TransportName+" ("+stateURI+")") {
+				return true
+			}
+		}
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ch, err := aliceClient.Subscribe(ctx, stateURI)
+	require.NoError(t, err)
+
+	select {
+	case maybeTx := <-ch:
+		require.NoError(t, maybeTx.Err)
+		require.Equal(t, tree.GenesisTxID, maybeTx.ID)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the genesis tx")
+	}
+	require.Eventually(t, func() bool { return braidhttp.HTTPSubscriptions(node.transport, stateURI) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.True(t, treeProtoIsAwaiting())
+
+	t.Run("other sessions can't forget the subscription", func(t *testing.T) {
+		err := bobClient.Unsubscribe(stateURI)
+		require.Equal(t, errors.Err404, errors.Cause(err))
+		require.Equal(t, 1, braidhttp.HTTPSubscriptions(node.transport, stateURI))
+		require.True(t, treeProtoIsAwaiting())
+	})
+
+	t.Run("the subscribing session can forget the subscription", func(t *testing.T) {
+		require.NoError(t, aliceClient.Unsubscribe(stateURI))
+
+		select {
+		case maybeTx, open := <-ch:
+			require.False(t, open, "unexpected message: %+v", maybeTx)
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the subscription to end")
+		}
+		require.Eventually(t, func() bool { return braidhttp.HTTPSubscriptions(node.transport, stateURI) == 0 }, 5*time.Second, 10*time.Millisecond)
+		require.Eventually(t, func() bool { return !treeProtoIsAwaiting() }, 5*time.Second, 10*time.Millisecond)
+
+		err := aliceClient.Unsubscribe(stateURI)
+		require.Equal(t, errors.Err404, errors.Cause(err))
+	})
+}
+
+func TestLightClient_FetchTxSpan(t *testing.T) {
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	node := setupTestNode(t)
+	server := httptest.NewServer(node.transport)
+	t.Cleanup(server.Close)
+
+	const stateURI = "foo.bar/span"
+	node.sendGenesis(t, stateURI, alice, `{"messages": []}`)
+
+	child := tree.Tx{
+		ID:       state.RandomVersion(),
+		Parents:  []state.Version{tree.GenesisTxID},
+		From:     alice.Address(),
+		StateURI: stateURI,
+		Patches:  []tree.Patch{{Keypath: state.Keypath("messages"), ValueJSON: []byte(`["hi"]`)}},
+	}
+	child.Sig, err = alice.SignHash(child.Hash())
+	require.NoError(t, err)
+	require.NoError(t, node.hub.AddTx(child))
+	require.Eventually(t, func() bool {
+		tx, err := node.txStore.FetchTx(stateURI, child.ID)
+		return err == nil && tx.Status == tree.TxStatusValid
+	}, 5*time.Second, 10*time.Millisecond)
+
+	c, err := braidhttp.NewLightClient(server.URL, alice, nil, false)
+	require.NoError(t, err)
+
+	txIDs := func(txs []tree.Tx) []state.Version {
+		var ids []state.Version
+		for _, tx := range txs {
+			ids = append(ids, tx.ID)
+		}
+		return ids
+	}
+
+	t.Run("a genesis parent fetches the entire history", func(t *testing.T) {
+		txs, err := c.FetchTxSpan(stateURI, []state.Version{tree.GenesisTxID}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []state.Version{tree.GenesisTxID, child.ID}, txIDs(txs))
+
+		txs, err = c.FetchTxSpan(stateURI, []state.Version{tree.GenesisTxID}, &tree.GenesisTxID)
+		require.NoError(t, err)
+		require.Equal(t, []state.Version{tree.GenesisTxID}, txIDs(txs))
+	})
+
+	t.Run("other parents are excluded along with their ancestors", func(t *testing.T) {
+		txs, err := c.FetchTxSpan(stateURI, []state.Version{child.ID}, nil)
+		require.NoError(t, err)
+		require.Empty(t, txs)
+
+		grandchild := tree.Tx{
+			ID:       state.RandomVersion(),
+			Parents:  []state.Version{child.ID},
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath("messages"), ValueJSON: []byte(`["hi", "bye"]`)}},
+		}
+		grandchild.Sig, err = alice.SignHash(grandchild.Hash())
+		require.NoError(t, err)
+		require.NoError(t, node.hub.AddTx(grandchild))
+		require.Eventually(t, func() bool {
+			tx, err := node.txStore.FetchTx(stateURI, grandchild.ID)
+			return err == nil && tx.Status == tree.TxStatusValid
+		}, 5*time.Second, 10*time.Millisecond)
+
+		txs, err = c.FetchTxSpan(stateURI, []state.Version{child.ID}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []state.Version{grandchild.ID}, txIDs(txs))
+	})
+}
+
+func TestTransport_KeypathSubscriptions(t *testing.T) {
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	node := setupTestNode(t)
+	server := httptest.NewServer(node.transport)
+	t.Cleanup(server.Close)
+
+	const stateURI = "foo.bar/chat"
+	node.sendGenesis(t, stateURI, alice, `{"chat": {"messages": [], "topic": "hi"}, "other": 1}`)
+
+	subscribeSSE := func(t *testing.T) <-chan prototree.SubscriptionMsg {
+		t.Helper()
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("State-URI", stateURI)
+		req.Header.Set("Keypath", "chat/messages")
+		req.Header.Set("Subscribe", "transactions,states")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		t.Cleanup(func() { resp.Body.Close() })
+
+		ch := make(chan prototree.SubscriptionMsg, 10)
+		go func() {
+			defer close(ch)
+			r := bufio.NewReader(resp.Body)
+			for {
+				bs, err := r.ReadBytes('\n')
+				if err != nil {
+					return
+				}
+				bs = bytes.Trim(bytes.TrimPrefix(bs, []byte("data: ")), "\n ")
+				if len(bs) == 0 {
+					continue
+				}
+				var msg prototree.SubscriptionMsg
+				if json.Unmarshal(bs, &msg) == nil {
+					ch <- msg
+				}
+			}
+		}()
+		return ch
+	}
+
+	subscribeWS := func(t *testing.T) <-chan prototree.SubscriptionMsg {
+		t.Helper()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?state_uri=" + stateURI + "&keypath=chat/messages&subscription_type=transactions,states"
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		ch := make(chan prototree.SubscriptionMsg, 10)
+		go func() {
+			defer close(ch)
+			for {
+				_, bs, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				var msg prototree.SubscriptionMsg
+				if json.Unmarshal(bs, &msg) == nil {
+					ch <- msg
+				}
+			}
+		}()
+		return ch
+	}
+
+	subs := map[string]<-chan prototree.SubscriptionMsg{
+		"http": subscribeSSE(t),
+		"ws":   subscribeWS(t),
+	}
+
+	newTx := func(t *testing.T, parent state.Version, patchStr string) tree.Tx {
+		t.Helper()
+		var patch tree.Patch
+		require.NoError(t, patch.UnmarshalText([]byte(patchStr)))
+		tx := tree.Tx{
+			ID:       state.RandomVersion(),
+			Parents:  []state.Version{parent},
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{patch},
+		}
+		tx.Sig, err = alice.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+
+	var (
+		splice    = newTx(t, tree.GenesisTxID, `.chat.messages[0:0] = ["hello"]`)
+		unrelated = newTx(t, splice.ID, `.other = 2`)
+		ancestor  = newTx(t, unrelated.ID, `.chat = {"messages": ["bye"], "topic": "bye"}`)
+	)
+	for _, tx := range []tree.Tx{splice, unrelated, ancestor} {
+		node.addTx(t, tx)
+	}
+
+	for name, ch := range subs {
+		ch := ch
+		t.Run(name, func(t *testing.T) {
+			var msgs []prototree.SubscriptionMsg
+			for len(msgs) < 3 {
+				select {
+				case msg, open := <-ch:
+					require.True(t, open)
+					if msg.Tx != nil {
+						msgs = append(msgs, msg)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("timed out after %v messages", len(msgs))
+				}
+			}
+
+			requireScoped := func(t *testing.T, msg prototree.SubscriptionMsg, txID state.Version, hasRange bool, valueJSON string) {
+				t.Helper()
+				require.Equal(t, txID, msg.Tx.ID)
+				require.Len(t, msg.Tx.Patches, 1)
+				require.Empty(t, msg.Tx.Patches[0].Keypath)
+				require.Equal(t, hasRange, msg.Tx.Patches[0].Range != nil)
+				require.JSONEq(t, valueJSON, string(msg.Tx.Patches[0].ValueJSON))
+			}
+			requireState := func(t *testing.T, msg prototree.SubscriptionMsg, expected interface{}) {
+				t.Helper()
+				require.NotNil(t, msg.State)
+				val, _, err := msg.State.Value(nil, nil)
+				require.NoError(t, err)
+				require.Equal(t, expected, val)
+			}
+
+			// The history replay includes the genesis tx, cut down to the subtree
+			requireScoped(t, msgs[0], tree.GenesisTxID, false, `[]`)
+
+			// The unrelated tx is skipped entirely
+			requireScoped(t, msgs[1], splice.ID, true, `["hello"]`)
+			requireState(t, msgs[1], []interface{}{"hello"})
+
+			requireScoped(t, msgs[2], ancestor.ID, false, `["bye"]`)
+			requireState(t, msgs[2], []interface{}{"bye"})
+		})
+	}
+}
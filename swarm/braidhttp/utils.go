@@ -55,10 +55,28 @@ func parseRawParam(r *http.Request) (bool, error) {
 	return raw, nil
 }
 
-func parseIndexParams(r *http.Request) (string, string) {
-	indexName := r.URL.Query().Get("index")
-	indexArg := r.URL.Query().Get("index_arg")
-	return indexName, indexArg
+// parseIndexParams parses a search of the index named by the "index" param.
+// The "index_arg" param may be given once for each of the index's leading
+// fields, and "index_prefix", "index_start", and "index_end" constrain the
+// field after them (see tree.IndexQuery).  Full-text indices are searched
+// with the "search" param instead.
+func parseIndexParams(r *http.Request) (string, tree.IndexQuery) {
+	params := r.URL.Query()
+
+	var query tree.IndexQuery
+	for _, arg := range params["index_arg"] {
+		query.Args = append(query.Args, arg)
+	}
+	if v := params.Get("index_prefix"); v != "" {
+		query.Prefix = v
+	}
+	if v := params.Get("index_start"); v != "" {
+		query.Start = v
+	}
+	if v := params.Get("index_end"); v != "" {
+		query.End = v
+	}
+	return params.Get("index"), query
 }
 
 // Creates an *http.Request representing the given Tx that follows the Braid-HTTP
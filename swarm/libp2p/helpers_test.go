@@ -0,0 +1,5 @@
+package libp2p
+
+type SubscribeMsg = subscribeMsg
+
+var NewSubscribeMsg = newSubscribeMsg
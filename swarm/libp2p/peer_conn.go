@@ -20,6 +20,7 @@ import (
 	"redwood.dev/swarm/protoauth"
 	"redwood.dev/swarm/protoblob"
 	"redwood.dev/swarm/protohush"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/swarm/prototree"
 	"redwood.dev/tree"
 	"redwood.dev/types"
@@ -78,7 +79,7 @@ func (peer *peerConn) ensureStreamWithProtocol(ctx context.Context, p protocol.I
 	return nil
 }
 
-func (peer *peerConn) Subscribe(ctx context.Context, stateURI string) (prototree.ReadableSubscription, error) {
+func (peer *peerConn) Subscribe(ctx context.Context, stateURI string, keypath state.Keypath) (prototree.ReadableSubscription, error) {
 	err := peer.EnsureConnected(ctx)
 	if err != nil {
 		return nil, err
@@ -89,7 +90,13 @@ func (peer *peerConn) Subscribe(ctx context.Context, stateURI string) (prototree
 		return nil, err
 	}
 
-	err = peer.writeMsg(Msg{Type: msgType_Subscribe, Payload: stateURI})
+	// Send our leaves so that the peer only sends the txs we're missing
+	leaves, err := peer.t.controllerHub.Leaves(stateURI)
+	if err != nil {
+		return nil, err
+	}
+
+	err = peer.writeMsg(newSubscribeMsg(subscribeMsg{StateURI: stateURI, Keypath: keypath, KnownLeaves: leaves}))
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +113,18 @@ func (peer *peerConn) SendTx(ctx context.Context, tx tree.Tx) error {
 	return peer.writeMsg(Msg{Type: msgType_Tx, Payload: tx})
 }
 
+func (peer *peerConn) SendCheckpoint(ctx context.Context, tx tree.Tx, snapshot state.Node, cert *protoprune.PruneCertificate) error {
+	err := peer.ensureStreamWithProtocol(ctx, PROTO_MAIN)
+	if err != nil {
+		return err
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "while encoding checkpoint snapshot")
+	}
+	return peer.writeMsg(Msg{Type: msgType_Checkpoint, Payload: checkpointMsg{Tx: tx, State: snapshotJSON, Certificate: cert}})
+}
+
 func (peer *peerConn) SendPrivateTx(ctx context.Context, encryptedTx prototree.EncryptedTx) error {
 	err := peer.ensureStreamWithProtocol(ctx, PROTO_MAIN)
 	if err != nil {
@@ -131,6 +150,30 @@ func (peer *peerConn) AnnounceP2PStateURI(ctx context.Context, stateURI string)
 	return peer.writeMsg(Msg{Type: msgType_AnnounceP2PStateURI, Payload: stateURI})
 }
 
+func (peer *peerConn) FetchTxs(ctx context.Context, stateURI string, txIDs []state.Version) (prototree.FetchTxsResponse, error) {
+	err := peer.ensureStreamWithProtocol(ctx, PROTO_MAIN)
+	if err != nil {
+		return prototree.FetchTxsResponse{}, err
+	}
+	err = peer.writeMsg(Msg{Type: msgType_FetchTxs, Payload: fetchTxsMsg{StateURI: stateURI, TxIDs: txIDs}})
+	if err != nil {
+		return prototree.FetchTxsResponse{}, err
+	}
+	msg, err := peer.readMsg()
+	if err != nil {
+		return prototree.FetchTxsResponse{}, err
+	}
+	resp, ok := msg.Payload.(prototree.FetchTxsResponse)
+	if !ok {
+		return prototree.FetchTxsResponse{}, swarm.ErrProtocol
+	}
+	return resp, nil
+}
+
+func (peer *peerConn) RespondFetchTxs(resp prototree.FetchTxsResponse) error {
+	return peer.writeMsg(Msg{Type: msgType_FetchTxsResponse, Payload: resp})
+}
+
 func (peer *peerConn) ChallengeIdentity(challengeMsg protoauth.ChallengeMsg) error {
 	err := peer.ensureStreamWithProtocol(peer.t.Process.Ctx(), PROTO_MAIN)
 	if err != nil {
@@ -155,6 +198,38 @@ func (peer *peerConn) RespondChallengeIdentity(challengeIdentityResponse []proto
 	return peer.writeMsg(Msg{Type: msgType_ChallengeIdentityResponse, Payload: challengeIdentityResponse})
 }
 
+func (peer *peerConn) ProposePrune(ctx context.Context, proposal protoprune.PruneProposal) (types.Signature, error) {
+	err := peer.ensureStreamWithProtocol(ctx, PROTO_MAIN)
+	if err != nil {
+		return nil, err
+	}
+	err = peer.writeMsg(Msg{Type: msgType_PruneProposal, Payload: proposal})
+	if err != nil {
+		return nil, err
+	}
+	msg, err := peer.readMsg()
+	if err != nil {
+		return nil, err
+	}
+	vote, ok := msg.Payload.(pruneVoteMsg)
+	if !ok {
+		return nil, swarm.ErrProtocol
+	}
+	return vote.Signature, nil
+}
+
+func (peer *peerConn) RespondPruneProposal(sig types.Signature) error {
+	return peer.writeMsg(Msg{Type: msgType_PruneVote, Payload: pruneVoteMsg{Signature: sig}})
+}
+
+func (peer *peerConn) SendPruneCertificate(ctx context.Context, cert protoprune.PruneCertificate) error {
+	err := peer.ensureStreamWithProtocol(ctx, PROTO_MAIN)
+	if err != nil {
+		return err
+	}
+	return peer.writeMsg(Msg{Type: msgType_PruneCertificate, Payload: cert})
+}
+
 func (peer *peerConn) FetchBlobManifest(blobID blob.ID) (blob.Manifest, error) {
 	err := peer.ensureStreamWithProtocol(peer.t.Process.Ctx(), PROTO_BLOB)
 	if err != nil {
@@ -2,12 +2,14 @@ package libp2p
 
 import (
 	"context"
+	"encoding/json"
 
 	"go.uber.org/multierr"
 
 	"redwood.dev/errors"
 	"redwood.dev/log"
 	"redwood.dev/process"
+	"redwood.dev/state"
 	"redwood.dev/swarm/prototree"
 	"redwood.dev/tree"
 )
@@ -33,8 +35,21 @@ func (sub *readableSubscription) Read() (_ prototree.SubscriptionMsg, err error)
 		encryptedTx := msg.Payload.(prototree.EncryptedTx)
 		return prototree.SubscriptionMsg{EncryptedTx: &encryptedTx}, nil
 
+	case msgType_Checkpoint:
+		checkpoint := msg.Payload.(checkpointMsg)
+		var snapshot interface{}
+		err := json.Unmarshal(checkpoint.State, &snapshot)
+		if err != nil {
+			return prototree.SubscriptionMsg{}, errors.Wrap(err, "while decoding checkpoint snapshot")
+		}
+		return prototree.SubscriptionMsg{
+			Tx:                    &checkpoint.Tx,
+			Snapshot:              state.NewMemoryNodeWithValue(snapshot),
+			CheckpointCertificate: checkpoint.Certificate,
+		}, nil
+
 	default:
-		return prototree.SubscriptionMsg{}, errors.New("protocol error, expecting msgType_Tx or msgType_EncryptedTx")
+		return prototree.SubscriptionMsg{}, errors.New("protocol error, expecting msgType_Tx, msgType_EncryptedTx or msgType_Checkpoint")
 	}
 }
 
@@ -69,6 +84,8 @@ func (sub *writableSubscription) Put(ctx context.Context, msg prototree.Subscrip
 	}
 	if msg.EncryptedTx != nil {
 		return sub.peerConn.SendPrivateTx(ctx, *msg.EncryptedTx)
+	} else if msg.Tx != nil && msg.Snapshot != nil {
+		return sub.peerConn.SendCheckpoint(ctx, *msg.Tx, msg.Snapshot, msg.CheckpointCertificate)
 	} else if msg.Tx != nil {
 		return sub.peerConn.SendTx(ctx, *msg.Tx)
 	} else {
@@ -45,6 +45,7 @@ import (
 	"redwood.dev/swarm/protoauth"
 	"redwood.dev/swarm/protoblob"
 	"redwood.dev/swarm/protohush"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/swarm/prototree"
 	"redwood.dev/tree"
 	"redwood.dev/types"
@@ -57,6 +58,7 @@ type Transport interface {
 	protoauth.AuthTransport
 	protoblob.BlobTransport
 	protohush.HushTransport
+	protoprune.PruneTransport
 	prototree.TreeTransport
 	Libp2pPeerID() string
 	ListenAddrs() []string
@@ -72,6 +74,7 @@ type transport struct {
 	protoauth.BaseAuthTransport
 	protoblob.BaseBlobTransport
 	protohush.BaseHushTransport
+	protoprune.BasePruneTransport
 	prototree.BaseTreeTransport
 
 	libp2pHost p2phost.Host
@@ -591,20 +594,21 @@ func (t *transport) handleIncomingStream(stream netp2p.Stream) {
 	peer := t.makeConnectedPeerConn(stream)
 
 	switch msg.Type {
-	case msgType_Subscribe:
-		stateURI, ok := msg.Payload.(string)
+	case msgType_Subscribe, msgType_SubscribeWithOpts:
+		payload, ok := msg.Payload.(subscribeMsg)
 		if !ok {
 			t.Errorf("Subscribe message: bad payload: (%T) %v", msg.Payload, msg.Payload)
 			return
 		}
-		t.Infof(0, "incoming libp2p subscription: %v %v", peer.DialInfo(), stateURI)
+		stateURI := payload.StateURI
+		t.Infof(0, "incoming libp2p subscription: %v %v %v", peer.DialInfo(), stateURI, payload.Keypath)
 
-		fetchHistoryOpts := &prototree.FetchHistoryOpts{FromTxID: tree.GenesisTxID} // Fetch all history (@@TODO)
+		fetchHistoryOpts := &prototree.FetchHistoryOpts{KnownLeaves: payload.KnownLeaves}
 
 		var writeSub *writableSubscription
 		req := prototree.SubscriptionRequest{
 			StateURI:         stateURI,
-			Keypath:          nil,
+			Keypath:          payload.Keypath,
 			Type:             prototree.SubscriptionType_Txs,
 			FetchHistoryOpts: fetchHistoryOpts,
 			Addresses:        types.NewAddressSet(peer.Addresses()),
@@ -658,6 +662,16 @@ func (t *transport) handleIncomingStream(stream netp2p.Stream) {
 		}
 		t.HandleP2PStateURIReceived(stateURI, peer)
 
+	case msgType_FetchTxs:
+		defer peer.Close()
+
+		fetchTxs, ok := msg.Payload.(fetchTxsMsg)
+		if !ok {
+			t.Errorf("Fetch txs message: bad payload: (%T) %v", msg.Payload, msg.Payload)
+			return
+		}
+		t.HandleFetchTxsReceived(fetchTxs.StateURI, fetchTxs.TxIDs, peer)
+
 	case msgType_ChallengeIdentityRequest:
 		defer peer.Close()
 
@@ -673,6 +687,26 @@ func (t *transport) handleIncomingStream(stream netp2p.Stream) {
 			return
 		}
 
+	case msgType_PruneProposal:
+		defer peer.Close()
+
+		proposal, ok := msg.Payload.(protoprune.PruneProposal)
+		if !ok {
+			t.Errorf("Prune proposal: bad payload: (%T) %v", msg.Payload, msg.Payload)
+			return
+		}
+		t.HandlePruneProposal(proposal, peer)
+
+	case msgType_PruneCertificate:
+		defer peer.Close()
+
+		cert, ok := msg.Payload.(protoprune.PruneCertificate)
+		if !ok {
+			t.Errorf("Prune certificate: bad payload: (%T) %v", msg.Payload, msg.Payload)
+			return
+		}
+		t.HandlePruneCertificate(cert, peer)
+
 	case msgType_AnnouncePeers:
 		defer peer.Close()
 
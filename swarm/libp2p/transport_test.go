@@ -0,0 +1,196 @@
+package libp2p_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/identity"
+	"redwood.dev/internal/testutils"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/swarm/libp2p"
+	hushmocks "redwood.dev/swarm/protohush/mocks"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/tree"
+	"redwood.dev/utils/badgerutils"
+)
+
+type testNode struct {
+	hub       tree.ControllerHub
+	txStore   tree.TxStore
+	transport libp2pTransport
+}
+
+type libp2pTransport interface {
+	swarm.Transport
+	ListenAddrs() []string
+}
+
+func setupTestNode(t *testing.T) testNode {
+	t.Helper()
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(filepath.Join(dir, "keys")), identity.InsecureScryptParams)
+	require.NoError(t, keyStore.Unlock("password", ""))
+	t.Cleanup(func() { keyStore.Close() })
+
+	peerStore := swarm.NewPeerStore(testutils.SetupDBTree(t))
+
+	store, err := libp2p.NewStore(testutils.SetupDBTree(t))
+	require.NoError(t, err)
+
+	transport, err := libp2p.NewTransport(0, "", nil, filepath.Join(dir, "libp2p"), "", store, hub, keyStore, blobStore, peerStore)
+	require.NoError(t, err)
+	require.NoError(t, transport.Start())
+	t.Cleanup(func() { transport.Close() })
+
+	hushProto := new(hushmocks.HushProtocol)
+	hushProto.On("OnGroupMessageEncrypted", prototree.ProtocolName, mock.Anything).Return()
+	hushProto.On("OnGroupMessageDecrypted", prototree.ProtocolName, mock.Anything).Return()
+
+	treeStore, err := prototree.NewStore(testutils.SetupDBTree(t))
+	require.NoError(t, err)
+
+	treeProto := prototree.NewTreeProtocol([]swarm.Transport{transport}, hushProto, hub, txStore, keyStore, peerStore, treeStore, nil, protoprune.Quorum{})
+	require.NoError(t, treeProto.Start())
+	t.Cleanup(func() { treeProto.Close() })
+
+	return testNode{hub: hub, txStore: txStore, transport: transport}
+}
+
+func (n testNode) addTx(t *testing.T, tx tree.Tx) {
+	t.Helper()
+	require.NoError(t, n.hub.AddTx(tx))
+	require.Eventually(t, func() bool {
+		tx, err := n.txStore.FetchTx(tx.StateURI, tx.ID)
+		return err == nil && tx.Status == tree.TxStatusValid
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (n testNode) localDialAddr(t *testing.T) string {
+	t.Helper()
+	for _, addr := range n.transport.ListenAddrs() {
+		if strings.HasPrefix(addr, "/ip4/127.0.0.1/") {
+			return addr
+		}
+	}
+	t.Fatal("no loopback listen address")
+	return ""
+}
+
+func TestTransport_KeypathSubscriptions(t *testing.T) {
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	server := setupTestNode(t)
+	client := setupTestNode(t)
+
+	const stateURI = "foo.bar/chat"
+
+	newTx := func(t *testing.T, id state.Version, parents []state.Version, patchStr string) tree.Tx {
+		t.Helper()
+		var patch tree.Patch
+		require.NoError(t, patch.UnmarshalText([]byte(patchStr)))
+		tx := tree.Tx{
+			ID:       id,
+			Parents:  parents,
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{patch},
+		}
+		tx.Sig, err = alice.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+
+	var (
+		genesis   = newTx(t, tree.GenesisTxID, nil, ` = {"chat": {"messages": [], "topic": "hi"}, "other": 1}`)
+		splice    = newTx(t, state.RandomVersion(), []state.Version{genesis.ID}, `.chat.messages[0:0] = ["hello"]`)
+		unrelated = newTx(t, state.RandomVersion(), []state.Version{splice.ID}, `.other = 2`)
+		ancestor  = newTx(t, state.RandomVersion(), []state.Version{unrelated.ID}, `.chat = {"messages": ["bye"], "topic": "bye"}`)
+	)
+	for _, tx := range []tree.Tx{genesis, splice, unrelated, ancestor} {
+		server.addTx(t, tx)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	peerConn, err := client.transport.NewPeerConn(ctx, server.localDialAddr(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { peerConn.Close() })
+
+	sub, err := peerConn.(prototree.TreePeerConn).Subscribe(ctx, stateURI, state.Keypath("chat/messages"))
+	require.NoError(t, err)
+
+	ch := make(chan prototree.SubscriptionMsg, 10)
+	go func() {
+		defer close(ch)
+		for {
+			msg, err := sub.Read()
+			if err != nil {
+				return
+			}
+			ch <- msg
+		}
+	}()
+
+	var txs []tree.Tx
+	for len(txs) < 3 {
+		select {
+		case msg, open := <-ch:
+			require.True(t, open)
+			require.NotNil(t, msg.Tx)
+			txs = append(txs, *msg.Tx)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out after %v messages", len(txs))
+		}
+	}
+
+	requireScoped := func(t *testing.T, tx tree.Tx, txID state.Version, hasRange bool, valueJSON string) {
+		t.Helper()
+		require.Equal(t, txID, tx.ID)
+		require.Len(t, tx.Patches, 1)
+		require.Empty(t, tx.Patches[0].Keypath)
+		require.Equal(t, hasRange, tx.Patches[0].Range != nil)
+		require.JSONEq(t, valueJSON, string(tx.Patches[0].ValueJSON))
+	}
+
+	requireScoped(t, txs[0], genesis.ID, false, `[]`)
+	// The unrelated tx is skipped entirely
+	requireScoped(t, txs[1], splice.ID, true, `["hello"]`)
+	requireScoped(t, txs[2], ancestor.ID, false, `["bye"]`)
+
+	select {
+	case msg := <-ch:
+		t.Fatalf("unexpected message: %+v", msg)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
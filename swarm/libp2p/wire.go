@@ -10,8 +10,10 @@ import (
 	"redwood.dev/state"
 	"redwood.dev/swarm"
 	"redwood.dev/swarm/protoauth"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/swarm/prototree"
 	"redwood.dev/tree"
+	"redwood.dev/types"
 )
 
 type Msg struct {
@@ -23,17 +25,64 @@ type msgType string
 
 const (
 	msgType_Subscribe                 msgType = "subscribe"
+	msgType_SubscribeWithOpts         msgType = "subscribe with opts"
 	msgType_Unsubscribe               msgType = "unsubscribe"
 	msgType_Tx                        msgType = "tx"
 	msgType_EncryptedTx               msgType = "encrypted tx"
+	msgType_Checkpoint                msgType = "checkpoint"
 	msgType_Ack                       msgType = "ack"
 	msgType_Error                     msgType = "error"
 	msgType_ChallengeIdentityRequest  msgType = "challenge identity"
 	msgType_ChallengeIdentityResponse msgType = "challenge identity response"
 	msgType_AnnouncePeers             msgType = "announce peers"
 	msgType_AnnounceP2PStateURI       msgType = "announce p2p stateURI"
+	msgType_PruneProposal             msgType = "prune proposal"
+	msgType_PruneVote                 msgType = "prune vote"
+	msgType_PruneCertificate          msgType = "prune certificate"
+	msgType_FetchTxs                  msgType = "fetch txs"
+	msgType_FetchTxsResponse          msgType = "fetch txs response"
 )
 
+// subscribeMsg is the payload of a subscription request.  On the wire, a plain
+// subscription is a msgType_Subscribe carrying just the state URI as a string,
+// which is all that older peers understand.  A subscription with a keypath or
+// known leaves is a msgType_SubscribeWithOpts carrying the whole struct, which
+// older peers reject as an unknown message rather than misreading it.
+type subscribeMsg struct {
+	StateURI    string          `json:"stateURI"`
+	Keypath     state.Keypath   `json:"keypath,omitempty"`
+	KnownLeaves []state.Version `json:"knownLeaves,omitempty"`
+}
+
+func newSubscribeMsg(payload subscribeMsg) Msg {
+	if len(payload.Keypath) == 0 && len(payload.KnownLeaves) == 0 {
+		return Msg{Type: msgType_Subscribe, Payload: payload.StateURI}
+	}
+	return Msg{Type: msgType_SubscribeWithOpts, Payload: payload}
+}
+
+// checkpointMsg carries a checkpoint tx along with the state as of that tx, for
+// peers who are missing the history that was pruned before it, and the prune
+// quorum's certificate vouching for that state.
+type checkpointMsg struct {
+	Tx          tree.Tx                      `json:"tx"`
+	State       json.RawMessage              `json:"state"`
+	Certificate *protoprune.PruneCertificate `json:"certificate,omitempty"`
+}
+
+// pruneVoteMsg is a store peer's response to a prune proposal.  A nil signature
+// means that the peer declined to sign.
+type pruneVoteMsg struct {
+	Signature types.Signature `json:"signature"`
+}
+
+// fetchTxsMsg asks a peer for specific txs, usually the missing parents of
+// a tx that it sent us.
+type fetchTxsMsg struct {
+	StateURI string          `json:"stateURI"`
+	TxIDs    []state.Version `json:"txIDs"`
+}
+
 type ackMsg struct {
 	StateURI string        `json:"stateURI"`
 	TxID     state.Version `json:"txID"`
@@ -95,8 +144,20 @@ func (msg *Msg) UnmarshalJSON(bs []byte) error {
 
 	switch msg.Type {
 	case msgType_Subscribe:
-		url := string(m.PayloadBytes)
-		msg.Payload = url[1 : len(url)-1] // remove quotes
+		var stateURI string
+		err := json.Unmarshal(m.PayloadBytes, &stateURI)
+		if err != nil {
+			return err
+		}
+		msg.Payload = subscribeMsg{StateURI: stateURI}
+
+	case msgType_SubscribeWithOpts:
+		var payload subscribeMsg
+		err := json.Unmarshal(m.PayloadBytes, &payload)
+		if err != nil {
+			return err
+		}
+		msg.Payload = payload
 
 	case msgType_Tx:
 		var tx tree.Tx
@@ -114,6 +175,14 @@ func (msg *Msg) UnmarshalJSON(bs []byte) error {
 		}
 		msg.Payload = ep
 
+	case msgType_Checkpoint:
+		var payload checkpointMsg
+		err := json.Unmarshal(m.PayloadBytes, &payload)
+		if err != nil {
+			return err
+		}
+		msg.Payload = payload
+
 	case msgType_Ack:
 		var payload ackMsg
 		err := json.Unmarshal(m.PayloadBytes, &payload)
@@ -151,6 +220,46 @@ func (msg *Msg) UnmarshalJSON(bs []byte) error {
 		}
 		msg.Payload = peerDialInfos
 
+	case msgType_PruneProposal:
+		var proposal protoprune.PruneProposal
+		err := json.Unmarshal(m.PayloadBytes, &proposal)
+		if err != nil {
+			return err
+		}
+		msg.Payload = proposal
+
+	case msgType_PruneVote:
+		var vote pruneVoteMsg
+		err := json.Unmarshal(m.PayloadBytes, &vote)
+		if err != nil {
+			return err
+		}
+		msg.Payload = vote
+
+	case msgType_PruneCertificate:
+		var cert protoprune.PruneCertificate
+		err := json.Unmarshal(m.PayloadBytes, &cert)
+		if err != nil {
+			return err
+		}
+		msg.Payload = cert
+
+	case msgType_FetchTxs:
+		var fetchTxs fetchTxsMsg
+		err := json.Unmarshal(m.PayloadBytes, &fetchTxs)
+		if err != nil {
+			return err
+		}
+		msg.Payload = fetchTxs
+
+	case msgType_FetchTxsResponse:
+		var resp prototree.FetchTxsResponse
+		err := json.Unmarshal(m.PayloadBytes, &resp)
+		if err != nil {
+			return err
+		}
+		msg.Payload = resp
+
 	default:
 		return errors.Errorf("bad msg: %v", msg.Type)
 	}
@@ -0,0 +1,43 @@
+package libp2p_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/state"
+	"redwood.dev/swarm/libp2p"
+)
+
+func TestSubscribeMsg(t *testing.T) {
+	roundTrip := func(t *testing.T, msg libp2p.Msg) (string, libp2p.Msg) {
+		t.Helper()
+		bs, err := json.Marshal(msg)
+		require.NoError(t, err)
+		var decoded libp2p.Msg
+		err = json.Unmarshal(bs, &decoded)
+		require.NoError(t, err)
+		return string(bs), decoded
+	}
+
+	t.Run("plain subscriptions use the format that older peers understand", func(t *testing.T) {
+		encoded, decoded := roundTrip(t, libp2p.NewSubscribeMsg(libp2p.SubscribeMsg{StateURI: "foo.bar/baz"}))
+		require.JSONEq(t, `{"type":"subscribe","payload":"foo.bar/baz"}`, encoded)
+		require.Equal(t, libp2p.SubscribeMsg{StateURI: "foo.bar/baz"}, decoded.Payload)
+	})
+
+	t.Run("subscriptions with options use their own message type", func(t *testing.T) {
+		payload := libp2p.SubscribeMsg{
+			StateURI:    "foo.bar/baz",
+			Keypath:     state.Keypath("chat/messages"),
+			KnownLeaves: []state.Version{state.RandomVersion()},
+		}
+		encoded, decoded := roundTrip(t, libp2p.NewSubscribeMsg(payload))
+
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(encoded), &m))
+		require.Equal(t, "subscribe with opts", m["type"])
+		require.Equal(t, payload, decoded.Payload)
+	})
+}
@@ -0,0 +1,373 @@
+package protoprune
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/log"
+	"redwood.dev/process"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+// PruneProtocol coordinates pruning among a quorum of designated store peers.
+// A node proposes a checkpoint to prune a state URI's history to, the store
+// peers each sign the proposal if they agree with it, and once `Threshold`
+// signatures have been collected, the resulting certificate is sent to every
+// store peer.  Store peers only prune in response to a certificate that they
+// can verify themselves.
+type PruneProtocol interface {
+	process.Interface
+	ProposePrune(ctx context.Context, stateURI string, checkpointTxID state.Version) (PruneCertificate, error)
+}
+
+type PruneTransport interface {
+	swarm.Transport
+	OnPruneProposal(handler PruneProposalCallback)
+	OnPruneCertificate(handler PruneCertificateCallback)
+}
+
+type PrunePeerConn interface {
+	swarm.PeerConn
+	// ProposePrune sends a proposal to the peer and awaits its signature.  A
+	// nil signature means that the peer declined to sign.
+	ProposePrune(ctx context.Context, proposal PruneProposal) (types.Signature, error)
+	RespondPruneProposal(sig types.Signature) error
+	SendPruneCertificate(ctx context.Context, cert PruneCertificate) error
+}
+
+// CertificateStore persists the certificate of the most recent prune of each
+// state URI, so that it can be sent to peers along with the checkpoint's
+// snapshot.
+type CertificateStore interface {
+	PruneCertificate(stateURI string) (PruneCertificate, error)
+	SavePruneCertificate(cert PruneCertificate) error
+}
+
+type pruneProtocol struct {
+	process.Process
+	log.Logger
+
+	quorum        Quorum
+	certStore     CertificateStore
+	controllerHub tree.ControllerHub
+	keyStore      identity.KeyStore
+	peerStore     swarm.PeerStore
+	transports    map[string]PruneTransport
+}
+
+func NewPruneProtocol(
+	transports []swarm.Transport,
+	quorum Quorum,
+	certStore CertificateStore,
+	controllerHub tree.ControllerHub,
+	keyStore identity.KeyStore,
+	peerStore swarm.PeerStore,
+) *pruneProtocol {
+	transportsMap := make(map[string]PruneTransport)
+	for _, tpt := range transports {
+		if tpt, is := tpt.(PruneTransport); is {
+			transportsMap[tpt.Name()] = tpt
+		}
+	}
+	return &pruneProtocol{
+		Process:       *process.New(ProtocolName),
+		Logger:        log.NewLogger(ProtocolName),
+		quorum:        quorum,
+		certStore:     certStore,
+		controllerHub: controllerHub,
+		keyStore:      keyStore,
+		peerStore:     peerStore,
+		transports:    transportsMap,
+	}
+}
+
+const ProtocolName = "protoprune"
+
+func (pp *pruneProtocol) Name() string {
+	return ProtocolName
+}
+
+func (pp *pruneProtocol) Start() error {
+	err := pp.quorum.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = pp.Process.Start()
+	if err != nil {
+		return err
+	}
+
+	for _, tpt := range pp.transports {
+		pp.Infof(0, "registering %v", tpt.Name())
+		tpt.OnPruneProposal(pp.handlePruneProposal)
+		tpt.OnPruneCertificate(pp.handlePruneCertificate)
+	}
+	return nil
+}
+
+func (pp *pruneProtocol) Close() error {
+	pp.Infof(0, "prune protocol shutting down")
+	return pp.Process.Close()
+}
+
+func (pp *pruneProtocol) ProposePrune(ctx context.Context, stateURI string, checkpointTxID state.Version) (_ PruneCertificate, err error) {
+	defer errors.AddStack(&err)
+
+	stateHash, err := pp.checkpointStateHash(stateURI, checkpointTxID)
+	if err != nil {
+		return PruneCertificate{}, err
+	}
+	proposal := PruneProposal{StateURI: stateURI, CheckpointTxID: checkpointTxID, StateHash: stateHash}
+
+	err = pp.checkProposal(proposal)
+	if err != nil {
+		return PruneCertificate{}, err
+	}
+
+	var (
+		sigs   = make(map[types.Address]types.Signature)
+		sigsMu sync.Mutex
+	)
+	addSig := func(signer types.Address, sig types.Signature) (done bool) {
+		sigsMu.Lock()
+		defer sigsMu.Unlock()
+		sigs[signer] = sig
+		return uint64(len(sigs)) >= pp.quorum.Threshold
+	}
+
+	// Sign with our own identities first
+	mySigners, err := pp.mySigners()
+	if err != nil {
+		return PruneCertificate{}, err
+	}
+	var done bool
+	for signer := range mySigners {
+		sig, err := pp.keyStore.SignHash(signer, proposal.Hash())
+		if err != nil {
+			return PruneCertificate{}, err
+		}
+		done = addSig(signer, sig)
+	}
+
+	// Then collect the rest from the other store peers
+	if !done {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		for _, signer := range pp.quorum.Signers {
+			if mySigners.Contains(signer) {
+				continue
+			}
+			signer := signer
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				pp.withPeers(ctx, pp.peerStore.PeersWithAddress(signer), func(ctx context.Context, peerConn PrunePeerConn) error {
+					sig, err := peerConn.ProposePrune(ctx, proposal)
+					if err != nil {
+						return err
+					} else if sig == nil {
+						pp.Warnf("store peer %v declined to sign prune of %v to %v", signer, stateURI, checkpointTxID.Pretty())
+						return nil
+					}
+
+					cert := PruneCertificate{Proposal: proposal, Signatures: []types.Signature{sig}}
+					signers, err := cert.Signers()
+					if err != nil {
+						return err
+					} else if !signers.Contains(signer) {
+						return errors.Wrapf(ErrBadSignature, "expected signature from %v", signer)
+					}
+
+					if addSig(signer, sig) {
+						cancel()
+					}
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+	}
+
+	cert := PruneCertificate{Proposal: proposal}
+	for _, sig := range sigs {
+		cert.Signatures = append(cert.Signatures, sig)
+	}
+
+	err = pp.quorum.Verify(cert)
+	if err != nil {
+		return PruneCertificate{}, err
+	}
+
+	err = pp.applyCertificate(cert)
+	if err != nil {
+		return PruneCertificate{}, err
+	}
+
+	pp.Process.Go(nil, "broadcast prune certificate", func(ctx context.Context) {
+		pp.broadcastCertificate(ctx, cert)
+	})
+	return cert, nil
+}
+
+// checkProposal determines whether this node is willing to approve a proposal.
+func (pp *pruneProtocol) checkProposal(proposal PruneProposal) error {
+	tx, err := pp.controllerHub.FetchTx(proposal.StateURI, proposal.CheckpointTxID)
+	if err != nil {
+		return err
+	} else if !tx.Checkpoint {
+		return errors.Wrapf(tree.ErrNotCheckpoint, "tx=%v", proposal.CheckpointTxID.Pretty())
+	} else if tx.Status != tree.TxStatusValid {
+		return errors.Errorf("checkpoint tx %v has not been applied (status=%v)", proposal.CheckpointTxID.Pretty(), tx.Status)
+	}
+
+	stateHash, err := pp.checkpointStateHash(proposal.StateURI, proposal.CheckpointTxID)
+	if err != nil {
+		return err
+	} else if stateHash != proposal.StateHash {
+		return errors.Errorf("checkpoint tx %v state hash mismatch (ours=%v theirs=%v)", proposal.CheckpointTxID.Pretty(), stateHash.Hex(), proposal.StateHash.Hex())
+	}
+	return nil
+}
+
+func (pp *pruneProtocol) checkpointStateHash(stateURI string, checkpointTxID state.Version) (types.Hash, error) {
+	node, err := pp.controllerHub.StateAtVersion(stateURI, &checkpointTxID)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	defer node.Close()
+	return tree.SnapshotHash(node)
+}
+
+func (pp *pruneProtocol) mySigners() (types.AddressSet, error) {
+	myAddrs, err := pp.keyStore.Addresses()
+	if err != nil {
+		return nil, err
+	}
+	return myAddrs.Intersection(types.NewAddressSet(pp.quorum.Signers)), nil
+}
+
+// applyCertificate prunes the state URI to the certificate's checkpoint.  The
+// certificate is saved first, as tx stores that mirror to a blind store need
+// it to have the prune mirrored (see blindstore.TxStore).
+func (pp *pruneProtocol) applyCertificate(cert PruneCertificate) error {
+	if pp.certStore != nil {
+		err := pp.certStore.SavePruneCertificate(cert)
+		if err != nil {
+			return errors.Wrap(err, "while saving prune certificate")
+		}
+	}
+
+	numPruned, err := pp.controllerHub.Prune(cert.Proposal.StateURI, cert.Proposal.CheckpointTxID)
+	if errors.Cause(err) == tree.ErrNoController {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	pp.Successf("pruned %v txs from %v (checkpoint=%v)", numPruned, cert.Proposal.StateURI, cert.Proposal.CheckpointTxID.Pretty())
+	return nil
+}
+
+func (pp *pruneProtocol) broadcastCertificate(ctx context.Context, cert PruneCertificate) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	mySigners, err := pp.mySigners()
+	if err != nil {
+		pp.Errorf("while fetching addresses from keystore: %v", err)
+		return
+	}
+
+	var peers []swarm.PeerInfo
+	for _, signer := range pp.quorum.Signers {
+		if mySigners.Contains(signer) {
+			continue
+		}
+		peers = append(peers, pp.peerStore.PeersWithAddress(signer)...)
+	}
+
+	pp.withPeers(ctx, peers, func(ctx context.Context, peerConn PrunePeerConn) error {
+		return peerConn.SendPruneCertificate(ctx, cert)
+	})
+}
+
+func (pp *pruneProtocol) handlePruneProposal(proposal PruneProposal, peerConn PrunePeerConn) {
+	sig, err := pp.signProposal(proposal)
+	if err != nil {
+		pp.Warnf("declining to sign prune of %v to %v: %v", proposal.StateURI, proposal.CheckpointTxID.Pretty(), err)
+		sig = nil
+	}
+
+	err = peerConn.RespondPruneProposal(sig)
+	if err != nil {
+		pp.Errorf("while responding to prune proposal: %v", err)
+	}
+}
+
+func (pp *pruneProtocol) signProposal(proposal PruneProposal) (types.Signature, error) {
+	mySigners, err := pp.mySigners()
+	if err != nil {
+		return nil, err
+	} else if len(mySigners) == 0 {
+		return nil, errors.New("not a member of the prune quorum")
+	}
+
+	err = pp.checkProposal(proposal)
+	if err != nil {
+		return nil, err
+	}
+	return pp.keyStore.SignHash(mySigners.Any(), proposal.Hash())
+}
+
+func (pp *pruneProtocol) handlePruneCertificate(cert PruneCertificate, peerConn PrunePeerConn) {
+	err := pp.quorum.Verify(cert)
+	if err != nil {
+		pp.Warnf("ignoring prune certificate for %v from %v: %v", cert.Proposal.StateURI, peerConn.DialInfo(), err)
+		return
+	}
+
+	err = pp.applyCertificate(cert)
+	if err != nil {
+		pp.Errorf("while applying prune certificate for %v: %v", cert.Proposal.StateURI, err)
+	}
+}
+
+func (pp *pruneProtocol) withPeers(
+	ctx context.Context,
+	peers []swarm.PeerInfo,
+	fn func(ctx context.Context, peerConn PrunePeerConn) error,
+) {
+	tpts := make(map[string]swarm.Transport)
+	for k, v := range pp.transports {
+		tpts[k] = v
+	}
+
+	var chDones []<-chan struct{}
+	for _, peer := range peers {
+		chDone := swarm.TryEndpoints(ctx, tpts, peer.Endpoints(), func(ctx context.Context, peerConn swarm.PeerConn) error {
+			prunePeerConn, is := peerConn.(PrunePeerConn)
+			if !is {
+				return nil
+			}
+			return fn(ctx, prunePeerConn)
+		})
+		chDones = append(chDones, chDone)
+	}
+
+	for _, chDone := range chDones {
+		select {
+		case <-chDone:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
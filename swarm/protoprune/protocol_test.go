@@ -0,0 +1,337 @@
+package protoprune_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/internal/testutils"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/swarm/protoprune/mocks"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/utils/badgerutils"
+)
+
+const stateURI = "foo.bar/baz"
+
+type certStore map[string]protoprune.PruneCertificate
+
+func (s certStore) PruneCertificate(stateURI string) (protoprune.PruneCertificate, error) {
+	cert, exists := s[stateURI]
+	if !exists {
+		return protoprune.PruneCertificate{}, errors.Err404
+	}
+	return cert, nil
+}
+
+func (s certStore) SavePruneCertificate(cert protoprune.PruneCertificate) error {
+	s[cert.Proposal.StateURI] = cert
+	return nil
+}
+
+type testNode struct {
+	hub        tree.ControllerHub
+	keyStore   identity.KeyStore
+	peerStore  swarm.PeerStore
+	certs      certStore
+	me         types.Address
+	genesis    tree.Tx
+	checkpoint tree.Tx
+}
+
+// setupTestNode returns a node whose history for `stateURI` is a genesis tx
+// followed by a checkpoint.
+func setupTestNode(t *testing.T) testNode {
+	t.Helper()
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(filepath.Join(dir, "keys")), identity.InsecureScryptParams)
+	require.NoError(t, keyStore.Unlock("password", ""))
+	t.Cleanup(func() { keyStore.Close() })
+
+	me, err := keyStore.DefaultPublicIdentity()
+	require.NoError(t, err)
+
+	newTx := func(id state.Version, parents []state.Version, checkpoint bool, keypath, valueJSON string) tree.Tx {
+		tx := tree.Tx{
+			ID:         id,
+			Parents:    parents,
+			From:       me.Address(),
+			StateURI:   stateURI,
+			Checkpoint: checkpoint,
+			Patches:    []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		tx.Sig, err = me.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+	genesis := newTx(tree.GenesisTxID, nil, false, "a", "1")
+	checkpoint := newTx(state.RandomVersion(), []state.Version{genesis.ID}, true, "b", "2")
+
+	for _, tx := range []tree.Tx{genesis, checkpoint} {
+		require.NoError(t, hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := hub.FetchTx(stateURI, tx.ID)
+			return err == nil && tx.Status == tree.TxStatusValid
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	return testNode{
+		hub:        hub,
+		keyStore:   keyStore,
+		peerStore:  swarm.NewPeerStore(testutils.SetupDBTree(t)),
+		certs:      make(certStore),
+		me:         me.Address(),
+		genesis:    genesis,
+		checkpoint: checkpoint,
+	}
+}
+
+func (n testNode) checkpointStateHash(t *testing.T) types.Hash {
+	t.Helper()
+	node, err := n.hub.StateAtVersion(stateURI, &n.checkpoint.ID)
+	require.NoError(t, err)
+	defer node.Close()
+	hash, err := tree.SnapshotHash(node)
+	require.NoError(t, err)
+	return hash
+}
+
+func (n testNode) requirePruned(t *testing.T, pruned bool) {
+	t.Helper()
+	_, err := n.hub.FetchTx(stateURI, n.genesis.ID)
+	if pruned {
+		require.True(t, errors.Cause(err) == errors.Err404)
+	} else {
+		require.NoError(t, err)
+	}
+}
+
+func newTransport(t *testing.T) *mocks.PruneTransport {
+	t.Helper()
+	transport := new(mocks.PruneTransport)
+	transport.On("Name").Return("test")
+	transport.On("OnPruneProposal", mock.Anything).Return()
+	transport.On("OnPruneCertificate", mock.Anything).Return()
+	return transport
+}
+
+// newPeer registers a store peer that answers prune proposals with `sign`.
+func newPeer(t *testing.T, node testNode, transport *mocks.PruneTransport, address types.Address, sign func(proposal protoprune.PruneProposal) types.Signature) *mocks.PrunePeerConn {
+	t.Helper()
+	dialInfo := swarm.PeerDialInfo{TransportName: "test", DialAddr: address.Hex()}
+	node.peerStore.AddVerifiedCredentials(dialInfo, address.Hex(), address, nil, nil)
+
+	peerConn := new(mocks.PrunePeerConn)
+	peerConn.On("Ready").Return(true).Maybe()
+	peerConn.On("RemainingBackoff").Return(10 * time.Millisecond).Maybe()
+	peerConn.On("EnsureConnected", mock.Anything).Return(nil).Maybe()
+	peerConn.On("Close").Return(nil).Maybe()
+	peerConn.On("DialInfo").Return(dialInfo).Maybe()
+	peerConn.On("ProposePrune", mock.Anything, mock.Anything).Return(func(ctx context.Context, proposal protoprune.PruneProposal) types.Signature {
+		return sign(proposal)
+	}, nil).Maybe()
+	peerConn.On("SendPruneCertificate", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	transport.On("NewPeerConn", mock.Anything, dialInfo.DialAddr).Return(peerConn, nil).Maybe()
+	return peerConn
+}
+
+func TestPruneProtocol_ProposePrune(t *testing.T) {
+	t.Run("prunes once our own signatures meet the threshold", func(t *testing.T) {
+		node := setupTestNode(t)
+		quorum := protoprune.Quorum{Signers: []types.Address{node.me}, Threshold: 1}
+
+		proto := protoprune.NewPruneProtocol(nil, quorum, node.certs, node.hub, node.keyStore, node.peerStore)
+		require.NoError(t, proto.Start())
+		defer proto.Close()
+
+		cert, err := proto.ProposePrune(context.Background(), stateURI, node.checkpoint.ID)
+		require.NoError(t, err)
+		require.NoError(t, quorum.VerifyCheckpoint(cert, stateURI, node.checkpoint.ID))
+		require.Equal(t, node.checkpointStateHash(t), cert.Proposal.StateHash)
+
+		node.requirePruned(t, true)
+		saved, err := node.certs.PruneCertificate(stateURI)
+		require.NoError(t, err)
+		require.Equal(t, cert, saved)
+	})
+
+	t.Run("collects the remaining signatures from the other store peers", func(t *testing.T) {
+		node := setupTestNode(t)
+		remote, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+		quorum := protoprune.Quorum{Signers: []types.Address{node.me, remote.Address()}, Threshold: 2}
+
+		transport := newTransport(t)
+		peerConn := newPeer(t, node, transport, remote.Address(), func(proposal protoprune.PruneProposal) types.Signature {
+			sig, err := remote.SignHash(proposal.Hash())
+			require.NoError(t, err)
+			return sig
+		})
+
+		proto := protoprune.NewPruneProtocol([]swarm.Transport{transport}, quorum, node.certs, node.hub, node.keyStore, node.peerStore)
+		require.NoError(t, proto.Start())
+		defer proto.Close()
+
+		cert, err := proto.ProposePrune(context.Background(), stateURI, node.checkpoint.ID)
+		require.NoError(t, err)
+		require.NoError(t, quorum.VerifyCheckpoint(cert, stateURI, node.checkpoint.ID))
+
+		signers, err := cert.Signers()
+		require.NoError(t, err)
+		require.True(t, signers.Contains(node.me))
+		require.True(t, signers.Contains(remote.Address()))
+
+		node.requirePruned(t, true)
+		peerConn.AssertCalled(t, "ProposePrune", mock.Anything, cert.Proposal)
+	})
+
+	t.Run("doesn't prune if a store peer declines to sign", func(t *testing.T) {
+		node := setupTestNode(t)
+		remote, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+		quorum := protoprune.Quorum{Signers: []types.Address{node.me, remote.Address()}, Threshold: 2}
+
+		transport := newTransport(t)
+		newPeer(t, node, transport, remote.Address(), func(proposal protoprune.PruneProposal) types.Signature { return nil })
+
+		proto := protoprune.NewPruneProtocol([]swarm.Transport{transport}, quorum, node.certs, node.hub, node.keyStore, node.peerStore)
+		require.NoError(t, proto.Start())
+		defer proto.Close()
+
+		_, err = proto.ProposePrune(context.Background(), stateURI, node.checkpoint.ID)
+		require.True(t, errors.Cause(err) == protoprune.ErrQuorumNotMet)
+
+		node.requirePruned(t, false)
+		_, err = node.certs.PruneCertificate(stateURI)
+		require.True(t, errors.Cause(err) == errors.Err404)
+	})
+
+	t.Run("doesn't prune if a store peer's signature is forged", func(t *testing.T) {
+		node := setupTestNode(t)
+		remote, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+		forger, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+		quorum := protoprune.Quorum{Signers: []types.Address{node.me, remote.Address()}, Threshold: 2}
+
+		transport := newTransport(t)
+		newPeer(t, node, transport, remote.Address(), func(proposal protoprune.PruneProposal) types.Signature {
+			sig, err := forger.SignHash(proposal.Hash())
+			require.NoError(t, err)
+			return sig
+		})
+
+		proto := protoprune.NewPruneProtocol([]swarm.Transport{transport}, quorum, node.certs, node.hub, node.keyStore, node.peerStore)
+		require.NoError(t, proto.Start())
+		defer proto.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err = proto.ProposePrune(ctx, stateURI, node.checkpoint.ID)
+		require.True(t, errors.Cause(err) == protoprune.ErrQuorumNotMet)
+		node.requirePruned(t, false)
+	})
+}
+
+func TestPruneProtocol_HandlePruneProposal(t *testing.T) {
+	// respond delivers a proposal to the protocol as if it came from a peer,
+	// and returns the protocol's response.
+	respond := func(t *testing.T, node testNode, quorum protoprune.Quorum, proposal protoprune.PruneProposal) types.Signature {
+		t.Helper()
+
+		var handleProposal protoprune.PruneProposalCallback
+		transport := new(mocks.PruneTransport)
+		transport.On("Name").Return("test")
+		transport.On("OnPruneCertificate", mock.Anything).Return()
+		transport.On("OnPruneProposal", mock.Anything).Run(func(args mock.Arguments) {
+			handleProposal = args.Get(0).(protoprune.PruneProposalCallback)
+		}).Return()
+
+		proto := protoprune.NewPruneProtocol([]swarm.Transport{transport}, quorum, node.certs, node.hub, node.keyStore, node.peerStore)
+		require.NoError(t, proto.Start())
+		defer proto.Close()
+		require.NotNil(t, handleProposal)
+
+		var response types.Signature
+		peerConn := new(mocks.PrunePeerConn)
+		peerConn.On("RespondPruneProposal", mock.Anything).Run(func(args mock.Arguments) {
+			response = args.Get(0).(types.Signature)
+		}).Return(nil).Once()
+
+		handleProposal(proposal, peerConn)
+		peerConn.AssertExpectations(t)
+		return response
+	}
+
+	node := setupTestNode(t)
+	other, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	quorum := protoprune.Quorum{Signers: []types.Address{node.me, other.Address()}, Threshold: 2}
+	proposal := protoprune.PruneProposal{StateURI: stateURI, CheckpointTxID: node.checkpoint.ID, StateHash: node.checkpointStateHash(t)}
+
+	t.Run("signs proposals that match our history", func(t *testing.T) {
+		sig := respond(t, node, quorum, proposal)
+		require.NotNil(t, sig)
+
+		signers, err := protoprune.PruneCertificate{Proposal: proposal, Signatures: []types.Signature{sig}}.Signers()
+		require.NoError(t, err)
+		require.Equal(t, types.NewAddressSet([]types.Address{node.me}), signers)
+
+		// Signing doesn't prune anything by itself
+		node.requirePruned(t, false)
+	})
+
+	t.Run("declines proposals with the wrong state hash", func(t *testing.T) {
+		bad := proposal
+		bad.StateHash = testutils.RandomHash(t)
+		require.Nil(t, respond(t, node, quorum, bad))
+	})
+
+	t.Run("declines proposals to prune to a tx that isn't a checkpoint", func(t *testing.T) {
+		bad := proposal
+		bad.CheckpointTxID = node.genesis.ID
+		require.Nil(t, respond(t, node, quorum, bad))
+	})
+
+	t.Run("declines proposals for unknown txs", func(t *testing.T) {
+		bad := proposal
+		bad.CheckpointTxID = state.RandomVersion()
+		require.Nil(t, respond(t, node, quorum, bad))
+	})
+
+	t.Run("declines to sign if we aren't in the quorum", func(t *testing.T) {
+		notMe := protoprune.Quorum{Signers: []types.Address{other.Address()}, Threshold: 1}
+		require.Nil(t, respond(t, node, notMe, proposal))
+	})
+}
@@ -0,0 +1,59 @@
+package protoprune
+
+import (
+	"sync"
+)
+
+type BasePruneTransport struct {
+	muPruneProposalCallbacks    sync.RWMutex
+	muPruneCertificateCallbacks sync.RWMutex
+	pruneProposalCallbacks      []PruneProposalCallback
+	pruneCertificateCallbacks   []PruneCertificateCallback
+}
+
+type (
+	PruneProposalCallback    func(proposal PruneProposal, peerConn PrunePeerConn)
+	PruneCertificateCallback func(cert PruneCertificate, peerConn PrunePeerConn)
+)
+
+func (t *BasePruneTransport) OnPruneProposal(handler PruneProposalCallback) {
+	t.muPruneProposalCallbacks.Lock()
+	defer t.muPruneProposalCallbacks.Unlock()
+	t.pruneProposalCallbacks = append(t.pruneProposalCallbacks, handler)
+}
+
+func (t *BasePruneTransport) OnPruneCertificate(handler PruneCertificateCallback) {
+	t.muPruneCertificateCallbacks.Lock()
+	defer t.muPruneCertificateCallbacks.Unlock()
+	t.pruneCertificateCallbacks = append(t.pruneCertificateCallbacks, handler)
+}
+
+func (t *BasePruneTransport) HandlePruneProposal(proposal PruneProposal, peerConn PrunePeerConn) {
+	t.muPruneProposalCallbacks.RLock()
+	defer t.muPruneProposalCallbacks.RUnlock()
+	var wg sync.WaitGroup
+	wg.Add(len(t.pruneProposalCallbacks))
+	for _, handler := range t.pruneProposalCallbacks {
+		handler := handler
+		go func() {
+			defer wg.Done()
+			handler(proposal, peerConn)
+		}()
+	}
+	wg.Wait()
+}
+
+func (t *BasePruneTransport) HandlePruneCertificate(cert PruneCertificate, peerConn PrunePeerConn) {
+	t.muPruneCertificateCallbacks.RLock()
+	defer t.muPruneCertificateCallbacks.RUnlock()
+	var wg sync.WaitGroup
+	wg.Add(len(t.pruneCertificateCallbacks))
+	for _, handler := range t.pruneCertificateCallbacks {
+		handler := handler
+		go func() {
+			defer wg.Done()
+			handler(cert, peerConn)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+package protoprune_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/internal/testutils"
+	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
+)
+
+func TestBasePruneTransport_PruneProposal(t *testing.T) {
+	t.Parallel()
+
+	var transport protoprune.BasePruneTransport
+
+	expectedProposal := protoprune.PruneProposal{StateURI: "foo.bar/baz", CheckpointTxID: state.RandomVersion()}
+
+	callback1 := testutils.NewAwaiter()
+	callback2 := testutils.NewAwaiter()
+
+	transport.OnPruneProposal(func(proposal protoprune.PruneProposal, peerConn protoprune.PrunePeerConn) {
+		require.Equal(t, expectedProposal, proposal)
+		callback1.ItHappened()
+	})
+	transport.OnPruneProposal(func(proposal protoprune.PruneProposal, peerConn protoprune.PrunePeerConn) {
+		require.Equal(t, expectedProposal, proposal)
+		callback2.ItHappened()
+	})
+
+	go transport.HandlePruneProposal(expectedProposal, nil)
+
+	callback1.AwaitOrFail(t, 1*time.Second)
+	callback2.AwaitOrFail(t, 1*time.Second)
+}
+
+func TestBasePruneTransport_PruneCertificate(t *testing.T) {
+	t.Parallel()
+
+	var transport protoprune.BasePruneTransport
+
+	expectedCert := protoprune.PruneCertificate{
+		Proposal: protoprune.PruneProposal{StateURI: "foo.bar/baz", CheckpointTxID: state.RandomVersion()},
+	}
+
+	callback1 := testutils.NewAwaiter()
+	callback2 := testutils.NewAwaiter()
+
+	transport.OnPruneCertificate(func(cert protoprune.PruneCertificate, peerConn protoprune.PrunePeerConn) {
+		require.Equal(t, expectedCert, cert)
+		callback1.ItHappened()
+	})
+	transport.OnPruneCertificate(func(cert protoprune.PruneCertificate, peerConn protoprune.PrunePeerConn) {
+		require.Equal(t, expectedCert, cert)
+		callback2.ItHappened()
+	})
+
+	go transport.HandlePruneCertificate(expectedCert, nil)
+
+	callback1.AwaitOrFail(t, 1*time.Second)
+	callback2.AwaitOrFail(t, 1*time.Second)
+}
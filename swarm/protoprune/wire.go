@@ -0,0 +1,102 @@
+package protoprune
+
+import (
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/types"
+)
+
+// PruneProposal names the checkpoint tx that a state URI's history should be
+// pruned to, along with the hash of the state as of that tx (see
+// tree.SnapshotHash).  Store peers approve a proposal by signing its hash, so a
+// certificate also vouches for the checkpoint's state, which lets new peers
+// bootstrap from a snapshot without the pruned history.
+type PruneProposal struct {
+	StateURI       string        `json:"stateURI"       tree:"stateURI"`
+	CheckpointTxID state.Version `json:"checkpointTxID" tree:"checkpointTxID"`
+	StateHash      types.Hash    `json:"stateHash"      tree:"stateHash"`
+}
+
+func (p PruneProposal) Hash() types.Hash {
+	return types.HashBytes([]byte("prune:" + p.StateURI + ":" + p.CheckpointTxID.Hex() + ":" + p.StateHash.Hex()))
+}
+
+// PruneCertificate is a proposal along with the signatures of the store peers
+// that approved it.
+type PruneCertificate struct {
+	Proposal   PruneProposal     `json:"proposal"   tree:"proposal"`
+	Signatures []types.Signature `json:"signatures" tree:"signatures"`
+}
+
+// Signers returns the distinct addresses that signed the certificate's proposal.
+func (c PruneCertificate) Signers() (types.AddressSet, error) {
+	hash := c.Proposal.Hash()
+	signers := types.NewAddressSet(nil)
+	for _, sig := range c.Signatures {
+		pubkey, err := crypto.RecoverSigningPubkey(hash, sig)
+		if err != nil {
+			return nil, errors.Wrap(ErrBadSignature, err.Error())
+		} else if !pubkey.VerifySignature(hash, sig) {
+			return nil, ErrBadSignature
+		}
+		signers.Add(pubkey.Address())
+	}
+	return signers, nil
+}
+
+// Quorum is the set of store peers allowed to approve prunes, and the number
+// of them (M of N) whose signatures are required before a prune is honored.
+type Quorum struct {
+	Signers   []types.Address `yaml:"Signers" json:"signers"`
+	Threshold uint64          `yaml:"Threshold" json:"threshold"`
+}
+
+var (
+	ErrInvalidQuorum = errors.New("invalid prune quorum")
+	ErrQuorumNotMet  = errors.New("prune quorum not met")
+	ErrBadSignature  = errors.New("bad prune signature")
+	ErrWrongProposal = errors.New("prune certificate is for a different proposal")
+)
+
+func (q Quorum) Validate() error {
+	signers := types.NewAddressSet(q.Signers)
+	if q.Threshold == 0 {
+		return errors.Wrap(ErrInvalidQuorum, "threshold must be at least 1")
+	} else if q.Threshold > uint64(len(signers)) {
+		return errors.Wrapf(ErrInvalidQuorum, "threshold (%v) is greater than the number of signers (%v)", q.Threshold, len(signers))
+	}
+	return nil
+}
+
+func (q Quorum) IsSigner(address types.Address) bool {
+	return types.NewAddressSet(q.Signers).Contains(address)
+}
+
+// Verify checks that the certificate carries valid signatures from at least
+// `Threshold` distinct members of the quorum.  Signatures from addresses
+// outside of the quorum are ignored.
+func (q Quorum) Verify(cert PruneCertificate) error {
+	err := q.Validate()
+	if err != nil {
+		return err
+	}
+	signers, err := cert.Signers()
+	if err != nil {
+		return err
+	}
+	n := uint64(len(signers.Intersection(types.NewAddressSet(q.Signers))))
+	if n < q.Threshold {
+		return errors.Wrapf(ErrQuorumNotMet, "have %v of %v signatures", n, q.Threshold)
+	}
+	return nil
+}
+
+// VerifyCheckpoint checks that the certificate is valid and that it vouches for
+// the given checkpoint tx.
+func (q Quorum) VerifyCheckpoint(cert PruneCertificate, stateURI string, checkpointTxID state.Version) error {
+	if cert.Proposal.StateURI != stateURI || cert.Proposal.CheckpointTxID != checkpointTxID {
+		return errors.Wrapf(ErrWrongProposal, "expected %v %v, got %v %v", stateURI, checkpointTxID.Pretty(), cert.Proposal.StateURI, cert.Proposal.CheckpointTxID.Pretty())
+	}
+	return q.Verify(cert)
+}
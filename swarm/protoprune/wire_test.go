@@ -0,0 +1,118 @@
+package protoprune_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/internal/testutils"
+	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/types"
+)
+
+func TestQuorum_Verify(t *testing.T) {
+	t.Parallel()
+
+	var keypairs []*crypto.SigKeypair
+	var signers []types.Address
+	for i := 0; i < 4; i++ {
+		kp, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+		keypairs = append(keypairs, kp)
+		signers = append(signers, kp.Address())
+	}
+	outsider := keypairs[3]
+	quorum := protoprune.Quorum{Signers: signers[:3], Threshold: 2}
+
+	proposal := protoprune.PruneProposal{StateURI: "foo.bar/baz", CheckpointTxID: state.RandomVersion(), StateHash: testutils.RandomHash(t)}
+
+	sign := func(t *testing.T, kp *crypto.SigKeypair, proposal protoprune.PruneProposal) types.Signature {
+		t.Helper()
+		sig, err := kp.SignHash(proposal.Hash())
+		require.NoError(t, err)
+		return sig
+	}
+
+	t.Run("accepts M of N signatures", func(t *testing.T) {
+		cert := protoprune.PruneCertificate{
+			Proposal:   proposal,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal), sign(t, keypairs[2], proposal)},
+		}
+		require.NoError(t, quorum.Verify(cert))
+	})
+
+	t.Run("counts each signer once", func(t *testing.T) {
+		cert := protoprune.PruneCertificate{
+			Proposal:   proposal,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal), sign(t, keypairs[0], proposal)},
+		}
+		require.True(t, errors.Cause(quorum.Verify(cert)) == protoprune.ErrQuorumNotMet)
+	})
+
+	t.Run("ignores signers outside of the quorum", func(t *testing.T) {
+		cert := protoprune.PruneCertificate{
+			Proposal:   proposal,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal), sign(t, outsider, proposal)},
+		}
+		require.True(t, errors.Cause(quorum.Verify(cert)) == protoprune.ErrQuorumNotMet)
+	})
+
+	t.Run("rejects signatures over a different proposal", func(t *testing.T) {
+		other := protoprune.PruneProposal{StateURI: proposal.StateURI, CheckpointTxID: state.RandomVersion()}
+		cert := protoprune.PruneCertificate{
+			Proposal:   proposal,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal), sign(t, keypairs[1], other)},
+		}
+		require.True(t, errors.Cause(quorum.Verify(cert)) == protoprune.ErrQuorumNotMet)
+	})
+
+	t.Run("rejects signatures over a different state hash", func(t *testing.T) {
+		other := proposal
+		other.StateHash = testutils.RandomHash(t)
+		cert := protoprune.PruneCertificate{
+			Proposal:   other,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal), sign(t, keypairs[1], proposal)},
+		}
+		require.True(t, errors.Cause(quorum.Verify(cert)) == protoprune.ErrQuorumNotMet)
+	})
+
+	t.Run("verifies that a certificate vouches for a checkpoint", func(t *testing.T) {
+		cert := protoprune.PruneCertificate{
+			Proposal:   proposal,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal), sign(t, keypairs[1], proposal)},
+		}
+		require.NoError(t, quorum.VerifyCheckpoint(cert, proposal.StateURI, proposal.CheckpointTxID))
+
+		err := quorum.VerifyCheckpoint(cert, proposal.StateURI, state.RandomVersion())
+		require.True(t, errors.Cause(err) == protoprune.ErrWrongProposal)
+		err = quorum.VerifyCheckpoint(cert, "foo.bar/other", proposal.CheckpointTxID)
+		require.True(t, errors.Cause(err) == protoprune.ErrWrongProposal)
+		err = protoprune.Quorum{}.VerifyCheckpoint(cert, proposal.StateURI, proposal.CheckpointTxID)
+		require.True(t, errors.Cause(err) == protoprune.ErrInvalidQuorum)
+	})
+
+	t.Run("rejects malformed signatures", func(t *testing.T) {
+		cert := protoprune.PruneCertificate{
+			Proposal:   proposal,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal), types.Signature("garbage")},
+		}
+		require.True(t, errors.Cause(quorum.Verify(cert)) == protoprune.ErrBadSignature)
+	})
+
+	t.Run("rejects invalid quorums", func(t *testing.T) {
+		cert := protoprune.PruneCertificate{
+			Proposal:   proposal,
+			Signatures: []types.Signature{sign(t, keypairs[0], proposal)},
+		}
+		for _, q := range []protoprune.Quorum{
+			{Signers: signers[:3], Threshold: 0},
+			{Signers: signers[:3], Threshold: 4},
+			{Signers: []types.Address{signers[0], signers[0]}, Threshold: 2},
+		} {
+			require.True(t, errors.Cause(q.Verify(cert)) == protoprune.ErrInvalidQuorum)
+		}
+	})
+}
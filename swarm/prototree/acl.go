@@ -8,6 +8,7 @@ import (
 	"redwood.dev/state"
 	"redwood.dev/tree"
 	"redwood.dev/types"
+	"redwood.dev/ucan"
 )
 
 type ACL interface {
@@ -41,15 +42,39 @@ func (t StateURIType) String() string {
 	}
 }
 
+// DefaultACL applies the policy that a state URI declares in its tree (see
+// tree.ACLPolicy).  State URIs that don't declare one are classified by their
+// hostname: those under ".local" are device-local, those under ".p2p" are
+// private, and all others are public.  Device-local state URIs never leave
+// the device, so they ignore any declared policy.
 type DefaultACL struct {
 	ControllerHub tree.ControllerHub
+	// UCANVerifier, if set, restricts device-local state URIs to this node's
+	// own identities and to addresses that have presented a UCAN granting
+	// read access.  Otherwise they're readable by anyone who can reach them.
+	UCANVerifier *ucan.Verifier
 }
 
-var DefaultACLMembersKeypath = state.Keypath("Members")
+var DefaultACLMembersKeypath = tree.MembersKeypath
 
 var _ ACL = DefaultACL{}
 
 func (acl DefaultACL) TypeOf(stateURI string) StateURIType {
+	typ := stateURITypeFromHostname(stateURI)
+	if typ == StateURIType_Invalid || typ == StateURIType_DeviceLocal {
+		return typ
+	}
+
+	policy, err := acl.policyOf(stateURI)
+	if err != nil || policy == nil {
+		return typ
+	} else if policy.Private() {
+		return StateURIType_Private
+	}
+	return StateURIType_Public
+}
+
+func stateURITypeFromHostname(stateURI string) StateURIType {
 	parts := strings.Split(stateURI, "/")
 	if len(parts) != 2 || len(parts[1]) == 0 {
 		return StateURIType_Invalid
@@ -68,9 +93,17 @@ func (acl DefaultACL) TypeOf(stateURI string) StateURIType {
 	}
 }
 
-func (acl DefaultACL) MembersOf(stateURI string) (types.AddressSet, error) {
-	addrs := types.NewAddressSet(nil)
+// policyOf returns the state URI's ACL policy, or nil if we don't have its
+// state yet or anyone may read and write it.
+func (acl DefaultACL) policyOf(stateURI string) (tree.ACLPolicy, error) {
+	policy, err := acl.ControllerHub.ACLPolicy(stateURI)
+	if errors.Cause(err) == tree.ErrNoController {
+		return nil, nil
+	}
+	return policy, err
+}
 
+func (acl DefaultACL) MembersOf(stateURI string) (types.AddressSet, error) {
 	state, err := acl.ControllerHub.StateAtVersion(stateURI, nil)
 	if errors.Cause(err) == tree.ErrNoController {
 		return nil, nil
@@ -79,19 +112,7 @@ func (acl DefaultACL) MembersOf(stateURI string) (types.AddressSet, error) {
 	}
 	defer state.Close()
 
-	iter := state.ChildIterator(DefaultACLMembersKeypath, true, 10)
-	defer iter.Close()
-
-	for iter.Rewind(); iter.Valid(); iter.Next() {
-		addrHex := iter.Node().Keypath().Part(-1).String()
-
-		addr, err := types.AddressFromHex(addrHex)
-		if err != nil {
-			return nil, err
-		}
-		addrs.Add(addr)
-	}
-	return addrs, nil
+	return tree.MembersOf(state)
 }
 
 func (acl DefaultACL) HasReadAccess(stateURI string, keypath state.Keypath, addresses types.AddressSet) (bool, error) {
@@ -99,32 +120,43 @@ func (acl DefaultACL) HasReadAccess(stateURI string, keypath state.Keypath, addr
 	case StateURIType_Invalid:
 		return false, errors.Errorf(`bad state URI: "%v"`, stateURI)
 	case StateURIType_DeviceLocal:
-		// @@TODO: credentials store to hold UCAN/JWT chains
-		return true, nil
-	case StateURIType_Private:
-		return acl.isMemberOfPrivateStateURI(stateURI, addresses)
-	case StateURIType_Public:
-		return true, nil
-	default:
-		panic("invariant violation")
+		if acl.UCANVerifier == nil {
+			return true, nil
+		}
+		return acl.UCANVerifier.HasCapability(addresses, ucan.Capability{StateURI: stateURI, Keypath: keypath, Ability: ucan.AbilityRead})
 	}
-}
 
-func (acl DefaultACL) isMemberOfPrivateStateURI(stateURI string, addresses types.AddressSet) (bool, error) {
-	state, err := acl.ControllerHub.StateAtVersion(stateURI, nil)
+	policy, err := acl.policyOf(stateURI)
 	if err != nil {
 		return false, err
+	} else if policy == nil {
+		policy = tree.DefaultACLPolicy(stateURI)
 	}
-	defer state.Close()
 
-	for addr := range addresses {
-		ok, err := state.Exists(DefaultACLMembersKeypath.Pushs(addr.Hex()))
+	if policy != nil {
+		root, err := acl.ControllerHub.StateAtVersion(stateURI, nil)
 		if err != nil {
-			continue
-		} else if !ok {
-			continue
+			return false, err
 		}
+		defer root.Close()
+
+		allowed, err := policy.HasReadAccess(stateURI, root, keypath, addresses, acl.UCANVerifier)
+		if err != nil {
+			return false, err
+		} else if !allowed {
+			return false, nil
+		}
+	}
+	return acl.hasTreeReadAccess(stateURI, keypath, addresses)
+}
+
+// hasTreeReadAccess enforces any read rules set by the validators in the state tree.
+func (acl DefaultACL) hasTreeReadAccess(stateURI string, keypath state.Keypath, addresses types.AddressSet) (bool, error) {
+	allowed, err := acl.ControllerHub.HasReadAccess(stateURI, keypath, addresses)
+	if errors.Cause(err) == tree.ErrNoController {
 		return true, nil
+	} else if err != nil {
+		return false, err
 	}
-	return false, nil
+	return allowed, nil
 }
@@ -0,0 +1,304 @@
+package prototree
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+const (
+	// MaxFetchTxs is the most txs that a peer may ask for in a single request.
+	MaxFetchTxs = 64
+	// maxFetchingTxs bounds the number of missing txs that we're waiting on
+	// from peers at any one time.
+	maxFetchingTxs = 1024
+	// refetchMissingTxAfter is how long we wait for a missing tx that we've
+	// asked a peer for before we'll ask for it again.
+	refetchMissingTxAfter = 30 * time.Second
+	fetchTxsTimeout       = 30 * time.Second
+)
+
+// fetchMissingParents asks the peer that sent us a tx for any of its parents
+// that we don't have yet, so that the tx doesn't sit in the mempool until they
+// happen to arrive.  If we can't dial the sender (or don't know who it was, as
+// with private txs, which arrive via hush), or it doesn't have them, we ask
+// the other peers that we know of for the state URI.  The parents are handled
+// like any other tx received from a peer, so gaps in the DAG of any depth are
+// filled in one generation at a time.
+func (tp *treeProtocol) fetchMissingParents(tx tree.Tx, peerConn TreePeerConn) {
+	if len(tx.Parents) == 0 {
+		return
+	}
+
+	var missing []state.Version
+	for _, parentID := range tx.Parents {
+		exists, err := tp.txStore.TxExists(tx.StateURI, parentID)
+		if err != nil {
+			tp.Errorf("error fetching tx %v from store: %v", parentID.Pretty(), err)
+			return
+		} else if exists {
+			continue
+		}
+		// History before a checkpoint is gone for good once it has been pruned
+		pruned, err := tp.txStore.TxWasPruned(tx.StateURI, parentID)
+		if err != nil {
+			tp.Errorf("error fetching tx %v from store: %v", parentID.Pretty(), err)
+			return
+		} else if pruned {
+			continue
+		}
+		missing = append(missing, parentID)
+	}
+
+	missing = tp.fetchingTxs.claim(tx.StateURI, missing)
+	if len(missing) == 0 {
+		return
+	}
+
+	var sender swarm.PeerDialInfo
+	if peerConn != nil {
+		sender = peerConn.DialInfo()
+	}
+
+	tp.Process.Go(nil, "fetch missing txs "+tx.StateURI, func(ctx context.Context) {
+		defer tp.fetchingTxs.release(tx.StateURI, missing)
+
+		for len(missing) > 0 {
+			batch := missing
+			if len(batch) > MaxFetchTxs {
+				batch = batch[:MaxFetchTxs]
+			}
+			missing = missing[len(batch):]
+
+			if sender.DialAddr != "" {
+				var err error
+				batch, err = tp.fetchTxsFromPeer(ctx, tx.StateURI, batch, sender)
+				if err != nil {
+					tp.Warnf("while fetching missing txs of %v from peer %v: %v", tx.StateURI, sender, err)
+				}
+			}
+			if len(batch) > 0 {
+				tp.fetchTxsFromProviders(ctx, tx.StateURI, batch, sender)
+			}
+		}
+	})
+}
+
+// fetchTxsFromProviders asks the peers that we know of for a state URI (other
+// than `skip`) for the given txs, one at a time, until they've all been found.
+func (tp *treeProtocol) fetchTxsFromProviders(ctx context.Context, stateURI string, txIDs []state.Version, skip swarm.PeerDialInfo) {
+	peerInfos := tp.peerStore.PeersServingStateURI(stateURI)
+	if tp.acl.TypeOf(stateURI) == StateURIType_Private {
+		members, err := tp.acl.MembersOf(stateURI)
+		if err != nil {
+			tp.Errorf("while fetching members of state URI %v: %v", stateURI, err)
+		}
+		for addr := range members {
+			peerInfos = append(peerInfos, tp.peerStore.PeersWithAddress(addr)...)
+		}
+	}
+
+	tried := map[swarm.PeerDialInfo]bool{skip: true}
+	for _, peerInfo := range peerInfos {
+		for dialInfo, endpoint := range peerInfo.Endpoints() {
+			if len(txIDs) == 0 {
+				return
+			} else if tried[dialInfo] || !endpoint.Dialable() {
+				continue
+			} else if _, exists := tp.transports[dialInfo.TransportName]; !exists {
+				continue
+			}
+			tried[dialInfo] = true
+
+			var err error
+			txIDs, err = tp.fetchTxsFromPeer(ctx, stateURI, txIDs, dialInfo)
+			if err != nil {
+				tp.Warnf("while fetching missing txs of %v from peer %v: %v", stateURI, dialInfo, err)
+			}
+		}
+	}
+	if len(txIDs) > 0 {
+		tp.Warnf("couldn't find %v missing txs of %v", len(txIDs), stateURI)
+	}
+}
+
+// fetchTxsFromPeer asks a peer for the given txs, and returns the ones that it
+// didn't send.
+func (tp *treeProtocol) fetchTxsFromPeer(ctx context.Context, stateURI string, txIDs []state.Version, dialInfo swarm.PeerDialInfo) ([]state.Version, error) {
+	tp.Infof(0, "fetching %v missing txs of %v from peer %v", len(txIDs), stateURI, dialInfo)
+
+	tpt, exists := tp.transports[dialInfo.TransportName]
+	if !exists {
+		return txIDs, errors.Errorf("unknown transport %v", dialInfo.TransportName)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTxsTimeout)
+	defer cancel()
+
+	// The request happens in a separate stream
+	peerConn, err := tpt.NewPeerConn(ctx, dialInfo.DialAddr)
+	if err != nil {
+		return txIDs, err
+	}
+	defer peerConn.Close()
+
+	treePeerConn, is := peerConn.(TreePeerConn)
+	if !is {
+		return txIDs, errors.Errorf("peer %v doesn't speak %v", dialInfo, ProtocolName)
+	}
+
+	resp, err := treePeerConn.FetchTxs(ctx, stateURI, txIDs)
+	if err != nil {
+		return txIDs, err
+	}
+
+	requested := make(map[state.Version]bool, len(txIDs))
+	for _, txID := range txIDs {
+		requested[txID] = true
+	}
+
+	// Only accept the txs that we asked for, so that peers can't use this to
+	// push arbitrary txs at us
+	for _, tx := range resp.Txs {
+		if tx.StateURI != stateURI || !requested[tx.ID] {
+			tp.Warnf("peer %v sent tx %v %v that we didn't ask for", dialInfo, tx.StateURI, tx.ID.Pretty())
+			continue
+		}
+		delete(requested, tx.ID)
+		tp.handleTxReceived(tx, treePeerConn)
+	}
+	for _, encryptedTx := range resp.EncryptedTxs {
+		txStateURI, txID, err := tp.parseHushMessageID(encryptedTx.ID)
+		if err != nil || txStateURI != stateURI || !requested[txID] {
+			tp.Warnf("peer %v sent encrypted tx %v that we didn't ask for", dialInfo, encryptedTx.ID)
+			continue
+		}
+		delete(requested, txID)
+		tp.handlePrivateTxReceived(encryptedTx, treePeerConn)
+	}
+
+	var notSent []state.Version
+	for _, txID := range txIDs {
+		if requested[txID] {
+			notSent = append(notSent, txID)
+		}
+	}
+	return notSent, nil
+}
+
+// handleFetchTxsReceived responds to a peer's request for specific txs, as
+// long as it's allowed to read the state URI.
+func (tp *treeProtocol) handleFetchTxsReceived(stateURI string, txIDs []state.Version, peerConn TreePeerConn) {
+	tp.Infof(0, "fetch txs received: stateURI=%v n=%v peer=%v", stateURI, len(txIDs), peerConn.DialInfo())
+
+	resp, err := tp.fetchTxs(stateURI, txIDs, peerConn.Addresses())
+	if err != nil {
+		tp.Errorf("while fetching txs of %v for peer %v: %v", stateURI, peerConn.DialInfo(), err)
+	}
+
+	err = peerConn.RespondFetchTxs(resp)
+	if err != nil {
+		tp.Errorf("while responding to peer %v: %v", peerConn.DialInfo(), err)
+	}
+}
+
+func (tp *treeProtocol) fetchTxs(stateURI string, txIDs []state.Version, addrs []types.Address) (FetchTxsResponse, error) {
+	if len(txIDs) > MaxFetchTxs {
+		txIDs = txIDs[:MaxFetchTxs]
+	}
+
+	allowed, err := tp.acl.HasReadAccess(stateURI, nil, types.NewAddressSet(addrs))
+	if err != nil {
+		return FetchTxsResponse{}, errors.Wrapf(err, "while querying ACL for read access (stateURI=%v)", stateURI)
+	} else if !allowed {
+		return FetchTxsResponse{}, errors.Err403
+	}
+
+	isPrivate := tp.acl.TypeOf(stateURI) == StateURIType_Private
+
+	var resp FetchTxsResponse
+	for _, txID := range txIDs {
+		if isPrivate {
+			encryptedTx, err := tp.store.EncryptedTx(stateURI, txID)
+			if errors.Cause(err) == errors.Err404 {
+				continue
+			} else if err != nil {
+				return FetchTxsResponse{}, err
+			}
+			resp.EncryptedTxs = append(resp.EncryptedTxs, encryptedTx)
+
+		} else {
+			tx, err := tp.txStore.FetchTx(stateURI, txID)
+			if errors.Cause(err) == errors.Err404 {
+				continue
+			} else if err != nil {
+				return FetchTxsResponse{}, err
+			}
+			resp.Txs = append(resp.Txs, tx)
+		}
+	}
+	return resp, nil
+}
+
+// fetchingTxs tracks the missing txs that we've asked peers for.  A tx that's
+// claimed isn't asked for again until it's released or enough time has passed
+// that the request has probably failed.
+type fetchingTxs struct {
+	mu    sync.Mutex
+	txIDs map[string]map[state.Version]time.Time // map[stateURI]map[txID]claimedAt
+	n     int
+}
+
+func newFetchingTxs() *fetchingTxs {
+	return &fetchingTxs{txIDs: make(map[string]map[state.Version]time.Time)}
+}
+
+// claim returns the given txs that aren't already being fetched, and marks
+// them as being fetched.  No more than maxFetchingTxs are fetched at once.
+func (f *fetchingTxs) claim(stateURI string, txIDs []state.Version) []state.Version {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if _, exists := f.txIDs[stateURI]; !exists {
+		f.txIDs[stateURI] = make(map[state.Version]time.Time)
+	}
+
+	var claimed []state.Version
+	for _, txID := range txIDs {
+		if claimedAt, exists := f.txIDs[stateURI][txID]; exists {
+			if now.Sub(claimedAt) < refetchMissingTxAfter {
+				continue
+			}
+			f.n--
+		}
+		if f.n >= maxFetchingTxs {
+			break
+		}
+		f.txIDs[stateURI][txID] = now
+		f.n++
+		claimed = append(claimed, txID)
+	}
+	return claimed
+}
+
+func (f *fetchingTxs) release(stateURI string, txIDs []state.Version) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, txID := range txIDs {
+		if _, exists := f.txIDs[stateURI][txID]; exists {
+			delete(f.txIDs[stateURI], txID)
+			f.n--
+		}
+	}
+	if len(f.txIDs[stateURI]) == 0 {
+		delete(f.txIDs, stateURI)
+	}
+}
@@ -0,0 +1,247 @@
+package prototree_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/identity"
+	"redwood.dev/internal/testutils"
+	"redwood.dev/state"
+	"redwood.dev/swarm"
+	"redwood.dev/swarm/protohush"
+	hushmocks "redwood.dev/swarm/protohush/mocks"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/swarm/prototree/mocks"
+	"redwood.dev/tree"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestFetchingTxs(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+	var (
+		txA = state.RandomVersion()
+		txB = state.RandomVersion()
+		txC = state.RandomVersion()
+	)
+
+	t.Run("txs are only claimed once", func(t *testing.T) {
+		f := prototree.NewFetchingTxs()
+		require.Equal(t, []state.Version{txA, txB}, f.Claim(stateURI, []state.Version{txA, txB}))
+		require.Equal(t, []state.Version{txC}, f.Claim(stateURI, []state.Version{txB, txC}))
+		require.Empty(t, f.Claim(stateURI, []state.Version{txA, txB, txC}))
+
+		// Claims are per state URI
+		require.Equal(t, []state.Version{txA}, f.Claim("foo.bar/other", []state.Version{txA}))
+	})
+
+	t.Run("released txs can be claimed again", func(t *testing.T) {
+		f := prototree.NewFetchingTxs()
+		require.Equal(t, []state.Version{txA, txB}, f.Claim(stateURI, []state.Version{txA, txB}))
+		f.Release(stateURI, []state.Version{txA})
+		require.Equal(t, []state.Version{txA}, f.Claim(stateURI, []state.Version{txA, txB}))
+	})
+
+	t.Run("no more than MaxFetchingTxs are claimed at once", func(t *testing.T) {
+		f := prototree.NewFetchingTxs()
+
+		var txIDs []state.Version
+		for i := 0; i < prototree.MaxFetchingTxs+10; i++ {
+			txIDs = append(txIDs, state.RandomVersion())
+		}
+		claimed := f.Claim(stateURI, txIDs)
+		require.Equal(t, txIDs[:prototree.MaxFetchingTxs], claimed)
+		require.Empty(t, f.Claim("foo.bar/other", []state.Version{txA}))
+
+		// Releasing makes room, and releasing txs that weren't claimed doesn't
+		f.Release(stateURI, txIDs[prototree.MaxFetchingTxs:])
+		require.Empty(t, f.Claim(stateURI, txIDs[prototree.MaxFetchingTxs:]))
+		f.Release(stateURI, claimed[:5])
+		require.Equal(t, txIDs[prototree.MaxFetchingTxs:prototree.MaxFetchingTxs+5], f.Claim(stateURI, txIDs[prototree.MaxFetchingTxs:]))
+	})
+}
+
+func TestTreeProtocol_FetchMissingParents(t *testing.T) {
+	sender, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	type testNode struct {
+		hub       tree.ControllerHub
+		txStore   tree.TxStore
+		peerStore swarm.PeerStore
+		transport *mocks.TreeTransport
+
+		handleTx        prototree.TxReceivedCallback
+		handleDecrypted protohush.GroupMessageDecryptedCallback
+	}
+
+	setupTestNode := func(t *testing.T) *testNode {
+		t.Helper()
+		dir := t.TempDir()
+		badgerOpts := badgerutils.OptsBuilder{}
+
+		keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(filepath.Join(dir, "keystore")), identity.InsecureScryptParams)
+		require.NoError(t, keyStore.Unlock("password", ""))
+		t.Cleanup(func() { keyStore.Close() })
+
+		txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+		require.NoError(t, txStore.Start())
+		t.Cleanup(txStore.Close)
+
+		blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+		require.NoError(t, blobStore.Start())
+		t.Cleanup(blobStore.Close)
+
+		statesDir := filepath.Join(dir, "states")
+		require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+		hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+		require.NoError(t, hub.Start())
+		t.Cleanup(func() { hub.Close() })
+
+		store, err := prototree.NewStore(testutils.SetupDBTree(t))
+		require.NoError(t, err)
+
+		node := &testNode{
+			hub:       hub,
+			txStore:   txStore,
+			peerStore: swarm.NewPeerStore(testutils.SetupDBTree(t)),
+			transport: new(mocks.TreeTransport),
+		}
+
+		hushProto := new(hushmocks.HushProtocol)
+		hushProto.On("OnGroupMessageEncrypted", prototree.ProtocolName, mock.Anything).Return()
+		hushProto.On("OnGroupMessageDecrypted", prototree.ProtocolName, mock.Anything).
+			Run(func(args mock.Arguments) {
+				node.handleDecrypted = args.Get(1).(protohush.GroupMessageDecryptedCallback)
+			}).
+			Return()
+		hushProto.On("EncryptGroupMessage", prototree.ProtocolName, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+		hushProto.On("DecryptGroupMessage", mock.Anything).Return(nil).Maybe()
+
+		node.transport.On("Name").Return("test")
+		node.transport.On("OnTxReceived", mock.Anything).
+			Run(func(args mock.Arguments) { node.handleTx = args.Get(0).(prototree.TxReceivedCallback) }).
+			Return()
+		node.transport.On("OnAckReceived", mock.Anything).Return()
+		node.transport.On("OnWritableSubscriptionOpened", mock.Anything).Return()
+		node.transport.On("OnP2PStateURIReceived", mock.Anything).Return()
+		node.transport.On("OnFetchTxsReceived", mock.Anything).Return()
+
+		treeProto := prototree.NewTreeProtocol([]swarm.Transport{node.transport}, hushProto, hub, txStore, keyStore, node.peerStore, store, nil, protoprune.Quorum{})
+		require.NoError(t, treeProto.Start())
+		t.Cleanup(func() { treeProto.Close() })
+		return node
+	}
+
+	// newPeer returns a peer that answers FetchTxs requests with `resp`.
+	newPeer := func(t *testing.T, node *testNode, dialAddr string, resp prototree.FetchTxsResponse) (*mocks.TreePeerConn, testutils.Awaiter) {
+		t.Helper()
+		dialInfo := swarm.PeerDialInfo{TransportName: "test", DialAddr: dialAddr}
+		fetched := testutils.NewAwaiter()
+
+		peerConn := new(mocks.TreePeerConn)
+		peerConn.On("DialInfo").Return(dialInfo).Maybe()
+		peerConn.On("DeviceUniqueID").Return(dialAddr).Maybe()
+		peerConn.On("Transport").Return(node.transport).Maybe()
+		peerConn.On("Ack", mock.Anything, mock.Anything).Return(nil).Maybe()
+		peerConn.On("Close").Return(nil).Maybe()
+		peerConn.On("FetchTxs", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { fetched.ItHappened() }).
+			Return(resp, nil).Maybe()
+
+		node.transport.On("NewPeerConn", mock.Anything, dialAddr).Return(peerConn, nil).Maybe()
+		return peerConn, fetched
+	}
+
+	newTx := func(t *testing.T, stateURI string, id state.Version, parents []state.Version, valueJSON string) tree.Tx {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       id,
+			Parents:  parents,
+			From:     sender.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath("value"), ValueJSON: []byte(valueJSON)}},
+		}
+		tx.Sig, err = sender.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+
+	requireValid := func(t *testing.T, node *testNode, stateURI string, txIDs ...state.Version) {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			for _, txID := range txIDs {
+				tx, err := node.txStore.FetchTx(stateURI, txID)
+				if err != nil || tx.Status != tree.TxStatusValid {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	const stateURI = "foo.bar/baz"
+	var (
+		genesis    = newTx(t, stateURI, tree.GenesisTxID, nil, `{}`)
+		child      = newTx(t, stateURI, state.RandomVersion(), []state.Version{genesis.ID}, `1`)
+		unasked    = newTx(t, stateURI, state.RandomVersion(), []state.Version{genesis.ID}, `2`)
+		grandchild = newTx(t, stateURI, state.RandomVersion(), []state.Version{child.ID}, `3`)
+	)
+
+	t.Run("missing parents are fetched from the sender", func(t *testing.T) {
+		node := setupTestNode(t)
+		peerConn, _ := newPeer(t, node, "sender", prototree.FetchTxsResponse{Txs: []tree.Tx{genesis, child, unasked}})
+
+		node.handleTx(grandchild, peerConn)
+		requireValid(t, node, stateURI, genesis.ID, child.ID, grandchild.ID)
+
+		// One generation at a time
+		peerConn.AssertCalled(t, "FetchTxs", mock.Anything, stateURI, []state.Version{child.ID})
+		peerConn.AssertCalled(t, "FetchTxs", mock.Anything, stateURI, []state.Version{genesis.ID})
+
+		// Txs that we didn't ask for are dropped
+		exists, err := node.txStore.TxExists(stateURI, unasked.ID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("missing parents are fetched from other peers if the sender can't be dialed", func(t *testing.T) {
+		node := setupTestNode(t)
+		senderConn, _ := newPeer(t, node, "", prototree.FetchTxsResponse{})
+		providerConn, _ := newPeer(t, node, "provider", prototree.FetchTxsResponse{Txs: []tree.Tx{genesis}})
+		node.peerStore.AddDialInfo(swarm.PeerDialInfo{TransportName: "test", DialAddr: "provider"}, "provider").AddStateURI(stateURI)
+
+		node.handleTx(child, senderConn)
+		requireValid(t, node, stateURI, genesis.ID, child.ID)
+
+		senderConn.AssertNotCalled(t, "FetchTxs", mock.Anything, mock.Anything, mock.Anything)
+		providerConn.AssertCalled(t, "FetchTxs", mock.Anything, stateURI, []state.Version{genesis.ID})
+	})
+
+	t.Run("missing parents of private txs are fetched", func(t *testing.T) {
+		const stateURI = "foo.p2p/baz"
+		var (
+			genesis = newTx(t, stateURI, tree.GenesisTxID, nil, `{}`)
+			child   = newTx(t, stateURI, state.RandomVersion(), []state.Version{genesis.ID}, `1`)
+		)
+
+		node := setupTestNode(t)
+		providerConn, fetched := newPeer(t, node, "provider", prototree.FetchTxsResponse{})
+		node.peerStore.AddDialInfo(swarm.PeerDialInfo{TransportName: "test", DialAddr: "provider"}, "provider").AddStateURI(stateURI)
+
+		plaintext, err := child.Marshal()
+		require.NoError(t, err)
+		node.handleDecrypted(sender.Address(), plaintext, protohush.GroupMessage{ID: stateURI + ":" + child.ID.Hex()})
+
+		fetched.AwaitOrFail(t, 5*time.Second)
+		providerConn.AssertCalled(t, "FetchTxs", mock.Anything, stateURI, []state.Version{genesis.ID})
+	})
+}
@@ -0,0 +1,27 @@
+package prototree
+
+import (
+	"time"
+
+	"redwood.dev/state"
+)
+
+const MaxFetchingTxs = maxFetchingTxs
+
+type FetchingTxs = fetchingTxs
+
+var NewFetchingTxs = newFetchingTxs
+
+func (f *fetchingTxs) Claim(stateURI string, txIDs []state.Version) []state.Version {
+	return f.claim(stateURI, txIDs)
+}
+
+func (f *fetchingTxs) Release(stateURI string, txIDs []state.Version) {
+	f.release(stateURI, txIDs)
+}
+
+func SetRotateHushSessionsTimeout(timeout time.Duration) (restore func()) {
+	old := rotateHushSessionsTimeout
+	rotateHushSessionsTimeout = timeout
+	return func() { rotateHushSessionsTimeout = old }
+}
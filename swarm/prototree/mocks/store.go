@@ -6,6 +6,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 	pb "redwood.dev/swarm/protohush/pb"
 
+	protoprune "redwood.dev/swarm/protoprune"
+
 	state "redwood.dev/state"
 
 	time "time"
@@ -58,6 +60,29 @@ func (_m *Store) EncryptedTx(stateURI string, txID state.Version) (pb.GroupMessa
 	return r0, r1
 }
 
+// KnownMembers provides a mock function with given fields: stateURI
+func (_m *Store) KnownMembers(stateURI string) (types.AddressSet, error) {
+	ret := _m.Called(stateURI)
+
+	var r0 types.AddressSet
+	if rf, ok := ret.Get(0).(func(string) types.AddressSet); ok {
+		r0 = rf(stateURI)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.AddressSet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(stateURI)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // MarkTxSeenByPeer provides a mock function with given fields: deviceUniqueID, stateURI, txID
 func (_m *Store) MarkTxSeenByPeer(deviceUniqueID string, stateURI string, txID state.Version) error {
 	ret := _m.Called(deviceUniqueID, stateURI, txID)
@@ -102,6 +127,27 @@ func (_m *Store) OnNewSubscribedStateURI(handler func(string)) func() {
 	return r0
 }
 
+// PruneCertificate provides a mock function with given fields: stateURI
+func (_m *Store) PruneCertificate(stateURI string) (protoprune.PruneCertificate, error) {
+	ret := _m.Called(stateURI)
+
+	var r0 protoprune.PruneCertificate
+	if rf, ok := ret.Get(0).(func(string) protoprune.PruneCertificate); ok {
+		r0 = rf(stateURI)
+	} else {
+		r0 = ret.Get(0).(protoprune.PruneCertificate)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(stateURI)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PruneTxSeenRecordsOlderThan provides a mock function with given fields: threshold
 func (_m *Store) PruneTxSeenRecordsOlderThan(threshold time.Duration) error {
 	ret := _m.Called(threshold)
@@ -144,6 +190,34 @@ func (_m *Store) SaveEncryptedTx(stateURI string, txID state.Version, etx pb.Gro
 	return r0
 }
 
+// SaveKnownMembers provides a mock function with given fields: stateURI, members
+func (_m *Store) SaveKnownMembers(stateURI string, members types.AddressSet) error {
+	ret := _m.Called(stateURI, members)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, types.AddressSet) error); ok {
+		r0 = rf(stateURI, members)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePruneCertificate provides a mock function with given fields: cert
+func (_m *Store) SavePruneCertificate(cert protoprune.PruneCertificate) error {
+	ret := _m.Called(cert)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(protoprune.PruneCertificate) error); ok {
+		r0 = rf(cert)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetMaxPeersPerSubscription provides a mock function with given fields: max
 func (_m *Store) SetMaxPeersPerSubscription(max uint64) error {
 	ret := _m.Called(max)
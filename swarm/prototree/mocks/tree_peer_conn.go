@@ -210,6 +210,27 @@ func (_m *TreePeerConn) EnsureConnected(ctx context.Context) error {
 	return r0
 }
 
+// FetchTxs provides a mock function with given fields: ctx, stateURI, txIDs
+func (_m *TreePeerConn) FetchTxs(ctx context.Context, stateURI string, txIDs []state.Version) (prototree.FetchTxsResponse, error) {
+	ret := _m.Called(ctx, stateURI, txIDs)
+
+	var r0 prototree.FetchTxsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, []state.Version) prototree.FetchTxsResponse); ok {
+		r0 = rf(ctx, stateURI, txIDs)
+	} else {
+		r0 = ret.Get(0).(prototree.FetchTxsResponse)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []state.Version) error); ok {
+		r1 = rf(ctx, stateURI, txIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Failures provides a mock function with given fields:
 func (_m *TreePeerConn) Failures() uint64 {
 	ret := _m.Called()
@@ -319,6 +340,20 @@ func (_m *TreePeerConn) RemoveStateURI(stateURI string) error {
 	return r0
 }
 
+// RespondFetchTxs provides a mock function with given fields: resp
+func (_m *TreePeerConn) RespondFetchTxs(resp prototree.FetchTxsResponse) error {
+	ret := _m.Called(resp)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(prototree.FetchTxsResponse) error); ok {
+		r0 = rf(resp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendPrivateTx provides a mock function with given fields: ctx, encryptedTx
 func (_m *TreePeerConn) SendPrivateTx(ctx context.Context, encryptedTx pb.GroupMessage) error {
 	ret := _m.Called(ctx, encryptedTx)
@@ -377,13 +412,13 @@ func (_m *TreePeerConn) StateURIs() types.StringSet {
 	return r0
 }
 
-// Subscribe provides a mock function with given fields: ctx, stateURI
-func (_m *TreePeerConn) Subscribe(ctx context.Context, stateURI string) (prototree.ReadableSubscription, error) {
-	ret := _m.Called(ctx, stateURI)
+// Subscribe provides a mock function with given fields: ctx, stateURI, keypath
+func (_m *TreePeerConn) Subscribe(ctx context.Context, stateURI string, keypath state.Keypath) (prototree.ReadableSubscription, error) {
+	ret := _m.Called(ctx, stateURI, keypath)
 
 	var r0 prototree.ReadableSubscription
-	if rf, ok := ret.Get(0).(func(context.Context, string) prototree.ReadableSubscription); ok {
-		r0 = rf(ctx, stateURI)
+	if rf, ok := ret.Get(0).(func(context.Context, string, state.Keypath) prototree.ReadableSubscription); ok {
+		r0 = rf(ctx, stateURI, keypath)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(prototree.ReadableSubscription)
@@ -391,8 +426,8 @@ func (_m *TreePeerConn) Subscribe(ctx context.Context, stateURI string) (prototr
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, stateURI)
+	if rf, ok := ret.Get(1).(func(context.Context, string, state.Keypath) error); ok {
+		r1 = rf(ctx, stateURI, keypath)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -13,6 +13,8 @@ import (
 	prototree "redwood.dev/swarm/prototree"
 
 	state "redwood.dev/state"
+
+	types "redwood.dev/types"
 )
 
 // TreeProtocol is an autogenerated mock type for the TreeProtocol type
@@ -283,3 +285,17 @@ func (_m *TreeProtocol) Unsubscribe(stateURI string) error {
 
 	return r0
 }
+
+// UpdateMembers provides a mock function with given fields: ctx, stateURI, add, remove
+func (_m *TreeProtocol) UpdateMembers(ctx context.Context, stateURI string, add []types.Address, remove []types.Address) error {
+	ret := _m.Called(ctx, stateURI, add, remove)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []types.Address, []types.Address) error); ok {
+		r0 = rf(ctx, stateURI, add, remove)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
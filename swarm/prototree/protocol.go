@@ -14,8 +14,10 @@ import (
 	"redwood.dev/state"
 	"redwood.dev/swarm"
 	"redwood.dev/swarm/protohush"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/tree"
 	"redwood.dev/types"
+	"redwood.dev/ucan"
 	"redwood.dev/utils"
 )
 
@@ -28,6 +30,7 @@ type TreeProtocol interface {
 	Unsubscribe(stateURI string) error
 	SubscribeStateURIs() (StateURISubscription, error)
 	SendTx(ctx context.Context, tx tree.Tx) error
+	UpdateMembers(ctx context.Context, stateURI string, add, remove []types.Address) error
 }
 
 //go:generate mockery --name TreeTransport --output ./mocks/ --case=underscore
@@ -39,16 +42,21 @@ type TreeTransport interface {
 	OnAckReceived(handler AckReceivedCallback)
 	OnWritableSubscriptionOpened(handler WritableSubscriptionOpenedCallback)
 	OnP2PStateURIReceived(handler P2PStateURIReceivedCallback)
+	OnFetchTxsReceived(handler FetchTxsReceivedCallback)
 }
 
 //go:generate mockery --name TreePeerConn --output ./mocks/ --case=underscore
 type TreePeerConn interface {
 	swarm.PeerConn
-	Subscribe(ctx context.Context, stateURI string) (ReadableSubscription, error)
+	// Subscribe asks the peer for the txs of a state URI.  If `keypath` is
+	// set, the peer only sends the parts of txs that touch that subtree.
+	Subscribe(ctx context.Context, stateURI string, keypath state.Keypath) (ReadableSubscription, error)
 	SendTx(ctx context.Context, tx tree.Tx) error
 	SendPrivateTx(ctx context.Context, encryptedTx EncryptedTx) (err error)
 	Ack(stateURI string, txID state.Version) error
 	AnnounceP2PStateURI(ctx context.Context, stateURI string) error
+	FetchTxs(ctx context.Context, stateURI string, txIDs []state.Version) (FetchTxsResponse, error)
+	RespondFetchTxs(resp FetchTxsResponse) error
 }
 
 type treeProtocol struct {
@@ -67,11 +75,23 @@ type treeProtocol struct {
 
 	acl ACL
 
+	// The store peers whose certificates we trust to vouch for the state of
+	// a checkpoint, which lets us bootstrap from a snapshot
+	pruneQuorum protoprune.Quorum
+
 	readableSubscriptions   map[string]*multiReaderSubscription // map[stateURI]
 	readableSubscriptionsMu sync.RWMutex
 	writableSubscriptions   map[string]map[WritableSubscription]struct{} // map[stateURI]
 	writableSubscriptionsMu sync.RWMutex
 
+	// Serializes updates of the last member set that we saw for each private
+	// state URI (see handleMembersChanged)
+	membersMu sync.Mutex
+
+	// The missing txs that we've asked peers for, so that we don't ask for
+	// them again while waiting (see fetchMissingParents)
+	fetchingTxs *fetchingTxs
+
 	announceP2PStateURIsTask *announceP2PStateURIsTask
 	poolWorker               process.PoolWorker
 }
@@ -81,6 +101,11 @@ var (
 	_ process.Interface = (*treeProtocol)(nil)
 )
 
+// rotateHushSessionsTimeout is how long the member with the higher address in
+// a pair waits for the other member to rotate their hush session before doing
+// it itself.
+var rotateHushSessionsTimeout = 30 * time.Second
+
 func NewTreeProtocol(
 	transports []swarm.Transport,
 	hushProto protohush.HushProtocol,
@@ -89,6 +114,8 @@ func NewTreeProtocol(
 	keyStore identity.KeyStore,
 	peerStore swarm.PeerStore,
 	store Store,
+	ucanVerifier *ucan.Verifier,
+	pruneQuorum protoprune.Quorum,
 ) *treeProtocol {
 	transportsMap := make(map[string]TreeTransport)
 	for _, tpt := range transports {
@@ -107,10 +134,12 @@ func NewTreeProtocol(
 		keyStore:      keyStore,
 		peerStore:     peerStore,
 
-		acl: DefaultACL{ControllerHub: controllerHub},
+		acl:         DefaultACL{ControllerHub: controllerHub, UCANVerifier: ucanVerifier},
+		pruneQuorum: pruneQuorum,
 
 		readableSubscriptions: make(map[string]*multiReaderSubscription),
 		writableSubscriptions: make(map[string]map[WritableSubscription]struct{}),
+		fetchingTxs:           newFetchingTxs(),
 	}
 	return tp
 }
@@ -137,6 +166,7 @@ func (tp *treeProtocol) Start() error {
 		tpt.OnAckReceived(tp.handleAckReceived)
 		tpt.OnWritableSubscriptionOpened(tp.handleWritableSubscriptionOpened)
 		tpt.OnP2PStateURIReceived(tp.handleP2PStateURIReceived)
+		tpt.OnFetchTxsReceived(tp.handleFetchTxsReceived)
 	}
 
 	tp.Process.Go(nil, "initial subscribe", func(ctx context.Context) {
@@ -217,6 +247,12 @@ func (tp *treeProtocol) SendTx(ctx context.Context, tx tree.Tx) (err error) {
 		return errors.Errorf("invalid state URI: %v", tx.StateURI)
 
 	case StateURIType_Public, StateURIType_Private, StateURIType_DeviceLocal:
+		// This is where local txs enter the network, so it's where the state
+		// URI's rate limits are enforced on them
+		err = tp.controllerHub.CheckRateLimits(tx)
+		if err != nil {
+			return err
+		}
 		err = tp.controllerHub.AddTx(tx)
 		if err != nil {
 			return err
@@ -228,6 +264,29 @@ func (tp *treeProtocol) SendTx(ctx context.Context, tx tree.Tx) (err error) {
 	return nil
 }
 
+// UpdateMembers adds and removes members of a private state URI by sending a
+// tx that patches its Members subtree, so that every member converges on the
+// same member set.  Once it's applied, removed members stop receiving the
+// state URI's txs and our hush sessions with the remaining members are rotated
+// (see handleMembersChanged).
+func (tp *treeProtocol) UpdateMembers(ctx context.Context, stateURI string, add, remove []types.Address) error {
+	if tp.acl.TypeOf(stateURI) != StateURIType_Private {
+		return errors.Errorf("not a private state URI: %v", stateURI)
+	}
+
+	var patches []tree.Patch
+	for _, addr := range add {
+		patches = append(patches, tree.Patch{Keypath: tree.MembersKeypath.Pushs(addr.Hex()), ValueJSON: []byte(`true`)})
+	}
+	for _, addr := range remove {
+		patches = append(patches, tree.Patch{Keypath: tree.MembersKeypath.Pushs(addr.Hex()), ValueJSON: []byte(`null`)})
+	}
+	if len(patches) == 0 {
+		return nil
+	}
+	return tp.SendTx(ctx, tree.Tx{ID: state.RandomVersion(), StateURI: stateURI, Patches: patches})
+}
+
 func (tp *treeProtocol) hushMessageIDForTx(tx tree.Tx) string {
 	return url.QueryEscape(tx.StateURI) + ":" + tx.ID.Hex()
 }
@@ -263,6 +322,8 @@ func (tp *treeProtocol) handleTxReceived(tx tree.Tx, peerConn TreePeerConn) {
 		err := tp.controllerHub.AddTx(tx)
 		if err != nil {
 			tp.Errorf("error adding tx to controllerHub: %v", err)
+		} else {
+			tp.fetchMissingParents(tx, peerConn)
 		}
 	}
 
@@ -278,6 +339,40 @@ func (tp *treeProtocol) handleTxReceived(tx tree.Tx, peerConn TreePeerConn) {
 	}
 }
 
+// handleCheckpointReceived bootstraps a state URI that we have no history for
+// from a peer's checkpoint snapshot.  The snapshot is only trusted if it comes
+// with a certificate from our prune quorum vouching for its state hash.
+// Otherwise, or if we already have history, the checkpoint is treated like any
+// other tx, so its missing ancestors are fetched from the peer.
+func (tp *treeProtocol) handleCheckpointReceived(tx tree.Tx, snapshot state.Node, cert *protoprune.PruneCertificate, peerConn TreePeerConn) {
+	tp.Infof(0, "checkpoint received: tx=%v peer=%v", tx.ID.Pretty(), peerConn.DialInfo())
+
+	exists, err := tp.txStore.TxExists(tx.StateURI, tx.ID)
+	if err != nil {
+		tp.Errorf("error fetching tx %v from store: %v", tx.ID.Pretty(), err)
+		return
+	}
+
+	if !exists {
+		err := tp.importCheckpoint(tx, snapshot, cert)
+		if err != nil && errors.Cause(err) != tree.ErrAlreadyHaveHistory {
+			tp.Warnf("not importing checkpoint %v from peer %v, fetching full history instead: %v", tx.ID.Pretty(), peerConn.DialInfo(), err)
+		}
+	}
+	tp.handleTxReceived(tx, peerConn)
+}
+
+func (tp *treeProtocol) importCheckpoint(tx tree.Tx, snapshot state.Node, cert *protoprune.PruneCertificate) error {
+	if cert == nil {
+		return errors.New("checkpoint has no prune certificate")
+	}
+	err := tp.pruneQuorum.VerifyCheckpoint(*cert, tx.StateURI, tx.ID)
+	if err != nil {
+		return err
+	}
+	return tp.controllerHub.ImportCheckpoint(tx, snapshot, cert.Proposal.StateHash)
+}
+
 func (tp *treeProtocol) handlePrivateTxReceived(encryptedTx EncryptedTx, peerConn TreePeerConn) {
 	tp.Infof(0, "private tx received: tx=%v peer=%v", encryptedTx.ID, peerConn.DialInfo())
 
@@ -329,6 +424,7 @@ func (tp *treeProtocol) handlePrivateTxDecrypted(sender types.Address, plaintext
 		tp.Errorf("while adding private tx to controller: %v", err)
 		return
 	}
+	tp.fetchMissingParents(tx, nil)
 
 	// @@TODO: send to Vault
 }
@@ -347,15 +443,16 @@ func (tp *treeProtocol) handleP2PStateURIReceived(stateURI string, peerConn Tree
 	}
 }
 
+// FetchHistoryOpts bounds the history sent to a new subscriber.  A subscriber
+// that sends its own leaves in `KnownLeaves` only receives the txs that it's
+// missing (see tree.TxRange).
 type FetchHistoryOpts struct {
-	FromTxID state.Version
-	ToTxID   state.Version
+	FromTxID    state.Version
+	ToTxID      state.Version
+	KnownLeaves []state.Version
 }
 
 func (tp *treeProtocol) handleFetchHistoryRequest(stateURI string, opts FetchHistoryOpts, writeSub WritableSubscription) error {
-	// @@TODO: respect the `opts.ToTxID` param
-	// @@TODO: if .FromTxID == 0, set it to GenesisTxID
-
 	allowed, err := tp.acl.HasReadAccess(stateURI, nil, types.NewAddressSet(writeSub.Addresses()))
 	if err != nil {
 		return errors.Wrapf(err, "while querying ACL for read access (stateURI=%v)", stateURI)
@@ -365,9 +462,23 @@ func (tp *treeProtocol) handleFetchHistoryRequest(stateURI string, opts FetchHis
 
 	isPrivate := tp.acl.TypeOf(stateURI) == StateURIType_Private
 
-	iter := tp.controllerHub.FetchTxs(stateURI, opts.FromTxID)
+	iter := tree.FetchTxRange(tp.controllerHub, stateURI, tree.TxRange{
+		FromTxID:    opts.FromTxID,
+		ToTxID:      opts.ToTxID,
+		KnownLeaves: opts.KnownLeaves,
+	})
 	defer iter.Close()
 
+	leaves, err := tp.controllerHub.Leaves(stateURI)
+	if err != nil {
+		return err
+	}
+
+	historyBase, err := tp.controllerHub.HistoryBase(stateURI)
+	if err != nil {
+		return err
+	}
+
 	for {
 		tx := iter.Next()
 		if iter.Error() != nil {
@@ -385,34 +496,67 @@ func (tp *treeProtocol) handleFetchHistoryRequest(stateURI string, opts FetchHis
 			encryptedTx = &encryptedTx2
 		}
 
-		leaves, err := tp.controllerHub.Leaves(stateURI)
-		if err != nil {
-			return err
-		}
-
 		if isPrivate {
 			tx = nil
 		} else {
 			encryptedTx = nil
 		}
+
+		// The peer can't replay history from before a checkpoint that we've
+		// pruned to, so we send it the checkpoint's state instead, along with
+		// the prune quorum's certificate so that it can trust the state.
+		// Without a certificate (e.g. if we pruned unilaterally), the peer
+		// will have to find the history elsewhere.
+		var snapshot state.Node
+		var cert *protoprune.PruneCertificate
+		if tx != nil && tx.ID == historyBase && historyBase != tree.GenesisTxID {
+			cert2, err := tp.store.PruneCertificate(stateURI)
+			if errors.Cause(err) == errors.Err404 {
+				tp.Warnf("no prune certificate for checkpoint %v of %v, not sending snapshot", historyBase.Pretty(), stateURI)
+			} else if err != nil {
+				return err
+			} else if cert2.Proposal.CheckpointTxID == historyBase {
+				snapshot, err = tp.checkpointSnapshot(stateURI, historyBase)
+				if err != nil {
+					return err
+				}
+				cert = &cert2
+			}
+		}
+
 		msg := SubscriptionMsg{
 			StateURI:    stateURI,
 			Tx:          tx,
 			EncryptedTx: encryptedTx,
 			State:       nil,
 			Leaves:      leaves,
+			Snapshot:    snapshot,
+
+			CheckpointCertificate: cert,
 		}
 		writeSub.EnqueueWrite(msg)
 	}
 	return nil
 }
 
+func (tp *treeProtocol) checkpointSnapshot(stateURI string, checkpointTxID state.Version) (state.Node, error) {
+	node, err := tp.controllerHub.StateAtVersion(stateURI, &checkpointTxID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while fetching checkpoint snapshot (stateURI=%v tx=%v)", stateURI, checkpointTxID.Pretty())
+	}
+	defer node.Close()
+	return node.CopyToMemory(nil, nil)
+}
+
 func (tp *treeProtocol) handleWritableSubscriptionOpened(
 	req SubscriptionRequest,
 	writeSubImplFactory WritableSubscriptionImplFactory,
 ) (<-chan struct{}, error) {
 
 	req.Keypath = req.Keypath.Normalized()
+	if req.Keypath.Equals(state.KeypathSeparator) {
+		req.Keypath = nil
+	}
 
 	myAddrs, err := tp.keyStore.Addresses()
 	if err != nil {
@@ -466,11 +610,6 @@ func (tp *treeProtocol) handleWritableSubscriptionOpened(
 	}
 
 	if req.Type.Includes(SubscriptionType_States) {
-		// Normalize empty keypaths
-		if req.Keypath.Equals(state.KeypathSeparator) {
-			req.Keypath = nil
-		}
-
 		// Immediately write the current state to the subscriber
 		node, err := tp.controllerHub.StateAtVersion(req.StateURI, nil)
 		if err != nil && errors.Cause(err) != tree.ErrNoController {
@@ -523,6 +662,8 @@ func (tp *treeProtocol) openReadableSubscription(stateURI string) {
 			func(msg SubscriptionMsg, peerConn TreePeerConn) {
 				if msg.EncryptedTx != nil {
 					tp.handlePrivateTxReceived(*msg.EncryptedTx, peerConn)
+				} else if msg.Tx != nil && msg.Snapshot != nil {
+					tp.handleCheckpointReceived(*msg.Tx, msg.Snapshot, msg.CheckpointCertificate, peerConn)
 				} else if msg.Tx != nil {
 					tp.handleTxReceived(*msg.Tx, peerConn)
 				} else {
@@ -778,6 +919,8 @@ func (tp *treeProtocol) handleNewState(tx tree.Tx, node state.Node, leaves []sta
 			})
 		}
 
+		tp.handleMembersChanged(tx.StateURI)
+
 		myAddrs, err := tp.keyStore.Addresses()
 		if err != nil {
 			tp.Errorf("while fetching own addresses from keystore: %v", err)
@@ -817,6 +960,108 @@ func (tp *treeProtocol) handleNewState(tx tree.Tx, node state.Node, leaves []sta
 	}
 }
 
+// handleMembersChanged compares the members of a private state URI to the last
+// member set we saw, which is persisted so that removals are noticed across
+// restarts.  When members have been removed, we start a new hush session epoch
+// with each of the remaining members, so that the keys of future txs don't
+// derive from session state that a removed member may hold.  The member with
+// the lower address in each pair proposes the new session right away, and the
+// other one only does so if that hasn't happened within
+// rotateHushSessionsTimeout (e.g. because the lower member is offline).
+func (tp *treeProtocol) handleMembersChanged(stateURI string) {
+	members, err := tp.acl.MembersOf(stateURI)
+	if err != nil {
+		tp.Errorf("while fetching members of state URI %v: %v", stateURI, err)
+		return
+	}
+
+	removed, err := func() (bool, error) {
+		tp.membersMu.Lock()
+		defer tp.membersMu.Unlock()
+
+		knownMembers, err := tp.store.KnownMembers(stateURI)
+		if err != nil {
+			return false, err
+		}
+
+		var removed bool
+		for addr := range knownMembers {
+			if !members.Contains(addr) {
+				removed = true
+				break
+			}
+		}
+		if !removed && len(knownMembers) == len(members) {
+			return false, nil
+		}
+		return removed, tp.store.SaveKnownMembers(stateURI, members)
+	}()
+	if err != nil {
+		tp.Errorf("while updating known members of state URI %v: %v", stateURI, err)
+	}
+	if !removed {
+		return
+	}
+
+	identity, err := tp.keyStore.DefaultPublicIdentity()
+	if err != nil {
+		tp.Errorf("while fetching default public identity from keystore: %v", err)
+		return
+	} else if !members.Contains(identity.Address()) {
+		return
+	}
+
+	tp.Infof(0, "members removed from %v, rotating hush sessions", stateURI)
+
+	for member := range members {
+		if member == identity.Address() {
+			continue
+		}
+		member := member
+
+		tp.Process.Go(nil, "rotate hush session "+stateURI+" "+member.Hex(), func(ctx context.Context) {
+			if identity.Address().Compare(member) > 0 {
+				rotated, err := tp.awaitHushSessionRotation(ctx, member)
+				if err != nil {
+					tp.Errorf("while awaiting next hush session with %v: %v", member, err)
+					return
+				} else if rotated {
+					return
+				}
+			}
+
+			_, err := tp.hushProto.ProposeNextIndividualSession(ctx, ProtocolName, member)
+			if err != nil {
+				tp.Errorf("while proposing next hush session with %v: %v", member, err)
+			}
+		})
+	}
+}
+
+// awaitHushSessionRotation waits rotateHushSessionsTimeout for `member` to
+// propose a new hush session epoch to us.
+func (tp *treeProtocol) awaitHushSessionRotation(ctx context.Context, member types.Address) (rotated bool, _ error) {
+	session, alreadyExisted, err := tp.hushProto.EnsureIndividualSession(ctx, ProtocolName, member)
+	if err != nil {
+		return false, err
+	} else if !alreadyExisted {
+		// There was no session to rotate, and we just proposed a fresh one
+		return true, nil
+	}
+
+	select {
+	case <-time.After(rotateHushSessionsTimeout):
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	latest, _, err := tp.hushProto.EnsureIndividualSession(ctx, ProtocolName, member)
+	if err != nil {
+		return false, err
+	}
+	return latest.SessionID.Epoch > session.SessionID.Epoch, nil
+}
+
 func (tp *treeProtocol) broadcastToWritableSubscribers(
 	ctx context.Context,
 	stateURI string,
@@ -839,7 +1084,8 @@ func (tp *treeProtocol) broadcastToWritableSubscribers(
 			tp.Errorf("while checking ACL of state URI %v", stateURI)
 			continue
 		} else if !allowed {
-			// @@TODO: close subscription
+			// The subscriber has lost access (e.g., they were removed from a private state URI)
+			writeSub.Close()
 			continue
 		}
 
@@ -850,23 +1096,17 @@ func (tp *treeProtocol) broadcastToWritableSubscribers(
 			}
 		}
 
+		msg := SubscriptionMsg{StateURI: stateURI, Leaves: leaves}
 		if state != nil {
 			// Drill down to the part of the state that the subscriber is interested in
-			state = state.NodeAt(writeSub.Keypath(), nil)
+			msg.State = state.NodeAt(writeSub.Keypath(), nil)
 		}
-
 		if isPrivate {
-			tx = nil
+			msg.EncryptedTx = encryptedTx
 		} else {
-			encryptedTx = nil
+			msg.Tx = tx
 		}
-		writeSub.EnqueueWrite(SubscriptionMsg{
-			StateURI:    stateURI,
-			Tx:          tx,
-			EncryptedTx: encryptedTx,
-			State:       state,
-			Leaves:      leaves,
-		})
+		writeSub.EnqueueWrite(msg)
 	}
 }
 
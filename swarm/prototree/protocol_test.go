@@ -0,0 +1,163 @@
+package prototree_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/identity"
+	"redwood.dev/internal/testutils"
+	swarmmocks "redwood.dev/swarm/mocks"
+	"redwood.dev/swarm/protohush"
+	hushmocks "redwood.dev/swarm/protohush/mocks"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestTreeProtocol_UpdateMembers(t *testing.T) {
+	const stateURI = "foo.p2p/bar"
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(filepath.Join(dir, "keystore")), identity.InsecureScryptParams)
+	require.NoError(t, keyStore.Unlock("password", ""))
+	t.Cleanup(func() { keyStore.Close() })
+
+	me, err := keyStore.DefaultPublicIdentity()
+	require.NoError(t, err)
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	store, err := prototree.NewStore(testutils.SetupDBTree(t))
+	require.NoError(t, err)
+
+	peerStore := new(swarmmocks.PeerStore)
+	peerStore.On("PeersWithAddress", mock.Anything).Return(nil).Maybe()
+	peerStore.On("PeersServingStateURI", mock.Anything).Return(nil).Maybe()
+
+	t.Cleanup(prototree.SetRotateHushSessionsTimeout(300 * time.Millisecond))
+
+	// The member with the lower address in each pair rotates the session right
+	// away.  The other one waits for that to happen, and rotates it itself if
+	// it doesn't.
+	var higher, lower, lowerOffline types.Address
+	for higher.IsZero() || lower.IsZero() || lowerOffline.IsZero() {
+		addr := testutils.RandomAddress(t)
+		if me.Address().Compare(addr) < 0 {
+			higher = addr
+		} else if lower.IsZero() {
+			lower = addr
+		} else {
+			lowerOffline = addr
+		}
+	}
+	removed := testutils.RandomAddress(t)
+	removedBeforeRestart := testutils.RandomAddress(t)
+
+	session := func(epoch uint64) protohush.IndividualSessionProposal {
+		return protohush.IndividualSessionProposal{SessionID: protohush.IndividualSessionID{Epoch: epoch}}
+	}
+
+	var (
+		rotatedHigher       = testutils.NewAwaiter()
+		rotatedLowerOffline = testutils.NewAwaiter()
+		rotatedLower        = testutils.NewAwaiter()
+	)
+	hushProto := new(hushmocks.HushProtocol)
+	hushProto.On("OnGroupMessageEncrypted", prototree.ProtocolName, mock.Anything).Return()
+	hushProto.On("OnGroupMessageDecrypted", prototree.ProtocolName, mock.Anything).Return()
+	hushProto.On("EncryptGroupMessage", prototree.ProtocolName, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	hushProto.On("ProposeNextIndividualSession", mock.Anything, prototree.ProtocolName, higher).
+		Run(func(mock.Arguments) { rotatedHigher.ItHappened() }).
+		Return(protohush.IndividualSessionProposal{}, nil)
+	// `lower` rotates the session while we wait
+	hushProto.On("EnsureIndividualSession", mock.Anything, prototree.ProtocolName, lower).Return(session(1), true, nil).Once()
+	hushProto.On("EnsureIndividualSession", mock.Anything, prototree.ProtocolName, lower).Return(session(2), true, nil).Once()
+	hushProto.On("ProposeNextIndividualSession", mock.Anything, prototree.ProtocolName, lower).
+		Run(func(mock.Arguments) { rotatedLower.ItHappened() }).
+		Return(protohush.IndividualSessionProposal{}, nil).
+		Maybe()
+	// `lowerOffline` doesn't
+	hushProto.On("EnsureIndividualSession", mock.Anything, prototree.ProtocolName, lowerOffline).Return(session(1), true, nil)
+	hushProto.On("ProposeNextIndividualSession", mock.Anything, prototree.ProtocolName, lowerOffline).
+		Run(func(mock.Arguments) { rotatedLowerOffline.ItHappened() }).
+		Return(protohush.IndividualSessionProposal{}, nil)
+
+	// The member set that we saw before a restart is remembered
+	require.NoError(t, store.SaveKnownMembers(stateURI, types.NewAddressSet([]types.Address{me.Address(), removedBeforeRestart})))
+
+	treeProto := prototree.NewTreeProtocol(nil, hushProto, hub, txStore, keyStore, peerStore, store, nil, protoprune.Quorum{})
+	require.NoError(t, treeProto.Start())
+	t.Cleanup(func() { treeProto.Close() })
+
+	requireMembers := func(t *testing.T, expected ...types.Address) {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			node, err := hub.StateAtVersion(stateURI, nil)
+			if err != nil {
+				return false
+			}
+			defer node.Close()
+			members, err := tree.MembersOf(node)
+			require.NoError(t, err)
+			return len(members) == len(expected) && len(members.Intersection(types.NewAddressSet(expected))) == len(expected)
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	requireRotated := func(t *testing.T) {
+		t.Helper()
+		rotatedHigher.AwaitOrFail(t, 5*time.Second)
+		rotatedLowerOffline.NeverHappenedOrFail(t, 100*time.Millisecond)
+		rotatedLowerOffline.AwaitOrFail(t, 5*time.Second)
+		rotatedLower.NeverHappenedOrFail(t, 500*time.Millisecond)
+	}
+
+	require.Error(t, treeProto.UpdateMembers(context.Background(), "foo.bar/baz", []types.Address{higher}, nil))
+
+	err = treeProto.SendTx(context.Background(), tree.Tx{
+		ID:       tree.GenesisTxID,
+		StateURI: stateURI,
+		Patches:  []tree.Patch{{ValueJSON: []byte(`{"Members": {"` + me.Address().Hex() + `": true, "` + higher.Hex() + `": true, "` + lower.Hex() + `": true, "` + lowerOffline.Hex() + `": true}}`)}},
+	})
+	require.NoError(t, err)
+	requireMembers(t, me.Address(), higher, lower, lowerOffline)
+	requireRotated(t)
+
+	err = treeProto.UpdateMembers(context.Background(), stateURI, []types.Address{removed}, nil)
+	require.NoError(t, err)
+	requireMembers(t, me.Address(), higher, lower, lowerOffline, removed)
+	rotatedHigher.NeverHappenedOrFail(t, 500*time.Millisecond)
+	rotatedLowerOffline.NeverHappenedOrFail(t, 500*time.Millisecond)
+
+	hushProto.On("EnsureIndividualSession", mock.Anything, prototree.ProtocolName, lower).Return(session(2), true, nil).Once()
+	hushProto.On("EnsureIndividualSession", mock.Anything, prototree.ProtocolName, lower).Return(session(3), true, nil).Once()
+
+	err = treeProto.UpdateMembers(context.Background(), stateURI, nil, []types.Address{removed})
+	require.NoError(t, err)
+	requireMembers(t, me.Address(), higher, lower, lowerOffline)
+	requireRotated(t)
+	hushProto.AssertExpectations(t)
+}
@@ -11,6 +11,7 @@ import (
 	"redwood.dev/log"
 	"redwood.dev/process"
 	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/tree"
 	"redwood.dev/types"
 	"redwood.dev/utils"
@@ -33,6 +34,11 @@ type Store interface {
 	EncryptedTx(stateURI string, txID state.Version) (EncryptedTx, error)
 	SaveEncryptedTx(stateURI string, txID state.Version, etx EncryptedTx) error
 
+	KnownMembers(stateURI string) (types.AddressSet, error)
+	SaveKnownMembers(stateURI string, members types.AddressSet) error
+
+	protoprune.CertificateStore
+
 	DebugPrint()
 }
 
@@ -58,6 +64,8 @@ type storeData struct {
 	MaxPeersPerSubscription uint64                                                `tree:"maxPeersPerSubscription"`
 	TxsSeenByPeers          map[string]map[tree.StateURI]map[state.Version]uint64 `tree:"txsSeenByPeers"`
 	EncryptedTxs            map[tree.StateURI]map[state.Version]EncryptedTx       `tree:"encryptedTxs"`
+	PruneCertificates       map[tree.StateURI]protoprune.PruneCertificate         `tree:"pruneCertificates"`
+	KnownMembers            map[tree.StateURI][]types.Address                     `tree:"knownMembers"`
 }
 
 var storeRootKeypath = state.Keypath("prototree")
@@ -347,6 +355,74 @@ func (s *store) keypathForEncryptedTx(stateURI tree.StateURI, txID state.Version
 	return storeRootKeypath.Pushs("encryptedTxs").Pushs(url.QueryEscape(string(stateURI))).Pushs(txID.Hex())
 }
 
+// KnownMembers returns the last member set that we saw for the given private
+// state URI.
+func (s *store) KnownMembers(stateURI string) (types.AddressSet, error) {
+	s.dataMu.RLock()
+	defer s.dataMu.RUnlock()
+	return types.NewAddressSet(s.data.KnownMembers[tree.StateURI(stateURI)]), nil
+}
+
+func (s *store) SaveKnownMembers(stateURI string, members types.AddressSet) error {
+	s.dataMu.Lock()
+	defer s.dataMu.Unlock()
+
+	if s.data.KnownMembers == nil {
+		s.data.KnownMembers = make(map[tree.StateURI][]types.Address)
+	}
+	s.data.KnownMembers[tree.StateURI(stateURI)] = members.Slice()
+
+	node := s.db.State(true)
+	defer node.Close()
+
+	err := node.Set(s.keypathForKnownMembers(tree.StateURI(stateURI)), nil, members.Slice())
+	if err != nil {
+		return err
+	}
+	return node.Save()
+}
+
+func (s *store) keypathForKnownMembers(stateURI tree.StateURI) state.Keypath {
+	return storeRootKeypath.Pushs("knownMembers").Pushs(url.QueryEscape(string(stateURI)))
+}
+
+// PruneCertificate returns the certificate of the most recent quorum-approved
+// prune of the given state URI.
+func (s *store) PruneCertificate(stateURI string) (protoprune.PruneCertificate, error) {
+	s.dataMu.RLock()
+	defer s.dataMu.RUnlock()
+
+	cert, exists := s.data.PruneCertificates[tree.StateURI(stateURI)]
+	if !exists {
+		return protoprune.PruneCertificate{}, errors.Err404
+	}
+	return cert, nil
+}
+
+func (s *store) SavePruneCertificate(cert protoprune.PruneCertificate) error {
+	s.dataMu.Lock()
+	defer s.dataMu.Unlock()
+
+	if s.data.PruneCertificates == nil {
+		s.data.PruneCertificates = make(map[tree.StateURI]protoprune.PruneCertificate)
+	}
+	stateURI := tree.StateURI(cert.Proposal.StateURI)
+	s.data.PruneCertificates[stateURI] = cert
+
+	node := s.db.State(true)
+	defer node.Close()
+
+	err := node.Set(s.keypathForPruneCertificate(stateURI), nil, cert)
+	if err != nil {
+		return err
+	}
+	return node.Save()
+}
+
+func (s *store) keypathForPruneCertificate(stateURI tree.StateURI) state.Keypath {
+	return storeRootKeypath.Pushs("pruneCertificates").Pushs(url.QueryEscape(string(stateURI)))
+}
+
 func (s *store) DebugPrint() {
 	node := s.db.State(false)
 	defer node.Close()
@@ -0,0 +1,74 @@
+package prototree_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/errors"
+	"redwood.dev/internal/testutils"
+	"redwood.dev/state"
+	"redwood.dev/swarm/protoprune"
+	"redwood.dev/swarm/prototree"
+	"redwood.dev/types"
+)
+
+func TestStore_PruneCertificate(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupDBTree(t)
+
+	store, err := prototree.NewStore(db)
+	require.NoError(t, err)
+
+	_, err = store.PruneCertificate("foo.bar/baz")
+	require.True(t, errors.Cause(err) == errors.Err404)
+
+	cert := protoprune.PruneCertificate{
+		Proposal: protoprune.PruneProposal{
+			StateURI:       "foo.bar/baz",
+			CheckpointTxID: state.RandomVersion(),
+			StateHash:      testutils.RandomHash(t),
+		},
+		Signatures: []types.Signature{types.Signature("sig1"), types.Signature("sig2")},
+	}
+	require.NoError(t, store.SavePruneCertificate(cert))
+
+	got, err := store.PruneCertificate("foo.bar/baz")
+	require.NoError(t, err)
+	require.Equal(t, cert, got)
+
+	// The certificate survives a restart
+	store2, err := prototree.NewStore(db)
+	require.NoError(t, err)
+	got, err = store2.PruneCertificate("foo.bar/baz")
+	require.NoError(t, err)
+	require.Equal(t, cert, got)
+}
+
+func TestStore_KnownMembers(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupDBTree(t)
+
+	store, err := prototree.NewStore(db)
+	require.NoError(t, err)
+
+	members, err := store.KnownMembers("foo.p2p/baz")
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	expected := types.NewAddressSet([]types.Address{testutils.RandomAddress(t), testutils.RandomAddress(t)})
+	require.NoError(t, store.SaveKnownMembers("foo.p2p/baz", expected))
+
+	members, err = store.KnownMembers("foo.p2p/baz")
+	require.NoError(t, err)
+	require.Equal(t, expected, members)
+
+	// The member set survives a restart
+	store2, err := prototree.NewStore(db)
+	require.NoError(t, err)
+	members, err = store2.KnownMembers("foo.p2p/baz")
+	require.NoError(t, err)
+	require.Equal(t, expected, members)
+}
@@ -2,6 +2,7 @@ package prototree
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	"redwood.dev/process"
 	"redwood.dev/state"
 	"redwood.dev/swarm"
+	"redwood.dev/tree"
 	"redwood.dev/types"
 	"redwood.dev/utils"
 )
@@ -141,6 +143,15 @@ func (sub *writableSubscription) writeMessages(ctx context.Context) (err error)
 		if !sub.subscriptionType.Includes(SubscriptionType_States) {
 			msg.State = nil
 		}
+		if len(sub.keypath) > 0 {
+			// A snapshot of part of the tree can't be used to bootstrap it
+			msg.Snapshot = nil
+		}
+
+		msg, ok := scopeMsgToKeypath(msg, sub.keypath)
+		if !ok {
+			continue
+		}
 
 		ctx, cancel := context.WithTimeout(ctx, 10*time.Second) // @@TODO: make configurable?
 		defer cancel()
@@ -153,6 +164,56 @@ func (sub *writableSubscription) writeMessages(ctx context.Context) (err error)
 	}
 }
 
+// scopeMsgToKeypath narrows a tx down to the patches that touch the subtree at
+// `keypath`, with their keypaths rewritten relative to it.  It returns false if
+// the message carries a tx that doesn't touch the subtree at all.
+//
+// Scoped txs are views for the subscriber and can't be verified or rebroadcast.
+// Encrypted txs are passed through untouched.
+func scopeMsgToKeypath(msg SubscriptionMsg, keypath state.Keypath) (SubscriptionMsg, bool) {
+	if len(keypath) == 0 || msg.Tx == nil {
+		return msg, true
+	}
+
+	var patches []tree.Patch
+	for _, patch := range msg.Tx.Patches {
+		if patch.Keypath.StartsWith(keypath) {
+			patches = append(patches, tree.Patch{
+				Keypath:   patch.Keypath.RelativeTo(keypath),
+				Range:     patch.Range,
+				ValueJSON: patch.ValueJSON,
+			})
+
+		} else if keypath.StartsWith(patch.Keypath) && patch.Range == nil {
+			// The patch replaces an ancestor of the subtree, so we extract the
+			// part of its value that lands at the subscription's keypath
+			// (splices of ancestors can't be expressed relative to the subtree)
+			var val interface{}
+			if len(patch.ValueJSON) > 0 {
+				err := json.Unmarshal(patch.ValueJSON, &val)
+				if err != nil {
+					continue
+				}
+			}
+			val, _ = utils.GetValue(val, keypath.RelativeTo(patch.Keypath).PartStrings())
+
+			valueJSON, err := json.Marshal(val)
+			if err != nil {
+				continue
+			}
+			patches = append(patches, tree.Patch{ValueJSON: valueJSON})
+		}
+	}
+	if len(patches) == 0 {
+		return msg, false
+	}
+
+	tx := *msg.Tx
+	tx.Patches = patches
+	msg.Tx = &tx
+	return msg, true
+}
+
 func (sub *writableSubscription) StateURI() string           { return sub.stateURI }
 func (sub *writableSubscription) Type() SubscriptionType     { return sub.subscriptionType }
 func (sub *writableSubscription) Keypath() state.Keypath     { return sub.keypath }
@@ -312,7 +373,7 @@ func (s *multiReaderSubscription) readUntilErrorOrShutdown(ctx context.Context,
 	}
 	defer peer.Close()
 
-	peerSub, err := peer.Subscribe(ctx, s.stateURI)
+	peerSub, err := peer.Subscribe(ctx, s.stateURI, nil)
 	if err != nil {
 		s.Errorf("error subscribing to peer %v (stateURI: %v): %v", peer.DialInfo(), s.stateURI, err)
 		return
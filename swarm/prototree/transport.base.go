@@ -13,12 +13,14 @@ type BaseTreeTransport struct {
 	muAckReceivedCallbacks               sync.RWMutex
 	muWritableSubscriptionOpenedCallback sync.RWMutex
 	muP2PStateURIReceivedCallbacks       sync.RWMutex
+	muFetchTxsReceivedCallbacks          sync.RWMutex
 
 	txReceivedCallbacks                []TxReceivedCallback
 	privateTxReceivedCallbacks         []PrivateTxReceivedCallback
 	ackReceivedCallbacks               []AckReceivedCallback
 	writableSubscriptionOpenedCallback WritableSubscriptionOpenedCallback
 	p2pStateURIReceivedCallbacks       []P2PStateURIReceivedCallback
+	fetchTxsReceivedCallbacks          []FetchTxsReceivedCallback
 }
 
 type TxReceivedCallback func(tx tree.Tx, peerConn TreePeerConn)
@@ -26,6 +28,7 @@ type PrivateTxReceivedCallback func(encryptedTx EncryptedTx, peerConn TreePeerCo
 type AckReceivedCallback func(stateURI string, txID state.Version, peerConn TreePeerConn)
 type WritableSubscriptionOpenedCallback func(req SubscriptionRequest, writeSubImplFactory WritableSubscriptionImplFactory) (<-chan struct{}, error)
 type P2PStateURIReceivedCallback func(stateURI string, peerConn TreePeerConn)
+type FetchTxsReceivedCallback func(stateURI string, txIDs []state.Version, peerConn TreePeerConn)
 
 type WritableSubscriptionImplFactory func() (WritableSubscriptionImpl, error)
 
@@ -62,6 +65,12 @@ func (t *BaseTreeTransport) OnP2PStateURIReceived(handler P2PStateURIReceivedCal
 	t.p2pStateURIReceivedCallbacks = append(t.p2pStateURIReceivedCallbacks, handler)
 }
 
+func (t *BaseTreeTransport) OnFetchTxsReceived(handler FetchTxsReceivedCallback) {
+	t.muFetchTxsReceivedCallbacks.Lock()
+	defer t.muFetchTxsReceivedCallbacks.Unlock()
+	t.fetchTxsReceivedCallbacks = append(t.fetchTxsReceivedCallbacks, handler)
+}
+
 func (t *BaseTreeTransport) HandleTxReceived(tx tree.Tx, peerConn TreePeerConn) {
 	t.muTxReceivedCallbacks.RLock()
 	defer t.muTxReceivedCallbacks.RUnlock()
@@ -127,3 +136,18 @@ func (t *BaseTreeTransport) HandleP2PStateURIReceived(stateURI string, peerConn
 	}
 	wg.Wait()
 }
+
+func (t *BaseTreeTransport) HandleFetchTxsReceived(stateURI string, txIDs []state.Version, peerConn TreePeerConn) {
+	t.muFetchTxsReceivedCallbacks.RLock()
+	defer t.muFetchTxsReceivedCallbacks.RUnlock()
+	var wg sync.WaitGroup
+	wg.Add(len(t.fetchTxsReceivedCallbacks))
+	for _, handler := range t.fetchTxsReceivedCallbacks {
+		handler := handler
+		go func() {
+			defer wg.Done()
+			handler(stateURI, txIDs, peerConn)
+		}()
+	}
+	wg.Wait()
+}
@@ -72,6 +72,37 @@ func TestBaseTreeTransport_AckReceived(t *testing.T) {
 	callback2.AwaitOrFail(t, 1*time.Second)
 }
 
+func TestBaseTreeTransport_FetchTxsReceived(t *testing.T) {
+	t.Parallel()
+
+	var transport prototree.BaseTreeTransport
+
+	expectedStateURI := "foo.bar/blah"
+	expectedTxIDs := []state.Version{state.RandomVersion(), state.RandomVersion()}
+	expectedPeerConn := new(mocks.TreePeerConn)
+
+	callback1 := testutils.NewAwaiter()
+	callback2 := testutils.NewAwaiter()
+
+	transport.OnFetchTxsReceived(func(stateURI string, txIDs []state.Version, peerConn prototree.TreePeerConn) {
+		require.Equal(t, expectedStateURI, stateURI)
+		require.Equal(t, expectedTxIDs, txIDs)
+		require.Equal(t, expectedPeerConn, peerConn)
+		callback1.ItHappened()
+	})
+
+	transport.OnFetchTxsReceived(func(stateURI string, txIDs []state.Version, peerConn prototree.TreePeerConn) {
+		require.Equal(t, expectedStateURI, stateURI)
+		require.Equal(t, expectedTxIDs, txIDs)
+		require.Equal(t, expectedPeerConn, peerConn)
+		callback2.ItHappened()
+	})
+
+	transport.HandleFetchTxsReceived(expectedStateURI, expectedTxIDs, expectedPeerConn)
+	callback1.AwaitOrFail(t, 1*time.Second)
+	callback2.AwaitOrFail(t, 1*time.Second)
+}
+
 func TestBaseTreeTransport_WritableSubscriptionOpened(t *testing.T) {
 	t.Parallel()
 
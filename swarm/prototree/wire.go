@@ -1,11 +1,13 @@
 package prototree
 
 import (
+	"encoding/json"
 	"strings"
 
 	"redwood.dev/errors"
 	"redwood.dev/state"
 	"redwood.dev/swarm/protohush"
+	"redwood.dev/swarm/protoprune"
 	"redwood.dev/tree"
 )
 
@@ -16,10 +18,74 @@ type SubscriptionMsg struct {
 	State       state.Node      `json:"state,omitempty"`
 	Leaves      []state.Version `json:"leaves,omitempty"`
 	Error       error           `json:"error,omitempty"`
+
+	// Snapshot is the full state as of Tx, which must be a checkpoint.  It's
+	// sent in place of the history that was pruned before the checkpoint,
+	// along with the prune quorum's certificate vouching for its state hash.
+	Snapshot              state.Node                   `json:"snapshot,omitempty"`
+	CheckpointCertificate *protoprune.PruneCertificate `json:"checkpointCertificate,omitempty"`
+}
+
+func (msg *SubscriptionMsg) UnmarshalJSON(bs []byte) error {
+	var m struct {
+		StateURI    string          `json:"stateURI"`
+		Tx          *tree.Tx        `json:"tx,omitempty"`
+		EncryptedTx *EncryptedTx    `json:"encryptedTx,omitempty"`
+		State       json.RawMessage `json:"state,omitempty"`
+		Leaves      []state.Version `json:"leaves,omitempty"`
+		Snapshot    json.RawMessage `json:"snapshot,omitempty"`
+
+		CheckpointCertificate *protoprune.PruneCertificate `json:"checkpointCertificate,omitempty"`
+	}
+	err := json.Unmarshal(bs, &m)
+	if err != nil {
+		return err
+	}
+
+	stateNode, err := unmarshalStateNode(m.State)
+	if err != nil {
+		return errors.Wrap(err, "while decoding state")
+	}
+	snapshot, err := unmarshalStateNode(m.Snapshot)
+	if err != nil {
+		return errors.Wrap(err, "while decoding snapshot")
+	}
+
+	*msg = SubscriptionMsg{
+		StateURI:    m.StateURI,
+		Tx:          m.Tx,
+		EncryptedTx: m.EncryptedTx,
+		State:       stateNode,
+		Leaves:      m.Leaves,
+		Snapshot:    snapshot,
+
+		CheckpointCertificate: m.CheckpointCertificate,
+	}
+	return nil
+}
+
+func unmarshalStateNode(bs json.RawMessage) (state.Node, error) {
+	if len(bs) == 0 || string(bs) == "null" {
+		return nil, nil
+	}
+	var val interface{}
+	err := json.Unmarshal(bs, &val)
+	if err != nil {
+		return nil, err
+	}
+	return state.NewMemoryNodeWithValue(val), nil
 }
 
 type EncryptedTx = protohush.GroupMessage
 
+// FetchTxsResponse carries the txs that a peer asked for by ID (see
+// TreePeerConn.FetchTxs).  The txs of private state URIs are sent encrypted.
+// Txs that the responding peer doesn't have are simply left out.
+type FetchTxsResponse struct {
+	Txs          []tree.Tx     `json:"txs,omitempty"`
+	EncryptedTxs []EncryptedTx `json:"encryptedTxs,omitempty"`
+}
+
 type SubscriptionType uint8
 
 const (
@@ -0,0 +1,227 @@
+package tree
+
+import (
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+	"redwood.dev/types"
+	"redwood.dev/ucan"
+)
+
+// An ACLPolicy decides who may read a state URI and send txs to it.  A state
+// URI declares its policy with a config node at the root of its tree, much
+// like a Validator:
+//
+//	"ACL": { "Content-Type": "acl/public", "writers": ["96216849c4...", ...] }
+//
+// Write access is enforced by the controller before each tx is applied, so it
+// may only depend on the state the tx is applied to.  Read access is enforced
+// by each node when it serves the state URI, and may also take into account
+// the UCANs that the readers have presented to it.
+type ACLPolicy interface {
+	// Private policies limit the state URI's txs to its Members, and cause
+	// them to be encrypted when they're sent to peers.
+	Private() bool
+	HasWriteAccess(root state.Node, sender types.Address) (bool, error)
+	HasReadAccess(stateURI string, root state.Node, keypath state.Keypath, addresses types.AddressSet, ucanVerifier *ucan.Verifier) (bool, error)
+}
+
+type ACLPolicyConstructor func(config state.Node) (ACLPolicy, error)
+
+// ACLKeypath is the root config node that declares a state URI's ACLPolicy.
+var ACLKeypath = state.Keypath("ACL")
+
+var aclPolicyRegistry = map[string]ACLPolicyConstructor{
+	"acl/public":    NewPublicACLPolicy,
+	"acl/private":   NewPrivateACLPolicy,
+	"acl/allowlist": NewAllowlistACLPolicy,
+	"acl/ucan":      NewUCANACLPolicy,
+}
+
+var ErrNoWriteAccess = errors.New("tx sender does not have write access to state URI")
+
+// RegisterACLPolicy makes an ACLPolicy available to state URIs under the given
+// Content-Type.
+func RegisterACLPolicy(contentType string, ctor ACLPolicyConstructor) {
+	aclPolicyRegistry[contentType] = ctor
+}
+
+// DefaultACLPolicy returns the policy that applies to a state URI that
+// doesn't declare one, or nil if anyone may read and write it.
+func DefaultACLPolicy(stateURI string) ACLPolicy {
+	if StateURIIsPrivate(stateURI) {
+		return privateACLPolicy{}
+	}
+	return nil
+}
+
+// aclPolicyOf returns the policy declared by the given state, or nil if there
+// isn't one.
+func aclPolicyOf(root state.Node, stateResolver nelson.StateResolver, blobResolver nelson.BlobResolver) (ACLPolicy, error) {
+	config, err := root.CopyToMemory(ACLKeypath, nil)
+	if errors.Cause(err) == errors.Err404 {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	config, anyMissing, err := nelson.Resolve(config, stateResolver, blobResolver)
+	if err != nil {
+		return nil, err
+	} else if anyMissing {
+		return nil, errors.WithStack(ErrMissingCriticalBlobs)
+	}
+
+	contentType, err := nelson.GetContentType(config)
+	if err != nil {
+		return nil, err
+	} else if contentType == "" {
+		return nil, errors.New("cannot initialize ACL without a 'Content-Type' key")
+	}
+
+	ctor, exists := aclPolicyRegistry[contentType]
+	if !exists {
+		return nil, errors.Errorf("unknown ACL type '%v'", contentType)
+	}
+	return ctor(config)
+}
+
+// The public policy lets anyone read the state URI.  If its config has a
+// "writers" list, only those addresses may write to it.
+type publicACLPolicy struct {
+	writers types.AddressSet
+}
+
+func NewPublicACLPolicy(config state.Node) (ACLPolicy, error) {
+	writers, _, err := aclAddressList(config, "writers")
+	if err != nil {
+		return nil, err
+	}
+	return publicACLPolicy{writers: writers}, nil
+}
+
+func (p publicACLPolicy) Private() bool { return false }
+
+func (p publicACLPolicy) HasWriteAccess(root state.Node, sender types.Address) (bool, error) {
+	return p.writers == nil || p.writers.Contains(sender), nil
+}
+
+func (p publicACLPolicy) HasReadAccess(stateURI string, root state.Node, keypath state.Keypath, addresses types.AddressSet, ucanVerifier *ucan.Verifier) (bool, error) {
+	return true, nil
+}
+
+// The private policy limits the state URI to the addresses in its Members
+// subtree.  It's the default for state URIs under ".p2p".
+type privateACLPolicy struct{}
+
+func NewPrivateACLPolicy(config state.Node) (ACLPolicy, error) {
+	return privateACLPolicy{}, nil
+}
+
+func (p privateACLPolicy) Private() bool { return true }
+
+func (p privateACLPolicy) HasWriteAccess(root state.Node, sender types.Address) (bool, error) {
+	members, err := MembersOf(root)
+	if err != nil {
+		return false, err
+	}
+	return members.Contains(sender), nil
+}
+
+func (p privateACLPolicy) HasReadAccess(stateURI string, root state.Node, keypath state.Keypath, addresses types.AddressSet, ucanVerifier *ucan.Verifier) (bool, error) {
+	members, err := MembersOf(root)
+	if err != nil {
+		return false, err
+	}
+	return len(members.Intersection(addresses)) > 0, nil
+}
+
+// The allowlist policy lets the addresses in its config's "readers" list read
+// the state URI, and those in its "writers" list (which defaults to the
+// readers) read and write it.  Unlike a private state URI, its txs aren't
+// encrypted.
+type allowlistACLPolicy struct {
+	readers types.AddressSet
+	writers types.AddressSet
+}
+
+func NewAllowlistACLPolicy(config state.Node) (ACLPolicy, error) {
+	readers, exists, err := aclAddressList(config, "readers")
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errors.New("allowlist ACL needs a 'readers' list")
+	}
+	writers, exists, err := aclAddressList(config, "writers")
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		writers = readers
+	}
+	return allowlistACLPolicy{readers: readers, writers: writers}, nil
+}
+
+func (p allowlistACLPolicy) Private() bool { return false }
+
+func (p allowlistACLPolicy) HasWriteAccess(root state.Node, sender types.Address) (bool, error) {
+	return p.writers.Contains(sender), nil
+}
+
+func (p allowlistACLPolicy) HasReadAccess(stateURI string, root state.Node, keypath state.Keypath, addresses types.AddressSet, ucanVerifier *ucan.Verifier) (bool, error) {
+	return len(p.readers.Intersection(addresses)) > 0 || len(p.writers.Intersection(addresses)) > 0, nil
+}
+
+// The UCAN policy only lets peers read the state URI (or the part of it
+// they've asked for) if they've presented a UCAN granting them read access.
+// Like the public policy, its config may have a "writers" list.
+type ucanACLPolicy struct {
+	writers types.AddressSet
+}
+
+func NewUCANACLPolicy(config state.Node) (ACLPolicy, error) {
+	writers, _, err := aclAddressList(config, "writers")
+	if err != nil {
+		return nil, err
+	}
+	return ucanACLPolicy{writers: writers}, nil
+}
+
+func (p ucanACLPolicy) Private() bool { return false }
+
+func (p ucanACLPolicy) HasWriteAccess(root state.Node, sender types.Address) (bool, error) {
+	return p.writers == nil || p.writers.Contains(sender), nil
+}
+
+func (p ucanACLPolicy) HasReadAccess(stateURI string, root state.Node, keypath state.Keypath, addresses types.AddressSet, ucanVerifier *ucan.Verifier) (bool, error) {
+	if ucanVerifier == nil {
+		return false, nil
+	}
+	return ucanVerifier.HasCapability(addresses, ucan.Capability{StateURI: stateURI, Keypath: keypath, Ability: ucan.AbilityRead})
+}
+
+func aclAddressList(config state.Node, key string) (types.AddressSet, bool, error) {
+	val, exists, err := nelson.GetValueRecursive(config, state.Keypath(key), nil)
+	if err != nil {
+		return nil, false, err
+	} else if !exists {
+		return nil, false, nil
+	}
+
+	list, isList := val.([]interface{})
+	if !isList {
+		return nil, false, errors.Errorf("ACL '%v' must be a list of addresses", key)
+	}
+	addrs := types.NewAddressSet(nil)
+	for _, x := range list {
+		addrHex, isString := x.(string)
+		if !isString {
+			return nil, false, errors.Errorf("ACL '%v' must be a list of addresses", key)
+		}
+		addr, err := types.AddressFromHex(addrHex)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "bad address in ACL '%v'", key)
+		}
+		addrs.Add(addr)
+	}
+	return addrs, true, nil
+}
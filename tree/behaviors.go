@@ -19,28 +19,86 @@ type Validator interface {
 	ValidateTx(node state.Node, tx *Tx) error
 }
 
+// ReadAccessValidator is implemented by validators that also govern who can
+// read the subtree they're attached to.
+type ReadAccessValidator interface {
+	Validator
+	HasReadAccess(node state.Node, keypath state.Keypath, addresses types.AddressSet) (bool, error)
+}
+
+// RateLimiter is implemented by validators that also limit how often a sender
+// may submit txs.  Unlike validation, rate limiting depends on when txs
+// arrive, so it's only applied to txs that this node writes on a client's
+// behalf (RPC SendTx and traditional HTTP writes).  Txs that arrive already
+// signed are never rate limited, since they can't be told apart from txs
+// being replicated from peers.
+type RateLimiter interface {
+	Validator
+	CheckRateLimits(node state.Node, tx *Tx) error
+}
+
 type Indexer interface {
 	IndexNode(relKeypath state.Keypath, node state.Node) (state.Keypath, state.Node, error)
 }
 
+// OrderedIndexer is implemented by indexers whose keys are made up of a fixed
+// number of fields (one keypath part each) that sort in a meaningful order.
+// Their indices can be searched with an IndexQuery.
+type OrderedIndexer interface {
+	Indexer
+	NumFields() int
+	// EncodeField encodes a value of the given field the same way that it's
+	// encoded in the indexer's keys.
+	EncodeField(idx int, value interface{}) (state.Keypath, error)
+}
+
+// TextIndexer is implemented by indexers that maintain an inverted index of
+// the terms in the text of each child.  Their indices can be searched with
+// Controller.SearchText.
+type TextIndexer interface {
+	state.MultiIndexer
+	// Tokenize splits text into terms the same way that the indexer does.
+	Tokenize(text string) []string
+}
+
+// ClosableBehavior is implemented by resolvers, validators, and indexers that
+// hold resources outside of the Go heap (like a WASM runtime).  The controller
+// closes them once the behavior tree replaces or removes them.
+type ClosableBehavior interface {
+	Close() error
+}
+
 type ResolverConstructor func(config state.Node, internalState map[string]interface{}) (Resolver, error)
 type ValidatorConstructor func(config state.Node) (Validator, error)
 type IndexerConstructor func(config state.Node) (Indexer, error)
 
 var resolverRegistry = map[string]ResolverConstructor{
-	"resolver/dumb": NewDumbResolver,
-	"resolver/lua":  NewLuaResolver,
-	"resolver/js":   NewJSResolver,
+	"resolver/dumb":  NewDumbResolver,
+	"resolver/lua":   NewLuaResolver,
+	"resolver/js":    NewJSResolver,
+	"resolver/sync9": NewSync9Resolver,
+	"resolver/wasm":  NewWASMResolver,
 	// "resolver/git":  NewGitResolver,
-	//"resolver/stack": NewStackResolver,
 }
 var validatorRegistry = map[string]ValidatorConstructor{
 	"validator/permissions": NewPermissionsValidator,
-	// "stack":       NewStackValidator,
+	"validator/schema":      NewSchemaValidator,
+	"validator/wasm":        NewWASMValidator,
 }
 var indexerRegistry = map[string]IndexerConstructor{
-	"indexer/keypath": NewKeypathIndexer,
-	"indexer/js":      NewJSIndexer,
+	"indexer/keypath":   NewKeypathIndexer,
+	"indexer/compound":  NewCompoundIndexer,
+	"indexer/aggregate": NewAggregateIndexer,
+	"indexer/fulltext":  NewFulltextIndexer,
+	"indexer/js":        NewJSIndexer,
+	"indexer/wasm":      NewWASMIndexer,
+}
+
+func init() {
+	// The stack behaviors construct their stages from these registries, so they
+	// have to be registered here to avoid an initialization cycle
+	resolverRegistry["resolver/stack"] = NewStackResolver
+	validatorRegistry["validator/stack"] = NewStackValidator
 }
 
 type behaviorTree struct {
@@ -50,6 +108,9 @@ type behaviorTree struct {
 	resolverKeypaths  []state.Keypath
 	resolvers         map[string]Resolver
 	indexers          map[string]map[string]Indexer
+	// displaced holds the closable behaviors that have been replaced or
+	// removed since the tree was copied
+	displaced []ClosableBehavior
 }
 
 func newBehaviorTree() *behaviorTree {
@@ -63,6 +124,7 @@ func newBehaviorTree() *behaviorTree {
 
 func (t *behaviorTree) copy() *behaviorTree {
 	cp := &behaviorTree{
+		Logger:            t.Logger,
 		validatorKeypaths: make([]state.Keypath, len(t.validatorKeypaths)),
 		validators:        make(map[string]Validator, len(t.validators)),
 		resolverKeypaths:  make([]state.Keypath, len(t.resolverKeypaths)),
@@ -90,6 +152,58 @@ func (t *behaviorTree) copy() *behaviorTree {
 	return cp
 }
 
+// held returns the closable behaviors in the tree.
+func (t *behaviorTree) held() map[ClosableBehavior]struct{} {
+	held := make(map[ClosableBehavior]struct{})
+	add := func(behavior interface{}) {
+		if closable, is := behavior.(ClosableBehavior); is {
+			held[closable] = struct{}{}
+		}
+	}
+	for _, validator := range t.validators {
+		add(validator)
+	}
+	for _, resolver := range t.resolvers {
+		add(resolver)
+	}
+	for _, indexers := range t.indexers {
+		for _, indexer := range indexers {
+			add(indexer)
+		}
+	}
+	return held
+}
+
+func (t *behaviorTree) displace(behavior interface{}) {
+	if closable, is := behavior.(ClosableBehavior); is {
+		t.displaced = append(t.displaced, closable)
+	}
+}
+
+// closeBehaviorsNotIn closes the behaviors that either tree has displaced, as
+// well as those that this one holds, unless `kept` holds them.  It's called
+// with the old tree once a new one replaces it, and with the new tree if it's
+// discarded instead.
+func (t *behaviorTree) closeBehaviorsNotIn(kept *behaviorTree) {
+	keep := kept.held()
+	toClose := t.held()
+	for _, closable := range append(t.displaced, kept.displaced...) {
+		toClose[closable] = struct{}{}
+	}
+	t.displaced = nil
+	kept.displaced = nil
+
+	for closable := range toClose {
+		if _, exists := keep[closable]; exists {
+			continue
+		}
+		err := closable.Close()
+		if err != nil {
+			t.Errorf("error closing behavior: %v", err)
+		}
+	}
+}
+
 func (t *behaviorTree) debugPrint() {
 	t.Debugf("BehaviorTree:\n----------------------------------------")
 	for i := range t.validatorKeypaths {
@@ -111,14 +225,18 @@ func (t *behaviorTree) addResolver(keypath state.Keypath, resolver Resolver) {
 		t.resolverKeypaths = append(t.resolverKeypaths, keypath)
 		// @@TODO: sucks
 		sort.Slice(t.resolverKeypaths, func(i, j int) bool { return bytes.Compare(t.resolverKeypaths[i], t.resolverKeypaths[j]) < 0 })
+	} else {
+		t.displace(t.resolvers[string(keypath)])
 	}
 	t.resolvers[string(keypath)] = resolver
 }
 
 func (t *behaviorTree) removeResolver(keypath state.Keypath) {
-	if _, exists := t.resolvers[string(keypath)]; !exists {
+	resolver, exists := t.resolvers[string(keypath)]
+	if !exists {
 		return
 	}
+	t.displace(resolver)
 	delete(t.resolvers, string(keypath))
 	var idx int
 	for i, kp := range t.resolverKeypaths {
@@ -136,14 +254,18 @@ func (t *behaviorTree) addValidator(keypath state.Keypath, validator Validator)
 		t.validatorKeypaths = append(t.validatorKeypaths, keypath)
 		// @@TODO: sucks
 		sort.Slice(t.validatorKeypaths, func(i, j int) bool { return bytes.Compare(t.validatorKeypaths[i], t.validatorKeypaths[j]) < 0 })
+	} else {
+		t.displace(t.validators[string(keypath)])
 	}
 	t.validators[string(keypath)] = validator
 }
 
 func (t *behaviorTree) removeValidator(keypath state.Keypath) {
-	if _, exists := t.validators[string(keypath)]; !exists {
+	validator, exists := t.validators[string(keypath)]
+	if !exists {
 		return
 	}
+	t.displace(validator)
 	delete(t.validators, string(keypath))
 	var idx int
 	for i, kp := range t.validatorKeypaths {
@@ -159,14 +281,18 @@ func (t *behaviorTree) removeValidator(keypath state.Keypath) {
 func (t *behaviorTree) addIndexer(keypath state.Keypath, indexName state.Keypath, indexer Indexer) {
 	if _, exists := t.indexers[string(keypath)]; !exists {
 		t.indexers[string(keypath)] = make(map[string]Indexer)
+	} else if old, exists := t.indexers[string(keypath)][string(indexName)]; exists {
+		t.displace(old)
 	}
 	t.indexers[string(keypath)][string(indexName)] = indexer
 }
 
 func (t *behaviorTree) removeIndexer(keypath state.Keypath, indexName state.Keypath) {
-	if _, exists := t.indexers[string(keypath)]; !exists {
+	indexer, exists := t.indexers[string(keypath)][string(indexName)]
+	if !exists {
 		return
 	}
+	t.displace(indexer)
 	delete(t.indexers[string(keypath)], string(indexName))
 }
 
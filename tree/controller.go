@@ -2,6 +2,7 @@ package tree
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"redwood.dev/process"
 	"redwood.dev/state"
 	"redwood.dev/tree/nelson"
+	"redwood.dev/types"
 	"redwood.dev/utils"
 	"redwood.dev/utils/badgerutils"
 )
@@ -25,7 +27,16 @@ type Controller interface {
 	AddTx(tx Tx) error
 	StateAtVersion(version *state.Version) state.Node
 	QueryIndex(version *state.Version, keypath state.Keypath, indexName state.Keypath, queryParam state.Keypath, rng *state.Range) (state.Node, error)
+	SearchIndex(version *state.Version, keypath state.Keypath, indexName state.Keypath, query IndexQuery, rng *state.Range) (state.Node, error)
+	SearchText(version *state.Version, keypath state.Keypath, indexName state.Keypath, query string, rng *state.Range) (state.Node, error)
+	RebuildIndices() (int, error)
+	HasReadAccess(keypath state.Keypath, addresses types.AddressSet) (bool, error)
+	CheckRateLimits(tx Tx) error
+	ACLPolicy() (ACLPolicy, error)
 	Leaves() ([]state.Version, error)
+	Mempool() []Tx
+	Prune(checkpointTxID state.Version) (int, error)
+	ImportCheckpoint(tx Tx, snapshot state.Node, stateHash types.Hash) error
 	OnNewState(fn NewStateCallback)
 	DebugPrint()
 }
@@ -52,6 +63,9 @@ type controller struct {
 
 	mempool Mempool
 	addTxMu sync.Mutex
+
+	// Held while applying txs so that the history can't be pruned out from under them
+	historyMu sync.Mutex
 }
 
 type NewStateCallback func(tx Tx, state state.Node, leaves []state.Version)
@@ -59,6 +73,7 @@ type NewStateCallback func(tx Tx, state state.Node, leaves []state.Version)
 var (
 	MergeTypeKeypath = state.Keypath("Merge-Type")
 	ValidatorKeypath = state.Keypath("Validator")
+	IndicesKeypath   = state.Keypath("Indices")
 )
 
 func NewController(
@@ -164,7 +179,9 @@ func (c *controller) Close() error {
 		}
 	}
 
-	return c.Process.Close()
+	err := c.Process.Close()
+	c.behaviorTree.closeBehaviorsNotIn(newBehaviorTree())
+	return err
 }
 
 func (c *controller) StateAtVersion(version *state.Version) state.Node {
@@ -201,8 +218,10 @@ func (c *controller) AddTx(tx Tx) error {
 	return nil
 }
 
-func (c *controller) Mempool() *txSortedSet {
-	return c.mempool.Get()
+// Mempool returns the txs that are waiting to be applied (usually because
+// their parents haven't arrived yet), in the order they were received.
+func (c *controller) Mempool() []Tx {
+	return c.mempool.Get().slice()
 }
 
 var (
@@ -214,6 +233,11 @@ var (
 	ErrTxMissingParents     = errors.New("tx must have parents")
 	ErrMissingCriticalBlobs = errors.New("missing critical blobs")
 	ErrSenderIsNotAMember   = errors.New("tx sender is not a member of state URI")
+	ErrNotCheckpoint        = errors.New("tx is not a checkpoint")
+	ErrCheckpointNotMerged  = errors.New("checkpoint does not merge every branch of history")
+	ErrAlreadyHaveHistory   = errors.New("already have history for state URI")
+	ErrBadSnapshot          = errors.New("checkpoint snapshot doesn't match its state hash")
+	ErrRateLimited          = errors.New("rate limit exceeded")
 )
 
 func (c *controller) processMempoolTx(tx Tx) processTxOutcome {
@@ -227,7 +251,7 @@ func (c *controller) processMempoolTx(tx Tx) processTxOutcome {
 	}
 
 	switch errors.Cause(err) {
-	case ErrTxMissingParents, ErrInvalidParent, ErrInvalidSignature, ErrInvalidTx:
+	case ErrTxMissingParents, ErrInvalidParent, ErrInvalidSignature, ErrInvalidTx, ErrSenderIsNotAMember, ErrNoWriteAccess:
 		c.Errorf("invalid tx %v: %+v: %v", tx.ID.Pretty(), err, utils.PrettyJSON(tx))
 		return processTxOutcome_Failed
 
@@ -244,6 +268,9 @@ func (c *controller) processMempoolTx(tx Tx) processTxOutcome {
 func (c *controller) tryApplyTx(tx Tx) (err error) {
 	defer errors.Annotate(&err, "stateURI=%v tx=%v", tx.StateURI, tx.ID.Pretty())
 
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
 	//
 	// Validate the tx's intrinsics
 	//
@@ -254,6 +281,13 @@ func (c *controller) tryApplyTx(tx Tx) (err error) {
 	for _, parentID := range tx.Parents {
 		parentTx, err := c.txStore.FetchTx(tx.StateURI, parentID)
 		if errors.Cause(err) == errors.Err404 {
+			// History before a checkpoint is final once it has been pruned
+			pruned, err := c.txStore.TxWasPruned(tx.StateURI, parentID)
+			if err != nil {
+				return errors.Wrapf(err, "parent=%v", parentID.Pretty())
+			} else if pruned {
+				return errors.Wrapf(ErrInvalidParent, "parent=%v was pruned", parentID.Pretty())
+			}
 			return errors.Wrapf(ErrNoParentYet, "parent=%v", parentID.Pretty())
 		} else if err != nil {
 			return errors.Wrapf(err, "parent=%v", parentID.Pretty())
@@ -271,14 +305,29 @@ func (c *controller) tryApplyTx(tx Tx) (err error) {
 		return ErrInvalidSignature
 	} else if sigPubKey.Address() != tx.From {
 		return errors.Wrapf(ErrInvalidSignature, "address doesn't match (expected=%v received=%v)", tx.From.Hex(), sigPubKey.Address().Hex())
-		// } else if c.isPrivate && !c.members.Contains(sigPubKey.Address()) {
-		// 	return errors.Wrapf(ErrSenderIsNotAMember, "tx=%v stateURI=%v sender=%v", tx.ID, tx.StateURI, sigPubKey.Address())
-		// @@TODO
 	}
 
 	root := c.states.StateAtVersion(nil, true)
 	defer root.Close()
 
+	// Enforce the state URI's ACL policy.  The genesis tx is exempt, as it's
+	// what declares the policy (and, for private state URIs, the members).
+	policy, err := c.aclPolicy(root)
+	if err != nil {
+		return err
+	}
+	isPrivate := policy != nil && policy.Private()
+	if policy != nil && tx.ID != GenesisTxID {
+		allowed, err := policy.HasWriteAccess(root, tx.From)
+		if err != nil {
+			return err
+		} else if !allowed && isPrivate {
+			return c.rejectTx(tx, errors.Wrapf(ErrSenderIsNotAMember, "sender=%v", tx.From.Hex()))
+		} else if !allowed {
+			return c.rejectTx(tx, errors.Wrapf(ErrNoWriteAccess, "sender=%v", tx.From.Hex()))
+		}
+	}
+
 	//
 	// Validate the tx's extrinsics
 	//
@@ -309,13 +358,7 @@ func (c *controller) tryApplyTx(tx Tx) (err error) {
 			validator := c.behaviorTree.validators[string(validatorKeypath)]
 			err := validator.ValidateTx(root.NodeAt(validatorKeypath, nil), &txCopy)
 			if err != nil {
-				// Mark the tx invalid and save it to the DB
-				tx.Status = TxStatusInvalid
-				err2 := c.txStore.AddTx(tx)
-				if err2 != nil {
-					return err2
-				}
-				return errors.Wrap(ErrInvalidTx, err.Error())
+				return c.rejectTx(tx, errors.Wrap(ErrInvalidTx, err.Error()))
 			}
 
 			patches = unprocessedPatches
@@ -397,8 +440,29 @@ func (c *controller) tryApplyTx(tx Tx) (err error) {
 		}
 	}
 
+	// A tx that changes the ACL config must leave behind a usable policy
+	aclChanged := patchesTouch(tx.Patches, ACLKeypath)
+	if aclChanged {
+		policy, err = c.aclPolicy(root)
+		if errors.Cause(err) == ErrMissingCriticalBlobs {
+			return err
+		} else if err != nil {
+			return c.rejectTx(tx, errors.Wrapf(ErrInvalidTx, "bad ACL: %v", err))
+		}
+		isPrivate = policy != nil && policy.Private()
+	}
+
+	if isPrivate && (aclChanged || patchesTouch(tx.Patches, MembersKeypath)) {
+		err = validateMembers(root)
+		if err != nil {
+			return c.rejectTx(tx, errors.Wrap(ErrInvalidTx, err.Error()))
+		}
+	}
+
 	c.handleNewBlobs(root)
 
+	oldIndexers := c.behaviorTree.indexers
+
 	err = c.updateBehaviorTree(root)
 	if err != nil {
 		return err
@@ -409,6 +473,13 @@ func (c *controller) tryApplyTx(tx Tx) (err error) {
 		return err
 	}
 
+	// The indices live in a separate DB, so they can't be updated atomically
+	// with the state.  If this fails, they can be fixed with RebuildIndices.
+	err = c.updateIndices(root.Diff(), oldIndexers)
+	if err != nil {
+		c.Errorf("error updating indices: %v", err)
+	}
+
 	if tx.Checkpoint {
 		err = c.states.CopyVersion(tx.ID, state.CurrentVersion)
 		if err != nil {
@@ -449,6 +520,221 @@ func (c *controller) tryApplyTx(tx Tx) (err error) {
 	return nil
 }
 
+// rejectTx marks the tx invalid and saves it to the DB, so that it isn't
+// retried and any txs that descend from it are rejected as well.
+func (c *controller) rejectTx(tx Tx, reason error) error {
+	tx.Status = TxStatusInvalid
+	err := c.txStore.AddTx(tx)
+	if err != nil {
+		return err
+	}
+	return reason
+}
+
+// Prune collapses the history before a checkpoint tx into the snapshot of the
+// state that was saved when the checkpoint was applied.  The checkpoint's
+// ancestors are deleted from the tx store (along with any older snapshots),
+// and the checkpoint becomes the first tx in the state URI's history.
+//
+// The checkpoint must merge every branch of history known at the time, since
+// any tx that isn't its ancestor or descendant couldn't be replayed on top of
+// the snapshot.  Txs that arrive later with a pruned parent are rejected.
+func (c *controller) Prune(checkpointTxID state.Version) (_ int, err error) {
+	defer errors.Annotate(&err, "stateURI=%v checkpoint=%v", c.stateURI, checkpointTxID.Pretty())
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	checkpointTx, err := c.txStore.FetchTx(c.stateURI, checkpointTxID)
+	if err != nil {
+		return 0, err
+	} else if !checkpointTx.Checkpoint {
+		return 0, ErrNotCheckpoint
+	} else if checkpointTx.Status != TxStatusValid {
+		return 0, errors.Wrapf(ErrNotCheckpoint, "checkpoint has status %v", checkpointTx.Status)
+	}
+
+	pruned := state.NewVersionSet(nil)
+	var oldCheckpoints []state.Version
+	err = walkTxAncestors(c.txStore, c.stateURI, checkpointTx.Parents, true, func(tx Tx) bool {
+		pruned.Add(tx.ID)
+		if tx.Checkpoint {
+			oldCheckpoints = append(oldCheckpoints, tx.ID)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	} else if len(pruned) == 0 {
+		return 0, nil
+	}
+
+	all, err := collectTxIDs(c.txStore.AllTxsForStateURI(c.stateURI, GenesisTxID))
+	if err != nil {
+		return 0, err
+	}
+	retained, err := collectTxIDs(c.txStore.AllTxsForStateURI(c.stateURI, checkpointTxID))
+	if err != nil {
+		return 0, err
+	}
+	for txID := range all {
+		_, isPruned := pruned[txID]
+		_, isRetained := retained[txID]
+		if !isPruned && !isRetained {
+			return 0, errors.Wrapf(ErrCheckpointNotMerged, "tx=%v", txID.Pretty())
+		}
+	}
+
+	err = c.txStore.PruneTxs(c.stateURI, checkpointTxID, pruned.Slice())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, txID := range oldCheckpoints {
+		err := c.states.DeleteVersion(txID)
+		if err != nil {
+			c.Errorf("while deleting snapshot for old checkpoint %v: %v", txID.Pretty(), err)
+		}
+	}
+	return len(pruned), nil
+}
+
+// ImportCheckpoint bootstraps a state URI that we have no history for from a
+// peer's snapshot of the state as of a checkpoint tx.  The checkpoint becomes
+// the first tx in our history, and its parents are treated as pruned.
+//
+// The snapshot bypasses validation entirely, so the caller must have obtained
+// `stateHash` from a source that it trusts (such as a prune quorum's
+// certificate).  The snapshot is rejected unless it has that hash.
+func (c *controller) ImportCheckpoint(tx Tx, snapshot state.Node, stateHash types.Hash) (err error) {
+	defer errors.Annotate(&err, "stateURI=%v checkpoint=%v", c.stateURI, tx.ID.Pretty())
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	if !tx.Checkpoint {
+		return ErrNotCheckpoint
+	}
+
+	leaves, err := c.txStore.Leaves(c.stateURI)
+	if err != nil {
+		return err
+	} else if len(leaves) > 0 {
+		return ErrAlreadyHaveHistory
+	}
+
+	sigPubKey, err := crypto.RecoverSigningPubkey(tx.Hash(), tx.Sig)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	} else if sigPubKey.Address() != tx.From {
+		return errors.Wrapf(ErrInvalidSignature, "address doesn't match (expected=%v received=%v)", tx.From.Hex(), sigPubKey.Address().Hex())
+	}
+
+	snapshotHash, err := SnapshotHash(snapshot)
+	if err != nil {
+		return err
+	} else if snapshotHash != stateHash {
+		return errors.Wrapf(ErrBadSnapshot, "expected=%v received=%v", stateHash.Hex(), snapshotHash.Hex())
+	}
+
+	root := c.states.StateAtVersion(nil, true)
+	defer root.Close()
+
+	err = root.Set(nil, nil, snapshot)
+	if err != nil {
+		return err
+	}
+
+	c.handleNewBlobs(root)
+
+	oldIndexers := c.behaviorTree.indexers
+
+	err = c.updateBehaviorTree(root)
+	if err != nil {
+		return err
+	}
+
+	err = root.Save()
+	if err != nil {
+		return err
+	}
+
+	// The indices live in a separate DB, so they can't be updated atomically
+	// with the state.  If this fails, they can be fixed with RebuildIndices.
+	err = c.updateIndices(root.Diff(), oldIndexers)
+	if err != nil {
+		c.Errorf("error updating indices: %v", err)
+	}
+
+	err = c.states.CopyVersion(tx.ID, state.CurrentVersion)
+	if err != nil {
+		return err
+	}
+
+	err = c.txStore.PruneTxs(c.stateURI, tx.ID, tx.Parents)
+	if err != nil {
+		return err
+	}
+
+	err = c.txStore.MarkLeaf(c.stateURI, tx.ID)
+	if err != nil {
+		return err
+	}
+
+	tx.Status = TxStatusValid
+	err = c.txStore.AddTx(tx)
+	if err != nil {
+		return err
+	}
+
+	c.Successf("imported checkpoint (%v) %v", tx.StateURI, tx.ID.Pretty())
+
+	node := c.states.StateAtVersion(nil, false)
+	defer node.Close()
+	c.notifyNewStateListeners(tx, node, []state.Version{tx.ID})
+	return nil
+}
+
+// SnapshotHash returns the hash of the canonical JSON encoding of a snapshot
+// of a state URI's state.  Peers that agree on the state as of a checkpoint
+// agree on its hash, regardless of how the snapshot was stored or transmitted.
+func SnapshotHash(snapshot state.Node) (types.Hash, error) {
+	val, _, err := snapshot.Value(nil, nil)
+	if err != nil {
+		return types.Hash{}, err
+	}
+	// Round-trip through JSON so that numbers and other values are normalized
+	// to the types that a peer decoding the snapshot would see
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return types.Hash{}, errors.WithStack(err)
+	}
+	var normalized interface{}
+	err = json.Unmarshal(bs, &normalized)
+	if err != nil {
+		return types.Hash{}, errors.WithStack(err)
+	}
+	bs, err = json.Marshal(normalized)
+	if err != nil {
+		return types.Hash{}, errors.WithStack(err)
+	}
+	return types.HashBytes(bs), nil
+}
+
+func collectTxIDs(iter TxIterator) (state.VersionSet, error) {
+	defer iter.Close()
+
+	txIDs := state.NewVersionSet(nil)
+	for {
+		tx := iter.Next()
+		if tx == nil {
+			break
+		}
+		txIDs.Add(tx.ID)
+	}
+	return txIDs, iter.Error()
+}
+
 func (c *controller) handleNewBlobs(root state.Node) {
 	var blobs []blob.ID
 	defer func() {
@@ -492,11 +778,20 @@ func (c *controller) handleNewBlobs(root state.Node) {
 	}
 }
 
-func (c *controller) updateBehaviorTree(root state.Node) error {
+func (c *controller) updateBehaviorTree(root state.Node) (err error) {
 	// Walk the tree and initialize validators and resolvers (@@TODO: inefficient)
 
-	// We need to be able to roll back in case of error, so we make a copy
+	// We need to be able to roll back in case of error, so we make a copy.
+	// Whichever tree is dropped releases the behaviors that only it held.
 	newBehaviorTree := c.behaviorTree.copy()
+	defer func() {
+		if err != nil {
+			newBehaviorTree.closeBehaviorsNotIn(c.behaviorTree)
+			return
+		}
+		c.behaviorTree.closeBehaviorsNotIn(newBehaviorTree)
+		c.behaviorTree = newBehaviorTree
+	}()
 
 	diff := root.Diff()
 
@@ -505,12 +800,14 @@ func (c *controller) updateBehaviorTree(root state.Node) error {
 		parentKeypath, key := state.Keypath(kp).Pop()
 		switch {
 		case key.Equals(MergeTypeKeypath):
-			c.behaviorTree.removeResolver(parentKeypath)
+			newBehaviorTree.removeResolver(parentKeypath)
 		case key.Equals(ValidatorKeypath):
-			c.behaviorTree.removeValidator(parentKeypath)
-		case parentKeypath.Part(-1).Equals(state.Keypath("Indices")):
-			//indicesKeypath, _ := parentKeypath.Pop()
-			//c.behaviorTree.removeIndexer()
+			newBehaviorTree.removeValidator(parentKeypath)
+		case key.Equals(IndicesKeypath):
+			err := c.initializeIndexer(newBehaviorTree, root, state.Keypath(kp))
+			if err != nil {
+				return err
+			}
 		}
 
 		for parentKeypath != nil {
@@ -526,6 +823,11 @@ func (c *controller) updateBehaviorTree(root state.Node) error {
 				if err != nil {
 					return err
 				}
+			case key.Equals(IndicesKeypath):
+				err := c.initializeIndexer(newBehaviorTree, root, parentKeypath)
+				if err != nil {
+					return err
+				}
 			}
 			parentKeypath = nextParentKeypath
 		}
@@ -548,7 +850,7 @@ func (c *controller) updateBehaviorTree(root state.Node) error {
 				return err
 			}
 
-		case key.Equals(state.Keypath("Indices")):
+		case key.Equals(IndicesKeypath):
 			err := c.initializeIndexer(newBehaviorTree, root, keypath)
 			if err != nil {
 				return err
@@ -568,19 +870,29 @@ func (c *controller) updateBehaviorTree(root state.Node) error {
 				if err != nil {
 					return err
 				}
+			case key.Equals(IndicesKeypath):
+				err := c.initializeIndexer(newBehaviorTree, root, parentKeypath)
+				if err != nil {
+					return err
+				}
 			}
 			parentKeypath = nextParentKeypath
 		}
 	}
-	c.behaviorTree = newBehaviorTree
 	return nil
 }
 
 func (c *controller) initializeResolver(behaviorTree *behaviorTree, root state.Node, resolverConfigKeypath state.Keypath) error {
+	resolverNodeKeypath, _ := resolverConfigKeypath.Pop()
+
 	// Resolve any blobs (to code) in the resolver config object.  We copy the config so
 	// that we don't inject any blobs into the state tree itself
 	config, err := root.CopyToMemory(resolverConfigKeypath, nil)
-	if err != nil {
+	if errors.Cause(err) == errors.Err404 {
+		// The config was removed along with some of its children
+		behaviorTree.removeResolver(resolverNodeKeypath)
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -617,8 +929,6 @@ func (c *controller) initializeResolver(behaviorTree *behaviorTree, root state.N
 		return err
 	}
 
-	resolverNodeKeypath, _ := resolverConfigKeypath.Pop()
-
 	behaviorTree.addResolver(resolverNodeKeypath, resolver)
 	return nil
 }
@@ -628,10 +938,16 @@ func debugPrint(inFormat string, args ...interface{}) {
 }
 
 func (c *controller) initializeValidator(behaviorTree *behaviorTree, root state.Node, validatorConfigKeypath state.Keypath) error {
+	validatorNodeKeypath, _ := validatorConfigKeypath.Pop()
+
 	// Resolve any blobs (to code) in the validator config object.  We copy the config so
 	// that we don't inject any blobs into the state tree itself
 	config, err := root.CopyToMemory(validatorConfigKeypath, nil)
-	if err != nil {
+	if errors.Cause(err) == errors.Err404 {
+		// The config was removed along with some of its children
+		behaviorTree.removeValidator(validatorNodeKeypath)
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -659,17 +975,25 @@ func (c *controller) initializeValidator(behaviorTree *behaviorTree, root state.
 		return err
 	}
 
-	validatorNodeKeypath, _ := validatorConfigKeypath.Pop()
-
 	behaviorTree.addValidator(validatorNodeKeypath, validator)
 	return nil
 }
 
 func (c *controller) initializeIndexer(behaviorTree *behaviorTree, root state.Node, indexerConfigKeypath state.Keypath) error {
+	indexerNodeKeypath, _ := indexerConfigKeypath.Pop()
+
+	// The indexers are re-initialized from scratch, so that those whose
+	// configs have been removed are dropped
+	for indexName := range behaviorTree.indexers[string(indexerNodeKeypath)] {
+		behaviorTree.removeIndexer(indexerNodeKeypath, state.Keypath(indexName))
+	}
+
 	// Resolve any blobs (to code) in the indexer config object.  We copy the config so
 	// that we don't inject any blobs into the state tree itself
 	indexConfigs, err := root.CopyToMemory(indexerConfigKeypath, nil)
-	if err != nil {
+	if errors.Cause(err) == errors.Err404 {
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -700,8 +1024,6 @@ func (c *controller) initializeIndexer(behaviorTree *behaviorTree, root state.No
 			return err
 		}
 
-		indexerNodeKeypath, _ := indexerConfigKeypath.Pop()
-
 		behaviorTree.addIndexer(indexerNodeKeypath, indexName, indexer)
 	}
 	return nil
@@ -730,58 +1052,270 @@ func (c *controller) notifyNewStateListeners(tx Tx, root state.Node, leaves []st
 	wg.Wait()
 }
 
+// QueryIndex returns the entries of an index that match the query param (or,
+// for aggregate indices, the summary of the group it names).  Indices of the
+// current state are updated as each tx is applied, while those of older
+// versions are built the first time they're queried.
 func (c *controller) QueryIndex(version *state.Version, keypath state.Keypath, indexName state.Keypath, queryParam state.Keypath, rng *state.Range) (node state.Node, err error) {
 	defer errors.Annotate(&err, "keypath=%v index=%v index_arg=%v rng=%v", keypath, indexName, queryParam, rng)
 
-	indexNode := c.indices.IndexAtVersion(version, keypath, indexName, false)
+	indexNode, err := c.openIndex(version, keypath, indexName)
+	if err != nil {
+		return nil, err
+	}
 
 	exists, err := indexNode.Exists(queryParam)
 	if err != nil {
+		indexNode.Close()
 		return nil, err
-
 	} else if !exists {
 		indexNode.Close()
-		indexNode = c.indices.IndexAtVersion(version, keypath, indexName, true)
+		return nil, errors.Err404
+	}
+	return indexNode.NodeAt(queryParam, rng), nil
+}
 
-		indices, exists := c.behaviorTree.indexers[string(keypath)]
-		if !exists {
-			return nil, errors.Err404
+func (c *controller) buildIndex(version *state.Version, keypath state.Keypath, indexName state.Keypath) error {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	indexer, exists := c.behaviorTree.indexers[string(keypath)][string(indexName)]
+	if !exists {
+		return errors.Err404
+	}
+
+	root := c.states.StateAtVersion(version, false)
+	defer root.Close()
+
+	nodeToIndex, err := nelson.FirstNonFrameNode(root.NodeAt(keypath, nil), 10)
+	if err != nil {
+		return err
+	}
+	return c.indices.BuildIndex(version, keypath, nodeToIndex, indexName, indexer)
+}
+
+// updateIndices brings the indices of the current state up to date with the
+// changes in the given diff.  Indices whose configs changed are rebuilt from
+// scratch, and those whose configs were removed are deleted.
+func (c *controller) updateIndices(diff *state.Diff, oldIndexers map[string]map[string]Indexer) error {
+	for kp, indexers := range oldIndexers {
+		for indexName := range indexers {
+			if _, exists := c.behaviorTree.indexers[kp][indexName]; exists {
+				continue
+			}
+			err := c.indices.DeleteIndex(nil, state.Keypath(kp), state.Keypath(indexName))
+			if err != nil {
+				return err
+			}
 		}
-		indexer, exists := indices[string(indexName)]
-		if !exists {
-			return nil, errors.Err404
+	}
+
+	root := c.states.StateAtVersion(nil, false)
+	defer root.Close()
+
+	for kp, indexers := range c.behaviorTree.indexers {
+		keypath := state.Keypath(kp)
+
+		nodeToIndex, err := nelson.FirstNonFrameNode(root.NodeAt(keypath, nil), 10)
+		if err != nil {
+			return err
 		}
+		childKeys, replaced := changedChildren(diff, nodeToIndex.Keypath())
+
+		for indexName, indexer := range indexers {
+			configKeypath := keypath.Push(IndicesKeypath).Push(state.Keypath(indexName))
 
-		if version == nil {
-			version = &state.CurrentVersion
+			if replaced || diffTouches(diff, configKeypath) {
+				err = c.indices.BuildIndex(nil, keypath, nodeToIndex, state.Keypath(indexName), indexer)
+			} else if len(childKeys) > 0 {
+				err = c.indices.UpdateIndex(nil, keypath, nodeToIndex, state.Keypath(indexName), indexer, childKeys)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "keypath=%v index=%v", keypath, indexName)
+			}
 		}
+	}
+	return nil
+}
 
-		nodeToIndex, err := c.states.StateAtVersion(version, false).NodeAt(keypath, nil).CopyToMemory(nil, nil)
-		if err != nil {
-			return nil, err
+// RebuildIndices rebuilds every index of the current state from scratch.  The
+// indexers are first re-initialized from the Indices config nodes in the
+// state, so this also picks up indices that were declared before the
+// controller was started.
+func (c *controller) RebuildIndices() (_ int, err error) {
+	defer errors.Annotate(&err, "stateURI=%v", c.stateURI)
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	root := c.states.StateAtVersion(nil, false)
+	defer root.Close()
+
+	var configKeypaths []state.Keypath
+	iter := root.Iterator(nil, false, 10)
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		keypath := iter.Node().Keypath()
+		if keypath.Part(-1).Equals(IndicesKeypath) {
+			configKeypaths = append(configKeypaths, keypath.Copy())
 		}
+	}
+	iter.Close()
 
-		nodeToIndex, err = nelson.FirstNonFrameNode(nodeToIndex, 10)
+	newBehaviorTree := c.behaviorTree.copy()
+	for _, configKeypath := range configKeypaths {
+		err := c.initializeIndexer(newBehaviorTree, root, configKeypath)
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
+	}
+	c.behaviorTree = newBehaviorTree
 
-		err = c.indices.BuildIndex(version, nodeToIndex, indexName, indexer)
+	var n int
+	for kp, indexers := range c.behaviorTree.indexers {
+		keypath := state.Keypath(kp)
+
+		nodeToIndex, err := nelson.FirstNonFrameNode(root.NodeAt(keypath, nil), 10)
 		if err != nil {
-			return nil, err
+			return n, err
+		}
+
+		for indexName, indexer := range indexers {
+			err := c.indices.BuildIndex(nil, keypath, nodeToIndex, state.Keypath(indexName), indexer)
+			if err != nil {
+				return n, errors.Wrapf(err, "keypath=%v index=%v", keypath, indexName)
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// changedChildren returns the keys of the children of the node at the given
+// keypath that were added, changed, or removed in the diff.  If the node
+// itself was replaced, it returns true instead.
+func changedChildren(diff *state.Diff, keypath state.Keypath) ([]state.Keypath, bool) {
+	var childKeys []state.Keypath
+	seen := make(map[string]struct{})
+	for _, list := range [][]state.Keypath{diff.AddedList, diff.RemovedList} {
+		for _, kp := range list {
+			if keypath.StartsWith(kp) {
+				return nil, true
+			} else if !kp.StartsWith(keypath) {
+				continue
+			}
+			childKey := kp.RelativeTo(keypath).Part(0)
+			if _, exists := seen[string(childKey)]; !exists {
+				seen[string(childKey)] = struct{}{}
+				childKeys = append(childKeys, childKey.Copy())
+			}
+		}
+	}
+	return childKeys, false
+}
+
+func diffTouches(diff *state.Diff, keypath state.Keypath) bool {
+	for _, list := range [][]state.Keypath{diff.AddedList, diff.RemovedList} {
+		for _, kp := range list {
+			if kp.StartsWith(keypath) || keypath.StartsWith(kp) {
+				return true
+			}
 		}
+	}
+	return false
+}
 
-		indexNode = c.indices.IndexAtVersion(version, keypath, indexName, false)
+// ACLPolicy returns the policy declared by the state URI's ACL config node,
+// or the default policy if it doesn't declare one (see DefaultACLPolicy).
+func (c *controller) ACLPolicy() (ACLPolicy, error) {
+	root := c.states.StateAtVersion(nil, false)
+	defer root.Close()
+	return c.aclPolicy(root)
+}
 
-		exists, err = indexNode.Exists(queryParam)
+func (c *controller) aclPolicy(root state.Node) (ACLPolicy, error) {
+	policy, err := aclPolicyOf(root, c.controllerHub, c.blobStore)
+	if err != nil {
+		return nil, err
+	} else if policy == nil {
+		return DefaultACLPolicy(c.stateURI), nil
+	}
+	return policy, nil
+}
+
+// HasReadAccess consults every validator that governs reads of the given
+// keypath: those attached above it, as well as those attached within the
+// subtree that would be returned.
+func (c *controller) HasReadAccess(keypath state.Keypath, addresses types.AddressSet) (bool, error) {
+	behaviorTree := c.behaviorTree
+
+	root := c.states.StateAtVersion(nil, false)
+	defer root.Close()
+
+	for _, validatorKeypath := range behaviorTree.validatorKeypaths {
+		var relKeypath state.Keypath
+		if keypath.StartsWith(validatorKeypath) {
+			relKeypath = keypath.RelativeTo(validatorKeypath)
+		} else if !validatorKeypath.StartsWith(keypath) {
+			continue
+		}
+
+		validator, is := behaviorTree.validators[string(validatorKeypath)].(ReadAccessValidator)
+		if !is {
+			continue
+		}
+		allowed, err := validator.HasReadAccess(root.NodeAt(validatorKeypath, nil), relKeypath, addresses)
 		if err != nil {
-			return nil, err
-		} else if !exists {
-			return nil, errors.Err404
+			return false, err
+		} else if !allowed {
+			return false, nil
 		}
 	}
+	return true, nil
+}
 
-	return indexNode.NodeAt(queryParam, rng), nil
+// CheckRateLimits counts a tx that's entering the network through this node
+// against the rate limits of the validators that govern its patches.  It's
+// kept out of tryApplyTx because its outcome depends on when the tx arrives,
+// which peers can't agree on.
+func (c *controller) CheckRateLimits(tx Tx) error {
+	behaviorTree := c.behaviorTree
+
+	root := c.states.StateAtVersion(nil, false)
+	defer root.Close()
+
+	patches := tx.Patches
+	for i := len(behaviorTree.validatorKeypaths) - 1; i >= 0; i-- {
+		validatorKeypath := behaviorTree.validatorKeypaths[i]
+
+		var unprocessedPatches []Patch
+		var patchesTrimmed []Patch
+		for _, patch := range patches {
+			if patch.Keypath.StartsWith(validatorKeypath) {
+				patchesTrimmed = append(patchesTrimmed, Patch{
+					Keypath:   patch.Keypath.RelativeTo(validatorKeypath),
+					Range:     patch.Range,
+					ValueJSON: patch.ValueJSON,
+				})
+			} else {
+				unprocessedPatches = append(unprocessedPatches, patch)
+			}
+		}
+		patches = unprocessedPatches
+
+		validator, is := behaviorTree.validators[string(validatorKeypath)].(RateLimiter)
+		if !is || len(patchesTrimmed) == 0 {
+			continue
+		}
+
+		txCopy := tx
+		txCopy.Patches = patchesTrimmed
+
+		err := validator.CheckRateLimits(root.NodeAt(validatorKeypath, nil), &txCopy)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (c *controller) DebugPrint() {
@@ -0,0 +1,214 @@
+package tree_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestControllerACL(t *testing.T) {
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	bob, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	carol, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	parents := make(map[string]state.Version)
+
+	sendTx := func(t *testing.T, stateURI string, sender *crypto.SigKeypair, keypath string, valueJSON string, status tree.TxStatus) {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       state.RandomVersion(),
+			From:     sender.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		if parent, exists := parents[stateURI]; exists {
+			tx.Parents = []state.Version{parent}
+		} else {
+			tx.ID = tree.GenesisTxID
+		}
+		tx.Sig, err = sender.SignHash(tx.Hash())
+		require.NoError(t, err)
+
+		require.NoError(t, hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := txStore.FetchTx(stateURI, tx.ID)
+			require.NoError(t, err)
+			return tx.Status == status
+		}, 5*time.Second, 10*time.Millisecond)
+		if status == tree.TxStatusValid {
+			parents[stateURI] = tx.ID
+		}
+	}
+
+	requireReadAccess := func(t *testing.T, stateURI string, addr types.Address, expected bool) {
+		t.Helper()
+		policy, err := hub.ACLPolicy(stateURI)
+		require.NoError(t, err)
+		root, err := hub.StateAtVersion(stateURI, nil)
+		require.NoError(t, err)
+		defer root.Close()
+		allowed, err := policy.HasReadAccess(stateURI, root, nil, types.NewAddressSet([]types.Address{addr}), nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, allowed)
+	}
+
+	t.Run("state URIs without a declared policy use the default", func(t *testing.T) {
+		sendTx(t, "foo.bar/default", alice, "", `{"messages": []}`, tree.TxStatusValid)
+		policy, err := hub.ACLPolicy("foo.bar/default")
+		require.NoError(t, err)
+		require.Nil(t, policy)
+
+		sendTx(t, "foo.p2p/default", alice, "", `{"Members": {"`+alice.Address().Hex()+`": true}}`, tree.TxStatusValid)
+		policy, err = hub.ACLPolicy("foo.p2p/default")
+		require.NoError(t, err)
+		require.True(t, policy.Private())
+	})
+
+	t.Run("public policy with writers", func(t *testing.T) {
+		const stateURI = "foo.bar/public"
+		sendTx(t, stateURI, alice, "", `{"ACL": {"Content-Type": "acl/public", "writers": ["`+alice.Address().Hex()+`"]}}`, tree.TxStatusValid)
+		sendTx(t, stateURI, alice, "messages", `["hi"]`, tree.TxStatusValid)
+		sendTx(t, stateURI, bob, "messages", `["hi"]`, tree.TxStatusInvalid)
+		requireReadAccess(t, stateURI, bob.Address(), true)
+
+		sendTx(t, stateURI, alice, "ACL/writers", `["`+alice.Address().Hex()+`", "`+bob.Address().Hex()+`"]`, tree.TxStatusValid)
+		sendTx(t, stateURI, bob, "messages", `["hi"]`, tree.TxStatusValid)
+	})
+
+	t.Run("allowlist policy", func(t *testing.T) {
+		const stateURI = "foo.bar/allowlist"
+		sendTx(t, stateURI, alice, "", `{"ACL": {"Content-Type": "acl/allowlist", "readers": ["`+alice.Address().Hex()+`", "`+bob.Address().Hex()+`"], "writers": ["`+alice.Address().Hex()+`"]}}`, tree.TxStatusValid)
+		sendTx(t, stateURI, bob, "messages", `["hi"]`, tree.TxStatusInvalid)
+		requireReadAccess(t, stateURI, bob.Address(), true)
+		requireReadAccess(t, stateURI, carol.Address(), false)
+
+		policy, err := hub.ACLPolicy(stateURI)
+		require.NoError(t, err)
+		require.False(t, policy.Private())
+	})
+
+	t.Run("private policy on a non-.p2p state URI", func(t *testing.T) {
+		const stateURI = "foo.bar/private"
+		sendTx(t, stateURI, alice, "", `{"ACL": {"Content-Type": "acl/private"}, "Members": {"`+alice.Address().Hex()+`": true}}`, tree.TxStatusValid)
+		sendTx(t, stateURI, bob, "messages", `["hi"]`, tree.TxStatusInvalid)
+		sendTx(t, stateURI, alice, "Members", `null`, tree.TxStatusInvalid)
+		requireReadAccess(t, stateURI, alice.Address(), true)
+		requireReadAccess(t, stateURI, bob.Address(), false)
+
+		policy, err := hub.ACLPolicy(stateURI)
+		require.NoError(t, err)
+		require.True(t, policy.Private())
+	})
+
+	t.Run("bad ACL configs are rejected", func(t *testing.T) {
+		const stateURI = "foo.bar/bad"
+		sendTx(t, stateURI, alice, "", `{"messages": []}`, tree.TxStatusValid)
+		sendTx(t, stateURI, alice, "ACL", `{"Content-Type": "acl/nonexistent"}`, tree.TxStatusInvalid)
+		sendTx(t, stateURI, alice, "ACL", `{"Content-Type": "acl/allowlist"}`, tree.TxStatusInvalid)
+		sendTx(t, stateURI, alice, "ACL", `{"Content-Type": "acl/public", "writers": ["not-an-address"]}`, tree.TxStatusInvalid)
+		sendTx(t, stateURI, alice, "ACL", `{"Content-Type": "acl/public", "writers": ["`+carol.Address().Hex()+`"]}`, tree.TxStatusValid)
+		sendTx(t, stateURI, alice, "messages", `["hi"]`, tree.TxStatusInvalid)
+		sendTx(t, stateURI, carol, "messages", `["hi"]`, tree.TxStatusValid)
+	})
+}
+
+func TestController_HasReadAccess(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	anyone := types.RandomAddress()
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	// The deny rule lives in a validator attached beneath the root
+	tx := tree.Tx{
+		ID:       tree.GenesisTxID,
+		From:     alice.Address(),
+		StateURI: stateURI,
+		Patches: []tree.Patch{{ValueJSON: []byte(`{
+			"public": {"foo": "bar"},
+			"room": {
+				"Validator": {
+					"Content-Type": "validator/permissions",
+					"value": {
+						"*": {
+							"^.*$": {"read": true, "write": true},
+							"^\\.secret.*$": {"read": false}
+						}
+					}
+				},
+				"messages": ["hi"],
+				"secret": {"password": "hunter2"}
+			}
+		}`)}},
+	}
+	tx.Sig, err = alice.SignHash(tx.Hash())
+	require.NoError(t, err)
+	require.NoError(t, hub.AddTx(tx))
+	require.Eventually(t, func() bool {
+		tx, err := txStore.FetchTx(stateURI, tx.ID)
+		require.NoError(t, err)
+		return tx.Status == tree.TxStatusValid
+	}, 5*time.Second, 10*time.Millisecond)
+
+	readable := func(keypath string) bool {
+		t.Helper()
+		allowed, err := hub.HasReadAccess(stateURI, state.Keypath(keypath), types.NewAddressSet([]types.Address{anyone}))
+		require.NoError(t, err)
+		return allowed
+	}
+	require.False(t, readable(""))
+	require.False(t, readable("room"))
+	require.False(t, readable("room/secret"))
+	require.False(t, readable("room/secret/password"))
+	require.True(t, readable("room/messages"))
+	require.True(t, readable("public"))
+}
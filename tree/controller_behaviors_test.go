@@ -0,0 +1,149 @@
+package tree_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/utils/badgerutils"
+)
+
+type closableValidator struct {
+	name   string
+	mu     sync.Mutex
+	closes int
+}
+
+func (v *closableValidator) ValidateTx(node state.Node, tx *tree.Tx) error { return nil }
+
+func (v *closableValidator) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.closes++
+	return nil
+}
+
+func (v *closableValidator) numCloses() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.closes
+}
+
+func TestControllerClosesBehaviors(t *testing.T) {
+	const stateURI = "foo.bar/behaviors"
+
+	var (
+		validators   []*closableValidator
+		validatorsMu sync.Mutex
+	)
+	t.Cleanup(tree.RegisterValidator("validator/closable", func(config state.Node) (tree.Validator, error) {
+		name, _, err := config.StringValue(state.Keypath("name"))
+		if err != nil {
+			return nil, err
+		}
+		validatorsMu.Lock()
+		defer validatorsMu.Unlock()
+		v := &closableValidator{name: name}
+		validators = append(validators, v)
+		return v, nil
+	}))
+
+	// requireLive checks that the latest validator with each of the given
+	// names is open, and that every other validator has been closed once
+	requireLive := func(t *testing.T, names ...string) {
+		t.Helper()
+		validatorsMu.Lock()
+		defer validatorsMu.Unlock()
+
+		live := make(map[string]*closableValidator)
+		for _, v := range validators {
+			live[v.name] = v
+		}
+		for name := range live {
+			if !types.NewStringSet(names).Contains(name) {
+				delete(live, name)
+			}
+		}
+		require.Len(t, live, len(names))
+
+		for _, v := range validators {
+			if live[v.name] == v {
+				require.Equal(t, 0, v.numCloses(), "%v should be open", v.name)
+			} else {
+				require.Equal(t, 1, v.numCloses(), "%v should be closed", v.name)
+			}
+		}
+	}
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+
+	var parents []state.Version
+	sendTx := func(t *testing.T, keypath string, valueJSON string) {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       state.RandomVersion(),
+			Parents:  parents,
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		if len(parents) == 0 {
+			tx.ID = tree.GenesisTxID
+		}
+		tx.Sig, err = alice.SignHash(tx.Hash())
+		require.NoError(t, err)
+
+		require.NoError(t, hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := txStore.FetchTx(stateURI, tx.ID)
+			require.NoError(t, err)
+			return tx.Status == tree.TxStatusValid
+		}, 5*time.Second, 10*time.Millisecond)
+		parents = []state.Version{tx.ID}
+	}
+
+	sendTx(t, "", `{"foo": {"Validator": {"Content-Type": "validator/closable", "name": "foo"}}, "bar": {"Validator": {"Content-Type": "validator/closable", "name": "bar"}}}`)
+	requireLive(t, "foo", "bar")
+
+	t.Run("replaced behaviors are closed", func(t *testing.T) {
+		sendTx(t, "foo/Validator", `{"Content-Type": "validator/closable", "name": "foo2"}`)
+		requireLive(t, "foo2", "bar")
+	})
+
+	t.Run("removed behaviors are closed", func(t *testing.T) {
+		sendTx(t, "bar", `{}`)
+		requireLive(t, "foo2")
+	})
+
+	t.Run("the remaining behaviors are closed with the controller", func(t *testing.T) {
+		require.NoError(t, hub.Close())
+		requireLive(t)
+	})
+}
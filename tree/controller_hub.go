@@ -26,7 +26,18 @@ type ControllerHub interface {
 	KnownStateURIs() (types.StringSet, error)
 	StateAtVersion(stateURI string, version *state.Version) (state.Node, error)
 	QueryIndex(stateURI string, version *state.Version, keypath state.Keypath, indexName state.Keypath, queryParam state.Keypath, rng *state.Range) (state.Node, error)
+	SearchIndex(stateURI string, version *state.Version, keypath state.Keypath, indexName state.Keypath, query IndexQuery, rng *state.Range) (state.Node, error)
+	SearchText(stateURI string, version *state.Version, keypath state.Keypath, indexName state.Keypath, query string, rng *state.Range) (state.Node, error)
+	RebuildIndices(stateURI string) (int, error)
+	HasReadAccess(stateURI string, keypath state.Keypath, addresses types.AddressSet) (bool, error)
+	CheckRateLimits(tx Tx) error
+	ACLPolicy(stateURI string) (ACLPolicy, error)
 	Leaves(stateURI string) ([]state.Version, error)
+	HistoryBase(stateURI string) (state.Version, error)
+	TxWasPruned(stateURI string, txID state.Version) (bool, error)
+	Mempool(stateURI string) ([]Tx, error)
+	Prune(stateURI string, checkpointTxID state.Version) (int, error)
+	ImportCheckpoint(tx Tx, snapshot state.Node, stateHash types.Hash) error
 
 	BlobReader(refID blob.ID) (io.ReadCloser, int64, error)
 
@@ -162,6 +173,42 @@ func (m *controllerHub) QueryIndex(stateURI string, version *state.Version, keyp
 	return ctrl.QueryIndex(version, keypath, indexName, queryParam, rng)
 }
 
+func (m *controllerHub) HasReadAccess(stateURI string, keypath state.Keypath, addresses types.AddressSet) (bool, error) {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[stateURI]
+	if ctrl == nil {
+		return false, errors.Wrapf(ErrNoController, stateURI)
+	}
+	return ctrl.HasReadAccess(keypath, addresses)
+}
+
+// CheckRateLimits applies the rate limits of the tx's state URI to a tx that's
+// entering the network through this node.  A state URI that doesn't exist yet
+// has no validators, and so no rate limits.
+func (m *controllerHub) CheckRateLimits(tx Tx) error {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[tx.StateURI]
+	if ctrl == nil {
+		return nil
+	}
+	return ctrl.CheckRateLimits(tx)
+}
+
+func (m *controllerHub) ACLPolicy(stateURI string) (ACLPolicy, error) {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[stateURI]
+	if ctrl == nil {
+		return nil, errors.Wrapf(ErrNoController, stateURI)
+	}
+	return ctrl.ACLPolicy()
+}
+
 func (m *controllerHub) BlobReader(refID blob.ID) (io.ReadCloser, int64, error) {
 	return m.blobStore.BlobReader(refID)
 }
@@ -170,6 +217,77 @@ func (m *controllerHub) Leaves(stateURI string) ([]state.Version, error) {
 	return m.txStore.Leaves(stateURI)
 }
 
+func (m *controllerHub) HistoryBase(stateURI string) (state.Version, error) {
+	return m.txStore.HistoryBase(stateURI)
+}
+
+func (m *controllerHub) TxWasPruned(stateURI string, txID state.Version) (bool, error) {
+	return m.txStore.TxWasPruned(stateURI, txID)
+}
+
+func (m *controllerHub) Mempool(stateURI string) ([]Tx, error) {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[stateURI]
+	if ctrl == nil {
+		return nil, errors.Wrapf(ErrNoController, stateURI)
+	}
+	return ctrl.Mempool(), nil
+}
+
+func (m *controllerHub) SearchIndex(stateURI string, version *state.Version, keypath state.Keypath, indexName state.Keypath, query IndexQuery, rng *state.Range) (state.Node, error) {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[stateURI]
+	if ctrl == nil {
+		return nil, errors.Wrapf(ErrNoController, stateURI)
+	}
+	return ctrl.SearchIndex(version, keypath, indexName, query, rng)
+}
+
+func (m *controllerHub) SearchText(stateURI string, version *state.Version, keypath state.Keypath, indexName state.Keypath, query string, rng *state.Range) (state.Node, error) {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[stateURI]
+	if ctrl == nil {
+		return nil, errors.Wrapf(ErrNoController, stateURI)
+	}
+	return ctrl.SearchText(version, keypath, indexName, query, rng)
+}
+
+func (m *controllerHub) RebuildIndices(stateURI string) (int, error) {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[stateURI]
+	if ctrl == nil {
+		return 0, errors.Wrapf(ErrNoController, stateURI)
+	}
+	return ctrl.RebuildIndices()
+}
+
+func (m *controllerHub) Prune(stateURI string, checkpointTxID state.Version) (int, error) {
+	m.controllersMu.RLock()
+	defer m.controllersMu.RUnlock()
+
+	ctrl := m.controllers[stateURI]
+	if ctrl == nil {
+		return 0, errors.Wrapf(ErrNoController, stateURI)
+	}
+	return ctrl.Prune(checkpointTxID)
+}
+
+func (m *controllerHub) ImportCheckpoint(tx Tx, snapshot state.Node, stateHash types.Hash) error {
+	ctrl, err := m.EnsureController(tx.StateURI)
+	if err != nil {
+		return err
+	}
+	return ctrl.ImportCheckpoint(tx, snapshot, stateHash)
+}
+
 func (m *controllerHub) OnNewState(fn NewStateCallback) {
 	m.newStateListenersMu.Lock()
 	defer m.newStateListenersMu.Unlock()
@@ -0,0 +1,90 @@
+package tree_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestControllerHub_Mempool(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	sigkeys, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	newTx := func(t *testing.T, id state.Version, parents []state.Version, keypath string, valueJSON string) tree.Tx {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       id,
+			Parents:  parents,
+			From:     sigkeys.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		tx.Sig, err = sigkeys.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+
+	mempoolIDs := func() []state.Version {
+		txs, err := hub.Mempool(stateURI)
+		require.NoError(t, err)
+		var ids []state.Version
+		for _, tx := range txs {
+			ids = append(ids, tx.ID)
+		}
+		return ids
+	}
+
+	_, err = hub.Mempool(stateURI)
+	require.True(t, errors.Cause(err) == tree.ErrNoController)
+
+	genesis := newTx(t, tree.GenesisTxID, nil, "a", "1")
+	parent := newTx(t, state.RandomVersion(), []state.Version{genesis.ID}, "b", "2")
+	child := newTx(t, state.RandomVersion(), []state.Version{parent.ID}, "c", "3")
+
+	require.NoError(t, hub.AddTx(genesis))
+	require.Eventually(t, func() bool { return len(mempoolIDs()) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// The child can't be applied until its parent arrives
+	require.NoError(t, hub.AddTx(child))
+	require.Eventually(t, func() bool {
+		ids := mempoolIDs()
+		return len(ids) == 1 && ids[0] == child.ID
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, hub.AddTx(parent))
+	require.Eventually(t, func() bool { return len(mempoolIDs()) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	leaves, err := hub.Leaves(stateURI)
+	require.NoError(t, err)
+	require.Equal(t, []state.Version{child.ID}, leaves)
+}
@@ -0,0 +1,488 @@
+package tree_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestControllerIndices(t *testing.T) {
+	const stateURI = "foo.bar/indices"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	var parent state.Version
+	sendTx := func(t *testing.T, keypath string, valueJSON string) {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       state.RandomVersion(),
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		if parent == (state.Version{}) {
+			tx.ID = tree.GenesisTxID
+		} else {
+			tx.Parents = []state.Version{parent}
+		}
+		tx.Sig, err = alice.SignHash(tx.Hash())
+		require.NoError(t, err)
+
+		require.NoError(t, hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := txStore.FetchTx(stateURI, tx.ID)
+			require.NoError(t, err)
+			return tx.Status == tree.TxStatusValid
+		}, 5*time.Second, 10*time.Millisecond)
+		parent = tx.ID
+	}
+
+	requireQuery := func(t *testing.T, indexName, queryParam string, expected interface{}) {
+		t.Helper()
+		node, err := hub.QueryIndex(stateURI, nil, state.Keypath("messages"), state.Keypath(indexName), state.Keypath(queryParam), nil)
+		if expected == nil {
+			require.True(t, errors.Cause(err) == errors.Err404, "expected 404, got %v", err)
+			return
+		}
+		require.NoError(t, err)
+		defer node.Close()
+		val, exists, err := node.Value(nil, nil)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, expected, val)
+	}
+
+	type M = map[string]interface{}
+
+	sendTx(t, "", `{"messages": {
+		"Indices": {"author": {"Content-Type": "indexer/keypath", "keypath": "author"}},
+		"m1": {"author": "alice", "text": "hi"}
+	}}`)
+	requireQuery(t, "author", "alice", M{"author": "alice", "text": "hi"})
+	requireQuery(t, "author", "bob", nil)
+
+	t.Run("new children are indexed", func(t *testing.T) {
+		sendTx(t, "messages/m2", `{"author": "bob", "text": "yo"}`)
+		requireQuery(t, "author", "bob", M{"author": "bob", "text": "yo"})
+	})
+
+	t.Run("changed children are reindexed", func(t *testing.T) {
+		sendTx(t, "messages/m1/author", `"bob"`)
+		requireQuery(t, "author", "alice", nil)
+		// Each index key holds the last child (in key order) indexed under it
+		requireQuery(t, "author", "bob", M{"author": "bob", "text": "yo"})
+	})
+
+	t.Run("removed children are unindexed", func(t *testing.T) {
+		sendTx(t, "messages/m2", `null`)
+		requireQuery(t, "author", "bob", M{"author": "bob", "text": "hi"})
+	})
+
+	t.Run("indices added later are built from existing data", func(t *testing.T) {
+		sendTx(t, "messages/Indices/text", `{"Content-Type": "indexer/keypath", "keypath": "text"}`)
+		requireQuery(t, "text", "hi", M{"author": "bob", "text": "hi"})
+
+		sendTx(t, "messages/m3", `{"author": "carol", "text": "hi"}`)
+		requireQuery(t, "text", "hi", M{"author": "carol", "text": "hi"})
+		requireQuery(t, "author", "carol", M{"author": "carol", "text": "hi"})
+	})
+
+	t.Run("indices can be rebuilt", func(t *testing.T) {
+		n, err := hub.RebuildIndices(stateURI)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		requireQuery(t, "author", "bob", M{"author": "bob", "text": "hi"})
+		requireQuery(t, "text", "hi", M{"author": "carol", "text": "hi"})
+	})
+
+	t.Run("removed indices are deleted", func(t *testing.T) {
+		sendTx(t, "messages/Indices/text", `null`)
+		requireQuery(t, "text", "hi", nil)
+		requireQuery(t, "author", "carol", M{"author": "carol", "text": "hi"})
+	})
+}
+
+func TestControllerSearchIndex(t *testing.T) {
+	const stateURI = "foo.bar/search"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	genesis := tree.Tx{
+		ID:       tree.GenesisTxID,
+		From:     alice.Address(),
+		StateURI: stateURI,
+		Patches: []tree.Patch{{ValueJSON: []byte(`{"messages": {
+			"Indices": {
+				"channel": {"Content-Type": "indexer/compound", "fields": [{"keypath": "channel"}, {"keypath": "sent", "type": "time"}]},
+				"score":   {"Content-Type": "indexer/compound", "fields": [{"keypath": "score", "type": "number"}]},
+				"author":  {"Content-Type": "indexer/keypath", "keypath": "author"}
+			},
+			"m1": {"channel": "general", "sent": "2021-06-01T10:00:00Z", "author": "alice",  "score": 10},
+			"m2": {"channel": "general", "sent": "2021-06-01T09:00:00Z", "author": "bob",    "score": -5},
+			"m3": {"channel": "random",  "sent": "2021-06-01T09:30:00Z", "author": "albert", "score": 100},
+			"m4": {"channel": "general", "sent": "2021-06-01T11:00:00Z", "author": "carol",  "score": 2.5},
+			"m5": {"channel": "general", "sent": "2021-06-01T12:00:00Z", "author": "alice"}
+		}}`)}},
+	}
+	genesis.Sig, err = alice.SignHash(genesis.Hash())
+	require.NoError(t, err)
+	require.NoError(t, hub.AddTx(genesis))
+	require.Eventually(t, func() bool {
+		tx, err := txStore.FetchTx(stateURI, genesis.ID)
+		require.NoError(t, err)
+		return tx.Status == tree.TxStatusValid
+	}, 5*time.Second, 10*time.Millisecond)
+
+	requireResults := func(t *testing.T, indexName string, query tree.IndexQuery, rng *state.Range, expected ...string) {
+		t.Helper()
+		node, err := hub.SearchIndex(stateURI, nil, state.Keypath("messages"), state.Keypath(indexName), query, rng)
+		require.NoError(t, err)
+		defer node.Close()
+
+		val, _, err := node.Value(nil, nil)
+		require.NoError(t, err)
+		var sent []string
+		for _, entry := range val.([]interface{}) {
+			sent = append(sent, entry.(map[string]interface{})["sent"].(string))
+		}
+		require.Equal(t, expected, sent)
+	}
+
+	t.Run("compound keys with time ranges", func(t *testing.T) {
+		requireResults(t, "channel", tree.IndexQuery{Args: []interface{}{"general"}}, nil,
+			"2021-06-01T09:00:00Z", "2021-06-01T10:00:00Z", "2021-06-01T11:00:00Z", "2021-06-01T12:00:00Z")
+		requireResults(t, "channel", tree.IndexQuery{Args: []interface{}{"general"}, Start: "2021-06-01T09:30:00Z", End: "2021-06-01T12:00:00Z"}, nil,
+			"2021-06-01T10:00:00Z", "2021-06-01T11:00:00Z")
+		requireResults(t, "channel", tree.IndexQuery{Args: []interface{}{"random"}, End: "2021-06-01T09:00:00Z"}, nil)
+		requireResults(t, "channel", tree.IndexQuery{Args: []interface{}{"general", "2021-06-01T11:00:00Z"}}, nil,
+			"2021-06-01T11:00:00Z")
+	})
+
+	t.Run("numeric ranges", func(t *testing.T) {
+		requireResults(t, "score", tree.IndexQuery{}, nil,
+			"2021-06-01T09:00:00Z", "2021-06-01T11:00:00Z", "2021-06-01T10:00:00Z", "2021-06-01T09:30:00Z")
+		requireResults(t, "score", tree.IndexQuery{Start: -10, End: "50"}, nil,
+			"2021-06-01T09:00:00Z", "2021-06-01T11:00:00Z", "2021-06-01T10:00:00Z")
+	})
+
+	t.Run("prefixes", func(t *testing.T) {
+		requireResults(t, "author", tree.IndexQuery{Prefix: "al"}, nil,
+			"2021-06-01T09:30:00Z", "2021-06-01T10:00:00Z", "2021-06-01T12:00:00Z")
+		requireResults(t, "channel", tree.IndexQuery{Prefix: "gen"}, nil,
+			"2021-06-01T09:00:00Z", "2021-06-01T10:00:00Z", "2021-06-01T11:00:00Z", "2021-06-01T12:00:00Z")
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		query := tree.IndexQuery{Args: []interface{}{"general"}}
+		requireResults(t, "channel", query, &state.Range{Start: 0, End: 2}, "2021-06-01T09:00:00Z", "2021-06-01T10:00:00Z")
+		requireResults(t, "channel", query, &state.Range{Start: 2, End: 10}, "2021-06-01T11:00:00Z", "2021-06-01T12:00:00Z")
+		requireResults(t, "channel", query, &state.Range{Start: 1, End: 0, Reverse: true}, "2021-06-01T12:00:00Z")
+	})
+
+	t.Run("bad queries", func(t *testing.T) {
+		_, err := hub.SearchIndex(stateURI, nil, state.Keypath("messages"), state.Keypath("score"), tree.IndexQuery{Start: "not a number"}, nil)
+		require.Error(t, err)
+		_, err = hub.SearchIndex(stateURI, nil, state.Keypath("messages"), state.Keypath("author"), tree.IndexQuery{Args: []interface{}{"alice"}, Prefix: "x"}, nil)
+		require.Error(t, err)
+		_, err = hub.SearchIndex(stateURI, nil, state.Keypath("messages"), state.Keypath("nonexistent"), tree.IndexQuery{}, nil)
+		require.True(t, errors.Cause(err) == errors.Err404)
+	})
+}
+
+func TestControllerSearchText(t *testing.T) {
+	const stateURI = "foo.bar/fulltext"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	var parent state.Version
+	sendTx := func(t *testing.T, keypath string, valueJSON string) {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       state.RandomVersion(),
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		if parent == (state.Version{}) {
+			tx.ID = tree.GenesisTxID
+		} else {
+			tx.Parents = []state.Version{parent}
+		}
+		tx.Sig, err = alice.SignHash(tx.Hash())
+		require.NoError(t, err)
+
+		require.NoError(t, hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := txStore.FetchTx(stateURI, tx.ID)
+			require.NoError(t, err)
+			return tx.Status == tree.TxStatusValid
+		}, 5*time.Second, 10*time.Millisecond)
+		parent = tx.ID
+	}
+
+	requireResults := func(t *testing.T, keypath, query string, expected ...string) {
+		t.Helper()
+		node, err := hub.SearchText(stateURI, nil, state.Keypath(keypath), state.Keypath("text"), query, nil)
+		require.NoError(t, err)
+		defer node.Close()
+
+		val, _, err := node.Value(nil, nil)
+		require.NoError(t, err)
+		var keypaths []string
+		for _, result := range val.([]interface{}) {
+			keypaths = append(keypaths, result.(map[string]interface{})["keypath"].(string))
+		}
+		require.Equal(t, expected, keypaths)
+	}
+
+	sendTx(t, "", `{
+		"docs": {
+			"Indices": {"text": {"Content-Type": "indexer/fulltext", "keypaths": ["title", "body"]}},
+			"d1": {"title": "Gardening", "body": "Tomatoes need sun. Tomatoes need water.", "author": "zucchini"},
+			"d2": {"title": "Cooking tomatoes", "body": "Roast them with garlic and olive oil for a long time."},
+			"d3": {"title": "Zucchini", "body": "Zucchini loves sun."}
+		},
+		"messages": {
+			"Indices": {"text": {"Content-Type": "indexer/fulltext"}},
+			"value": ["hello world", "Hello, hello!", "goodbye"]
+		}
+	}`)
+
+	t.Run("results are ranked by relevance", func(t *testing.T) {
+		requireResults(t, "docs", "tomatoes", "docs/d1", "docs/d2")
+		requireResults(t, "docs", "TOMATOES garlic", "docs/d2", "docs/d1")
+		requireResults(t, "docs", "sun", "docs/d3", "docs/d1")
+		requireResults(t, "docs", "nonexistent")
+	})
+
+	t.Run("only the configured keypaths are indexed", func(t *testing.T) {
+		requireResults(t, "docs", "zucchini", "docs/d3")
+	})
+
+	t.Run("changed and removed children are reindexed", func(t *testing.T) {
+		sendTx(t, "docs/d1/body", `"Peppers need sun."`)
+		requireResults(t, "docs", "tomatoes", "docs/d2")
+		requireResults(t, "docs", "peppers", "docs/d1")
+
+		sendTx(t, "docs/d2", `null`)
+		requireResults(t, "docs", "tomatoes")
+		requireResults(t, "docs", "garlic")
+	})
+
+	t.Run("slices of strings", func(t *testing.T) {
+		requireResults(t, "messages", "hello", "messages[1]", "messages[0]")
+		requireResults(t, "messages", "goodbye", "messages[2]")
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		node, err := hub.SearchText(stateURI, nil, state.Keypath("messages"), state.Keypath("text"), "hello", &state.Range{Start: 1, End: 2})
+		require.NoError(t, err)
+		defer node.Close()
+		val, _, err := node.Value(nil, nil)
+		require.NoError(t, err)
+		require.Len(t, val, 1)
+		require.Equal(t, "messages[0]", val.([]interface{})[0].(map[string]interface{})["keypath"])
+	})
+
+	t.Run("bad searches", func(t *testing.T) {
+		_, err := hub.SearchText(stateURI, nil, state.Keypath("docs"), state.Keypath("nonexistent"), "sun", nil)
+		require.True(t, errors.Cause(err) == errors.Err404)
+	})
+}
+
+func TestControllerAggregateIndices(t *testing.T) {
+	const stateURI = "foo.bar/aggregate"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	var parent state.Version
+	sendTx := func(t *testing.T, keypath string, valueJSON string) {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       state.RandomVersion(),
+			From:     alice.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		if parent == (state.Version{}) {
+			tx.ID = tree.GenesisTxID
+		} else {
+			tx.Parents = []state.Version{parent}
+		}
+		tx.Sig, err = alice.SignHash(tx.Hash())
+		require.NoError(t, err)
+
+		require.NoError(t, hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := txStore.FetchTx(stateURI, tx.ID)
+			require.NoError(t, err)
+			return tx.Status == tree.TxStatusValid
+		}, 5*time.Second, 10*time.Millisecond)
+		parent = tx.ID
+	}
+
+	requireQuery := func(t *testing.T, keypath, indexName, queryParam string, expected interface{}) {
+		t.Helper()
+		node, err := hub.QueryIndex(stateURI, nil, state.Keypath(keypath), state.Keypath(indexName), state.Keypath(queryParam), nil)
+		if expected == nil {
+			require.True(t, errors.Cause(err) == errors.Err404, "expected 404, got %v", err)
+			return
+		}
+		require.NoError(t, err)
+		defer node.Close()
+		val, exists, err := node.Value(nil, nil)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, expected, val)
+	}
+
+	type M = map[string]interface{}
+
+	sendTx(t, "", `{
+		"messages": {
+			"Indices": {
+				"unread": {"Content-Type": "indexer/aggregate", "groupBy": "channel", "where": {"read": false}},
+				"likes":  {"Content-Type": "indexer/aggregate", "keypath": "likes"}
+			},
+			"m1": {"channel": "general", "read": false, "likes": 3},
+			"m2": {"channel": "general", "read": true,  "likes": 10},
+			"m3": {"channel": "random",  "read": false, "likes": 1},
+			"m4": {"channel": "general", "read": false}
+		},
+		"ballots": {
+			"Indices": {"tally": {"Content-Type": "indexer/aggregate", "groupBy": "choice"}},
+			"value": [{"choice": "yes"}, {"choice": "no"}, {"choice": "yes"}]
+		}
+	}`)
+
+	requireQuery(t, "messages", "unread", "general", M{"count": uint64(2)})
+	requireQuery(t, "messages", "unread", "random", M{"count": uint64(1)})
+	requireQuery(t, "messages", "likes", "", M{"count": uint64(3), "sum": float64(14), "min": float64(1), "max": float64(10)})
+	requireQuery(t, "ballots", "tally", "yes", M{"count": uint64(2)})
+	requireQuery(t, "ballots", "tally", "no", M{"count": uint64(1)})
+
+	t.Run("aggregates are updated as children change", func(t *testing.T) {
+		sendTx(t, "messages/m1/read", `true`)
+		requireQuery(t, "messages", "unread", "general", M{"count": uint64(1)})
+
+		sendTx(t, "messages/m2/likes", `0`)
+		requireQuery(t, "messages", "likes", "", M{"count": uint64(3), "sum": float64(4), "min": float64(0), "max": float64(3)})
+
+		sendTx(t, "messages/m5", `{"channel": "random", "read": false, "likes": 20}`)
+		requireQuery(t, "messages", "unread", "random", M{"count": uint64(2)})
+		requireQuery(t, "messages", "likes", "", M{"count": uint64(4), "sum": float64(24), "min": float64(0), "max": float64(20)})
+
+		sendTx(t, "ballots/value", `[{"choice": "no"}, {"choice": "maybe"}]`)
+		requireQuery(t, "ballots", "tally", "yes", nil)
+		requireQuery(t, "ballots", "tally", "no", M{"count": uint64(1)})
+		requireQuery(t, "ballots", "tally", "maybe", M{"count": uint64(1)})
+	})
+
+	t.Run("empty groups are removed", func(t *testing.T) {
+		sendTx(t, "messages/m3", `null`)
+		sendTx(t, "messages/m5/read", `true`)
+		requireQuery(t, "messages", "unread", "random", nil)
+		requireQuery(t, "messages", "unread", "general", M{"count": uint64(1)})
+	})
+
+	t.Run("ungrouped aggregates of nothing", func(t *testing.T) {
+		sendTx(t, "messages/m1", `null`)
+		sendTx(t, "messages/m2", `null`)
+		sendTx(t, "messages/m5", `null`)
+		requireQuery(t, "messages", "likes", "", M{"count": uint64(0)})
+	})
+
+	t.Run("aggregates can be rebuilt", func(t *testing.T) {
+		_, err := hub.RebuildIndices(stateURI)
+		require.NoError(t, err)
+		requireQuery(t, "messages", "unread", "general", M{"count": uint64(1)})
+		requireQuery(t, "messages", "likes", "", M{"count": uint64(0)})
+		requireQuery(t, "ballots", "tally", "no", M{"count": uint64(1)})
+	})
+}
@@ -0,0 +1,134 @@
+package tree_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestControllerMembers(t *testing.T) {
+	const stateURI = "foo.p2p/bar"
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	bob, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	carol, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	badgerOpts := badgerutils.OptsBuilder{}
+
+	txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+	require.NoError(t, txStore.Start())
+	t.Cleanup(txStore.Close)
+
+	blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+	require.NoError(t, blobStore.Start())
+	t.Cleanup(blobStore.Close)
+
+	statesDir := filepath.Join(dir, "states")
+	require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+	hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+	require.NoError(t, hub.Start())
+	t.Cleanup(func() { hub.Close() })
+
+	var parent state.Version
+	newTx := func(t *testing.T, sender *crypto.SigKeypair, keypath string, valueJSON string) tree.Tx {
+		t.Helper()
+		tx := tree.Tx{
+			ID:       state.RandomVersion(),
+			Parents:  []state.Version{parent},
+			From:     sender.Address(),
+			StateURI: stateURI,
+			Patches:  []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		tx.Sig, err = sender.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+
+	requireStatus := func(t *testing.T, tx tree.Tx, status tree.TxStatus) {
+		t.Helper()
+		require.NoError(t, hub.AddTx(tx))
+		require.Eventually(t, func() bool {
+			tx, err := txStore.FetchTx(stateURI, tx.ID)
+			require.NoError(t, err)
+			return tx.Status == status
+		}, 5*time.Second, 10*time.Millisecond)
+		if status == tree.TxStatusValid {
+			parent = tx.ID
+		}
+	}
+
+	requireMembers := func(t *testing.T, expected ...types.Address) {
+		t.Helper()
+		node, err := hub.StateAtVersion(stateURI, nil)
+		require.NoError(t, err)
+		defer node.Close()
+		members, err := tree.MembersOf(node)
+		require.NoError(t, err)
+		require.Equal(t, types.NewAddressSet(expected), members)
+	}
+
+	memberKeypath := func(addr types.Address) string {
+		return tree.MembersKeypath.Pushs(addr.Hex()).String()
+	}
+
+	genesis := tree.Tx{
+		ID:       tree.GenesisTxID,
+		From:     alice.Address(),
+		StateURI: stateURI,
+		Patches: []tree.Patch{{
+			ValueJSON: []byte(`{"Members": {"` + alice.Address().Hex() + `": true, "` + bob.Address().Hex() + `": true}}`),
+		}},
+	}
+	genesis.Sig, err = alice.SignHash(genesis.Hash())
+	require.NoError(t, err)
+	requireStatus(t, genesis, tree.TxStatusValid)
+	requireMembers(t, alice.Address(), bob.Address())
+
+	t.Run("members can write", func(t *testing.T) {
+		requireStatus(t, newTx(t, bob, "messages", `["hi"]`), tree.TxStatusValid)
+	})
+
+	t.Run("non-members can't write", func(t *testing.T) {
+		requireStatus(t, newTx(t, carol, "messages", `["hi"]`), tree.TxStatusInvalid)
+	})
+
+	t.Run("members can add members", func(t *testing.T) {
+		requireStatus(t, newTx(t, bob, memberKeypath(carol.Address()), `true`), tree.TxStatusValid)
+		requireMembers(t, alice.Address(), bob.Address(), carol.Address())
+		requireStatus(t, newTx(t, carol, "messages", `["hi"]`), tree.TxStatusValid)
+	})
+
+	t.Run("removed members can't write", func(t *testing.T) {
+		requireStatus(t, newTx(t, alice, memberKeypath(bob.Address()), `null`), tree.TxStatusValid)
+		requireMembers(t, alice.Address(), carol.Address())
+		requireStatus(t, newTx(t, bob, "messages", `["hi"]`), tree.TxStatusInvalid)
+		requireStatus(t, newTx(t, bob, memberKeypath(bob.Address()), `true`), tree.TxStatusInvalid)
+		requireMembers(t, alice.Address(), carol.Address())
+	})
+
+	t.Run("membership changes are validated", func(t *testing.T) {
+		requireStatus(t, newTx(t, alice, tree.MembersKeypath.Pushs("not-an-address").String(), `true`), tree.TxStatusInvalid)
+		requireStatus(t, newTx(t, alice, tree.MembersKeypath.String(), `null`), tree.TxStatusInvalid)
+		requireStatus(t, newTx(t, alice, "", `{"messages": []}`), tree.TxStatusInvalid)
+		requireMembers(t, alice.Address(), carol.Address())
+
+		requireStatus(t, newTx(t, carol, tree.MembersKeypath.String(), `{"`+carol.Address().Hex()+`": true}`), tree.TxStatusValid)
+		requireMembers(t, carol.Address())
+		requireStatus(t, newTx(t, alice, "messages", `["hi"]`), tree.TxStatusInvalid)
+	})
+}
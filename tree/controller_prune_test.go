@@ -0,0 +1,196 @@
+package tree_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/blob"
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestControllerPrune(t *testing.T) {
+	const stateURI = "foo.bar/baz"
+
+	sigkeys, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	newHub := func(t *testing.T) tree.ControllerHub {
+		t.Helper()
+		dir := t.TempDir()
+		badgerOpts := badgerutils.OptsBuilder{}
+
+		txStore := tree.NewBadgerTxStore(badgerOpts.ForPath(filepath.Join(dir, "txs")))
+		require.NoError(t, txStore.Start())
+		t.Cleanup(txStore.Close)
+
+		blobStore := blob.NewBadgerStore(badgerOpts.ForPath(filepath.Join(dir, "blobs")))
+		require.NoError(t, blobStore.Start())
+		t.Cleanup(blobStore.Close)
+
+		statesDir := filepath.Join(dir, "states")
+		require.NoError(t, os.MkdirAll(statesDir, 0777|os.ModeDir))
+
+		hub := tree.NewControllerHub(statesDir, txStore, blobStore, badgerOpts)
+		require.NoError(t, hub.Start())
+		t.Cleanup(func() { hub.Close() })
+		return hub
+	}
+
+	newTx := func(t *testing.T, id string, parents []state.Version, checkpoint bool, keypath string, valueJSON string) tree.Tx {
+		t.Helper()
+		txID := tree.GenesisTxID
+		if id != "genesis" {
+			txID = state.VersionFromString(id)
+		}
+		tx := tree.Tx{
+			ID:         txID,
+			Parents:    parents,
+			From:       sigkeys.Address(),
+			StateURI:   stateURI,
+			Checkpoint: checkpoint,
+			Patches:    []tree.Patch{{Keypath: state.Keypath(keypath), ValueJSON: []byte(valueJSON)}},
+		}
+		tx.Sig, err = sigkeys.SignHash(tx.Hash())
+		require.NoError(t, err)
+		return tx
+	}
+
+	requireLeaves := func(t *testing.T, hub tree.ControllerHub, expected ...state.Version) {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			leaves, err := hub.Leaves(stateURI)
+			require.NoError(t, err)
+			return len(leaves) == len(expected) && contains(leaves, expected[0])
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	requireState := func(t *testing.T, hub tree.ControllerHub, version *state.Version, expected map[string]interface{}) {
+		t.Helper()
+		node, err := hub.StateAtVersion(stateURI, version)
+		require.NoError(t, err)
+		defer node.Close()
+		val, _, err := node.Value(nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, val)
+	}
+
+	var (
+		genesis    = newTx(t, "genesis", nil, false, "a", "1")
+		tx1        = newTx(t, "tx1", []state.Version{genesis.ID}, false, "b", "2")
+		checkpoint = newTx(t, "checkpoint", []state.Version{tx1.ID}, true, "c", "3")
+		tx3        = newTx(t, "tx3", []state.Version{checkpoint.ID}, false, "d", "4")
+	)
+
+	hub := newHub(t)
+	for _, tx := range []tree.Tx{genesis, tx1, checkpoint, tx3} {
+		require.NoError(t, hub.AddTx(tx))
+		requireLeaves(t, hub, tx.ID)
+	}
+
+	t.Run("prunes history before the checkpoint", func(t *testing.T) {
+		numPruned, err := hub.Prune(stateURI, checkpoint.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, numPruned)
+
+		_, err = hub.FetchTx(stateURI, genesis.ID)
+		require.True(t, errors.Cause(err) == errors.Err404)
+		_, err = hub.FetchTx(stateURI, tx1.ID)
+		require.True(t, errors.Cause(err) == errors.Err404)
+
+		base, err := hub.HistoryBase(stateURI)
+		require.NoError(t, err)
+		require.Equal(t, checkpoint.ID, base)
+
+		iter := hub.FetchTxs(stateURI, tree.GenesisTxID)
+		defer iter.Close()
+		var txIDs []state.Version
+		for tx := iter.Next(); tx != nil; tx = iter.Next() {
+			txIDs = append(txIDs, tx.ID)
+		}
+		require.NoError(t, iter.Error())
+		require.Equal(t, []state.Version{checkpoint.ID, tx3.ID}, txIDs)
+
+		requireState(t, hub, nil, map[string]interface{}{"a": 1.0, "b": 2.0, "c": 3.0, "d": 4.0})
+		requireState(t, hub, &checkpoint.ID, map[string]interface{}{"a": 1.0, "b": 2.0, "c": 3.0})
+	})
+
+	t.Run("rejects txs with pruned parents", func(t *testing.T) {
+		late := newTx(t, "late", []state.Version{tx1.ID}, false, "e", "5")
+		require.NoError(t, hub.AddTx(late))
+		require.Never(t, func() bool {
+			tx, err := hub.FetchTx(stateURI, late.ID)
+			require.NoError(t, err)
+			return tx.Status == tree.TxStatusValid
+		}, 500*time.Millisecond, 10*time.Millisecond)
+		requireLeaves(t, hub, tx3.ID)
+	})
+
+	t.Run("new nodes bootstrap from the snapshot plus the tail", func(t *testing.T) {
+		snapshot, err := hub.StateAtVersion(stateURI, &checkpoint.ID)
+		require.NoError(t, err)
+		defer snapshot.Close()
+		snapshotCopy, err := snapshot.CopyToMemory(nil, nil)
+		require.NoError(t, err)
+
+		stateHash, err := tree.SnapshotHash(snapshot)
+		require.NoError(t, err)
+
+		hub2 := newHub(t)
+		require.NoError(t, hub2.ImportCheckpoint(checkpoint, snapshotCopy, stateHash))
+		require.NoError(t, hub2.AddTx(tx3))
+		requireLeaves(t, hub2, tx3.ID)
+		requireState(t, hub2, nil, map[string]interface{}{"a": 1.0, "b": 2.0, "c": 3.0, "d": 4.0})
+
+		err = hub2.ImportCheckpoint(checkpoint, snapshotCopy, stateHash)
+		require.True(t, errors.Cause(err) == tree.ErrAlreadyHaveHistory)
+	})
+
+	t.Run("rejects snapshots that don't match the vouched-for state hash", func(t *testing.T) {
+		snapshot, err := hub.StateAtVersion(stateURI, &checkpoint.ID)
+		require.NoError(t, err)
+		defer snapshot.Close()
+		stateHash, err := tree.SnapshotHash(snapshot)
+		require.NoError(t, err)
+
+		forged := state.NewMemoryNodeWithValue(map[string]interface{}{"a": 1.0, "b": 2.0, "c": 3.0, "evil": true})
+
+		hub2 := newHub(t)
+		err = hub2.ImportCheckpoint(checkpoint, forged, stateHash)
+		require.True(t, errors.Cause(err) == tree.ErrBadSnapshot)
+
+		leaves, err := hub2.Leaves(stateURI)
+		require.NoError(t, err)
+		require.Empty(t, leaves)
+	})
+
+	t.Run("refuses checkpoints that don't merge every branch", func(t *testing.T) {
+		hub := newHub(t)
+		var (
+			genesis    = newTx(t, "genesis", nil, false, "a", "1")
+			branchA    = newTx(t, "branchA", []state.Version{genesis.ID}, false, "b", "2")
+			branchB    = newTx(t, "branchB", []state.Version{genesis.ID}, false, "c", "3")
+			checkpoint = newTx(t, "checkpoint", []state.Version{branchA.ID}, true, "d", "4")
+		)
+		require.NoError(t, hub.AddTx(genesis))
+		requireLeaves(t, hub, genesis.ID)
+		require.NoError(t, hub.AddTx(branchA))
+		requireLeaves(t, hub, branchA.ID)
+		require.NoError(t, hub.AddTx(branchB))
+		require.NoError(t, hub.AddTx(checkpoint))
+		requireLeaves(t, hub, checkpoint.ID, branchB.ID)
+
+		_, err := hub.Prune(stateURI, checkpoint.ID)
+		require.True(t, errors.Cause(err) == tree.ErrCheckpointNotMerged)
+
+		_, err = hub.Prune(stateURI, branchA.ID)
+		require.True(t, errors.Cause(err) == tree.ErrNotCheckpoint)
+	})
+}
@@ -1,5 +1,9 @@
 package tree
 
+import (
+	"time"
+)
+
 type ProcessTxOutcome = processTxOutcome
 
 var (
@@ -7,3 +11,21 @@ var (
 	ProcessTxOutcome_Failed    = processTxOutcome_Failed
 	ProcessTxOutcome_Retry     = processTxOutcome_Retry
 )
+
+func SetRateLimitSweepInterval(d time.Duration) (restore func()) {
+	old := rateLimitSweepInterval
+	rateLimitSweepInterval = d
+	return func() { rateLimitSweepInterval = old }
+}
+
+func RateLimitHistorySize(validator Validator) int {
+	v := validator.(*permissionsValidator)
+	v.rateLimitHistoryMu.Lock()
+	defer v.rateLimitHistoryMu.Unlock()
+	return len(v.rateLimitHistory)
+}
+
+func RegisterValidator(contentType string, ctor ValidatorConstructor) (restore func()) {
+	validatorRegistry[contentType] = ctor
+	return func() { delete(validatorRegistry, contentType) }
+}
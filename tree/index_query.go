@@ -0,0 +1,310 @@
+package tree
+
+import (
+	"bytes"
+	"math"
+	"sort"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+)
+
+// An IndexQuery searches an index whose Indexer is an OrderedIndexer.  The
+// matching entries are the ones whose leading fields equal Args, and whose
+// next field (if any of Prefix, Start, or End are set) begins with Prefix and
+// falls within [Start, End).  For example, a query of an index on
+// (channel, timestamp) for the messages in a channel between t1 and t2 is:
+//
+//	IndexQuery{Args: []interface{}{"general"}, Start: t1, End: t2}
+//
+// Values are given in the field's own type, or as strings that can be parsed
+// as it (e.g. from URL query params).  Prefix is only meaningful for string
+// fields.
+type IndexQuery struct {
+	Args   []interface{}
+	Prefix interface{}
+	Start  interface{}
+	End    interface{}
+}
+
+func (q IndexQuery) constrainsNextField() bool {
+	return q.Prefix != nil || q.Start != nil || q.End != nil
+}
+
+// SearchIndex returns the entries of an index that match the query, in the
+// order of their index keys, as a slice.  The range, if given, selects a page
+// of the results.
+func (c *controller) SearchIndex(version *state.Version, keypath state.Keypath, indexName state.Keypath, query IndexQuery, rng *state.Range) (_ state.Node, err error) {
+	defer errors.Annotate(&err, "keypath=%v index=%v query=%+v rng=%v", keypath, indexName, query, rng)
+
+	indexer, exists := c.behaviorTree.indexers[string(keypath)][string(indexName)]
+	if !exists {
+		return nil, errors.Err404
+	}
+	orderedIndexer, is := indexer.(OrderedIndexer)
+	if !is {
+		return nil, errors.Errorf("index %v doesn't support searches", indexName)
+	}
+
+	numFields := orderedIndexer.NumFields()
+	if len(query.Args) > numFields || (len(query.Args) == numFields && query.constrainsNextField()) {
+		return nil, errors.Errorf("index %v only has %v fields", indexName, numFields)
+	}
+
+	var prefix state.Keypath
+	for i, arg := range query.Args {
+		part, err := orderedIndexer.EncodeField(i, arg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad arg %v", i)
+		}
+		prefix = prefix.Push(part)
+	}
+
+	var partPrefix, start, end state.Keypath
+	if query.Prefix != nil {
+		partPrefix, err = orderedIndexer.EncodeField(len(query.Args), query.Prefix)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad prefix")
+		}
+	}
+	if query.Start != nil {
+		start, err = orderedIndexer.EncodeField(len(query.Args), query.Start)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad start")
+		}
+	}
+	if query.End != nil {
+		end, err = orderedIndexer.EncodeField(len(query.Args), query.End)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad end")
+		}
+	}
+
+	// Search every child indexed under each key, even in indices that only
+	// hold one of them
+	indexNode, err := c.openIndex(version, keypath, indexName)
+	if err != nil {
+		return nil, err
+	}
+	indexNode.Close()
+
+	members, err := c.indices.IndexMembersAtVersion(version, keypath, indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer members.Close()
+
+	var entries []interface{}
+	err = collectIndexEntries(members.NodeAt(prefix, nil), numFields-len(query.Args), func(part state.Keypath) bool {
+		if partPrefix != nil && !bytes.HasPrefix(part, partPrefix) {
+			return false
+		} else if start != nil && bytes.Compare(part, start) < 0 {
+			return false
+		} else if end != nil && bytes.Compare(part, end) >= 0 {
+			return false
+		}
+		return true
+	}, func(val interface{}) {
+		entries = append(entries, val)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state.NewMemoryNodeWithValue(paginate(entries, rng)), nil
+}
+
+// SearchText returns the children of the node at the given keypath that
+// contain any of the terms in the query, according to one of its full-text
+// indices.  Each result is a map of the child's "keypath" and its "score", and
+// results are ranked by score, highest first.  Children score higher for
+// containing more of the query's terms, more occurrences of them, and rarer
+// ones, and lower for being longer.  The range, if given, selects a page of
+// the results.
+func (c *controller) SearchText(version *state.Version, keypath state.Keypath, indexName state.Keypath, query string, rng *state.Range) (_ state.Node, err error) {
+	defer errors.Annotate(&err, "keypath=%v index=%v query=%v rng=%v", keypath, indexName, query, rng)
+
+	indexer, exists := c.behaviorTree.indexers[string(keypath)][string(indexName)]
+	if !exists {
+		return nil, errors.Err404
+	}
+	textIndexer, is := indexer.(TextIndexer)
+	if !is {
+		return nil, errors.Errorf("index %v isn't a full-text index", indexName)
+	}
+
+	root := c.states.StateAtVersion(version, false)
+	defer root.Close()
+
+	node, err := nelson.FirstNonFrameNode(root.NodeAt(keypath, nil), 10)
+	if err != nil {
+		return nil, err
+	}
+	nodeType, _, numChildren, err := node.NodeInfo(nil)
+	if errors.Cause(err) == errors.Err404 {
+		return state.NewMemoryNodeWithValue(paginate(nil, rng)), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	indexNode, err := c.openIndex(version, keypath, indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer indexNode.Close()
+
+	scores := make(map[string]float64)
+	seen := make(map[string]bool)
+	for _, term := range textIndexer.Tokenize(query) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		err := scoreTerm(indexNode.NodeAt(state.Keypath(term), nil), numChildren, scores)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]interface{}, 0, len(scores))
+	for childKey, score := range scores {
+		var childKeypath string
+		if nodeType == state.NodeTypeSlice {
+			childKeypath = keypath.String() + "[" + childKey + "]"
+		} else {
+			childKeypath = keypath.Push(state.Keypath(childKey)).String()
+		}
+		results = append(results, map[string]interface{}{"keypath": childKeypath, "score": score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].(map[string]interface{}), results[j].(map[string]interface{})
+		if a["score"].(float64) != b["score"].(float64) {
+			return a["score"].(float64) > b["score"].(float64)
+		}
+		return a["keypath"].(string) < b["keypath"].(string)
+	})
+	return state.NewMemoryNodeWithValue(paginate(results, rng)), nil
+}
+
+// scoreTerm adds the score of a term to each of the children that contain it,
+// weighting rarer terms more heavily and longer children less.
+func scoreTerm(termNode state.Node, numChildren uint64, scores map[string]float64) error {
+	_, _, numMatches, err := termNode.NodeInfo(nil)
+	if errors.Cause(err) == errors.Err404 {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	idf := 1 + math.Log(float64(numChildren)/float64(numMatches+1))
+
+	iter := termNode.ChildIterator(nil, false, 10)
+	defer iter.Close()
+
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		posting := iter.Node()
+		childKey := posting.Keypath().RelativeTo(termNode.Keypath())
+
+		count, err := postingNumber(posting, "count")
+		if err != nil {
+			return err
+		}
+		length, err := postingNumber(posting, "length")
+		if err != nil {
+			return err
+		} else if length == 0 {
+			continue
+		}
+		scores[string(childKey)] += math.Sqrt(count) * idf * idf / math.Sqrt(length)
+	}
+	return nil
+}
+
+func postingNumber(posting state.Node, field string) (float64, error) {
+	val, exists, err := posting.Value(state.Keypath(field), nil)
+	if err != nil {
+		return 0, err
+	} else if !exists {
+		return 0, errors.Errorf("full-text index entry is missing '%v'", field)
+	}
+	return indexFieldNumber(val)
+}
+
+// openIndex returns the given index, building it first if it hasn't been
+// built yet, or was built with an older layout.
+func (c *controller) openIndex(version *state.Version, keypath state.Keypath, indexName state.Keypath) (*state.DBNode, error) {
+	current, err := c.indices.IndexIsCurrent(version, keypath, indexName)
+	if err != nil {
+		return nil, err
+	} else if current {
+		return c.indices.IndexAtVersion(version, keypath, indexName, false), nil
+	}
+
+	err = c.buildIndex(version, keypath, indexName)
+	if err != nil {
+		return nil, err
+	}
+	return c.indices.IndexAtVersion(version, keypath, indexName, false), nil
+}
+
+// collectIndexEntries walks the index keys beneath the given node in order,
+// descending the given number of fields, and passes each entry to fn.  Only
+// the keys of the first field are filtered.
+func collectIndexEntries(node state.Node, depth int, filter func(part state.Keypath) bool, fn func(val interface{})) error {
+	exists, err := node.Exists(nil)
+	if err != nil || !exists {
+		return err
+	}
+
+	iter := node.ChildIterator(nil, false, 10)
+	defer iter.Close()
+
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		child := iter.Node()
+		subkey := child.Keypath().RelativeTo(node.Keypath())
+		if filter != nil && !filter(subkey) {
+			continue
+		}
+		if depth > 0 {
+			err := collectIndexEntries(child, depth-1, nil, fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		val, _, err := child.Value(nil, nil)
+		if err != nil {
+			return err
+		}
+		fn(val)
+	}
+	return nil
+}
+
+func paginate(entries []interface{}, rng *state.Range) []interface{} {
+	if entries == nil {
+		entries = []interface{}{}
+	}
+	if rng == nil {
+		return entries
+	}
+
+	length := uint64(len(entries))
+	clamp := func(i uint64) uint64 {
+		if i > length {
+			return length
+		}
+		return i
+	}
+
+	var startIdx, endIdx uint64
+	if rng.Reverse {
+		startIdx, endIdx = length-clamp(rng.Start), length-clamp(rng.End)
+	} else {
+		startIdx, endIdx = clamp(rng.Start), clamp(rng.End)
+	}
+	if endIdx < startIdx {
+		return []interface{}{}
+	}
+	return entries[startIdx:endIdx]
+}
@@ -0,0 +1,180 @@
+package tree
+
+import (
+	"fmt"
+	"reflect"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+)
+
+// The aggregate indexer maintains a summary of the children of a node, so that
+// (for example) unread counts, vote tallies, and per-user totals can be read
+// without fetching the children themselves.  Its config is:
+//
+//	{
+//	    "Content-Type": "indexer/aggregate",
+//	    "keypath": "amount",      // optional
+//	    "groupBy": "author",      // optional
+//	    "where": {"read": false}  // optional
+//	}
+//
+// The summary always includes the "count" of the children.  If "keypath" is
+// given, it also includes the "sum", "min", and "max" of the numbers found
+// there, and children without a number there are left out.  If "where" is
+// given, only the children whose values at each of its keypaths are equal to
+// the given ones are included.
+//
+// Without "groupBy", the summary is the root of the index (i.e., it's queried
+// with an empty query param).  With it, the children are grouped by their
+// value at that keypath (a string, number, or bool), and each group's summary
+// is stored under that value.
+type aggregateIndexer struct {
+	keypath state.Keypath
+	groupBy state.Keypath
+	where   map[string]interface{}
+}
+
+// Ensure aggregateIndexer conforms to the state.Aggregator interface
+var _ state.Aggregator = (*aggregateIndexer)(nil)
+
+func NewAggregateIndexer(config state.Node) (Indexer, error) {
+	keypathStr, _, err := config.StringValue(state.Keypath("keypath"))
+	if err != nil {
+		return nil, err
+	}
+	groupByStr, _, err := config.StringValue(state.Keypath("groupBy"))
+	if err != nil {
+		return nil, err
+	}
+
+	whereVal, exists, err := nelson.GetValueRecursive(config, state.Keypath("where"), nil)
+	if err != nil {
+		return nil, err
+	}
+	var where map[string]interface{}
+	if exists {
+		var isMap bool
+		where, isMap = whereVal.(map[string]interface{})
+		if !isMap {
+			return nil, errors.New("aggregate indexer 'where' must be an object")
+		}
+	}
+
+	indexer := &aggregateIndexer{where: where}
+	if keypathStr != "" {
+		indexer.keypath = state.Keypath(keypathStr)
+	}
+	if groupByStr != "" {
+		indexer.groupBy = state.Keypath(groupByStr)
+	}
+	return indexer, nil
+}
+
+// IndexNode isn't used, as the aggregate indexer summarizes the children
+// rather than re-keying them (see IndexEntries and Aggregate).
+func (i *aggregateIndexer) IndexNode(relKeypath state.Keypath, node state.Node) (state.Keypath, state.Node, error) {
+	return nil, nil, nil
+}
+
+func (i *aggregateIndexer) IndexEntries(relKeypath state.Keypath, node state.Node) (map[string]interface{}, error) {
+	for keypath, expected := range i.where {
+		val, exists, err := node.Value(state.Keypath(keypath), nil)
+		if err != nil {
+			return nil, err
+		} else if !exists || !aggregateValuesEqual(val, expected) {
+			return nil, nil
+		}
+	}
+
+	var contribution interface{} = true
+	if i.keypath != nil {
+		val, exists, err := node.Value(i.keypath, nil)
+		if err != nil {
+			return nil, err
+		} else if !exists {
+			return nil, nil
+		}
+		f, isNumber := aggregateNumber(val)
+		if !isNumber {
+			return nil, nil
+		}
+		contribution = f
+	}
+
+	var group string
+	if i.groupBy != nil {
+		val, exists, err := node.Value(i.groupBy, nil)
+		if err != nil {
+			return nil, err
+		} else if !exists {
+			return nil, nil
+		}
+		switch v := val.(type) {
+		case string:
+			group = indexFieldEscaper.Replace(v)
+		case bool:
+			group = fmt.Sprint(v)
+		default:
+			f, isNumber := aggregateNumber(v)
+			if !isNumber {
+				return nil, nil
+			}
+			group = fmt.Sprint(f)
+		}
+		if group == "" {
+			return nil, nil
+		}
+	}
+	return map[string]interface{}{group: contribution}, nil
+}
+
+func (i *aggregateIndexer) Grouped() bool {
+	return i.groupBy != nil
+}
+
+func (i *aggregateIndexer) Aggregate(values []interface{}) (interface{}, error) {
+	summary := map[string]interface{}{"count": uint64(len(values))}
+	if i.keypath == nil || len(values) == 0 {
+		return summary, nil
+	}
+
+	var sum, min, max float64
+	for idx, val := range values {
+		f, isNumber := aggregateNumber(val)
+		if !isNumber {
+			return nil, errors.Errorf("aggregate indexer contribution %v isn't a number", val)
+		}
+		sum += f
+		if idx == 0 || f < min {
+			min = f
+		}
+		if idx == 0 || f > max {
+			max = f
+		}
+	}
+	summary["sum"] = sum
+	summary["min"] = min
+	summary["max"] = max
+	return summary, nil
+}
+
+func aggregateNumber(val interface{}) (float64, bool) {
+	switch val.(type) {
+	case float64, float32, int, int64, uint64:
+		f, err := indexFieldNumber(val)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func aggregateValuesEqual(a, b interface{}) bool {
+	fa, aIsNumber := aggregateNumber(a)
+	fb, bIsNumber := aggregateNumber(b)
+	if aIsNumber && bIsNumber {
+		return fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,193 @@
+package tree
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+)
+
+// The compound indexer indexes the children of a node by one or more of their
+// fields, in order.  Its indices can be searched by the values of the leading
+// fields and then range-scanned or prefix-matched on the next one (see
+// IndexQuery).  Its config lists the fields:
+//
+//	{
+//	    "Content-Type": "indexer/compound",
+//	    "fields": [
+//	        {"keypath": "channel"},
+//	        {"keypath": "timestamp", "type": "time"}
+//	    ]
+//	}
+//
+// A field's type may be "string" (the default), "number", or "time" (an RFC
+// 3339 string or a number of milliseconds since the epoch).  Numbers and times
+// are encoded so that their index keys sort in numeric order.  Children that
+// are missing any of the fields aren't indexed.  The children of a map are
+// indexed under their own keys beneath their index keys, so that children with
+// the same field values are all kept.
+type compoundIndexer struct {
+	fields []compoundIndexField
+}
+
+type compoundIndexField struct {
+	keypath   state.Keypath
+	fieldType string
+}
+
+// Ensure compoundIndexer conforms to the OrderedIndexer and
+// state.GroupingIndexer interfaces
+var (
+	_ OrderedIndexer        = (*compoundIndexer)(nil)
+	_ state.GroupingIndexer = (*compoundIndexer)(nil)
+)
+
+func NewCompoundIndexer(config state.Node) (Indexer, error) {
+	fieldsVal, exists, err := nelson.GetValueRecursive(config, state.Keypath("fields"), nil)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errors.New("compound indexer needs a 'fields' list in its config")
+	}
+
+	fieldConfigs, isList := fieldsVal.([]interface{})
+	if !isList || len(fieldConfigs) == 0 {
+		return nil, errors.New("compound indexer 'fields' must be a non-empty list")
+	}
+
+	var fields []compoundIndexField
+	for i, x := range fieldConfigs {
+		fieldConfig, isMap := x.(map[string]interface{})
+		if !isMap {
+			return nil, errors.Errorf("compound indexer field %v must be an object", i)
+		}
+		keypath, _ := fieldConfig["keypath"].(string)
+		if keypath == "" {
+			return nil, errors.Errorf("compound indexer field %v needs a 'keypath'", i)
+		}
+		fieldType, _ := fieldConfig["type"].(string)
+		switch fieldType {
+		case "":
+			fieldType = "string"
+		case "string", "number", "time":
+		default:
+			return nil, errors.Errorf("compound indexer field %v has unknown type '%v'", i, fieldType)
+		}
+		fields = append(fields, compoundIndexField{keypath: state.Keypath(keypath), fieldType: fieldType})
+	}
+	return &compoundIndexer{fields: fields}, nil
+}
+
+func (i *compoundIndexer) IndexNode(relKeypath state.Keypath, node state.Node) (state.Keypath, state.Node, error) {
+	var indexKey state.Keypath
+	for _, field := range i.fields {
+		val, exists, err := node.Value(field.keypath, nil)
+		if err != nil {
+			return nil, nil, err
+		} else if !exists {
+			return nil, nil, nil
+		}
+
+		part, err := encodeIndexField(field.fieldType, val)
+		if err != nil {
+			// Children whose fields have the wrong type simply aren't indexed
+			return nil, nil, nil
+		}
+		indexKey = indexKey.Push(part)
+	}
+	return indexKey, node, nil
+}
+
+func (i *compoundIndexer) GroupsChildren() bool {
+	return true
+}
+
+func (i *compoundIndexer) NumFields() int {
+	return len(i.fields)
+}
+
+func (i *compoundIndexer) EncodeField(idx int, value interface{}) (state.Keypath, error) {
+	if idx >= len(i.fields) {
+		return nil, errors.Errorf("index only has %v fields", len(i.fields))
+	}
+	return encodeIndexField(i.fields[idx].fieldType, value)
+}
+
+var indexFieldEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+// encodeIndexField encodes a field value as a single keypath part.  Values
+// given as strings (e.g. in URL query params) are parsed as the field's type.
+func encodeIndexField(fieldType string, value interface{}) (state.Keypath, error) {
+	switch fieldType {
+	case "string":
+		s, isString := value.(string)
+		if !isString || s == "" {
+			return nil, errors.Errorf("expected a non-empty string, got %v", value)
+		}
+		return state.Keypath(indexFieldEscaper.Replace(s)), nil
+
+	case "number":
+		f, err := indexFieldNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeOrderedFloat(f), nil
+
+	case "time":
+		if s, isString := value.(string); isString {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				value = t
+			}
+		}
+		if t, isTime := value.(time.Time); isTime {
+			value = float64(t.UnixNano()) / float64(time.Millisecond)
+		}
+		f, err := indexFieldNumber(value)
+		if err != nil {
+			return nil, errors.Errorf("expected an RFC 3339 time or milliseconds since the epoch, got %v", value)
+		}
+		return encodeOrderedFloat(f), nil
+
+	default:
+		return nil, errors.Errorf("unknown field type '%v'", fieldType)
+	}
+}
+
+func indexFieldNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, errors.Errorf("expected a number, got %v", value)
+	}
+}
+
+// encodeOrderedFloat encodes a float64 as hex such that the encodings sort in
+// the same order as the numbers.
+func encodeOrderedFloat(f float64) state.Keypath {
+	bits := math.Float64bits(f)
+	if bits&(1<<63) == 0 {
+		bits ^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	var bs [8]byte
+	binary.BigEndian.PutUint64(bs[:], bits)
+	return state.Keypath(hex.EncodeToString(bs[:]))
+}
@@ -9,6 +9,9 @@ type keypathIndexer struct {
 	keypathName state.Keypath
 }
 
+// Ensure keypathIndexer conforms to the OrderedIndexer interface
+var _ OrderedIndexer = (*keypathIndexer)(nil)
+
 func NewKeypathIndexer(config state.Node) (Indexer, error) {
 	keypathStr, exists, err := config.StringValue(state.Keypath("keypath"))
 	if err != nil {
@@ -34,3 +37,18 @@ func (i *keypathIndexer) IndexNode(relKeypath state.Keypath, node state.Node) (s
 
 	return state.Keypath(valStr), node, nil
 }
+
+func (i *keypathIndexer) NumFields() int {
+	return 1
+}
+
+func (i *keypathIndexer) EncodeField(idx int, value interface{}) (state.Keypath, error) {
+	if idx != 0 {
+		return nil, errors.New("keypath index only has 1 field")
+	}
+	s, isString := value.(string)
+	if !isString || s == "" {
+		return nil, errors.Errorf("expected a non-empty string, got %v", value)
+	}
+	return state.Keypath(s), nil
+}
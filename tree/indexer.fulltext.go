@@ -0,0 +1,120 @@
+package tree
+
+import (
+	"strings"
+	"unicode"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+)
+
+// The full-text indexer maintains an inverted index of the terms in the string
+// values of each child of a node, which can be searched with
+// Controller.SearchText.  By default, every string under each child is
+// indexed.  The config may instead list the keypaths of the strings to index:
+//
+//	{
+//	    "Content-Type": "indexer/fulltext",
+//	    "keypaths": ["title", "body"]
+//	}
+//
+// Text is split into terms at anything that isn't a letter or a digit, and
+// terms are lowercased.  Each child is indexed under each of its terms, along
+// with the number of times that the term occurs in it and its total number of
+// terms, which are used to rank search results.
+type fulltextIndexer struct {
+	keypaths []state.Keypath
+}
+
+// Ensure fulltextIndexer conforms to the TextIndexer interface
+var _ TextIndexer = (*fulltextIndexer)(nil)
+
+func NewFulltextIndexer(config state.Node) (Indexer, error) {
+	keypathsVal, exists, err := nelson.GetValueRecursive(config, state.Keypath("keypaths"), nil)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return &fulltextIndexer{}, nil
+	}
+
+	keypathStrs, isList := keypathsVal.([]interface{})
+	if !isList {
+		return nil, errors.New("fulltext indexer 'keypaths' must be a list")
+	}
+
+	var keypaths []state.Keypath
+	for i, x := range keypathStrs {
+		keypathStr, isString := x.(string)
+		if !isString || keypathStr == "" {
+			return nil, errors.Errorf("fulltext indexer keypath %v must be a non-empty string", i)
+		}
+		keypaths = append(keypaths, state.Keypath(keypathStr))
+	}
+	return &fulltextIndexer{keypaths: keypaths}, nil
+}
+
+// IndexNode isn't used, as the full-text indexer indexes each child under all
+// of its terms (see IndexEntries).
+func (i *fulltextIndexer) IndexNode(relKeypath state.Keypath, node state.Node) (state.Keypath, state.Node, error) {
+	return nil, nil, nil
+}
+
+func (i *fulltextIndexer) IndexEntries(relKeypath state.Keypath, node state.Node) (map[string]interface{}, error) {
+	var vals []interface{}
+	if len(i.keypaths) == 0 {
+		val, exists, err := node.Value(nil, nil)
+		if err != nil {
+			return nil, err
+		} else if exists {
+			vals = append(vals, val)
+		}
+	} else {
+		for _, keypath := range i.keypaths {
+			val, exists, err := node.Value(keypath, nil)
+			if err != nil {
+				return nil, err
+			} else if exists {
+				vals = append(vals, val)
+			}
+		}
+	}
+
+	counts := make(map[string]uint64)
+	var length uint64
+	for _, val := range vals {
+		walkStrings(val, func(s string) {
+			for _, term := range i.Tokenize(s) {
+				counts[term]++
+				length++
+			}
+		})
+	}
+
+	entries := make(map[string]interface{}, len(counts))
+	for term, count := range counts {
+		entries[term] = map[string]interface{}{"count": count, "length": length}
+	}
+	return entries, nil
+}
+
+func (i *fulltextIndexer) Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func walkStrings(val interface{}, fn func(s string)) {
+	switch v := val.(type) {
+	case string:
+		fn(v)
+	case map[string]interface{}:
+		for _, x := range v {
+			walkStrings(x, fn)
+		}
+	case []interface{}:
+		for _, x := range v {
+			walkStrings(x, fn)
+		}
+	}
+}
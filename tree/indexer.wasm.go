@@ -0,0 +1,69 @@
+package tree
+
+import (
+	"redwood.dev/errors"
+	"redwood.dev/state"
+)
+
+type wasmIndexer struct {
+	sandbox *wasmSandbox
+}
+
+// Ensure wasmIndexer conforms to the Indexer and ClosableBehavior interfaces
+var (
+	_ Indexer          = (*wasmIndexer)(nil)
+	_ ClosableBehavior = (*wasmIndexer)(nil)
+)
+
+func NewWASMIndexer(config state.Node) (_ Indexer, err error) {
+	defer errors.Annotate(&err, "NewWASMIndexer")
+
+	sandbox, err := newWASMSandbox(config, "index_node")
+	if err != nil {
+		return nil, err
+	}
+	return &wasmIndexer{sandbox: sandbox}, nil
+}
+
+func (i *wasmIndexer) IndexNode(relKeypath state.Keypath, node state.Node) (_ state.Keypath, _ state.Node, err error) {
+	defer errors.Annotate(&err, "wasmIndexer.IndexNode")
+
+	exists, err := node.Exists(nil)
+	if err != nil {
+		return nil, nil, err
+	} else if !exists {
+		return nil, nil, nil
+	}
+
+	input := map[string]interface{}{
+		"keypath": relKeypath.String(),
+		"node":    node,
+	}
+
+	// The indexer can return no output or null to indicate that this keypath shouldn't be indexed
+	var output []interface{}
+	hasOutput, err := i.sandbox.call("index_node", input, &output)
+	if err != nil {
+		return nil, nil, err
+	} else if !hasOutput || output == nil {
+		return nil, nil, nil
+	} else if len(output) != 2 {
+		return nil, nil, errors.New("wasm indexer must return [indexKey, node]")
+	}
+
+	indexKey, ok := output[0].(string)
+	if !ok {
+		return nil, nil, errors.New("index key must be a string")
+	}
+
+	nodeToIndex := state.NewMemoryNode()
+	err = nodeToIndex.Set(nil, nil, output[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return state.Keypath(indexKey), nodeToIndex, nil
+}
+
+func (i *wasmIndexer) Close() error {
+	return i.sandbox.Close()
+}
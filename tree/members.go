@@ -0,0 +1,67 @@
+package tree
+
+import (
+	"strings"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/types"
+)
+
+// MembersKeypath is the subtree of a private state URI that lists its members,
+// keyed by their hex addresses.  Only members may send txs to a private state
+// URI, and only they receive its txs.
+var MembersKeypath = state.Keypath("Members")
+
+// StateURIIsPrivate returns true if the state URI's host is under the ".p2p"
+// pseudo-TLD (e.g. "chat.p2p/room").
+func StateURIIsPrivate(stateURI string) bool {
+	parts := strings.Split(stateURI, "/")
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return false
+	}
+	hostParts := strings.Split(parts[0], ".")
+	return len(hostParts) >= 2 && len(hostParts[0]) > 0 && hostParts[1] == "p2p"
+}
+
+// MembersOf returns the addresses listed in the Members subtree of the given
+// state.
+func MembersOf(node state.Node) (types.AddressSet, error) {
+	addrs := types.NewAddressSet(nil)
+
+	iter := node.ChildIterator(MembersKeypath, true, 10)
+	defer iter.Close()
+
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		addrHex := iter.Node().Keypath().Part(-1).String()
+
+		addr, err := types.AddressFromHex(addrHex)
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad member '%v'", addrHex)
+		}
+		addrs.Add(addr)
+	}
+	return addrs, nil
+}
+
+func patchesTouch(patches []Patch, keypath state.Keypath) bool {
+	for _, patch := range patches {
+		if patch.Keypath.StartsWith(keypath) || keypath.StartsWith(patch.Keypath) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateMembers checks the Members subtree left behind by a tx that
+// changed it.  Every key must be an address, and a private state URI can't be
+// left without any members, as nobody would be able to write to it again.
+func validateMembers(node state.Node) error {
+	members, err := MembersOf(node)
+	if err != nil {
+		return err
+	} else if len(members) == 0 {
+		return errors.New("private state URI must have at least one member")
+	}
+	return nil
+}
@@ -171,6 +171,17 @@ func (s *txSortedSet) takeAll() []Tx {
 	return cp
 }
 
+func (s *txSortedSet) slice() []Tx {
+	s.RLock()
+	defer s.RUnlock()
+
+	txs := make([]Tx, len(s.order))
+	for i, hash := range s.order {
+		txs[i] = s.txs[hash].Copy()
+	}
+	return txs
+}
+
 func (s *txSortedSet) copy() *txSortedSet {
 	s.RLock()
 	defer s.RUnlock()
@@ -42,6 +42,11 @@ func NewLuaResolver(config state.Node, internalState map[string]interface{}) (Re
 	if err != nil {
 		return nil, err
 	}
+
+	// Scripts that define preprocess_patches can be used as the early stages of a resolver stack
+	if L.GetGlobal("preprocess_patches").Type() == lua.LTFunction {
+		return &luaPatchPreprocessor{luaResolver{L: L}}, nil
+	}
 	return &luaResolver{L: L}, nil
 }
 
@@ -79,3 +84,58 @@ func (r *luaResolver) ResolveState(node state.Node, blobStore blob.Store, sender
 	}
 	return nil
 }
+
+type luaPatchPreprocessor struct {
+	luaResolver
+}
+
+// Ensure luaPatchPreprocessor conforms to the PatchPreprocessor interface
+var _ PatchPreprocessor = (*luaPatchPreprocessor)(nil)
+
+// PreprocessPatches calls the script's preprocess_patches(state, sender, patches)
+// function.  Patches are passed to and returned from the script as a list of
+// patch strings (i.e. `.foo.bar = {"some": "json"}`).
+func (r *luaPatchPreprocessor) PreprocessPatches(node state.Node, sender types.Address, txID state.Version, parents []state.Version, patches []Patch) (_ []Patch, err error) {
+	defer errors.Annotate(&err, "luaPatchPreprocessor.PreprocessPatches")
+
+	luaPatches := r.L.NewTable()
+	for _, patch := range patches {
+		luaPatches.Append(lua.LString(patch.String()))
+	}
+
+	luaState, err := luaconv.Wrap(r.L, reflect.ValueOf(node))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = r.L.CallByParam(lua.P{
+		Fn:      r.L.GetGlobal("preprocess_patches"),
+		NRet:    1,
+		Protect: true,
+	}, luaState, lua.LString(sender.String()), luaPatches)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	retval := r.L.Get(-1)
+	r.L.Pop(1)
+
+	table, ok := retval.(*lua.LTable)
+	if !ok {
+		return nil, errors.Errorf("preprocess_patches must return a table of patch strings (got %v)", retval.Type())
+	}
+
+	var newPatches []Patch
+	table.ForEach(func(_ lua.LValue, value lua.LValue) {
+		if err != nil {
+			return
+		}
+		var patch Patch
+		err = patch.UnmarshalText([]byte(lua.LVAsString(value)))
+		newPatches = append(newPatches, patch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPatches, nil
+}
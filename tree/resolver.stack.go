@@ -0,0 +1,149 @@
+package tree
+
+import (
+	"strconv"
+
+	"redwood.dev/blob"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+	"redwood.dev/types"
+)
+
+// A PatchPreprocessor is a Resolver that can act as an early stage of a
+// "resolver/stack", rewriting the patches that the later stages receive
+// instead of resolving them itself.
+type PatchPreprocessor interface {
+	Resolver
+	PreprocessPatches(node state.Node, sender types.Address, txID state.Version, parents []state.Version, patches []Patch) ([]Patch, error)
+}
+
+type stackResolver struct {
+	stages       []Resolver
+	contentTypes []string
+}
+
+// Ensure stackResolver conforms to the Resolver and ClosableBehavior interfaces
+var (
+	_ Resolver         = (*stackResolver)(nil)
+	_ ClosableBehavior = (*stackResolver)(nil)
+)
+
+// NewStackResolver creates a resolver from an ordered list of child resolver
+// configs.  Each stage runs against the state left by the previous one.
+// Stages that are PatchPreprocessors hand their rewritten patches to the next
+// stage rather than resolving them.
+func NewStackResolver(config state.Node, internalState map[string]interface{}) (_ Resolver, err error) {
+	defer errors.Annotate(&err, "NewStackResolver")
+
+	stageConfigs, contentTypes, err := stackStageConfigs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]Resolver, len(stageConfigs))
+	defer func() {
+		if err != nil {
+			closeStages(stages)
+		}
+	}()
+	for i, stageConfig := range stageConfigs {
+		ctor, exists := resolverRegistry[contentTypes[i]]
+		if !exists {
+			return nil, errors.Errorf("stage %v: unknown resolver type '%v'", i, contentTypes[i])
+		}
+
+		stageInternalState, _ := internalState[strconv.Itoa(i)].(map[string]interface{})
+		if stageInternalState == nil {
+			stageInternalState = make(map[string]interface{})
+		}
+
+		stages[i], err = ctor(stageConfig, stageInternalState)
+		if err != nil {
+			return nil, errors.Wrapf(err, "stage %v (%v)", i, contentTypes[i])
+		}
+	}
+	return &stackResolver{stages: stages, contentTypes: contentTypes}, nil
+}
+
+func (r *stackResolver) InternalState() map[string]interface{} {
+	internalState := make(map[string]interface{}, len(r.stages))
+	for i, stage := range r.stages {
+		internalState[strconv.Itoa(i)] = stage.InternalState()
+	}
+	return internalState
+}
+
+func (r *stackResolver) ResolveState(node state.Node, blobStore blob.Store, sender types.Address, txID state.Version, parents []state.Version, patches []Patch) (err error) {
+	for i, stage := range r.stages {
+		if preprocessor, is := stage.(PatchPreprocessor); is && i < len(r.stages)-1 {
+			patches, err = preprocessor.PreprocessPatches(node, sender, txID, parents, patches)
+		} else {
+			err = stage.ResolveState(node, blobStore, sender, txID, parents, patches)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "resolver stack: stage %v (%v)", i, r.contentTypes[i])
+		}
+	}
+	return nil
+}
+
+func (r *stackResolver) Close() error {
+	return closeStages(r.stages)
+}
+
+// closeStages closes the stages of a stack behavior that are
+// ClosableBehaviors, returning the first error.
+func closeStages(stages interface{}) error {
+	var closables []ClosableBehavior
+	switch stages := stages.(type) {
+	case []Resolver:
+		for _, stage := range stages {
+			if closable, is := stage.(ClosableBehavior); is {
+				closables = append(closables, closable)
+			}
+		}
+	case []Validator:
+		for _, stage := range stages {
+			if closable, is := stage.(ClosableBehavior); is {
+				closables = append(closables, closable)
+			}
+		}
+	}
+
+	var err error
+	for _, closable := range closables {
+		err2 := closable.Close()
+		if err == nil {
+			err = err2
+		}
+	}
+	return err
+}
+
+// stackStageConfigs returns the child configs (and their Content-Types) of a
+// stack behavior, whose config value must be a list.
+func stackStageConfigs(config state.Node) ([]state.Node, []string, error) {
+	nodeType, _, length, err := config.NodeInfo(nil)
+	if err != nil {
+		return nil, nil, err
+	} else if nodeType != state.NodeTypeSlice || length == 0 {
+		return nil, nil, errors.New("stack behaviors need a non-empty list of child behaviors as their config")
+	}
+
+	stageConfigs := make([]state.Node, length)
+	contentTypes := make([]string, length)
+	for i := uint64(0); i < length; i++ {
+		stageConfig := config.NodeAt(state.Keypath(nil).PushIndex(i), nil)
+
+		contentType, err := nelson.GetContentType(stageConfig)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "stage %v", i)
+		} else if contentType == "" || contentType == "application/json" {
+			return nil, nil, errors.Errorf("stage %v is missing a 'Content-Type' key", i)
+		}
+		stageConfigs[i] = stageConfig
+		contentTypes[i] = contentType
+	}
+	return stageConfigs, contentTypes, nil
+}
@@ -0,0 +1,81 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/tree/nelson"
+	"redwood.dev/types"
+)
+
+func resolvedConfig(t *testing.T, config map[string]interface{}) state.Node {
+	t.Helper()
+	node, anyMissing, err := nelson.Resolve(state.NewMemoryNodeWithValue(config), nil, nil)
+	require.NoError(t, err)
+	require.False(t, anyMissing)
+	return node
+}
+
+func TestStackResolver(t *testing.T) {
+	config := resolvedConfig(t, map[string]interface{}{
+		"Content-Type": "resolver/stack",
+		"value": []interface{}{
+			map[string]interface{}{
+				"Content-Type": "resolver/lua",
+				"value": map[string]interface{}{
+					"src": `
+						function preprocess_patches(state, sender, patches)
+							local out = {}
+							for i, p in ipairs(patches) do
+								out[i] = string.gsub(p, "hello", "HELLO")
+							end
+							return out
+						end
+					`,
+				},
+			},
+			map[string]interface{}{"Content-Type": "resolver/dumb"},
+		},
+	})
+
+	resolver, err := tree.NewStackResolver(config, nil)
+	require.NoError(t, err)
+
+	node := state.NewMemoryNode()
+	err = resolver.ResolveState(node, nil, types.Address{}, state.RandomVersion(), nil, []tree.Patch{
+		{Keypath: state.Keypath("greeting"), ValueJSON: []byte(`"hello world"`)},
+	})
+	require.NoError(t, err)
+
+	val, exists, err := node.StringValue(state.Keypath("greeting"))
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "HELLO world", val)
+
+	t.Run("errors name the stage that produced them", func(t *testing.T) {
+		config := resolvedConfig(t, map[string]interface{}{
+			"Content-Type": "resolver/stack",
+			"value": []interface{}{
+				map[string]interface{}{
+					"Content-Type": "resolver/lua",
+					"value": map[string]interface{}{
+						"src": `function preprocess_patches(state, sender, patches) error("nope") end`,
+					},
+				},
+				map[string]interface{}{"Content-Type": "resolver/dumb"},
+			},
+		})
+
+		resolver, err := tree.NewStackResolver(config, nil)
+		require.NoError(t, err)
+
+		err = resolver.ResolveState(state.NewMemoryNode(), nil, types.Address{}, state.RandomVersion(), nil, []tree.Patch{
+			{Keypath: state.Keypath("greeting"), ValueJSON: []byte(`"hello world"`)},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stage 0 (resolver/lua)")
+	})
+}
@@ -0,0 +1,652 @@
+package tree
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"redwood.dev/blob"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/types"
+)
+
+// sync9Resolver is a Go port of the Sync9 sequence CRDT (see
+// redwood.js/src/resolver.sync9.browser.js).  Every string and slice in the
+// subtree is represented as a "space DAG" whose nodes are tagged with the ID of
+// the tx that inserted them.  Splice offsets are interpreted relative to the
+// version described by each tx's parents, and concurrent inserts at the same
+// position are ordered by tx ID, so every peer converges on the same state
+// regardless of the order in which it receives concurrent txs.
+type sync9Resolver struct {
+	Versions map[state.Version][]state.Version `json:"versions"`
+	Leaves   map[state.Version]bool            `json:"leaves"`
+	Root     *s9Value                          `json:"root"`
+}
+
+// Ensure sync9Resolver conforms to the Resolver interface
+var _ Resolver = (*sync9Resolver)(nil)
+
+func NewSync9Resolver(config state.Node, internalState map[string]interface{}) (Resolver, error) {
+	r := &sync9Resolver{
+		Versions: make(map[state.Version][]state.Version),
+		Leaves:   make(map[state.Version]bool),
+	}
+	if len(internalState) == 0 {
+		return r, nil
+	}
+
+	bs, err := json.Marshal(internalState)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = json.Unmarshal(bs, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "sync9 resolver could not decode its internal state")
+	}
+	if r.Versions == nil {
+		r.Versions = make(map[state.Version][]state.Version)
+	}
+	if r.Leaves == nil {
+		r.Leaves = make(map[state.Version]bool)
+	}
+	return r, nil
+}
+
+func (r *sync9Resolver) InternalState() map[string]interface{} {
+	bs, err := json.Marshal(r)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	var internalState map[string]interface{}
+	err = json.Unmarshal(bs, &internalState)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	return internalState
+}
+
+func (r *sync9Resolver) ResolveState(node state.Node, blobStore blob.Store, sender types.Address, txID state.Version, parents []state.Version, patches []Patch) (err error) {
+	defer errors.Annotate(&err, "sync9Resolver.ResolveState")
+
+	if r.Root == nil {
+		// The first tx we see establishes the base version of the subtree.  Anything
+		// that was written before the resolver was attached is treated as having been
+		// written by the (nil) root version.
+		base, _, err := node.Value(nil, nil)
+		if err != nil && errors.Cause(err) != errors.Err404 {
+			return err
+		}
+		base, err = s9NormalizeJSON(base)
+		if err != nil {
+			return err
+		}
+		r.Root = s9MakeLit(base)
+	}
+
+	err = r.addVersion(txID, parents, patches)
+	if err != nil {
+		return err
+	}
+
+	val := r.Root.read(func(state.Version) bool { return true })
+	if val == nil {
+		return node.Delete(nil, nil)
+	}
+	return node.Set(nil, nil, val)
+}
+
+func (r *sync9Resolver) ancestors(vids []state.Version) map[state.Version]bool {
+	ancs := make(map[state.Version]bool)
+	var mark func(vid state.Version)
+	mark = func(vid state.Version) {
+		if ancs[vid] {
+			return
+		}
+		ancs[vid] = true
+		for _, parent := range r.Versions[vid] {
+			mark(parent)
+		}
+	}
+	for _, vid := range vids {
+		mark(vid)
+	}
+	return ancs
+}
+
+func (r *sync9Resolver) addVersion(vid state.Version, parents []state.Version, patches []Patch) error {
+	if _, exists := r.Versions[vid]; exists {
+		return nil
+	}
+
+	ancs := r.ancestors(parents)
+	isAnc := func(v state.Version) bool { return ancs[v] }
+
+	for _, patch := range patches {
+		err := r.applyPatch(vid, patch, isAnc)
+		if err != nil {
+			return errors.Wrapf(err, "patch=%v", patch.String())
+		}
+	}
+
+	r.Versions[vid] = append([]state.Version(nil), parents...)
+	for _, parent := range parents {
+		delete(r.Leaves, parent)
+	}
+	r.Leaves[vid] = true
+	return nil
+}
+
+var (
+	ErrSync9BadKeypath = errors.New("sync9: keypath does not exist")
+	ErrSync9BadValue   = errors.New("sync9: value has the wrong type for this operation")
+	ErrSync9BadRange   = errors.New("sync9: range is out of bounds")
+)
+
+func (r *sync9Resolver) applyPatch(vid state.Version, patch Patch, isAnc func(state.Version) bool) error {
+	var val interface{}
+	if len(patch.ValueJSON) > 0 {
+		var err error
+		val, err = patch.Value()
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Root == nil || r.Root.T == s9TypeLit {
+		r.Root = &s9Value{T: s9TypeVal, S: newS9SpaceNode(state.Version{}, false, []*s9Value{r.Root}, "")}
+	}
+
+	var (
+		cur     = r.Root
+		prevS   *s9SpaceNode
+		prevI   int
+		rng     = patch.Range
+		isElem  bool
+		keypath = patch.Keypath.Parts()
+	)
+	for i, key := range keypath {
+		if cur.T == s9TypeVal {
+			prevS, prevI = cur.S, 0
+			cur = cur.S.get(0, isAnc)
+			if cur == nil {
+				// Like the other resolvers, create intermediate maps as needed
+				cur = s9MakeLit(map[string]interface{}{})
+			}
+		}
+		if cur == nil {
+			return errors.Wrapf(ErrSync9BadKeypath, "keypath=%v", patch.Keypath)
+		}
+		if cur.T == s9TypeLit {
+			switch lit := cur.Lit.(type) {
+			case map[string]interface{}:
+				obj := make(map[string]*s9Value, len(lit))
+				for k, v := range lit {
+					obj[k] = s9MakeLit(v)
+				}
+				cur = &s9Value{T: s9TypeObj, Obj: obj}
+			case []interface{}:
+				cur = &s9Value{T: s9TypeArr, S: newS9SpaceNode(state.Version{}, false, s9MakeLits(lit), "")}
+			default:
+				return errors.Wrapf(ErrSync9BadKeypath, "keypath=%v", patch.Keypath)
+			}
+			prevS.set(prevI, cur, isAnc)
+		}
+
+		switch {
+		case cur.T == s9TypeObj:
+			x := cur.Obj[string(key)]
+			if x == nil || x.T == s9TypeLit {
+				x = &s9Value{T: s9TypeVal, S: newS9SpaceNode(state.Version{}, false, []*s9Value{x}, "")}
+				cur.Obj[string(key)] = x
+			}
+			cur = x
+
+		case i == len(keypath)-1 && rng == nil && (cur.T == s9TypeArr || cur.T == s9TypeStr):
+			idx, err := strconv.ParseUint(string(key), 10, 64)
+			if err != nil {
+				return errors.Wrapf(ErrSync9BadKeypath, "keypath=%v", patch.Keypath)
+			}
+			rng = &state.Range{Start: idx, End: idx + 1}
+			isElem = true
+
+		case cur.T == s9TypeArr:
+			idx, err := strconv.ParseUint(string(key), 10, 64)
+			if err != nil {
+				return errors.Wrapf(ErrSync9BadKeypath, "keypath=%v", patch.Keypath)
+			}
+			prevS, prevI = cur.S, int(idx)
+			cur = cur.S.get(int(idx), isAnc)
+
+		default:
+			return errors.Wrapf(ErrSync9BadKeypath, "keypath=%v", patch.Keypath)
+		}
+	}
+
+	if rng == nil {
+		if cur.T != s9TypeVal {
+			return errors.Wrapf(ErrSync9BadKeypath, "keypath=%v", patch.Keypath)
+		}
+		length := cur.S.length(isAnc)
+		return cur.S.addVersion(vid, []s9Splice{{Offset: 0, Del: length, Ins: newS9SpaceNode(vid, false, []*s9Value{s9MakeLit(val)}, "")}}, isAnc)
+	}
+
+	if cur.T == s9TypeVal {
+		prevS, prevI = cur.S, 0
+		cur = cur.S.get(0, isAnc)
+		if cur == nil {
+			return errors.Wrapf(ErrSync9BadKeypath, "keypath=%v", patch.Keypath)
+		}
+	}
+	if cur.T == s9TypeLit {
+		switch lit := cur.Lit.(type) {
+		case string:
+			cur = &s9Value{T: s9TypeStr, S: newS9SpaceNode(state.Version{}, true, nil, lit)}
+		case []interface{}:
+			cur = &s9Value{T: s9TypeArr, S: newS9SpaceNode(state.Version{}, false, s9MakeLits(lit), "")}
+		default:
+			return errors.Wrapf(ErrSync9BadValue, "cannot splice a %T", cur.Lit)
+		}
+		prevS.set(prevI, cur, isAnc)
+	}
+
+	var ins *s9SpaceNode
+	switch cur.T {
+	case s9TypeStr:
+		switch v := val.(type) {
+		case nil:
+		case string:
+			if len(v) > 0 {
+				ins = newS9SpaceNode(vid, true, nil, v)
+			}
+		default:
+			return errors.Wrapf(ErrSync9BadValue, "cannot splice a %T into a string", val)
+		}
+	case s9TypeArr:
+		switch v := val.(type) {
+		case nil:
+		case []interface{}:
+			if isElem {
+				ins = newS9SpaceNode(vid, false, []*s9Value{s9MakeLit(v)}, "")
+			} else if len(v) > 0 {
+				ins = newS9SpaceNode(vid, false, s9MakeLits(v), "")
+			}
+		default:
+			if !isElem {
+				return errors.Wrapf(ErrSync9BadValue, "cannot splice a %T into a slice", val)
+			}
+			ins = newS9SpaceNode(vid, false, []*s9Value{s9MakeLit(v)}, "")
+		}
+	default:
+		return errors.Wrapf(ErrSync9BadValue, "cannot splice a %v", cur.T)
+	}
+
+	length := cur.S.length(isAnc)
+	if !rng.ValidForLength(uint64(length)) {
+		return errors.Wrapf(ErrSync9BadRange, "range=%v length=%v", rng, length)
+	}
+	start, end := rng.IndicesForLength(uint64(length))
+	return cur.S.addVersion(vid, []s9Splice{{Offset: int(start), Del: int(end - start), Ins: ins}}, isAnc)
+}
+
+type s9Type string
+
+const (
+	s9TypeLit s9Type = "lit"
+	s9TypeVal s9Type = "val"
+	s9TypeObj s9Type = "obj"
+	s9TypeArr s9Type = "arr"
+	s9TypeStr s9Type = "str"
+)
+
+// s9Value is a node in the Sync9 value tree.  Literals are left unexpanded until
+// a patch needs to reach inside of them.  Registers ("val") are single-element
+// space DAGs, so concurrent writes to the same key resolve the same way that
+// concurrent inserts do.
+type s9Value struct {
+	T   s9Type              `json:"t"`
+	Lit interface{}         `json:"lit,omitempty"`
+	Obj map[string]*s9Value `json:"obj,omitempty"`
+	S   *s9SpaceNode        `json:"s,omitempty"`
+}
+
+func s9MakeLit(x interface{}) *s9Value {
+	if x == nil {
+		return nil
+	}
+	return &s9Value{T: s9TypeLit, Lit: x}
+}
+
+func s9MakeLits(xs []interface{}) []*s9Value {
+	vals := make([]*s9Value, len(xs))
+	for i := range xs {
+		vals[i] = s9MakeLit(xs[i])
+	}
+	return vals
+}
+
+func s9NormalizeJSON(x interface{}) (interface{}, error) {
+	if x == nil {
+		return nil, nil
+	}
+	bs, err := json.Marshal(x)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var normalized interface{}
+	err = json.Unmarshal(bs, &normalized)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return normalized, nil
+}
+
+func (v *s9Value) read(isAnc func(state.Version) bool) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch v.T {
+	case s9TypeLit:
+		return v.Lit
+	case s9TypeVal:
+		return v.S.get(0, isAnc).read(isAnc)
+	case s9TypeObj:
+		m := make(map[string]interface{}, len(v.Obj))
+		for k, x := range v.Obj {
+			val := x.read(isAnc)
+			if val == nil {
+				continue
+			}
+			m[k] = val
+		}
+		return m
+	case s9TypeArr:
+		s := []interface{}{}
+		v.S.traverse(isAnc, func(node *s9SpaceNode) bool {
+			for _, elem := range node.Elems {
+				s = append(s, elem.read(isAnc))
+			}
+			return true
+		})
+		return s
+	case s9TypeStr:
+		var s []byte
+		v.S.traverse(isAnc, func(node *s9SpaceNode) bool {
+			s = append(s, node.Text...)
+			return true
+		})
+		return string(s)
+	}
+	return nil
+}
+
+// s9SpaceNode is a node in a space DAG.  String DAGs store their contents in
+// Text and index it by rune, all others use Elems.
+type s9SpaceNode struct {
+	Vid       state.Version          `json:"vid"`
+	IsText    bool                   `json:"isText,omitempty"`
+	Elems     []*s9Value             `json:"elems,omitempty"`
+	Text      string                 `json:"text,omitempty"`
+	DeletedBy map[state.Version]bool `json:"deletedBy,omitempty"`
+	EndCap    bool                   `json:"endCap,omitempty"`
+	Gash      bool                   `json:"gash,omitempty"`
+	Nexts     []*s9SpaceNode         `json:"nexts,omitempty"`
+	Next      *s9SpaceNode           `json:"next,omitempty"`
+}
+
+type s9Splice struct {
+	Offset int
+	Del    int
+	Ins    *s9SpaceNode
+}
+
+func newS9SpaceNode(vid state.Version, isText bool, elems []*s9Value, text string) *s9SpaceNode {
+	return &s9SpaceNode{
+		Vid:       vid,
+		IsText:    isText,
+		Elems:     elems,
+		Text:      text,
+		DeletedBy: make(map[state.Version]bool),
+	}
+}
+
+func (n *s9SpaceNode) numElems() int {
+	if n.IsText {
+		return utf8.RuneCountInString(n.Text)
+	}
+	return len(n.Elems)
+}
+
+func (n *s9SpaceNode) isDeleted(isAnc func(state.Version) bool) bool {
+	for vid := range n.DeletedBy {
+		if isAnc(vid) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *s9SpaceNode) hasNexts(isAnc func(state.Version) bool) bool {
+	for _, next := range n.Nexts {
+		if isAnc(next.Vid) {
+			return true
+		}
+	}
+	return false
+}
+
+// traverse visits every node that is visible in the version described by isAnc.
+// Returning false from the callback ends the traversal.
+func (n *s9SpaceNode) traverse(isAnc func(state.Version) bool, fn func(node *s9SpaceNode) bool) {
+	var helper func(node *s9SpaceNode) bool
+	helper = func(node *s9SpaceNode) bool {
+		if !node.isDeleted(isAnc) {
+			if !fn(node) {
+				return false
+			}
+		}
+		for _, next := range node.Nexts {
+			if isAnc(next.Vid) {
+				if !helper(next) {
+					return false
+				}
+			}
+		}
+		if node.Next != nil {
+			return helper(node.Next)
+		}
+		return true
+	}
+	helper(n)
+}
+
+func (n *s9SpaceNode) get(i int, isAnc func(state.Version) bool) *s9Value {
+	var ret *s9Value
+	var offset int
+	n.traverse(isAnc, func(node *s9SpaceNode) bool {
+		if i-offset < len(node.Elems) {
+			ret = node.Elems[i-offset]
+			return false
+		}
+		offset += len(node.Elems)
+		return true
+	})
+	return ret
+}
+
+func (n *s9SpaceNode) set(i int, v *s9Value, isAnc func(state.Version) bool) {
+	var offset int
+	n.traverse(isAnc, func(node *s9SpaceNode) bool {
+		if i-offset < len(node.Elems) {
+			node.Elems[i-offset] = v
+			return false
+		}
+		offset += len(node.Elems)
+		return true
+	})
+}
+
+func (n *s9SpaceNode) length(isAnc func(state.Version) bool) int {
+	var count int
+	n.traverse(isAnc, func(node *s9SpaceNode) bool {
+		count += node.numElems()
+		return true
+	})
+	return count
+}
+
+// breakAt splits the node at element x.  The node keeps the first x elements
+// and the remainder moves into a new tail node that inherits the node's
+// deletions and children.
+func (n *s9SpaceNode) breakAt(x int, endCap bool, newNext *s9SpaceNode) *s9SpaceNode {
+	tail := newS9SpaceNode(state.Version{}, n.IsText, nil, "")
+	if n.IsText {
+		runes := []rune(n.Text)
+		tail.Text = string(runes[x:])
+		n.Text = string(runes[:x])
+	} else {
+		tail.Elems = append([]*s9Value(nil), n.Elems[x:]...)
+		n.Elems = n.Elems[:x:x]
+	}
+	for vid := range n.DeletedBy {
+		tail.DeletedBy[vid] = true
+	}
+	tail.EndCap = n.EndCap
+	tail.Gash = endCap
+	tail.Nexts = n.Nexts
+	tail.Next = n.Next
+
+	n.EndCap = endCap
+	n.Nexts = nil
+	if newNext != nil {
+		n.Nexts = []*s9SpaceNode{newNext}
+	}
+	n.Next = tail
+	return tail
+}
+
+func (n *s9SpaceNode) addToNexts(newNode *s9SpaceNode) {
+	i := sort.Search(len(n.Nexts), func(i int) bool { return n.Nexts[i].Vid.Compare(newNode.Vid) >= 0 })
+	n.Nexts = append(n.Nexts, nil)
+	copy(n.Nexts[i+1:], n.Nexts[i:])
+	n.Nexts[i] = newNode
+}
+
+// addVersion applies a set of splices, expressed as offsets into the version
+// described by isAnc, to the space DAG.
+func (n *s9SpaceNode) addVersion(vid state.Version, splices []s9Splice, isAnc func(state.Version) bool) error {
+	var (
+		si         int
+		deleteUpTo int
+		offset     int
+		err        error
+	)
+
+	cb := func(node *s9SpaceNode, offset int, hasNexts bool, prev *s9SpaceNode, deleted bool) bool {
+		if si >= len(splices) {
+			return false
+		}
+		s := splices[si]
+
+		if deleted {
+			if s.Del == 0 && s.Offset == offset {
+				if node.numElems() == 0 && !node.EndCap && hasNexts {
+					return true
+				}
+				if node.numElems() == 0 && !node.EndCap {
+					node.addToNexts(s.Ins)
+				} else {
+					node.breakAt(0, false, s.Ins)
+				}
+				si++
+			}
+			return true
+		}
+
+		if s.Del == 0 {
+			d := s.Offset - (offset + node.numElems())
+			if d > 0 {
+				return true
+			} else if d == 0 && !node.EndCap && hasNexts {
+				return true
+			}
+			if d == 0 && !node.EndCap {
+				node.addToNexts(s.Ins)
+			} else {
+				node.breakAt(s.Offset-offset, false, s.Ins)
+			}
+			si++
+			return true
+		}
+
+		if deleteUpTo <= offset {
+			d := s.Offset - (offset + node.numElems())
+			if d >= 0 {
+				return true
+			}
+			deleteUpTo = s.Offset + s.Del
+
+			if s.Ins != nil {
+				if s.Offset == offset && node.Gash {
+					if prev == nil || !prev.EndCap {
+						err = errors.New("sync9: gash node without an end cap")
+						return false
+					}
+					prev.addToNexts(s.Ins)
+				} else {
+					node.breakAt(s.Offset-offset, true, s.Ins)
+					return true
+				}
+			} else if s.Offset != offset {
+				node.breakAt(s.Offset-offset, false, nil)
+				return true
+			}
+		}
+
+		if deleteUpTo > offset {
+			if deleteUpTo <= offset+node.numElems() {
+				if deleteUpTo < offset+node.numElems() {
+					node.breakAt(deleteUpTo-offset, false, nil)
+				}
+				si++
+			}
+			if node.DeletedBy == nil {
+				node.DeletedBy = make(map[state.Version]bool)
+			}
+			node.DeletedBy[vid] = true
+		}
+		return true
+	}
+
+	var helper func(node *s9SpaceNode, prev *s9SpaceNode) bool
+	helper = func(node *s9SpaceNode, prev *s9SpaceNode) bool {
+		hasNexts := node.hasNexts(isAnc)
+		deleted := node.isDeleted(isAnc)
+		if !cb(node, offset, hasNexts, prev, deleted) {
+			return false
+		}
+		if !deleted {
+			offset += node.numElems()
+		}
+		for _, next := range node.Nexts {
+			if isAnc(next.Vid) {
+				if !helper(next, nil) {
+					return false
+				}
+			}
+		}
+		if node.Next != nil {
+			return helper(node.Next, node)
+		}
+		return true
+	}
+	helper(n, nil)
+
+	if err != nil {
+		return err
+	} else if si < len(splices) {
+		return errors.Wrapf(ErrSync9BadRange, "splice offset=%v del=%v", splices[si].Offset, splices[si].Del)
+	}
+	return nil
+}
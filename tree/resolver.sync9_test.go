@@ -0,0 +1,137 @@
+package tree_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+type sync9TestTx struct {
+	id      state.Version
+	parents []state.Version
+	patches []tree.Patch
+}
+
+func sync9Patch(t *testing.T, keypath string, rng *state.Range, val interface{}) tree.Patch {
+	t.Helper()
+	var valueJSON []byte
+	if val != nil {
+		var err error
+		valueJSON, err = json.Marshal(val)
+		require.NoError(t, err)
+	}
+	return tree.Patch{Keypath: state.Keypath(keypath), Range: rng, ValueJSON: valueJSON}
+}
+
+func sync9Apply(t *testing.T, resolver tree.Resolver, node state.Node, txs ...sync9TestTx) {
+	t.Helper()
+	for _, tx := range txs {
+		err := resolver.ResolveState(node, nil, types.Address{}, tx.id, tx.parents, tx.patches)
+		require.NoError(t, err)
+	}
+}
+
+func sync9Read(t *testing.T, node state.Node) string {
+	t.Helper()
+	val, _, err := node.Value(nil, nil)
+	require.NoError(t, err)
+	bs, err := json.Marshal(val)
+	require.NoError(t, err)
+	return string(bs)
+}
+
+func TestSync9Resolver(t *testing.T) {
+	var (
+		genesis = tree.GenesisTxID
+		idA     = state.VersionFromString("a")
+		idB     = state.VersionFromString("b")
+		idC     = state.VersionFromString("c")
+		idD     = state.VersionFromString("d")
+		idE     = state.VersionFromString("e")
+	)
+
+	txA := sync9TestTx{idA, []state.Version{genesis}, []tree.Patch{
+		sync9Patch(t, "text", &state.Range{Start: 5, End: 5}, " world"),
+	}}
+	txB := sync9TestTx{idB, []state.Version{genesis}, []tree.Patch{
+		sync9Patch(t, "text", &state.Range{Start: 0, End: 1}, "H"),
+		sync9Patch(t, "text", &state.Range{Start: 5, End: 5}, "!!"),
+	}}
+	txC := sync9TestTx{idC, []state.Version{idA, idB}, []tree.Patch{
+		sync9Patch(t, "list", &state.Range{Start: 0, End: 1}, nil),
+		sync9Patch(t, "text", &state.Range{Start: 13, End: 13}, "?"),
+	}}
+	txD := sync9TestTx{idD, []state.Version{idA}, []tree.Patch{
+		sync9Patch(t, "title", nil, "from d"),
+		sync9Patch(t, "list", &state.Range{Start: 3, End: 3}, []interface{}{4.0}),
+	}}
+	txE := sync9TestTx{idE, []state.Version{idB}, []tree.Patch{
+		sync9Patch(t, "title", nil, "from e"),
+		sync9Patch(t, "list", &state.Range{Start: 3, End: 3}, []interface{}{5.0}),
+	}}
+
+	orders := [][]sync9TestTx{
+		{txA, txB, txC, txD, txE},
+		{txB, txA, txE, txD, txC},
+		{txA, txD, txB, txE, txC},
+		{txB, txE, txA, txC, txD},
+		{txA, txB, txE, txC, txD},
+	}
+
+	var expected string
+	for i, order := range orders {
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{
+			"text": "hello",
+			"list": []interface{}{1.0, 2.0, 3.0},
+		})
+		resolver, err := tree.NewSync9Resolver(nil, nil)
+		require.NoError(t, err)
+
+		sync9Apply(t, resolver, node, order...)
+
+		if i == 0 {
+			expected = sync9Read(t, node)
+			continue
+		}
+		require.Equal(t, expected, sync9Read(t, node), "order %v diverged", i)
+	}
+
+	var final map[string]interface{}
+	err := json.Unmarshal([]byte(expected), &final)
+	require.NoError(t, err)
+	require.Equal(t, "Hello world!!?", final["text"])
+	require.Equal(t, []interface{}{2.0, 3.0, 4.0, 5.0}, final["list"])
+	require.Contains(t, []interface{}{"from d", "from e"}, final["title"])
+
+	t.Run("internal state survives reinitialization", func(t *testing.T) {
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{
+			"text": "hello",
+			"list": []interface{}{1.0, 2.0, 3.0},
+		})
+		resolver, err := tree.NewSync9Resolver(nil, nil)
+		require.NoError(t, err)
+		sync9Apply(t, resolver, node, txB, txE, txA)
+
+		resolver, err = tree.NewSync9Resolver(nil, resolver.InternalState())
+		require.NoError(t, err)
+		sync9Apply(t, resolver, node, txD, txC)
+
+		require.Equal(t, expected, sync9Read(t, node))
+	})
+
+	t.Run("rejects splices outside of the parent version", func(t *testing.T) {
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{"text": "hello"})
+		resolver, err := tree.NewSync9Resolver(nil, nil)
+		require.NoError(t, err)
+
+		err = resolver.ResolveState(node, nil, types.Address{}, idA, []state.Version{genesis}, []tree.Patch{
+			sync9Patch(t, "text", &state.Range{Start: 3, End: 9}, "x"),
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,82 @@
+package tree
+
+import (
+	"redwood.dev/blob"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/types"
+)
+
+type wasmResolver struct {
+	sandbox       *wasmSandbox
+	internalState map[string]interface{}
+}
+
+// Ensure wasmResolver conforms to the Resolver and ClosableBehavior interfaces
+var (
+	_ Resolver         = (*wasmResolver)(nil)
+	_ ClosableBehavior = (*wasmResolver)(nil)
+)
+
+func NewWASMResolver(config state.Node, internalState map[string]interface{}) (_ Resolver, err error) {
+	defer errors.Annotate(&err, "NewWASMResolver")
+
+	sandbox, err := newWASMSandbox(config, "resolve_state")
+	if err != nil {
+		return nil, err
+	}
+	if internalState == nil {
+		internalState = make(map[string]interface{})
+	}
+	return &wasmResolver{sandbox: sandbox, internalState: internalState}, nil
+}
+
+func (r *wasmResolver) InternalState() map[string]interface{} {
+	return r.internalState
+}
+
+func (r *wasmResolver) ResolveState(node state.Node, blobStore blob.Store, sender types.Address, txID state.Version, parents []state.Version, patches []Patch) (err error) {
+	defer errors.Annotate(&err, "wasmResolver.ResolveState")
+
+	convertedPatches, err := wasmPatches(patches)
+	if err != nil {
+		return err
+	}
+
+	var parentsArr []string
+	for i := range parents {
+		parentsArr = append(parentsArr, parents[i].String())
+	}
+
+	input := map[string]interface{}{
+		"state":         node,
+		"sender":        sender.String(),
+		"txID":          txID.String(),
+		"parents":       parentsArr,
+		"patches":       convertedPatches,
+		"internalState": r.internalState,
+	}
+
+	var output struct {
+		State         interface{}            `json:"state"`
+		InternalState map[string]interface{} `json:"internalState"`
+		Error         string                 `json:"error"`
+	}
+	hasOutput, err := r.sandbox.call("resolve_state", input, &output)
+	if err != nil {
+		return err
+	} else if !hasOutput {
+		return errors.New("wasm resolver returned no output")
+	} else if output.Error != "" {
+		return errors.New(output.Error)
+	}
+
+	if output.InternalState != nil {
+		r.internalState = output.InternalState
+	}
+	return node.Set(nil, nil, output.State)
+}
+
+func (r *wasmResolver) Close() error {
+	return r.sandbox.Close()
+}
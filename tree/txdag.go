@@ -0,0 +1,253 @@
+package tree
+
+import (
+	"bytes"
+	"sort"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+)
+
+// TxSource is anything that can look up txs and the current leaves of a state
+// URI's tx DAG (i.e., a TxStore or a ControllerHub).
+type TxSource interface {
+	FetchTx(stateURI string, txID state.Version) (Tx, error)
+	Leaves(stateURI string) ([]state.Version, error)
+	TxWasPruned(stateURI string, txID state.Version) (bool, error)
+	HistoryBase(stateURI string) (state.Version, error)
+}
+
+// TxRange describes a slice of a state URI's tx DAG.
+type TxRange struct {
+	// FromTxID limits the range to this tx and its descendants.  The zero
+	// value (or GenesisTxID) means the beginning of history.
+	FromTxID state.Version
+	// ToTxID limits the range to this tx and its ancestors.  The zero value
+	// means the current leaves.
+	ToTxID state.Version
+	// KnownLeaves excludes these txs and all of their ancestors from the
+	// range.  Peers send their own leaves so that they're only sent the txs
+	// they're missing.  Leaves that we don't have are ignored.
+	KnownLeaves []state.Version
+}
+
+// FetchTxRange walks the tx DAG backwards from the range's upper bound and
+// returns the txs in the range in topological order (every tx comes after all
+// of its parents that are also in the range).
+func FetchTxRange(source TxSource, stateURI string, rng TxRange) TxIterator {
+	txIter := NewTxIterator()
+
+	go func() {
+		defer close(txIter.ch)
+
+		err := walkTxRange(source, stateURI, rng, func(tx Tx) bool {
+			select {
+			case <-txIter.chClose:
+				return false
+			case txIter.ch <- &tx:
+				return true
+			}
+		})
+		if err != nil {
+			txIter.err = err
+		}
+	}()
+
+	return txIter
+}
+
+// walkTxRange calls `fn` with each tx in the range, in topological order,
+// until `fn` returns false.  Only the IDs and parents of the txs in the range
+// are held in memory: each tx is fetched again once it's ready to be handed to
+// `fn`.
+func walkTxRange(source TxSource, stateURI string, rng TxRange, fn func(tx Tx) bool) (err error) {
+	defer errors.Annotate(&err, "walkTxRange")
+
+	fromTxID := rng.FromTxID
+	if fromTxID == (state.Version{}) {
+		fromTxID = GenesisTxID
+	}
+
+	// Everything the requester already has (mapped to its parents), down to
+	// the lower bound.  Below that, it doesn't matter what they have.
+	known := make(map[state.Version][]state.Version)
+	err = walkTxAncestors(source, stateURI, rng.KnownLeaves, true, func(tx Tx) bool {
+		known[tx.ID] = tx.Parents
+		return tx.ID != fromTxID
+	})
+	if err != nil {
+		return err
+	}
+
+	var tips []state.Version
+	if rng.ToTxID != (state.Version{}) {
+		tips = []state.Version{rng.ToTxID}
+	} else {
+		tips, err = source.Leaves(stateURI)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Everything at or below the tips that the requester doesn't have,
+	// stopping at the lower bound
+	parents := make(map[state.Version][]state.Version)
+	err = walkTxAncestors(source, stateURI, tips, false, func(tx Tx) bool {
+		if _, isKnown := known[tx.ID]; isKnown {
+			return false
+		}
+		parents[tx.ID] = tx.Parents
+		return tx.ID != fromTxID
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sort topologically, keeping only the lower bound and its descendants
+	children := make(map[state.Version][]state.Version)
+	pending := make(map[state.Version]int)
+	var ready []state.Version
+	for txID, txParents := range parents {
+		for _, parentID := range txParents {
+			if _, inRange := parents[parentID]; inRange {
+				children[parentID] = append(children[parentID], txID)
+				pending[txID]++
+			}
+		}
+		if pending[txID] == 0 {
+			ready = append(ready, txID)
+		}
+	}
+
+	descendsFromLowerBound := state.NewVersionSet(nil)
+	if fromTxID == GenesisTxID {
+		for txID := range parents {
+			descendsFromLowerBound.Add(txID)
+		}
+	} else if _, isKnown := known[fromTxID]; isKnown {
+		// The requester already has the lower bound (and perhaps some of its
+		// descendants), so the range starts with the txs just past what they
+		// have
+		knownDescendants := knownDescendantsOf(fromTxID, known)
+		for txID, txParents := range parents {
+			for _, parentID := range txParents {
+				if _, descends := knownDescendants[parentID]; descends {
+					descendsFromLowerBound.Add(txID)
+					break
+				}
+			}
+		}
+	} else {
+		descendsFromLowerBound.Add(fromTxID)
+	}
+
+	sortVersions(ready)
+	for _, txChildren := range children {
+		sortVersions(txChildren)
+	}
+
+	for len(ready) > 0 {
+		txID := ready[0]
+		ready = ready[1:]
+
+		if _, descends := descendsFromLowerBound[txID]; descends {
+			tx, err := source.FetchTx(stateURI, txID)
+			if err != nil {
+				return errors.Wrapf(err, "tx %v", txID.Pretty())
+			} else if !fn(tx) {
+				return nil
+			}
+		}
+		for _, childID := range children[txID] {
+			if _, descends := descendsFromLowerBound[txID]; descends {
+				descendsFromLowerBound.Add(childID)
+			}
+			pending[childID]--
+			if pending[childID] == 0 {
+				ready = append(ready, childID)
+			}
+		}
+	}
+	return nil
+}
+
+// knownDescendantsOf returns `txID` and those of its descendants that are in
+// `known` (which maps txs to their parents).
+func knownDescendantsOf(txID state.Version, known map[state.Version][]state.Version) state.VersionSet {
+	children := make(map[state.Version][]state.Version)
+	for childID, txParents := range known {
+		for _, parentID := range txParents {
+			children[parentID] = append(children[parentID], childID)
+		}
+	}
+
+	descendants := state.NewVersionSet([]state.Version{txID})
+	stack := []state.Version{txID}
+	for len(stack) > 0 {
+		txID := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, childID := range children[txID] {
+			if _, seen := descendants[childID]; !seen {
+				descendants.Add(childID)
+				stack = append(stack, childID)
+			}
+		}
+	}
+	return descendants
+}
+
+// walkTxAncestors visits each of the given txs and their ancestors once.  If
+// `fn` returns false, the walk doesn't continue past that tx.  History starts
+// at the history base, so the walk never continues past it either.  If any of
+// the given txs are missing, that's an error unless `ignoreMissing` is set.
+// Missing ancestors are skipped if they were pruned, and are an error
+// otherwise, since the history would be incomplete.
+func walkTxAncestors(source TxSource, stateURI string, txIDs []state.Version, ignoreMissing bool, fn func(tx Tx) bool) error {
+	historyBase, err := source.HistoryBase(stateURI)
+	if err != nil {
+		return err
+	}
+
+	stack := append([]state.Version(nil), txIDs...)
+	visited := state.NewVersionSet(nil)
+	initial := state.NewVersionSet(txIDs)
+
+	for len(stack) > 0 {
+		txID := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, wasVisited := visited[txID]; wasVisited {
+			continue
+		}
+		visited.Add(txID)
+
+		tx, err := source.FetchTx(stateURI, txID)
+		if errors.Cause(err) == errors.Err404 {
+			if _, isInitial := initial[txID]; isInitial {
+				if ignoreMissing {
+					continue
+				}
+				return errors.Wrapf(err, "tx %v", txID.Pretty())
+			}
+			pruned, err := source.TxWasPruned(stateURI, txID)
+			if err != nil {
+				return errors.Wrapf(err, "tx %v", txID.Pretty())
+			} else if !pruned {
+				return errors.Wrapf(errors.Err404, "tx %v", txID.Pretty())
+			}
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "tx %v", txID.Pretty())
+		}
+
+		if fn(tx) && tx.ID != historyBase {
+			stack = append(stack, tx.Parents...)
+		}
+	}
+	return nil
+}
+
+func sortVersions(versions []state.Version) {
+	sort.Slice(versions, func(i, j int) bool { return bytes.Compare(versions[i][:], versions[j][:]) < 0 })
+}
@@ -0,0 +1,225 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+)
+
+type memoryTxSource struct {
+	txs     map[state.Version]tree.Tx
+	leaves  []state.Version
+	pruned  state.VersionSet
+	base    state.Version
+	fetched state.VersionSet
+}
+
+func (s memoryTxSource) FetchTx(stateURI string, txID state.Version) (tree.Tx, error) {
+	if s.fetched != nil {
+		s.fetched.Add(txID)
+	}
+	tx, exists := s.txs[txID]
+	if !exists {
+		return tree.Tx{}, errors.Err404
+	}
+	return tx, nil
+}
+
+func (s memoryTxSource) Leaves(stateURI string) ([]state.Version, error) {
+	return s.leaves, nil
+}
+
+func (s memoryTxSource) TxWasPruned(stateURI string, txID state.Version) (bool, error) {
+	_, pruned := s.pruned[txID]
+	return pruned, nil
+}
+
+func (s memoryTxSource) HistoryBase(stateURI string) (state.Version, error) {
+	if s.base == (state.Version{}) {
+		return tree.GenesisTxID, nil
+	}
+	return s.base, nil
+}
+
+func TestFetchTxRange(t *testing.T) {
+	//        genesis
+	//        /     \
+	//       A       B
+	//      / \     /
+	//     C   D   /
+	//      \   \ /
+	//       \   E
+	//        \ /
+	//         F
+	var (
+		genesis = tree.GenesisTxID
+		idA     = state.VersionFromString("a")
+		idB     = state.VersionFromString("b")
+		idC     = state.VersionFromString("c")
+		idD     = state.VersionFromString("d")
+		idE     = state.VersionFromString("e")
+		idF     = state.VersionFromString("f")
+	)
+
+	source := memoryTxSource{
+		txs: map[state.Version]tree.Tx{
+			genesis: {ID: genesis},
+			idA:     {ID: idA, Parents: []state.Version{genesis}},
+			idB:     {ID: idB, Parents: []state.Version{genesis}},
+			idC:     {ID: idC, Parents: []state.Version{idA}},
+			idD:     {ID: idD, Parents: []state.Version{idA}},
+			idE:     {ID: idE, Parents: []state.Version{idD, idB}},
+			idF:     {ID: idF, Parents: []state.Version{idC, idE}},
+		},
+		leaves: []state.Version{idF},
+	}
+
+	fetchFrom := func(t *testing.T, source tree.TxSource, rng tree.TxRange) []state.Version {
+		t.Helper()
+		iter := tree.FetchTxRange(source, "foo.bar/baz", rng)
+		defer iter.Close()
+
+		var txIDs []state.Version
+		for {
+			tx := iter.Next()
+			require.NoError(t, iter.Error())
+			if tx == nil {
+				return txIDs
+			}
+			txIDs = append(txIDs, tx.ID)
+		}
+	}
+
+	fetch := func(t *testing.T, rng tree.TxRange) []state.Version {
+		t.Helper()
+		return fetchFrom(t, source, rng)
+	}
+
+	requireTopological := func(t *testing.T, txIDs []state.Version) {
+		t.Helper()
+		seen := state.NewVersionSet(nil)
+		for _, txID := range txIDs {
+			for _, parentID := range source.txs[txID].Parents {
+				if contains(txIDs, parentID) {
+					require.Contains(t, seen, parentID, "tx %v sent before its parent %v", txID, parentID)
+				}
+			}
+			seen.Add(txID)
+		}
+	}
+
+	t.Run("full history", func(t *testing.T) {
+		txIDs := fetch(t, tree.TxRange{})
+		require.ElementsMatch(t, []state.Version{genesis, idA, idB, idC, idD, idE, idF}, txIDs)
+		requireTopological(t, txIDs)
+	})
+
+	t.Run("upper bound", func(t *testing.T) {
+		txIDs := fetch(t, tree.TxRange{ToTxID: idE})
+		require.ElementsMatch(t, []state.Version{genesis, idA, idB, idD, idE}, txIDs)
+		requireTopological(t, txIDs)
+	})
+
+	t.Run("lower bound", func(t *testing.T) {
+		txIDs := fetch(t, tree.TxRange{FromTxID: idD})
+		require.ElementsMatch(t, []state.Version{idD, idE, idF}, txIDs)
+		requireTopological(t, txIDs)
+	})
+
+	t.Run("lower and upper bounds", func(t *testing.T) {
+		txIDs := fetch(t, tree.TxRange{FromTxID: idA, ToTxID: idE})
+		require.ElementsMatch(t, []state.Version{idA, idD, idE}, txIDs)
+		requireTopological(t, txIDs)
+	})
+
+	t.Run("reconciling against known leaves", func(t *testing.T) {
+		unknownToUs := state.VersionFromString("zzz")
+		txIDs := fetch(t, tree.TxRange{KnownLeaves: []state.Version{idC, idB, unknownToUs}})
+		require.ElementsMatch(t, []state.Version{idD, idE, idF}, txIDs)
+		requireTopological(t, txIDs)
+
+		txIDs = fetch(t, tree.TxRange{KnownLeaves: []state.Version{idF}})
+		require.Empty(t, txIDs)
+	})
+
+	t.Run("lower bound that the requester already has", func(t *testing.T) {
+		txIDs := fetch(t, tree.TxRange{FromTxID: idA, KnownLeaves: []state.Version{idA}})
+		require.ElementsMatch(t, []state.Version{idC, idD, idE, idF}, txIDs)
+		requireTopological(t, txIDs)
+
+		txIDs = fetch(t, tree.TxRange{FromTxID: idA, KnownLeaves: []state.Version{idD}})
+		require.ElementsMatch(t, []state.Version{idC, idE, idF}, txIDs)
+		requireTopological(t, txIDs)
+
+		txIDs = fetch(t, tree.TxRange{FromTxID: idD, KnownLeaves: []state.Version{idD}})
+		require.ElementsMatch(t, []state.Version{idE, idF}, txIDs)
+		requireTopological(t, txIDs)
+
+		txIDs = fetch(t, tree.TxRange{FromTxID: idA, KnownLeaves: []state.Version{idF}})
+		require.Empty(t, txIDs)
+	})
+
+	t.Run("pruned history", func(t *testing.T) {
+		pruned := memoryTxSource{txs: make(map[state.Version]tree.Tx), leaves: source.leaves, pruned: state.NewVersionSet([]state.Version{genesis, idA, idB})}
+		for txID, tx := range source.txs {
+			if _, isPruned := pruned.pruned[txID]; !isPruned {
+				pruned.txs[txID] = tx
+			}
+		}
+		txIDs := fetchFrom(t, pruned, tree.TxRange{})
+		require.ElementsMatch(t, []state.Version{idC, idD, idE, idF}, txIDs)
+		requireTopological(t, txIDs)
+	})
+
+	t.Run("history starts at the history base", func(t *testing.T) {
+		based := source
+		based.base = idA
+		based.fetched = state.NewVersionSet(nil)
+
+		txIDs := fetchFrom(t, based, tree.TxRange{ToTxID: idD})
+		require.Equal(t, []state.Version{idA, idD}, txIDs)
+		require.NotContains(t, based.fetched, genesis)
+	})
+
+	t.Run("known leaves are only walked down to the lower bound", func(t *testing.T) {
+		counted := source
+		counted.fetched = state.NewVersionSet(nil)
+
+		txIDs := fetchFrom(t, counted, tree.TxRange{FromTxID: idE, ToTxID: idE, KnownLeaves: []state.Version{idE}})
+		require.Empty(t, txIDs)
+		require.Equal(t, state.NewVersionSet([]state.Version{idE}), counted.fetched)
+	})
+
+	t.Run("missing ancestors that weren't pruned", func(t *testing.T) {
+		incomplete := memoryTxSource{txs: make(map[state.Version]tree.Tx), leaves: source.leaves}
+		for txID, tx := range source.txs {
+			if txID != idD {
+				incomplete.txs[txID] = tx
+			}
+		}
+		iter := tree.FetchTxRange(incomplete, "foo.bar/baz", tree.TxRange{})
+		defer iter.Close()
+		require.Nil(t, iter.Next())
+		require.True(t, errors.Cause(iter.Error()) == errors.Err404)
+	})
+
+	t.Run("missing upper bound", func(t *testing.T) {
+		iter := tree.FetchTxRange(source, "foo.bar/baz", tree.TxRange{ToTxID: state.VersionFromString("zzz")})
+		defer iter.Close()
+		require.Nil(t, iter.Next())
+		require.True(t, errors.Cause(iter.Error()) == errors.Err404)
+	})
+}
+
+func contains(txIDs []state.Version, txID state.Version) bool {
+	for _, x := range txIDs {
+		if x == txID {
+			return true
+		}
+	}
+	return false
+}
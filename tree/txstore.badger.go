@@ -47,6 +47,14 @@ func makeTxKey(stateURI string, txID state.Version) []byte {
 	return append([]byte("tx:"+stateURI+":"), txID[:]...)
 }
 
+func makePrunedTxKey(stateURI string, txID state.Version) []byte {
+	return append([]byte("pruned:"+stateURI+":"), txID[:]...)
+}
+
+func makeHistoryBaseKey(stateURI string) []byte {
+	return []byte("historybase:" + stateURI)
+}
+
 func (p *badgerTxStore) AddStateURI(stateURI string) error {
 	return p.db.Update(func(txn *badger.Txn) error {
 		return txn.Set([]byte("stateuri:"+stateURI), nil)
@@ -73,7 +81,13 @@ func (p *badgerTxStore) AddTx(tx Tx) (err error) {
 		if tx.Status == TxStatusValid {
 			for _, parentID := range tx.Parents {
 				item, err := txn.Get(makeTxKey(tx.StateURI, parentID))
-				if err != nil {
+				if err == badger.ErrKeyNotFound {
+					// Pruned parents don't need to know about their children
+					if _, err := txn.Get(makePrunedTxKey(tx.StateURI, parentID)); err == nil {
+						continue
+					}
+					return errors.Wrapf(err, "can't find parent %v of tx %v", parentID, tx.ID)
+				} else if err != nil {
 					return errors.Wrapf(err, "can't find parent %v of tx %v", parentID, tx.ID)
 				}
 				var parentTx Tx
@@ -164,15 +178,21 @@ func (p *badgerTxStore) FetchTx(stateURI string, txID state.Version) (Tx, error)
 }
 
 func (p *badgerTxStore) AllTxsForStateURI(stateURI string, fromTxID state.Version) TxIterator {
-	if fromTxID == (state.Version{}) {
-		fromTxID = GenesisTxID
-	}
-
 	txIter := NewTxIterator()
 
 	go func() {
 		defer close(txIter.ch)
 
+		// If the history has been pruned, it starts at the checkpoint it was pruned to
+		if fromTxID == (state.Version{}) || fromTxID == GenesisTxID {
+			var err error
+			fromTxID, err = p.HistoryBase(stateURI)
+			if err != nil {
+				txIter.err = err
+				return
+			}
+		}
+
 		stack := []state.Version{fromTxID}
 		sent := make(map[state.Version]struct{})
 
@@ -221,6 +241,75 @@ func (p *badgerTxStore) AllTxsForStateURI(stateURI string, fromTxID state.Versio
 	return txIter
 }
 
+func (p *badgerTxStore) PruneTxs(stateURI string, newBase state.Version, txIDs []state.Version) (err error) {
+	defer errors.Annotate(&err, "badgerTxStore#PruneTxs")
+
+	// Move the base first so that an interrupted prune never leaves the history without a beginning
+	err = p.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(makeHistoryBaseKey(stateURI), newBase[:])
+	})
+	if err != nil {
+		return err
+	}
+
+	// This can touch far more keys than fit in a single badger txn
+	batch := p.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	for _, txID := range txIDs {
+		err := batch.Delete(makeTxKey(stateURI, txID))
+		if err != nil {
+			return err
+		}
+		err = batch.Delete(append([]byte("leaf:"+stateURI+":"), txID[:]...))
+		if err != nil {
+			return err
+		}
+		err = batch.Set(makePrunedTxKey(stateURI, txID), nil)
+		if err != nil {
+			return err
+		}
+	}
+	err = batch.Flush()
+	if err != nil {
+		return err
+	}
+	p.Infof(0, "pruned %v txs from %v (new base: %v)", len(txIDs), stateURI, newBase.Pretty())
+	return nil
+}
+
+func (p *badgerTxStore) TxWasPruned(stateURI string, txID state.Version) (bool, error) {
+	var pruned bool
+	err := p.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(makePrunedTxKey(stateURI, txID))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+		pruned = true
+		return nil
+	})
+	return pruned, err
+}
+
+func (p *badgerTxStore) HistoryBase(stateURI string) (state.Version, error) {
+	base := GenesisTxID
+	err := p.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(makeHistoryBaseKey(stateURI))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+		return item.Value(func(val []byte) error {
+			base = state.VersionFromBytes(val)
+			return nil
+		})
+	})
+	return base, err
+}
+
 func (s *badgerTxStore) KnownStateURIs() (types.StringSet, error) {
 	stateURIs := types.NewStringSet(nil)
 	err := s.db.View(func(txn *badger.Txn) error {
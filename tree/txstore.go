@@ -20,6 +20,15 @@ type TxStore interface {
 	UnmarkLeaf(stateURI string, txID state.Version) error
 	Leaves(stateURI string) ([]state.Version, error)
 
+	// PruneTxs deletes the given txs (which must all be ancestors of the
+	// checkpoint tx `newBase`) and makes `newBase` the first tx in the state
+	// URI's history.  A tombstone is kept for each pruned tx.
+	PruneTxs(stateURI string, newBase state.Version, txIDs []state.Version) error
+	TxWasPruned(stateURI string, txID state.Version) (bool, error)
+	// HistoryBase returns the first tx in the state URI's history, which is
+	// GenesisTxID unless the history has been pruned.
+	HistoryBase(stateURI string) (state.Version, error)
+
 	DebugPrint()
 }
 
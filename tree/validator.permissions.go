@@ -4,18 +4,83 @@ import (
 	"bytes"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"redwood.dev/errors"
 	"redwood.dev/state"
 	"redwood.dev/tree/nelson"
 	"redwood.dev/tree/pb"
-	"redwood.dev/utils"
+	"redwood.dev/types"
 )
 
+// The permissions validator's config maps subjects to rules:
+//
+//	{
+//	    "96216849c4...": { "^\\.posts.*$": { "write": true } },
+//	    "role:editors":  { "^\\.posts.*$": { "set": true, "delete": false, "rateLimit": { "txs": 10, "seconds": 60 } } },
+//	    "*":             { "^.*$":         { "read": true } }
+//	}
+//
+// A subject is an address, a role (whose members are listed in the validated
+// node under "Roles/<name>", either as a list of addresses or as a map keyed by
+// address), or "*" (anyone).  A sender's own address-specific rules replace the
+// "*" rules rather than adding to them, so they can be used to narrow what a
+// particular user may do.  The rules of any roles the sender belongs to apply
+// in either case.  Each rule maps a keypath regex (in which
+// "$(sender)" expands to the sender's address) to a set of bits:
+//   - "read" governs access to state, and is only enforced once some rule in the
+//     config mentions it.  Denying reads of a keypath also denies reads of its
+//     ancestors, as those would return it.
+//   - "set", "delete", and "splice" (patches with a range) govern individual
+//     patch operations, and fall back to "write" when absent
+//
+// A bit that's explicitly false is a deny rule, and overrides any matching
+// rule that allows the operation.  An allowing rule may also carry a
+// "rateLimit", which caps how many txs a given sender can submit under that
+// rule within a sliding window.  Rate limits depend on when txs arrive, so
+// they're not part of validation (which every peer must agree on), and are
+// only enforced on txs that a node writes on a client's behalf (see
+// RateLimiter).
 type permissionsValidator struct {
-	permissions map[string]interface{}
+	permissions  map[string]interface{}
+	hasRoles     bool
+	enforceReads bool
+
+	rateLimitHistory   map[string]rateLimitWindow // keyed by sender and rule
+	rateLimitLastSweep time.Time
+	rateLimitHistoryMu sync.Mutex
+}
+
+// rateLimitWindow holds the times of a sender's recent txs under a rule.
+type rateLimitWindow struct {
+	duration time.Duration
+	txs      []time.Time
 }
 
+// rateLimitSweepInterval is how often the rate limit history of senders who
+// have gone quiet is discarded.
+var rateLimitSweepInterval = time.Minute
+
+// Ensure permissionsValidator conforms to the ReadAccessValidator and
+// RateLimiter interfaces
+var (
+	_ ReadAccessValidator = (*permissionsValidator)(nil)
+	_ RateLimiter         = (*permissionsValidator)(nil)
+)
+
+var PermissionsRolesKeypath = state.Keypath("Roles")
+
+const (
+	permissionsRolePrefix = "role:"
+
+	permissionsOpRead   = "read"
+	permissionsOpWrite  = "write"
+	permissionsOpSet    = "set"
+	permissionsOpDelete = "delete"
+	permissionsOpSplice = "splice"
+)
+
 func NewPermissionsValidator(config state.Node) (Validator, error) {
 	cfg, exists, err := nelson.GetValueRecursive(config, nil, nil)
 	if err != nil {
@@ -28,52 +93,372 @@ func NewPermissionsValidator(config state.Node) (Validator, error) {
 	if !isMap {
 		return nil, errors.New("permissions validator needs a map of permissions as its config")
 	}
-	lowercasePerms := make(map[string]interface{}, len(asMap))
-	for address, perms := range asMap {
-		lowercasePerms[strings.ToLower(address)] = perms
+
+	v := &permissionsValidator{
+		permissions:      make(map[string]interface{}, len(asMap)),
+		rateLimitHistory: make(map[string]rateLimitWindow),
+	}
+	for subject, perms := range asMap {
+		if strings.HasPrefix(subject, permissionsRolePrefix) {
+			v.hasRoles = true
+		} else {
+			subject = strings.ToLower(subject)
+		}
+		v.permissions[subject] = perms
+
+		permsMap, _ := perms.(map[string]interface{})
+		for pattern, rule := range permsMap {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, errors.Wrapf(err, "bad permissions pattern '%v'", pattern)
+			}
+			ruleMap, _ := rule.(map[string]interface{})
+			if _, exists := ruleMap[permissionsOpRead]; exists {
+				v.enforceReads = true
+			}
+		}
 	}
-	return &permissionsValidator{permissions: lowercasePerms}, nil
+	return v, nil
 }
 
 var senderRegexp = regexp.MustCompile(`\$\(sender\)`)
 
 func (v *permissionsValidator) ValidateTx(node state.Node, tx *Tx) error {
-	perms, exists := v.permissions[strings.ToLower(tx.From.Hex())]
-	if !exists {
-		perms, exists = v.permissions["*"]
-		if !exists {
-			return errors.WithStack(errors.Wrapf(errors.Err403, "permissions key for user '%v' does not exist", tx.From.Hex()))
+	subjects, err := v.subjectsFor(node, tx.From)
+	if err != nil {
+		return err
+	} else if len(subjects) == 0 {
+		return errors.WithStack(errors.Wrapf(errors.Err403, "permissions key for user '%v' does not exist", tx.From.Hex()))
+	}
+
+	for _, patch := range tx.Patches {
+		op := permissionsOpForPatch(patch)
+
+		allowed, denied, _, err := v.evaluate(subjects, tx.From, permissionsKeypath(patch.Keypath), op)
+		if err != nil {
+			return err
+		} else if denied {
+			return errors.Wrapf(errors.Err403, "denied by rule (user: %v, op: %v, patch: %v)", tx.From.String(), op, patch.String())
+		} else if !allowed {
+			return errors.Wrapf(errors.Err403, "could not find a matching rule (user: %v, patch: %v)", tx.From.String(), patch.String())
 		}
 	}
-	permsMap, isMap := perms.(map[string]interface{})
-	if !isMap {
-		return errors.WithStack(errors.Wrapf(errors.Err403, "permissions key for user '%v' does not contain a map", tx.From.Hex()))
+	return nil
+}
+
+// CheckRateLimits counts the tx against the rate limits of the rules that
+// allow its patches, and returns ErrRateLimited if any of them is exhausted.
+func (v *permissionsValidator) CheckRateLimits(node state.Node, tx *Tx) error {
+	subjects, err := v.subjectsFor(node, tx.From)
+	if err != nil {
+		return err
 	}
 
+	rateLimitedRules := make(map[string]map[string]interface{})
 	for _, patch := range tx.Patches {
-		var valid bool
+		_, _, matchedRules, err := v.evaluate(subjects, tx.From, permissionsKeypath(patch.Keypath), permissionsOpForPatch(patch))
+		if err != nil {
+			return err
+		}
+		for ruleID, rule := range matchedRules {
+			if _, exists := rule["rateLimit"]; exists {
+				rateLimitedRules[ruleID] = rule
+			}
+		}
+	}
+	return v.checkRateLimits(tx.From, rateLimitedRules)
+}
+
+// HasReadAccess returns true if any of the given addresses may read the given
+// keypath (relative to the validated node).  Reading a keypath returns its
+// whole subtree, so the read is refused if a rule denies any keypath within it.
+func (v *permissionsValidator) HasReadAccess(node state.Node, keypath state.Keypath, addresses types.AddressSet) (bool, error) {
+	if !v.enforceReads {
+		return true, nil
+	}
+	for addr := range addresses {
+		subjects, err := v.subjectsFor(node, addr)
+		if err != nil {
+			return false, err
+		}
+		allowed, denied, _, err := v.evaluate(subjects, addr, permissionsKeypath(keypath), permissionsOpRead)
+		if err != nil {
+			return false, err
+		} else if !allowed || denied {
+			continue
+		}
+
+		denied, err = v.subtreeReadDenied(node, keypath, subjects, addr)
+		if err != nil {
+			return false, err
+		} else if !denied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// subtreeReadDenied returns true if a rule denies the given address read
+// access to any keypath beneath `keypath`.
+func (v *permissionsValidator) subtreeReadDenied(
+	node state.Node,
+	keypath state.Keypath,
+	subjects map[string]map[string]interface{},
+	addr types.Address,
+) (bool, error) {
+	if node == nil || !permissionsHaveReadDenyRules(subjects) {
+		return false, nil
+	}
+
+	iter := node.Iterator(keypath, false, 10)
+	defer iter.Close()
+
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		descendantKeypath := iter.Node().Keypath().RelativeTo(node.Keypath())
+		_, denied, _, err := v.evaluate(subjects, addr, permissionsKeypath(descendantKeypath), permissionsOpRead)
+		if err != nil {
+			return false, err
+		} else if denied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// subjectsFor returns the rulesets that apply to the given address: its own
+// (or the wildcard ruleset if it has none) and those of any roles it belongs to.
+func (v *permissionsValidator) subjectsFor(node state.Node, addr types.Address) (map[string]map[string]interface{}, error) {
+	subjects := make(map[string]map[string]interface{})
+
+	add := func(subject string) error {
+		perms, exists := v.permissions[subject]
+		if !exists {
+			return nil
+		}
+		permsMap, isMap := perms.(map[string]interface{})
+		if !isMap {
+			return errors.WithStack(errors.Wrapf(errors.Err403, "permissions key for '%v' does not contain a map", subject))
+		}
+		subjects[subject] = permsMap
+		return nil
+	}
+
+	if _, exists := v.permissions[strings.ToLower(addr.Hex())]; exists {
+		err := add(strings.ToLower(addr.Hex()))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err := add("*")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if v.hasRoles && node != nil {
+		roles, exists, err := node.Value(PermissionsRolesKeypath, nil)
+		if err != nil && errors.Cause(err) != errors.Err404 {
+			return nil, err
+		} else if exists {
+			rolesMap, _ := roles.(map[string]interface{})
+			for role, members := range rolesMap {
+				if permissionsRoleContains(members, addr) {
+					err := add(permissionsRolePrefix + role)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return subjects, nil
+}
+
+// evaluate checks every rule matching the keypath.  Explicitly denying rules
+// take precedence over allowing ones.  The allowing rules are returned, keyed
+// by subject and pattern.
+func (v *permissionsValidator) evaluate(
+	subjects map[string]map[string]interface{},
+	sender types.Address,
+	keypath string,
+	op string,
+) (allowed, denied bool, matchedRules map[string]map[string]interface{}, _ error) {
+	matchedRules = make(map[string]map[string]interface{})
 
-		// @@TODO: hacky
-		keypath := pb.KeypathSeparator + string(bytes.ReplaceAll(patch.Keypath, state.KeypathSeparator, []byte(pb.KeypathSeparator)))
-		for pattern := range permsMap {
-			expandedPattern := string(senderRegexp.ReplaceAll([]byte(pattern), []byte(tx.From.Hex())))
+	for subject, permsMap := range subjects {
+		for pattern, rule := range permsMap {
+			expandedPattern := string(senderRegexp.ReplaceAll([]byte(pattern), []byte(sender.Hex())))
 			matched, err := regexp.MatchString(expandedPattern, keypath)
 			if err != nil {
-				return errors.Wrapf(errors.Err403, "error executing regex")
+				return false, false, nil, errors.Wrapf(errors.Err403, "error executing regex")
+			} else if !matched {
+				continue
 			}
 
-			if matched {
-				canWrite, _ := utils.GetValue(permsMap, []string{pattern, "write"})
-				if canWrite == true {
-					valid = true
-					break
-				}
+			ruleMap, isMap := rule.(map[string]interface{})
+			if !isMap {
+				continue
+			}
+
+			bit, exists := permissionsBit(ruleMap, op)
+			if !exists {
+				continue
+			} else if !bit {
+				denied = true
+			} else {
+				allowed = true
+				matchedRules[subject+"\x00"+pattern] = ruleMap
 			}
 		}
-		if !valid {
-			return errors.Wrapf(errors.Err403, "could not find a matching rule (user: %v, patch: %v)", tx.From.String(), patch.String())
+	}
+	return allowed, denied, matchedRules, nil
+}
+
+func (v *permissionsValidator) checkRateLimits(sender types.Address, rules map[string]map[string]interface{}) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	v.rateLimitHistoryMu.Lock()
+	defer v.rateLimitHistoryMu.Unlock()
+
+	now := time.Now()
+	v.sweepRateLimitHistory(now)
+
+	windows := make(map[string]rateLimitWindow, len(rules))
+	for ruleID, rule := range rules {
+		txs, seconds, err := permissionsRateLimit(rule)
+		if err != nil {
+			return err
+		}
+
+		key := sender.Hex() + "\x00" + ruleID
+		window := rateLimitWindow{duration: time.Duration(seconds * float64(time.Second))}
+		cutoff := now.Add(-window.duration)
+		for _, t := range v.rateLimitHistory[key].txs {
+			if t.After(cutoff) {
+				window.txs = append(window.txs, t)
+			}
+		}
+		if len(window.txs) == 0 {
+			delete(v.rateLimitHistory, key)
+		} else {
+			v.rateLimitHistory[key] = window
+		}
+
+		if float64(len(window.txs)) >= txs {
+			pattern := ruleID[strings.IndexByte(ruleID, 0)+1:]
+			return errors.Wrapf(ErrRateLimited, "user: %v, rule: %v", sender.String(), pattern)
 		}
+		windows[key] = window
 	}
 
+	// Only record the tx once it has passed every limit
+	for key, window := range windows {
+		window.txs = append(window.txs, now)
+		v.rateLimitHistory[key] = window
+	}
 	return nil
 }
+
+// sweepRateLimitHistory discards the history of senders who haven't sent a
+// tx within their rule's window, so that it doesn't grow without bound.
+func (v *permissionsValidator) sweepRateLimitHistory(now time.Time) {
+	if now.Sub(v.rateLimitLastSweep) < rateLimitSweepInterval {
+		return
+	}
+	for key, window := range v.rateLimitHistory {
+		if len(window.txs) == 0 || !window.txs[len(window.txs)-1].After(now.Add(-window.duration)) {
+			delete(v.rateLimitHistory, key)
+		}
+	}
+	v.rateLimitLastSweep = now
+}
+
+func permissionsHaveReadDenyRules(subjects map[string]map[string]interface{}) bool {
+	for _, permsMap := range subjects {
+		for _, rule := range permsMap {
+			ruleMap, _ := rule.(map[string]interface{})
+			if bit, exists := permissionsBit(ruleMap, permissionsOpRead); exists && !bit {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func permissionsOpForPatch(patch Patch) string {
+	if patch.Range != nil {
+		return permissionsOpSplice
+	} else if len(patch.ValueJSON) == 0 || bytes.Equal(bytes.TrimSpace(patch.ValueJSON), []byte("null")) {
+		return permissionsOpDelete
+	}
+	return permissionsOpSet
+}
+
+// permissionsBit returns the value of the rule's bit for the given operation.
+// Write operations fall back to the "write" bit.
+func permissionsBit(rule map[string]interface{}, op string) (value bool, exists bool) {
+	if bit, is := rule[op].(bool); is {
+		return bit, true
+	}
+	if op != permissionsOpRead {
+		if bit, is := rule[permissionsOpWrite].(bool); is {
+			return bit, true
+		}
+	}
+	return false, false
+}
+
+func permissionsRateLimit(rule map[string]interface{}) (txs float64, seconds float64, _ error) {
+	rateLimit, isMap := rule["rateLimit"].(map[string]interface{})
+	if !isMap {
+		return 0, 0, errors.New("permissions rule 'rateLimit' must be a map")
+	}
+	txs, ok := permissionsNumber(rateLimit["txs"])
+	if !ok {
+		return 0, 0, errors.New("permissions rule 'rateLimit' needs a numeric 'txs' param")
+	}
+	seconds, ok = permissionsNumber(rateLimit["seconds"])
+	if !ok {
+		return 0, 0, errors.New("permissions rule 'rateLimit' needs a numeric 'seconds' param")
+	}
+	return txs, seconds, nil
+}
+
+func permissionsNumber(x interface{}) (float64, bool) {
+	switch n := x.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func permissionsRoleContains(members interface{}, addr types.Address) bool {
+	switch members := members.(type) {
+	case []interface{}:
+		for _, member := range members {
+			if s, is := member.(string); is && strings.EqualFold(strings.TrimPrefix(s, "0x"), addr.Hex()) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for member, val := range members {
+			if strings.EqualFold(strings.TrimPrefix(member, "0x"), addr.Hex()) && val != false && val != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// @@TODO: hacky
+func permissionsKeypath(keypath state.Keypath) string {
+	return pb.KeypathSeparator + string(bytes.ReplaceAll(keypath, state.KeypathSeparator, []byte(pb.KeypathSeparator)))
+}
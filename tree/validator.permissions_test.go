@@ -0,0 +1,203 @@
+package tree_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+func TestPermissionsValidator(t *testing.T) {
+	var (
+		owner    = types.RandomAddress()
+		editor   = types.RandomAddress()
+		narrowed = types.RandomAddress()
+		anyone   = types.RandomAddress()
+	)
+
+	rule := func(bits ...interface{}) map[string]interface{} {
+		m := make(map[string]interface{})
+		for i := 0; i < len(bits); i += 2 {
+			m[bits[i].(string)] = bits[i+1]
+		}
+		return m
+	}
+
+	newValidator := func(t *testing.T, perms map[string]interface{}) tree.ReadAccessValidator {
+		t.Helper()
+		config := resolvedConfig(t, map[string]interface{}{
+			"Content-Type": "validator/permissions",
+			"value":        perms,
+		})
+		validator, err := tree.NewPermissionsValidator(config)
+		require.NoError(t, err)
+		return validator.(tree.ReadAccessValidator)
+	}
+
+	node := state.NewMemoryNodeWithValue(map[string]interface{}{
+		"Roles": map[string]interface{}{
+			"editors": []interface{}{"0x" + strings.ToUpper(editor.Hex())},
+		},
+	})
+
+	perms := map[string]interface{}{
+		strings.ToUpper(owner.Hex()): map[string]interface{}{
+			"^.*$":                 rule("write", true, "read", true),
+			"^\\.posts\\.pinned.*": rule("write", false),
+		},
+		strings.ToUpper(narrowed.Hex()): map[string]interface{}{
+			"^\\.profile.*$": rule("write", true),
+		},
+		"role:editors": map[string]interface{}{
+			"^\\.posts.*$":  rule("set", true, "splice", true, "delete", false),
+			"^\\.drafts.*$": rule("write", true, "rateLimit", map[string]interface{}{"txs": 2.0, "seconds": 3600.0}),
+		},
+		"*": map[string]interface{}{
+			"^\\.posts.*$":         rule("read", true),
+			"^\\.posts\\.pinned.*": rule("write", false),
+			"^\\.comments.*$":      rule("write", true),
+		},
+	}
+
+	patch := func(t *testing.T, text string) tree.Patch {
+		t.Helper()
+		var p tree.Patch
+		err := p.UnmarshalText([]byte(text))
+		require.NoError(t, err)
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		sender types.Address
+		patch  string
+		errMsg string
+	}{
+		{"address rules apply", owner, `.config = {}`, ""},
+		{"role rules apply", editor, `.posts.foo = "bar"`, ""},
+		{"range splices are their own op", editor, `.posts.list[0:0] = ["bar"]`, ""},
+		{"deletes can be denied separately", editor, `.posts.foo = null`, "denied by rule"},
+		{"deny rules override allows", owner, `.posts.pinned = "bar"`, "denied by rule"},
+		{"unmatched patches are rejected", editor, `.config = {}`, "could not find a matching rule"},
+		{"non-members don't get role rules", anyone, `.posts.foo = "bar"`, "could not find a matching rule"},
+		{"wildcard rules apply to anyone without their own", anyone, `.comments.foo = "bar"`, ""},
+		{"wildcard rules apply alongside role rules", editor, `.comments.foo = "bar"`, ""},
+		{"address rules replace the wildcard", narrowed, `.comments.foo = "bar"`, "could not find a matching rule"},
+		{"address rules replace the wildcard (allowed)", narrowed, `.profile.name = "bar"`, ""},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			validator := newValidator(t, perms)
+			err := validator.ValidateTx(node, &tree.Tx{From: test.sender, Patches: []tree.Patch{patch(t, test.patch)}})
+			if test.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.True(t, errors.Cause(err) == errors.Err403)
+				require.Contains(t, err.Error(), test.errMsg)
+			}
+		})
+	}
+
+	t.Run("rate limits", func(t *testing.T) {
+		validator := newValidator(t, perms).(tree.RateLimiter)
+		tx := &tree.Tx{From: editor, Patches: []tree.Patch{patch(t, `.drafts.foo = "bar"`)}}
+
+		require.NoError(t, validator.CheckRateLimits(node, tx))
+		require.NoError(t, validator.CheckRateLimits(node, tx))
+		err := validator.CheckRateLimits(node, tx)
+		require.Error(t, err)
+		require.True(t, errors.Cause(err) == tree.ErrRateLimited)
+
+		// Limits are tracked per sender
+		err = validator.CheckRateLimits(node, &tree.Tx{From: owner, Patches: tx.Patches})
+		require.NoError(t, err)
+
+		// Validation doesn't depend on when txs arrive, so every peer agrees on it
+		for i := 0; i < 3; i++ {
+			require.NoError(t, validator.ValidateTx(node, tx))
+		}
+	})
+
+	t.Run("rate limit history of quiet senders is discarded", func(t *testing.T) {
+		defer tree.SetRateLimitSweepInterval(0)()
+
+		validator := newValidator(t, map[string]interface{}{
+			"*": map[string]interface{}{
+				"^.*$": rule("write", true, "rateLimit", map[string]interface{}{"txs": 1.0, "seconds": 0.05}),
+			},
+		})
+		tx := func(sender types.Address) *tree.Tx {
+			return &tree.Tx{From: sender, Patches: []tree.Patch{patch(t, `.foo = "bar"`)}}
+		}
+
+		for i := 0; i < 10; i++ {
+			require.NoError(t, validator.(tree.RateLimiter).CheckRateLimits(node, tx(types.RandomAddress())))
+		}
+		require.Equal(t, 10, tree.RateLimitHistorySize(validator))
+
+		time.Sleep(100 * time.Millisecond)
+		require.NoError(t, validator.(tree.RateLimiter).CheckRateLimits(node, tx(owner)))
+		require.Equal(t, 1, tree.RateLimitHistorySize(validator))
+	})
+
+	t.Run("read access", func(t *testing.T) {
+		validator := newValidator(t, perms)
+
+		readable := func(addr types.Address, keypath string) bool {
+			allowed, err := validator.HasReadAccess(node, state.Keypath(keypath), types.NewAddressSet([]types.Address{addr}))
+			require.NoError(t, err)
+			return allowed
+		}
+		require.True(t, readable(owner, ""))
+		require.True(t, readable(owner, "config"))
+		require.True(t, readable(anyone, "posts/foo"))
+		require.False(t, readable(anyone, "config"))
+		require.False(t, readable(anyone, ""))
+	})
+
+	t.Run("reads of ancestors of denied keypaths are refused", func(t *testing.T) {
+		validator := newValidator(t, map[string]interface{}{
+			"*": map[string]interface{}{
+				"^.*$":          rule("read", true),
+				"^\\.secret.*$": rule("read", false),
+			},
+		})
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{
+			"public": map[string]interface{}{"foo": "bar"},
+			"nested": map[string]interface{}{
+				"secret": map[string]interface{}{"password": "hunter2"},
+			},
+			"secret": map[string]interface{}{"password": "hunter2"},
+		})
+
+		readable := func(keypath string) bool {
+			allowed, err := validator.HasReadAccess(node, state.Keypath(keypath), types.NewAddressSet([]types.Address{anyone}))
+			require.NoError(t, err)
+			return allowed
+		}
+		require.False(t, readable(""))
+		require.False(t, readable("secret"))
+		require.False(t, readable("secret/password"))
+		require.True(t, readable("public"))
+		require.True(t, readable("nested"))
+		require.True(t, readable("nested/secret"))
+	})
+
+	t.Run("configs without read rules don't restrict reads", func(t *testing.T) {
+		validator := newValidator(t, map[string]interface{}{
+			"*": map[string]interface{}{"^.*$": rule("write", true)},
+		})
+		allowed, err := validator.HasReadAccess(node, state.Keypath("config"), types.NewAddressSet([]types.Address{anyone}))
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+}
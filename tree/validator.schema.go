@@ -0,0 +1,234 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+	"redwood.dev/tree/pb"
+)
+
+type schemaValidator struct {
+	schema *jsonschema.Schema
+}
+
+// Ensure schemaValidator conforms to the Validator interface
+var _ Validator = (*schemaValidator)(nil)
+
+var ErrSchemaMismatch = errors.New("state does not match schema")
+
+// NewSchemaValidator creates a validator that rejects any tx that would leave
+// its subtree non-conforming to a JSON Schema.  The schema is provided in the
+// config's 'schema' key, either inline or as a NelSON link to a blob.
+func NewSchemaValidator(config state.Node) (_ Validator, err error) {
+	defer errors.Annotate(&err, "NewSchemaValidator")
+
+	schemaVal, exists, err := nelson.GetValueRecursive(config, state.Keypath("schema"), nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !exists {
+		return nil, errors.New("schema validator needs a 'schema' param")
+	}
+
+	var schemaBytes []byte
+	switch v := schemaVal.(type) {
+	case map[string]interface{}, bool:
+		schemaBytes, err = json.Marshal(v)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	default:
+		readableSchema, ok := nelson.GetReadCloser(schemaVal)
+		if !ok {
+			return nil, errors.Errorf("schema validator needs a 'schema' param of type object, string, []byte, or io.ReadCloser (got %T)", schemaVal)
+		}
+		defer readableSchema.Close()
+
+		schemaBytes, err = ioutil.ReadAll(readableSchema)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	compiler := jsonschema.NewCompiler()
+	err = compiler.AddResource("schema.json", bytes.NewReader(schemaBytes))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	schema, err := compiler.Compile("schema.json")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &schemaValidator{schema: schema}, nil
+}
+
+func (v *schemaValidator) ValidateTx(node state.Node, tx *Tx) (err error) {
+	nodeJSON, err := json.Marshal(node)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var proposed interface{}
+	if len(nodeJSON) > 0 {
+		err = json.Unmarshal(nodeJSON, &proposed)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	// Behavior configs live inside of the subtrees they govern, but aren't part of their data
+	if asMap, is := proposed.(map[string]interface{}); is {
+		delete(asMap, string(MergeTypeKeypath))
+		delete(asMap, string(ValidatorKeypath))
+		delete(asMap, string(IndicesKeypath))
+	}
+
+	for _, patch := range tx.Patches {
+		var val interface{}
+		if len(patch.ValueJSON) > 0 {
+			val, err = patch.Value()
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		proposed, err = schemaApplyPatch(proposed, patch.Keypath.Parts(), patch.Range, val)
+		if err != nil {
+			return errors.Wrapf(err, "patch=%v", patch.String())
+		}
+	}
+
+	err = v.schema.Validate(proposed)
+	if verr, is := err.(*jsonschema.ValidationError); is {
+		return errors.Wrap(ErrSchemaMismatch, schemaErrorMessages(verr))
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// schemaApplyPatch applies a patch to a plain JSON value, following the same
+// semantics as the dumb resolver (a nil value deletes).
+func schemaApplyPatch(current interface{}, keypath []state.Keypath, rng *state.Range, val interface{}) (interface{}, error) {
+	if len(keypath) == 0 {
+		if rng == nil {
+			return val, nil
+		}
+
+		switch current := current.(type) {
+		case []interface{}:
+			if !rng.ValidForLength(uint64(len(current))) {
+				return nil, state.ErrInvalidRange
+			}
+			var insert []interface{}
+			if val != nil {
+				var is bool
+				insert, is = val.([]interface{})
+				if !is {
+					return nil, errors.Wrapf(state.ErrWrongType, "cannot splice %T into a slice", val)
+				}
+			}
+			start, end := rng.IndicesForLength(uint64(len(current)))
+			spliced := append([]interface{}{}, current[:start]...)
+			spliced = append(spliced, insert...)
+			return append(spliced, current[end:]...), nil
+
+		case string:
+			if !rng.ValidForLength(uint64(len(current))) {
+				return nil, state.ErrInvalidRange
+			}
+			var insert string
+			if val != nil {
+				var is bool
+				insert, is = val.(string)
+				if !is {
+					return nil, errors.Wrapf(state.ErrWrongType, "cannot splice %T into a string", val)
+				}
+			}
+			start, end := rng.IndicesForLength(uint64(len(current)))
+			return current[:start] + insert + current[end:], nil
+
+		default:
+			return nil, state.ErrRangeOverNonSlice
+		}
+	}
+
+	key := keypath[0]
+	deleting := len(keypath) == 1 && rng == nil && val == nil
+
+	switch c := current.(type) {
+	case nil:
+		if deleting {
+			return nil, nil
+		}
+		current = map[string]interface{}{}
+		return schemaApplyPatch(current, keypath, rng, val)
+
+	case map[string]interface{}:
+		if deleting {
+			delete(c, string(key))
+			return c, nil
+		}
+		child, err := schemaApplyPatch(c[string(key)], keypath[1:], rng, val)
+		if err != nil {
+			return nil, err
+		}
+		c[string(key)] = child
+		return c, nil
+
+	case []interface{}:
+		idx, err := strconv.ParseUint(string(key), 10, 64)
+		if err != nil || idx >= uint64(len(c)) {
+			return nil, errors.Wrapf(errors.Err404, "keypath %v", key)
+		}
+		if deleting {
+			return append(c[:idx:idx], c[idx+1:]...), nil
+		}
+		child, err := schemaApplyPatch(c[idx], keypath[1:], rng, val)
+		if err != nil {
+			return nil, err
+		}
+		c[idx] = child
+		return c, nil
+
+	default:
+		return nil, errors.Wrapf(errors.Err404, "keypath %v", key)
+	}
+}
+
+// schemaErrorMessages flattens a tree of validation errors into a message that
+// names each offending keypath.
+func schemaErrorMessages(verr *jsonschema.ValidationError) string {
+	var msgs []string
+	var walk func(verr *jsonschema.ValidationError)
+	walk = func(verr *jsonschema.ValidationError) {
+		if len(verr.Causes) == 0 {
+			msgs = append(msgs, "keypath '"+schemaKeypath(verr.InstanceLocation)+"': "+verr.Message)
+			return
+		}
+		for _, cause := range verr.Causes {
+			walk(cause)
+		}
+	}
+	walk(verr)
+	sort.Strings(msgs)
+	return strings.Join(msgs, "; ")
+}
+
+// schemaKeypath converts a JSON pointer into the patch keypath syntax (i.e. ".foo.bar")
+func schemaKeypath(jsonPointer string) string {
+	if jsonPointer == "" {
+		return pb.KeypathSeparator
+	}
+	parts := strings.Split(strings.TrimPrefix(jsonPointer, "/"), "/")
+	for i := range parts {
+		parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(parts[i])
+	}
+	return pb.KeypathSeparator + strings.Join(parts, pb.KeypathSeparator)
+}
@@ -0,0 +1,73 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+func TestSchemaValidator(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"messages"},
+		"properties": map[string]interface{}{
+			"messages": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"required":             []interface{}{"text"},
+					"properties":           map[string]interface{}{"text": map[string]interface{}{"type": "string"}},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		schema  interface{}
+		patch   string
+		invalid string
+	}{
+		{"accepts conforming patches (inline schema)", schema, `.messages[0:0] = [{"text": "hi"}]`, ""},
+		{"accepts conforming patches (string schema)", `{"type": "object", "required": ["messages"]}`, `.foo = 123`, ""},
+		{"rejects patches of the wrong type", schema, `.messages[1:1] = [{"text": 123}]`, ".messages.1.text"},
+		{"rejects patches with extra fields", schema, `.messages.0.foo = "bar"`, ".messages.0"},
+		{"rejects deleting required fields", schema, `.messages = null`, "."},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			config := resolvedConfig(t, map[string]interface{}{
+				"Content-Type": "validator/schema",
+				"value":        map[string]interface{}{"schema": test.schema},
+			})
+			validator, err := tree.NewSchemaValidator(config)
+			require.NoError(t, err)
+
+			node := state.NewMemoryNodeWithValue(map[string]interface{}{
+				"Validator": map[string]interface{}{"Content-Type": "validator/schema"},
+				"messages":  []interface{}{map[string]interface{}{"text": "hello"}},
+			})
+
+			var patch tree.Patch
+			err = patch.UnmarshalText([]byte(test.patch))
+			require.NoError(t, err)
+
+			err = validator.ValidateTx(node, &tree.Tx{ID: state.RandomVersion(), From: types.RandomAddress(), Patches: []tree.Patch{patch}})
+			if test.invalid == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.True(t, errors.Cause(err) == tree.ErrSchemaMismatch)
+				require.Contains(t, err.Error(), "keypath '"+test.invalid+"'")
+			}
+		})
+	}
+}
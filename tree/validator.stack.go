@@ -0,0 +1,96 @@
+package tree
+
+import (
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/types"
+)
+
+type stackValidator struct {
+	stages       []Validator
+	contentTypes []string
+}
+
+// Ensure stackValidator conforms to the ReadAccessValidator, RateLimiter, and
+// ClosableBehavior interfaces
+var (
+	_ ReadAccessValidator = (*stackValidator)(nil)
+	_ RateLimiter         = (*stackValidator)(nil)
+	_ ClosableBehavior    = (*stackValidator)(nil)
+)
+
+// NewStackValidator creates a validator from an ordered list of child validator
+// configs.  A tx is valid only if every stage accepts it.
+func NewStackValidator(config state.Node) (_ Validator, err error) {
+	defer errors.Annotate(&err, "NewStackValidator")
+
+	stageConfigs, contentTypes, err := stackStageConfigs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]Validator, len(stageConfigs))
+	defer func() {
+		if err != nil {
+			closeStages(stages)
+		}
+	}()
+	for i, stageConfig := range stageConfigs {
+		ctor, exists := validatorRegistry[contentTypes[i]]
+		if !exists {
+			return nil, errors.Errorf("stage %v: unknown validator type '%v'", i, contentTypes[i])
+		}
+
+		stages[i], err = ctor(stageConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "stage %v (%v)", i, contentTypes[i])
+		}
+	}
+	return &stackValidator{stages: stages, contentTypes: contentTypes}, nil
+}
+
+func (v *stackValidator) ValidateTx(node state.Node, tx *Tx) error {
+	for i, stage := range v.stages {
+		err := stage.ValidateTx(node, tx)
+		if err != nil {
+			return errors.Wrapf(err, "validator stack: stage %v (%v)", i, v.contentTypes[i])
+		}
+	}
+	return nil
+}
+
+// HasReadAccess grants read access only if every stage that governs reads grants it.
+func (v *stackValidator) HasReadAccess(node state.Node, keypath state.Keypath, addresses types.AddressSet) (bool, error) {
+	for i, stage := range v.stages {
+		readAccessStage, is := stage.(ReadAccessValidator)
+		if !is {
+			continue
+		}
+		allowed, err := readAccessStage.HasReadAccess(node, keypath, addresses)
+		if err != nil {
+			return false, errors.Wrapf(err, "validator stack: stage %v (%v)", i, v.contentTypes[i])
+		} else if !allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// CheckRateLimits applies the rate limits of every stage that has them.
+func (v *stackValidator) CheckRateLimits(node state.Node, tx *Tx) error {
+	for i, stage := range v.stages {
+		rateLimitingStage, is := stage.(RateLimiter)
+		if !is {
+			continue
+		}
+		err := rateLimitingStage.CheckRateLimits(node, tx)
+		if err != nil {
+			return errors.Wrapf(err, "validator stack: stage %v (%v)", i, v.contentTypes[i])
+		}
+	}
+	return nil
+}
+
+func (v *stackValidator) Close() error {
+	return closeStages(v.stages)
+}
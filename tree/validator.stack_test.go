@@ -0,0 +1,48 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+func TestStackValidator(t *testing.T) {
+	config := resolvedConfig(t, map[string]interface{}{
+		"Content-Type": "validator/stack",
+		"value": []interface{}{
+			map[string]interface{}{
+				"Content-Type": "validator/permissions",
+				"value": map[string]interface{}{
+					"*": map[string]interface{}{"^.*$": map[string]interface{}{"write": true}},
+				},
+			},
+			map[string]interface{}{
+				"Content-Type": "validator/permissions",
+				"value": map[string]interface{}{
+					"*": map[string]interface{}{"^\\.messages.*$": map[string]interface{}{"write": true}},
+				},
+			},
+		},
+	})
+
+	validator, err := tree.NewStackValidator(config)
+	require.NoError(t, err)
+
+	sender := types.RandomAddress()
+	node := state.NewMemoryNode()
+
+	err = validator.ValidateTx(node, &tree.Tx{From: sender, Patches: []tree.Patch{
+		{Keypath: state.Keypath("messages"), ValueJSON: []byte(`[]`)},
+	}})
+	require.NoError(t, err)
+
+	err = validator.ValidateTx(node, &tree.Tx{From: sender, Patches: []tree.Patch{
+		{Keypath: state.Keypath("config"), ValueJSON: []byte(`{}`)},
+	}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stage 1 (validator/permissions)")
+}
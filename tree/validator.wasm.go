@@ -0,0 +1,69 @@
+package tree
+
+import (
+	"redwood.dev/errors"
+	"redwood.dev/state"
+)
+
+type wasmValidator struct {
+	sandbox *wasmSandbox
+}
+
+// Ensure wasmValidator conforms to the Validator and ClosableBehavior interfaces
+var (
+	_ Validator        = (*wasmValidator)(nil)
+	_ ClosableBehavior = (*wasmValidator)(nil)
+)
+
+func NewWASMValidator(config state.Node) (_ Validator, err error) {
+	defer errors.Annotate(&err, "NewWASMValidator")
+
+	sandbox, err := newWASMSandbox(config, "validate_tx")
+	if err != nil {
+		return nil, err
+	}
+	return &wasmValidator{sandbox: sandbox}, nil
+}
+
+// ValidateTx hands the subtree and the (relative) tx to the module.  A module
+// accepts the tx by returning no output, or by returning an object with an
+// empty "error" field.
+func (v *wasmValidator) ValidateTx(node state.Node, tx *Tx) (err error) {
+	defer errors.Annotate(&err, "wasmValidator.ValidateTx")
+
+	convertedPatches, err := wasmPatches(tx.Patches)
+	if err != nil {
+		return err
+	}
+
+	var parentsArr []string
+	for i := range tx.Parents {
+		parentsArr = append(parentsArr, tx.Parents[i].String())
+	}
+
+	input := map[string]interface{}{
+		"state": node,
+		"tx": map[string]interface{}{
+			"id":       tx.ID.String(),
+			"parents":  parentsArr,
+			"from":     tx.From.String(),
+			"stateURI": tx.StateURI,
+			"patches":  convertedPatches,
+		},
+	}
+
+	var output struct {
+		Error string `json:"error"`
+	}
+	hasOutput, err := v.sandbox.call("validate_tx", input, &output)
+	if err != nil {
+		return err
+	} else if hasOutput && output.Error != "" {
+		return errors.New(output.Error)
+	}
+	return nil
+}
+
+func (v *wasmValidator) Close() error {
+	return v.sandbox.Close()
+}
@@ -0,0 +1,271 @@
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree/nelson"
+)
+
+// WASM behaviors (resolvers, validators, and indexers) run in a pure-Go
+// interpreter with no access to the host beyond an empty WASI environment.
+//
+// Modules communicate with the node by exchanging JSON through their linear
+// memory.  Every module must export:
+//   - `memory`
+//   - `alloc(size: i32) -> i32`, which returns a buffer the node can write into
+//
+// and, depending on its role, one of:
+//   - `resolve_state(ptr: i32, len: i32) -> i64`
+//   - `validate_tx(ptr: i32, len: i32) -> i64`
+//   - `index_node(ptr: i32, len: i32) -> i64`
+//
+// These return the location of their JSON output packed as `ptr << 32 | len`
+// (or 0 for no output).  If the module also exports `dealloc(ptr: i32, len: i32)`,
+// it's called to release both buffers after each invocation.
+//
+// Execution is bounded by the config's `fuel` (the number of instructions
+// allowed per invocation, counted by code compiled into the module; see
+// meterWASM), `memoryPages` (64KiB each), and a hard time limit.
+//
+// The runtime lives outside of the Go heap, so sandboxes have to be closed
+// when their behavior is no longer in use.
+type wasmSandbox struct {
+	runtime    wazero.Runtime
+	compiled   wazero.CompiledModule
+	module     api.Module
+	alloc      api.Function
+	dealloc    api.Function
+	fuelGlobal api.MutableGlobal
+	fuel       uint64
+	mu         sync.Mutex
+}
+
+var (
+	ErrWASMOutOfFuel = errors.New("wasm module ran out of fuel")
+
+	wasmDefaultFuel        = uint64(100000000)
+	wasmDefaultMemoryPages = uint64(256)
+	wasmMaxExecutionTime   = 5 * time.Second
+)
+
+func newWASMSandbox(config state.Node, entrypoint string) (_ *wasmSandbox, err error) {
+	defer errors.Annotate(&err, "newWASMSandbox")
+
+	srcval, exists, err := nelson.GetValueRecursive(config, state.Keypath("src"), nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	} else if !exists {
+		return nil, errors.Errorf("wasm behaviors need a 'src' param")
+	}
+
+	readableSrc, ok := nelson.GetReadCloser(srcval)
+	if !ok {
+		return nil, errors.Errorf("wasm behaviors need a 'src' param of type string, []byte, or io.ReadCloser (got %T)", srcval)
+	}
+	defer readableSrc.Close()
+
+	src, err := ioutil.ReadAll(readableSrc)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	fuel, exists, err := config.UintValue(state.Keypath("fuel"))
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		fuel = wasmDefaultFuel
+	}
+
+	memoryPages, exists, err := config.UintValue(state.Keypath("memoryPages"))
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		memoryPages = wasmDefaultMemoryPages
+	}
+
+	src, err = meterWASM(src, fuel)
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox := &wasmSandbox{fuel: fuel}
+
+	ctx := context.Background()
+
+	sandbox.runtime = wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigInterpreter().
+		WithMemoryLimitPages(uint32(memoryPages)).
+		WithCloseOnContextDone(true),
+	)
+	defer func() {
+		if err != nil {
+			sandbox.runtime.Close(ctx)
+		}
+	}()
+
+	// Modules compiled for WASI get an environment with no args, env vars, or filesystem
+	_, err = wasi_snapshot_preview1.Instantiate(ctx, sandbox.runtime)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sandbox.compiled, err = sandbox.runtime.CompileModule(ctx, src)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if _, exists := sandbox.compiled.ExportedFunctions()["alloc"]; !exists {
+		return nil, errors.New("wasm module must export 'alloc'")
+	} else if _, exists := sandbox.compiled.ExportedFunctions()[entrypoint]; !exists {
+		return nil, errors.Errorf("wasm module must export '%v'", entrypoint)
+	} else if _, exists := sandbox.compiled.ExportedMemories()["memory"]; !exists {
+		return nil, errors.New("wasm module must export its memory")
+	}
+
+	err = sandbox.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sandbox, nil
+}
+
+// instantiate creates a fresh instance of the module.  This happens once up
+// front, and again whenever an invocation is aborted for exceeding its limits
+// (which closes the instance).
+func (s *wasmSandbox) instantiate(ctx context.Context) error {
+	module, err := s.runtime.InstantiateModule(ctx, s.compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	s.module = module
+	s.alloc = module.ExportedFunction("alloc")
+	s.dealloc = module.ExportedFunction("dealloc")
+	s.fuelGlobal = module.ExportedGlobal(wasmFuelExport).(api.MutableGlobal)
+	return nil
+}
+
+// Close releases the runtime, along with the compiled module and any instance
+// of it.  Calls made after closing fail.
+func (s *wasmSandbox) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.runtime == nil {
+		return nil
+	}
+	err := s.runtime.Close(context.Background())
+	s.runtime = nil
+	s.module = nil
+	return errors.WithStack(err)
+}
+
+// call JSON-encodes the input, hands it to the given export, and decodes its
+// output into `output`.  It returns false if the module produced no output.
+func (s *wasmSandbox) call(entrypoint string, input interface{}, output interface{}) (_ bool, err error) {
+	defer errors.Annotate(&err, "wasm %v", entrypoint)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inputBytes, err := json.Marshal(input)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	if s.runtime == nil {
+		return false, errors.Wrap(errors.ErrClosed, "wasm sandbox")
+	} else if s.module == nil {
+		err = s.instantiate(context.Background())
+		if err != nil {
+			return false, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wasmMaxExecutionTime)
+	defer cancel()
+	s.fuelGlobal.Set(s.fuel)
+	defer func() {
+		if ctx.Err() != nil {
+			// The instance was closed when the context was canceled
+			s.module = nil
+		} else if err != nil && s.fuelGlobal.Get() == wasmOutOfFuel {
+			// The module trapped partway through, so its memory may be inconsistent
+			s.module.Close(context.Background())
+			s.module = nil
+			err = errors.Wrap(ErrWASMOutOfFuel, err.Error())
+		}
+	}()
+
+	results, err := s.alloc.Call(ctx, uint64(len(inputBytes)))
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	inputPtr := uint32(results[0])
+	if !s.module.Memory().Write(inputPtr, inputBytes) {
+		return false, errors.New("alloc returned a buffer outside of the module's memory")
+	}
+
+	results, err = s.module.ExportedFunction(entrypoint).Call(ctx, uint64(inputPtr), uint64(len(inputBytes)))
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	outputPtr, outputLen := uint32(results[0]>>32), uint32(results[0])
+
+	if s.dealloc != nil {
+		defer func() {
+			_, err2 := s.dealloc.Call(ctx, uint64(inputPtr), uint64(len(inputBytes)))
+			if err2 == nil && outputLen > 0 {
+				_, err2 = s.dealloc.Call(ctx, uint64(outputPtr), uint64(outputLen))
+			}
+			if err == nil && err2 != nil {
+				err = errors.WithStack(err2)
+			}
+		}()
+	}
+
+	if outputLen == 0 {
+		return false, nil
+	}
+	outputBytes, ok := s.module.Memory().Read(outputPtr, outputLen)
+	if !ok {
+		return false, errors.New("module returned output outside of its memory")
+	}
+	err = json.Unmarshal(outputBytes, output)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// wasmPatches converts patches into the same shape that the JS behaviors receive.
+func wasmPatches(patches []Patch) ([]interface{}, error) {
+	converted := make([]interface{}, len(patches))
+	for i, patch := range patches {
+		var value interface{}
+		if len(patch.ValueJSON) > 0 {
+			err := json.Unmarshal(patch.ValueJSON, &value)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+
+		convertedPatch := map[string]interface{}{
+			"keys": patch.Keypath.PartStrings(),
+			"val":  value,
+		}
+		if patch.Range != nil {
+			convertedPatch["range"] = []interface{}{patch.Range.Start, patch.Range.End}
+		}
+		converted[i] = convertedPatch
+	}
+	return converted, nil
+}
@@ -0,0 +1,518 @@
+package tree
+
+import (
+	"bytes"
+
+	"redwood.dev/errors"
+)
+
+// meterWASM instruments a WASM module so that it meters its own execution.
+// The module gets a new mutable i64 global (exported as `wasmFuelExport`)
+// holding the fuel that's left, and the code is split into straight-line
+// segments (at function entry and at every block, loop, if, else, and end),
+// each of which starts by charging the number of instructions in it.  Loops
+// pay on every iteration, so modules can't spin without running out of fuel.
+//
+// When a segment can't be paid for, the global is set to `wasmOutOfFuel` and
+// the module traps.  The global starts out holding `fuel`, so that start
+// functions are metered as well.
+func meterWASM(src []byte, fuel uint64) (_ []byte, err error) {
+	defer errors.Annotate(&err, "meterWASM")
+
+	if len(src) < 8 || !bytes.Equal(src[:8], []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}) {
+		return nil, errors.New("not a wasm module")
+	}
+
+	type section struct {
+		id      byte
+		payload []byte
+	}
+	var sections []section
+
+	r := &wasmReader{buf: src, pos: 8}
+	for !r.done() {
+		id := r.byte()
+		size := r.u32()
+		payload := r.bytes(int(size))
+		if r.err != nil {
+			return nil, r.err
+		}
+		sections = append(sections, section{id, payload})
+	}
+
+	// The fuel global goes after the module's own globals (and imported ones)
+	var numGlobals uint32
+	for _, s := range sections {
+		switch s.id {
+		case wasmSectionImport:
+			n, err := wasmCountImportedGlobals(s.payload)
+			if err != nil {
+				return nil, err
+			}
+			numGlobals += n
+		case wasmSectionGlobal:
+			r := &wasmReader{buf: s.payload}
+			numGlobals += r.u32()
+			if r.err != nil {
+				return nil, r.err
+			}
+		}
+	}
+	fuelGlobal := numGlobals
+
+	newGlobal := []byte{wasmTypeI64, 0x01, wasmOpI64Const}
+	newGlobal = appendSLEB(newGlobal, int64(fuel))
+	newGlobal = append(newGlobal, wasmOpEnd)
+
+	newExport := appendULEB(nil, uint32(len(wasmFuelExport)))
+	newExport = append(newExport, wasmFuelExport...)
+	newExport = append(newExport, wasmExternGlobal)
+	newExport = appendULEB(newExport, fuelGlobal)
+
+	var out []section
+	var addedGlobal, addedExport bool
+	addGlobal := func(existing []byte) {
+		out = append(out, section{wasmSectionGlobal, appendToVec(existing, newGlobal)})
+		addedGlobal = true
+	}
+	addExport := func(existing []byte) {
+		out = append(out, section{wasmSectionExport, appendToVec(existing, newExport)})
+		addedExport = true
+	}
+
+	for _, s := range sections {
+		// Sections must stay in order, so the global and export sections
+		// are added just before the first section that belongs after them
+		if s.id != wasmSectionCustom {
+			if !addedGlobal && wasmSectionOrder[s.id] > wasmSectionOrder[wasmSectionGlobal] {
+				addGlobal(nil)
+			}
+			if !addedExport && wasmSectionOrder[s.id] > wasmSectionOrder[wasmSectionExport] {
+				addExport(nil)
+			}
+		}
+
+		switch s.id {
+		case wasmSectionGlobal:
+			addGlobal(s.payload)
+		case wasmSectionExport:
+			err := wasmCheckExportNames(s.payload)
+			if err != nil {
+				return nil, err
+			}
+			addExport(s.payload)
+		case wasmSectionCode:
+			payload, err := meterWASMCode(s.payload, fuelGlobal)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, section{s.id, payload})
+		default:
+			out = append(out, s)
+		}
+	}
+	if !addedGlobal {
+		addGlobal(nil)
+	}
+	if !addedExport {
+		addExport(nil)
+	}
+
+	metered := append([]byte(nil), src[:8]...)
+	for _, s := range out {
+		metered = append(metered, s.id)
+		metered = appendULEB(metered, uint32(len(s.payload)))
+		metered = append(metered, s.payload...)
+	}
+	return metered, nil
+}
+
+const (
+	wasmFuelExport = "__redwood_fuel"
+	wasmOutOfFuel  = ^uint64(0)
+
+	wasmSectionCustom = 0
+	wasmSectionImport = 2
+	wasmSectionGlobal = 6
+	wasmSectionExport = 7
+	wasmSectionCode   = 10
+
+	wasmExternGlobal = 0x03
+	wasmTypeI64      = 0x7e
+
+	wasmOpUnreachable = 0x00
+	wasmOpBlock       = 0x02
+	wasmOpLoop        = 0x03
+	wasmOpIf          = 0x04
+	wasmOpElse        = 0x05
+	wasmOpEnd         = 0x0b
+	wasmOpGlobalGet   = 0x23
+	wasmOpGlobalSet   = 0x24
+	wasmOpI64Const    = 0x42
+	wasmOpI64LtU      = 0x54
+	wasmOpI64Sub      = 0x7d
+)
+
+// wasmSectionOrder is the order in which sections must appear.  (The data
+// count section, 12, comes before the code section.)
+var wasmSectionOrder = map[byte]int{
+	1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 6: 6, 7: 7, 8: 8, 9: 9, 12: 10, 10: 11, 11: 12,
+}
+
+// meterWASMCode instruments every function body in a code section.
+func meterWASMCode(payload []byte, fuelGlobal uint32) ([]byte, error) {
+	r := &wasmReader{buf: payload}
+	numFuncs := r.u32()
+	out := appendULEB(nil, numFuncs)
+
+	for i := uint32(0); i < numFuncs && r.err == nil; i++ {
+		size := r.u32()
+		body := r.bytes(int(size))
+		if r.err != nil {
+			break
+		}
+		metered, err := meterWASMFunc(body, fuelGlobal)
+		if err != nil {
+			return nil, errors.Wrapf(err, "func %v", i)
+		}
+		out = appendULEB(out, uint32(len(metered)))
+		out = append(out, metered...)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return out, nil
+}
+
+func meterWASMFunc(body []byte, fuelGlobal uint32) ([]byte, error) {
+	r := &wasmReader{buf: body}
+
+	// Locals
+	numLocalDecls := r.u32()
+	for i := uint32(0); i < numLocalDecls && r.err == nil; i++ {
+		r.u32()
+		r.byte()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	out := append([]byte(nil), body[:r.pos]...)
+
+	// Each segment is copied out along with the charge for it once its end
+	// (and so its length) is known
+	var (
+		segmentStart = r.pos
+		numInstrs    int64
+		depth        = 1
+	)
+	endSegment := func() {
+		out = appendFuelCharge(out, fuelGlobal, numInstrs)
+		out = append(out, body[segmentStart:r.pos]...)
+		segmentStart = r.pos
+		numInstrs = 0
+	}
+
+	for depth > 0 {
+		if r.done() {
+			return nil, errors.New("function body has no end")
+		}
+		op := r.byte()
+		numInstrs++
+
+		switch op {
+		case wasmOpBlock, wasmOpLoop, wasmOpIf:
+			r.blockType()
+			depth++
+			endSegment()
+		case wasmOpElse:
+			endSegment()
+		case wasmOpEnd:
+			depth--
+			endSegment()
+		default:
+			err := r.skipImmediates(op)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+	if !r.done() {
+		return nil, errors.New("function body continues past its end")
+	}
+	return out, nil
+}
+
+// appendFuelCharge appends:
+//
+//	(if (i64.lt_u (global.get $fuel) (i64.const cost))
+//	  (then
+//	    (global.set $fuel (i64.const -1))
+//	    unreachable))
+//	(global.set $fuel (i64.sub (global.get $fuel) (i64.const cost)))
+func appendFuelCharge(out []byte, fuelGlobal uint32, cost int64) []byte {
+	out = append(out, wasmOpGlobalGet)
+	out = appendULEB(out, fuelGlobal)
+	out = append(out, wasmOpI64Const)
+	out = appendSLEB(out, cost)
+	out = append(out, wasmOpI64LtU, wasmOpIf, 0x40, wasmOpI64Const)
+	out = appendSLEB(out, -1)
+	out = append(out, wasmOpGlobalSet)
+	out = appendULEB(out, fuelGlobal)
+	out = append(out, wasmOpUnreachable, wasmOpEnd)
+
+	out = append(out, wasmOpGlobalGet)
+	out = appendULEB(out, fuelGlobal)
+	out = append(out, wasmOpI64Const)
+	out = appendSLEB(out, cost)
+	out = append(out, wasmOpI64Sub, wasmOpGlobalSet)
+	out = appendULEB(out, fuelGlobal)
+	return out
+}
+
+func wasmCountImportedGlobals(payload []byte) (uint32, error) {
+	r := &wasmReader{buf: payload}
+	numImports := r.u32()
+	var numGlobals uint32
+	for i := uint32(0); i < numImports && r.err == nil; i++ {
+		r.bytes(int(r.u32())) // module
+		r.bytes(int(r.u32())) // name
+		switch kind := r.byte(); kind {
+		case 0x00: // func
+			r.u32()
+		case 0x01: // table
+			r.byte()
+			r.limits()
+		case 0x02: // memory
+			r.limits()
+		case wasmExternGlobal:
+			r.byte()
+			r.byte()
+			numGlobals++
+		default:
+			return 0, errors.Errorf("unknown import kind 0x%x", kind)
+		}
+	}
+	return numGlobals, r.err
+}
+
+func wasmCheckExportNames(payload []byte) error {
+	r := &wasmReader{buf: payload}
+	numExports := r.u32()
+	for i := uint32(0); i < numExports && r.err == nil; i++ {
+		name := r.bytes(int(r.u32()))
+		if string(name) == wasmFuelExport {
+			return errors.Errorf("wasm modules may not export '%v'", wasmFuelExport)
+		}
+		r.byte()
+		r.u32()
+	}
+	return r.err
+}
+
+// appendToVec appends an entry to a section that's a vector of entries (or
+// starts a new one if `vec` is empty).
+func appendToVec(vec []byte, entry []byte) []byte {
+	var count uint32
+	var rest []byte
+	if len(vec) > 0 {
+		r := &wasmReader{buf: vec}
+		count = r.u32()
+		rest = vec[r.pos:]
+	}
+	out := appendULEB(nil, count+1)
+	out = append(out, rest...)
+	return append(out, entry...)
+}
+
+func appendULEB(out []byte, x uint32) []byte {
+	for {
+		b := byte(x & 0x7f)
+		x >>= 7
+		if x == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func appendSLEB(out []byte, x int64) []byte {
+	for {
+		b := byte(x & 0x7f)
+		x >>= 7
+		if (x == 0 && b&0x40 == 0) || (x == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+// wasmReader decodes the parts of the binary format that metering needs.  The
+// first error it hits is kept in `err`, after which it returns zero values.
+type wasmReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+var errWASMTruncated = errors.New("wasm module is truncated")
+
+func (r *wasmReader) done() bool {
+	return r.err != nil || r.pos >= len(r.buf)
+}
+
+func (r *wasmReader) byte() byte {
+	if r.err != nil {
+		return 0
+	} else if r.pos >= len(r.buf) {
+		r.err = errWASMTruncated
+		return 0
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *wasmReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	} else if n < 0 || r.pos+n > len(r.buf) {
+		r.err = errWASMTruncated
+		return nil
+	}
+	bs := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return bs
+}
+
+func (r *wasmReader) u32() uint32 {
+	var x uint32
+	for shift := uint(0); shift < 35; shift += 7 {
+		b := r.byte()
+		x |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return x
+		}
+	}
+	if r.err == nil {
+		r.err = errors.New("wasm module has an overlong integer")
+	}
+	return 0
+}
+
+// skipLEB skips a signed or unsigned LEB128 integer.
+func (r *wasmReader) skipLEB() {
+	for i := 0; i < 10; i++ {
+		if r.byte()&0x80 == 0 {
+			return
+		}
+	}
+	if r.err == nil {
+		r.err = errors.New("wasm module has an overlong integer")
+	}
+}
+
+func (r *wasmReader) limits() {
+	flags := r.byte()
+	r.u32()
+	if flags&0x01 != 0 {
+		r.u32()
+	}
+}
+
+func (r *wasmReader) blockType() {
+	if r.pos >= len(r.buf) {
+		r.err = errWASMTruncated
+		return
+	}
+	switch r.buf[r.pos] {
+	case 0x40, 0x7f, 0x7e, 0x7d, 0x7c, 0x7b, 0x70, 0x6f:
+		r.pos++
+	default:
+		r.skipLEB() // type index
+	}
+}
+
+func (r *wasmReader) memarg() {
+	r.u32()
+	r.u32()
+}
+
+func (r *wasmReader) skipImmediates(op byte) error {
+	switch {
+	case op == 0x0c, op == 0x0d: // br, br_if
+		r.u32()
+	case op == 0x0e: // br_table
+		n := r.u32()
+		for i := uint32(0); i <= n && r.err == nil; i++ {
+			r.u32()
+		}
+	case op == 0x10: // call
+		r.u32()
+	case op == 0x11: // call_indirect
+		r.u32()
+		r.u32()
+	case op == 0x1c: // select t*
+		r.bytes(int(r.u32()))
+	case op >= 0x20 && op <= 0x26: // locals, globals, table.get/set
+		r.u32()
+	case op >= 0x28 && op <= 0x3e: // loads and stores
+		r.memarg()
+	case op == 0x3f, op == 0x40: // memory.size, memory.grow
+		r.u32()
+	case op == 0x41, op == 0x42: // i32.const, i64.const
+		r.skipLEB()
+	case op == 0x43: // f32.const
+		r.bytes(4)
+	case op == 0x44: // f64.const
+		r.bytes(8)
+	case op == 0xd0: // ref.null
+		r.byte()
+	case op == 0xd2: // ref.func
+		r.u32()
+	case op == 0xfc:
+		return r.skipMiscImmediates()
+	case op == 0xfd:
+		return r.skipVectorImmediates()
+	case op <= 0x01, op == 0x0f, op == 0x1a, op == 0x1b, op >= 0x45 && op <= 0xc4, op == 0xd1:
+		// No immediates
+	default:
+		return errors.Errorf("unsupported wasm instruction 0x%x", op)
+	}
+	return nil
+}
+
+func (r *wasmReader) skipMiscImmediates() error {
+	switch op := r.u32(); {
+	case op <= 7: // saturating truncation
+	case op == 8, op == 10, op == 12, op == 14: // memory.init, memory.copy, table.init, table.copy
+		r.u32()
+		r.u32()
+	case op == 9, op == 11, op == 13, op >= 15 && op <= 17: // data.drop, memory.fill, elem.drop, table.grow/size/fill
+		r.u32()
+	default:
+		return errors.Errorf("unsupported wasm instruction 0xfc %v", op)
+	}
+	return nil
+}
+
+func (r *wasmReader) skipVectorImmediates() error {
+	switch op := r.u32(); {
+	case op <= 11, op == 92, op == 93: // loads and stores
+		r.memarg()
+	case op == 12, op == 13: // v128.const, i8x16.shuffle
+		r.bytes(16)
+	case op >= 21 && op <= 34: // lane extraction and replacement
+		r.byte()
+	case op >= 84 && op <= 91: // lane loads and stores
+		r.memarg()
+		r.byte()
+	case op <= 255:
+		// No immediates
+	default:
+		return errors.Errorf("unsupported wasm instruction 0xfd %v", op)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package tree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/tree"
+	"redwood.dev/types"
+)
+
+// testWASMModule is the binary encoding of:
+//
+//	(module
+//	  (memory (export "memory") 1)
+//	  (data (i32.const 2048) "{\"error\":\"nope\"}")
+//	  (func (export "alloc") (param i32) (result i32)
+//	    i32.const 1024)
+//	  (func (export "validate_tx") (param i32 i32) (result i64)
+//	    i64.const 0x80000000010) ;; 2048 << 32 | 16
+//	  (func (export "index_node") (param i32 i32) (result i64)
+//	    (loop (call $nop) (br 0))
+//	    i64.const 0)
+//	  (func $nop))
+var testWASMModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x0f, 0x03, 0x60, 0x01, 0x7f, 0x01, 0x7f,
+	0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e, 0x60, 0x00, 0x00, 0x03, 0x05, 0x04, 0x00, 0x01, 0x01, 0x02,
+	0x05, 0x03, 0x01, 0x00, 0x01, 0x07, 0x2d, 0x04, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x02,
+	0x00, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x00, 0x00, 0x0b, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x74, 0x78, 0x00, 0x01, 0x0a, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x6e, 0x6f,
+	0x64, 0x65, 0x00, 0x02, 0x0a, 0x21, 0x04, 0x05, 0x00, 0x41, 0x80, 0x08, 0x0b, 0x0a, 0x00, 0x42,
+	0x90, 0x80, 0x80, 0x80, 0x80, 0x80, 0x02, 0x0b, 0x0b, 0x00, 0x03, 0x40, 0x10, 0x03, 0x0c, 0x00,
+	0x0b, 0x42, 0x00, 0x0b, 0x02, 0x00, 0x0b, 0x0b, 0x17, 0x01, 0x00, 0x41, 0x80, 0x10, 0x0b, 0x10,
+	0x7b, 0x22, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x3a, 0x22, 0x6e, 0x6f, 0x70, 0x65, 0x22, 0x7d,
+}
+
+// testWASMResolverModule is the binary encoding of:
+//
+//	(module
+//	  (memory (export "memory") 1)
+//	  (data (i32.const 2048) "{\"state\":{\"foo\":\"baz\"}}")
+//	  (func (export "alloc") (param i32) (result i32)
+//	    i32.const 1024)
+//	  (func (export "resolve_state") (param i32 i32) (result i64)
+//	    i64.const 0x80000000017) ;; 2048 << 32 | 23
+//	  (func (export "index_node") (param i32 i32) (result i64)
+//	    (loop (br 0))
+//	    i64.const 0))
+var testWASMResolverModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x0c, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f,
+	0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e, 0x03, 0x04, 0x03, 0x00, 0x01, 0x01, 0x05, 0x03, 0x01, 0x00,
+	0x01, 0x07, 0x2f, 0x04, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x02, 0x00, 0x05, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x00, 0x00, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x00, 0x01, 0x0a, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x6e, 0x6f, 0x64, 0x65,
+	0x00, 0x02, 0x0a, 0x1c, 0x03, 0x05, 0x00, 0x41, 0x80, 0x08, 0x0b, 0x0a, 0x00, 0x42, 0x97, 0x80,
+	0x80, 0x80, 0x80, 0x80, 0x02, 0x0b, 0x09, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x42, 0x00, 0x0b,
+	0x0b, 0x1e, 0x01, 0x00, 0x41, 0x80, 0x10, 0x0b, 0x17, 0x7b, 0x22, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x22, 0x3a, 0x7b, 0x22, 0x66, 0x6f, 0x6f, 0x22, 0x3a, 0x22, 0x62, 0x61, 0x7a, 0x22, 0x7d, 0x7d,
+}
+
+func TestWASMBehaviors(t *testing.T) {
+	configFor := func(module []byte, fuel uint64) state.Node {
+		return state.NewMemoryNodeWithValue(map[string]interface{}{
+			"Content-Type": "wasm",
+			"src":          string(module),
+			"fuel":         fuel,
+		})
+	}
+	config := func(fuel uint64) state.Node {
+		return configFor(testWASMModule, fuel)
+	}
+
+	t.Run("validators can reject txs", func(t *testing.T) {
+		validator, err := tree.NewWASMValidator(config(1000))
+		require.NoError(t, err)
+
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{"foo": "bar"})
+		err = validator.ValidateTx(node, &tree.Tx{ID: state.RandomVersion()})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "nope")
+	})
+
+	t.Run("modules are stopped when they run out of fuel", func(t *testing.T) {
+		indexer, err := tree.NewWASMIndexer(config(1000))
+		require.NoError(t, err)
+
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{"foo": "bar"})
+		for i := 0; i < 2; i++ {
+			_, _, err = indexer.IndexNode(state.Keypath("foo"), node)
+			require.Error(t, err)
+			require.True(t, errors.Cause(err) == tree.ErrWASMOutOfFuel, "expected out of fuel error, got: %v", err)
+		}
+	})
+
+	t.Run("tight loops run out of fuel", func(t *testing.T) {
+		indexer, err := tree.NewWASMIndexer(configFor(testWASMResolverModule, 1000))
+		require.NoError(t, err)
+
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{"foo": "bar"})
+		for i := 0; i < 2; i++ {
+			start := time.Now()
+			_, _, err = indexer.IndexNode(state.Keypath("foo"), node)
+			require.Error(t, err)
+			require.True(t, errors.Cause(err) == tree.ErrWASMOutOfFuel, "expected out of fuel error, got: %v", err)
+			require.Less(t, int64(time.Since(start)), int64(time.Second))
+		}
+	})
+
+	t.Run("resolvers can update the state", func(t *testing.T) {
+		resolver, err := tree.NewWASMResolver(configFor(testWASMResolverModule, 1000), nil)
+		require.NoError(t, err)
+
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{"foo": "bar"})
+		patches := []tree.Patch{{Keypath: state.Keypath("foo"), ValueJSON: []byte(`"baz"`)}}
+		for i := 0; i < 2; i++ {
+			err = resolver.ResolveState(node, nil, types.RandomAddress(), state.RandomVersion(), nil, patches)
+			require.NoError(t, err)
+
+			val, exists, err := node.Value(state.Keypath("foo"), nil)
+			require.NoError(t, err)
+			require.True(t, exists)
+			require.Equal(t, "baz", val)
+		}
+	})
+
+	t.Run("closed behaviors can't be called", func(t *testing.T) {
+		validator, err := tree.NewWASMValidator(config(1000))
+		require.NoError(t, err)
+
+		closable, is := validator.(tree.ClosableBehavior)
+		require.True(t, is)
+		require.NoError(t, closable.Close())
+		require.NoError(t, closable.Close())
+
+		node := state.NewMemoryNodeWithValue(map[string]interface{}{"foo": "bar"})
+		err = validator.ValidateTx(node, &tree.Tx{ID: state.RandomVersion()})
+		require.True(t, errors.Cause(err) == errors.ErrClosed, "expected closed error, got: %v", err)
+	})
+
+	t.Run("modules must export the behavior's entrypoint", func(t *testing.T) {
+		_, err := tree.NewWASMResolver(config(1000), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve_state")
+	})
+}
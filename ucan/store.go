@@ -0,0 +1,252 @@
+package ucan
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"redwood.dev/errors"
+	"redwood.dev/log"
+	"redwood.dev/state"
+	"redwood.dev/types"
+)
+
+// Store holds revocations as well as the credentials (verified tokens) that
+// peers have presented to us, so that access checks that only know a peer's
+// address (like the tree protocol's ACL) can find the tokens they hold.
+type Store interface {
+	Revokers(tokenHash types.Hash) types.AddressSet
+	AddRevocation(rev Revocation) error
+
+	Credentials(audience types.Address) []*UCAN
+	SaveCredential(u *UCAN) error
+
+	DebugPrint()
+}
+
+const (
+	// MaxCredentialsPerAudience bounds how many tokens we keep for a single
+	// address.  Once it's reached, the token that expires soonest is dropped
+	// to make room.
+	MaxCredentialsPerAudience = 16
+	// MaxCredentials bounds the number of tokens we keep altogether, as
+	// anyone holding a delegable token can mint new ones for new addresses.
+	MaxCredentials = 4096
+)
+
+var ErrTooManyCredentials = errors.New("too many ucan credentials are stored")
+
+type store struct {
+	log.Logger
+	db     *state.DBTree
+	data   storeData
+	dataMu sync.RWMutex
+}
+
+type storeData struct {
+	Revocations map[string]map[string]bool   `tree:"revocations"` // map[tokenHash]map[revokerAddr]
+	Credentials map[string]map[string]string `tree:"credentials"` // map[audienceAddr]map[tokenHash]token
+}
+
+var storeRootKeypath = state.Keypath("ucan")
+
+func NewStore(db *state.DBTree) (*store, error) {
+	s := &store{
+		Logger: log.NewLogger("ucan store"),
+		db:     db,
+	}
+	s.Infof(0, "opening ucan store")
+	err := s.loadData()
+	return s, err
+}
+
+func (s *store) loadData() error {
+	node := s.db.State(false)
+	defer node.Close()
+
+	err := node.NodeAt(storeRootKeypath, nil).Scan(&s.data)
+	if errors.Cause(err) == errors.Err404 {
+		// do nothing
+	} else if err != nil {
+		return err
+	}
+	if s.data.Revocations == nil {
+		s.data.Revocations = make(map[string]map[string]bool)
+	}
+	if s.data.Credentials == nil {
+		s.data.Credentials = make(map[string]map[string]string)
+	}
+	return nil
+}
+
+func (s *store) Revokers(tokenHash types.Hash) types.AddressSet {
+	s.dataMu.RLock()
+	defer s.dataMu.RUnlock()
+
+	revokers := types.NewAddressSet(nil)
+	for addrHex := range s.data.Revocations[tokenHash.Hex()] {
+		addr, err := types.AddressFromHex(addrHex)
+		if err != nil {
+			s.Errorf("bad revoker address '%v'", addrHex)
+			continue
+		}
+		revokers.Add(addr)
+	}
+	return revokers
+}
+
+func (s *store) AddRevocation(rev Revocation) error {
+	revoker, err := rev.Revoker()
+	if err != nil {
+		return err
+	}
+
+	s.dataMu.Lock()
+	defer s.dataMu.Unlock()
+
+	if s.data.Revocations[rev.TokenHash.Hex()] == nil {
+		s.data.Revocations[rev.TokenHash.Hex()] = make(map[string]bool)
+	}
+	s.data.Revocations[rev.TokenHash.Hex()][revoker.Hex()] = true
+
+	node := s.db.State(true)
+	defer node.Close()
+
+	err = node.Set(s.keypathForRevocation(rev.TokenHash, revoker), nil, true)
+	if err != nil {
+		return err
+	}
+	return node.Save()
+}
+
+// Credentials returns the unexpired tokens that have been presented by the
+// given address.  They must still be verified before they're relied upon.
+func (s *store) Credentials(audience types.Address) []*UCAN {
+	s.dataMu.RLock()
+	defer s.dataMu.RUnlock()
+
+	var creds []*UCAN
+	for _, token := range s.data.Credentials[audience.Hex()] {
+		u, err := Parse(token)
+		if err != nil {
+			s.Errorf("bad stored credential: %v", err)
+			continue
+		} else if time.Now().After(u.Expiry()) {
+			continue
+		}
+		creds = append(creds, u)
+	}
+	return creds
+}
+
+// SaveCredential stores a token for its audience.  Expired tokens are dropped
+// along the way, and the store is bounded by MaxCredentialsPerAudience and
+// MaxCredentials.
+func (s *store) SaveCredential(u *UCAN) error {
+	s.dataMu.Lock()
+	defer s.dataMu.Unlock()
+
+	audience := u.Audience()
+	if _, exists := s.data.Credentials[audience.Hex()][u.Hash().Hex()]; exists {
+		return nil
+	}
+
+	node := s.db.State(true)
+	defer node.Close()
+
+	err := s.dropExpiredCredentials(node, audience.Hex())
+	if err != nil {
+		return err
+	}
+
+	if len(s.data.Credentials[audience.Hex()]) >= MaxCredentialsPerAudience {
+		err := s.dropSoonestExpiringCredential(node, audience.Hex())
+		if err != nil {
+			return err
+		}
+	} else if s.numCredentials() >= MaxCredentials {
+		for audienceHex := range s.data.Credentials {
+			err := s.dropExpiredCredentials(node, audienceHex)
+			if err != nil {
+				return err
+			}
+		}
+		if s.numCredentials() >= MaxCredentials {
+			return ErrTooManyCredentials
+		}
+	}
+
+	if s.data.Credentials[audience.Hex()] == nil {
+		s.data.Credentials[audience.Hex()] = make(map[string]string)
+	}
+	s.data.Credentials[audience.Hex()][u.Hash().Hex()] = u.String()
+
+	err = node.Set(s.keypathForCredential(audience.Hex(), u.Hash().Hex()), nil, u.String())
+	if err != nil {
+		return err
+	}
+	return node.Save()
+}
+
+func (s *store) dropExpiredCredentials(node *state.DBNode, audienceHex string) error {
+	now := time.Now()
+	for tokenHashHex, token := range s.data.Credentials[audienceHex] {
+		existing, err := Parse(token)
+		if err == nil && now.Before(existing.Expiry()) {
+			continue
+		}
+		err = s.deleteCredential(node, audienceHex, tokenHashHex)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *store) dropSoonestExpiringCredential(node *state.DBNode, audienceHex string) error {
+	var soonest string
+	var soonestExpiry time.Time
+	for tokenHashHex, token := range s.data.Credentials[audienceHex] {
+		existing, err := Parse(token)
+		if err != nil {
+			return s.deleteCredential(node, audienceHex, tokenHashHex)
+		} else if soonest == "" || existing.Expiry().Before(soonestExpiry) {
+			soonest = tokenHashHex
+			soonestExpiry = existing.Expiry()
+		}
+	}
+	if soonest == "" {
+		return nil
+	}
+	return s.deleteCredential(node, audienceHex, soonest)
+}
+
+func (s *store) deleteCredential(node *state.DBNode, audienceHex, tokenHashHex string) error {
+	delete(s.data.Credentials[audienceHex], tokenHashHex)
+	if len(s.data.Credentials[audienceHex]) == 0 {
+		delete(s.data.Credentials, audienceHex)
+	}
+	return node.Delete(s.keypathForCredential(audienceHex, tokenHashHex), nil)
+}
+
+func (s *store) numCredentials() int {
+	var n int
+	for _, creds := range s.data.Credentials {
+		n += len(creds)
+	}
+	return n
+}
+
+func (s *store) keypathForRevocation(tokenHash types.Hash, revoker types.Address) state.Keypath {
+	return storeRootKeypath.Pushs("revocations").Pushs(tokenHash.Hex()).Pushs(revoker.Hex())
+}
+
+func (s *store) keypathForCredential(audienceHex, tokenHashHex string) state.Keypath {
+	return storeRootKeypath.Pushs("credentials").Pushs(audienceHex).Pushs(tokenHashHex)
+}
+
+func (s *store) DebugPrint() {
+	node := s.db.State(false)
+	defer node.Close()
+	node.NodeAt(storeRootKeypath, nil).DebugPrint(func(msg string, args ...interface{}) { fmt.Printf(msg, args...) }, true, 0)
+}
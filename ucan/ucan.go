@@ -0,0 +1,320 @@
+// Package ucan implements UCANs (User Controlled Authorization Networks):
+// JWT-shaped bearer tokens signed by an identity's signing key that grant
+// capabilities on state URIs.  A token can delegate a subset of the
+// capabilities it was granted to another address by embedding the token that
+// granted them as a proof, forming a chain that ends at one of the verifying
+// node's own identities (see Verifier).
+package ucan
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/state"
+	"redwood.dev/types"
+)
+
+type UCAN struct {
+	Header    Header
+	Payload   Payload
+	Signature []byte
+	Proofs    []*UCAN
+
+	encoded string
+}
+
+type Header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	Version   string `json:"ucv"`
+}
+
+type Payload struct {
+	Issuer       types.Address `json:"iss"`
+	Audience     types.Address `json:"aud"`
+	NotBefore    int64         `json:"nbf,omitempty"`
+	Expiry       int64         `json:"exp"`
+	Nonce        string        `json:"nnc,omitempty"`
+	Capabilities []Capability  `json:"att"`
+	Proofs       []string      `json:"prf,omitempty"`
+}
+
+const (
+	Version = "0.8.1"
+
+	// Tokens are signed with recoverable secp256k1 signatures, the same kind
+	// used to sign txs, so the issuer's address can be checked against the
+	// signature without knowing their public key in advance.
+	algorithm = "ES256K-R"
+
+	// maxProofDepth bounds the length of a delegation chain.
+	maxProofDepth = 16
+)
+
+var (
+	ErrInvalidToken     = errors.New("invalid ucan")
+	ErrInvalidSignature = errors.New("invalid ucan signature")
+	ErrNotYetValid      = errors.New("ucan is not yet valid")
+	ErrExpired          = errors.New("ucan has expired")
+	ErrBrokenChain      = errors.New("ucan proof was not issued to the ucan's issuer")
+	ErrEscalation       = errors.New("ucan claims capabilities it was not delegated")
+	ErrRevoked          = errors.New("ucan has been revoked")
+)
+
+// Signer is satisfied by identity.Identity and *crypto.SigKeypair.
+type Signer interface {
+	Address() types.Address
+	SignHash(hash types.Hash) ([]byte, error)
+}
+
+// Issue creates a token signed by the issuer that grants the given capabilities
+// to the audience until the given expiry.  Any proofs must have been issued to
+// the issuer, and must grant every capability being delegated unless the
+// issuer is a root authority for the node that will verify the token.
+func Issue(issuer Signer, audience types.Address, capabilities []Capability, expiry time.Time, proofs ...*UCAN) (*UCAN, error) {
+	nonce := make([]byte, 12)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &UCAN{
+		Header: Header{
+			Algorithm: algorithm,
+			Type:      "JWT",
+			Version:   Version,
+		},
+		Payload: Payload{
+			Issuer:       issuer.Address(),
+			Audience:     audience,
+			Expiry:       expiry.Unix(),
+			Nonce:        hex.EncodeToString(nonce),
+			Capabilities: capabilities,
+		},
+		Proofs: proofs,
+	}
+	for _, proof := range proofs {
+		u.Payload.Proofs = append(u.Payload.Proofs, proof.String())
+	}
+
+	signingInput, err := u.signingInput()
+	if err != nil {
+		return nil, err
+	}
+	u.Signature, err = issuer.SignHash(types.HashBytes([]byte(signingInput)))
+	if err != nil {
+		return nil, err
+	}
+	u.encoded = signingInput + "." + base64.RawURLEncoding.EncodeToString(u.Signature)
+	return u, nil
+}
+
+// Parse decodes a token (optionally prefixed with "Bearer ") and its proofs
+// and checks their signatures.  It does not check expiry, revocation or
+// whether the chain is rooted in an authority; that's the Verifier's job.
+func Parse(token string) (*UCAN, error) {
+	return parse(strings.TrimSpace(strings.TrimPrefix(token, "Bearer ")), 0)
+}
+
+func parse(token string, depth int) (*UCAN, error) {
+	if depth > maxProofDepth {
+		return nil, errors.Wrapf(ErrInvalidToken, "proof chain is longer than %v", maxProofDepth)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.Wrap(ErrInvalidToken, "expected 3 segments")
+	}
+
+	u := &UCAN{encoded: token}
+	err := decodeSegment(parts[0], &u.Header)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidToken, "bad header: %v", err)
+	} else if u.Header.Algorithm != algorithm {
+		return nil, errors.Wrapf(ErrInvalidToken, "unsupported algorithm %q", u.Header.Algorithm)
+	}
+
+	err = decodeSegment(parts[1], &u.Payload)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidToken, "bad payload: %v", err)
+	}
+
+	u.Signature, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidToken, "bad signature: %v", err)
+	}
+
+	hash := types.HashBytes([]byte(parts[0] + "." + parts[1]))
+	sigpubkey, err := crypto.RecoverSigningPubkey(hash, u.Signature)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignature, err.Error())
+	} else if !sigpubkey.VerifySignature(hash, u.Signature) {
+		return nil, ErrInvalidSignature
+	} else if sigpubkey.Address() != u.Payload.Issuer {
+		return nil, errors.Wrapf(ErrInvalidSignature, "signed by %v, not by issuer %v", sigpubkey.Address().Hex(), u.Payload.Issuer.Hex())
+	}
+
+	for _, encodedProof := range u.Payload.Proofs {
+		proof, err := parse(encodedProof, depth+1)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad proof")
+		}
+		u.Proofs = append(u.Proofs, proof)
+	}
+	return u, nil
+}
+
+func decodeSegment(segment string, into interface{}) error {
+	bs, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, into)
+}
+
+func (u *UCAN) signingInput() (string, error) {
+	header, err := json.Marshal(u.Header)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(u.Payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func (u *UCAN) Issuer() types.Address   { return u.Payload.Issuer }
+func (u *UCAN) Audience() types.Address { return u.Payload.Audience }
+
+func (u *UCAN) NotBefore() time.Time {
+	if u.Payload.NotBefore == 0 {
+		return time.Time{}
+	}
+	return time.Unix(u.Payload.NotBefore, 0)
+}
+
+func (u *UCAN) Expiry() time.Time {
+	return time.Unix(u.Payload.Expiry, 0)
+}
+
+// Hash identifies the token for the purposes of revocation.
+func (u *UCAN) Hash() types.Hash {
+	return types.HashBytes([]byte(u.encoded))
+}
+
+// Grants returns true if any of the token's capabilities contain the given
+// capability.  It is only meaningful for tokens returned by Verifier.Verify,
+// which rejects tokens that claim capabilities they weren't delegated.
+func (u *UCAN) Grants(capability Capability) bool {
+	for _, c := range u.Payload.Capabilities {
+		if c.Contains(capability) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *UCAN) String() string {
+	return u.encoded
+}
+
+func (u UCAN) MarshalText() ([]byte, error) {
+	return []byte(u.encoded), nil
+}
+
+func (u *UCAN) UnmarshalText(bs []byte) error {
+	parsed, err := Parse(string(bs))
+	if err != nil {
+		return err
+	}
+	*u = *parsed
+	return nil
+}
+
+// A Capability grants an Ability over a state URI, or over the subtree of it
+// rooted at Keypath.
+type Capability struct {
+	StateURI string        `json:"with"`
+	Keypath  state.Keypath `json:"path,omitempty"`
+	Ability  Ability       `json:"can"`
+}
+
+// AllStateURIs may be used as a Capability's StateURI to cover every state URI,
+// as well as node-level operations (like managing identities and peers) that
+// aren't tied to any one of them.
+const AllStateURIs = "*"
+
+type Ability string
+
+const (
+	AbilityRead  Ability = "tree/read"
+	AbilityWrite Ability = "tree/write"
+	AbilityAll   Ability = "*"
+)
+
+// AdminCapability is the capability required for node-level operations.
+var AdminCapability = Capability{StateURI: AllStateURIs, Ability: AbilityAll}
+
+// Contains returns true if holding c implies holding other.
+func (c Capability) Contains(other Capability) bool {
+	if c.StateURI != AllStateURIs && c.StateURI != other.StateURI {
+		return false
+	} else if !other.Keypath.Normalized().StartsWith(c.Keypath.Normalized()) {
+		return false
+	}
+	return c.Ability.Contains(other.Ability)
+}
+
+// Contains returns true if a implies other.  Being able to write a tree
+// implies being able to read it.
+func (a Ability) Contains(other Ability) bool {
+	switch a {
+	case AbilityAll:
+		return true
+	case AbilityWrite:
+		return other == AbilityWrite || other == AbilityRead
+	case AbilityRead:
+		return other == AbilityRead
+	default:
+		return false
+	}
+}
+
+// A Revocation invalidates a token, and every token delegated from it.  It must
+// be signed by the issuer of the revoked token or of one of its proofs.
+type Revocation struct {
+	TokenHash types.Hash `json:"revoke"`
+	Signature []byte     `json:"sig"`
+}
+
+func Revoke(revoker Signer, u *UCAN) (Revocation, error) {
+	rev := Revocation{TokenHash: u.Hash()}
+	sig, err := revoker.SignHash(rev.signingHash())
+	if err != nil {
+		return Revocation{}, err
+	}
+	rev.Signature = sig
+	return rev, nil
+}
+
+// Revoker returns the address that signed the revocation.
+func (r Revocation) Revoker() (types.Address, error) {
+	sigpubkey, err := crypto.RecoverSigningPubkey(r.signingHash(), r.Signature)
+	if err != nil {
+		return types.Address{}, errors.Wrap(ErrInvalidSignature, err.Error())
+	} else if !sigpubkey.VerifySignature(r.signingHash(), r.Signature) {
+		return types.Address{}, ErrInvalidSignature
+	}
+	return sigpubkey.Address(), nil
+}
+
+func (r Revocation) signingHash() types.Hash {
+	return types.HashBytes([]byte("REVOKE:" + r.TokenHash.Hex()))
+}
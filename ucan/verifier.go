@@ -0,0 +1,199 @@
+package ucan
+
+import (
+	"context"
+	"time"
+
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/types"
+)
+
+// Verifier checks tokens presented to this node.  The node's own identities
+// (those in its KeyStore) are the root authorities: they may issue any
+// capability, and every other issuer must prove that each capability it
+// grants was delegated to it by a chain of tokens ending at one of them.
+type Verifier struct {
+	keyStore identity.KeyStore
+	store    Store
+}
+
+func NewVerifier(keyStore identity.KeyStore, store Store) *Verifier {
+	return &Verifier{keyStore: keyStore, store: store}
+}
+
+// Issue creates a token signed by the node's default identity.
+func (v *Verifier) Issue(audience types.Address, capabilities []Capability, expiry time.Time) (*UCAN, error) {
+	issuer, err := v.keyStore.DefaultPublicIdentity()
+	if err != nil {
+		return nil, err
+	}
+	return Issue(issuer, audience, capabilities, expiry)
+}
+
+// Revoke revokes a token using whichever of the node's identities appears
+// earliest in the token's chain.
+func (v *Verifier) Revoke(u *UCAN) error {
+	roots, err := v.keyStore.Addresses()
+	if err != nil {
+		return err
+	}
+
+	issuer, found := findIssuer(u, roots)
+	if !found {
+		return errors.Wrap(errors.Err403, "none of this node's identities issued the ucan or its proofs")
+	}
+	revoker, err := v.keyStore.IdentityWithAddress(issuer)
+	if err != nil {
+		return err
+	}
+	rev, err := Revoke(revoker, u)
+	if err != nil {
+		return err
+	}
+	return v.store.AddRevocation(rev)
+}
+
+func findIssuer(u *UCAN, issuers types.AddressSet) (types.Address, bool) {
+	for _, proof := range u.Proofs {
+		if issuer, found := findIssuer(proof, issuers); found {
+			return issuer, true
+		}
+	}
+	if issuers.Contains(u.Issuer()) {
+		return u.Issuer(), true
+	}
+	return types.Address{}, false
+}
+
+func (v *Verifier) AddRevocation(rev Revocation) error {
+	return v.store.AddRevocation(rev)
+}
+
+// Verify parses the given token and checks that it is currently valid: that it
+// grants at least one capability, that it and its proofs are unexpired and
+// unrevoked, and that every capability it grants is backed by a chain of
+// delegations from one of the node's identities.  A token is a bearer
+// credential, so it says nothing about who is presenting it; callers that
+// treat its audience as the presenter's identity must check that separately.
+func (v *Verifier) Verify(token string) (*UCAN, error) {
+	u, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	err = v.VerifyParsed(u)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (v *Verifier) VerifyParsed(u *UCAN) error {
+	if len(u.Payload.Capabilities) == 0 {
+		return errors.Wrap(ErrInvalidToken, "ucan grants no capabilities")
+	}
+
+	roots, err := v.keyStore.Addresses()
+	if err != nil {
+		return err
+	}
+	_, err = v.verify(u, roots, time.Now())
+	return err
+}
+
+// verify returns the issuers of the token and of all of its proofs, which are
+// the addresses that are allowed to revoke it.
+func (v *Verifier) verify(u *UCAN, roots types.AddressSet, now time.Time) (types.AddressSet, error) {
+	if now.Before(u.NotBefore()) {
+		return nil, ErrNotYetValid
+	} else if !now.Before(u.Expiry()) {
+		return nil, ErrExpired
+	}
+
+	issuers := types.NewAddressSet([]types.Address{u.Issuer()})
+	for _, proof := range u.Proofs {
+		if proof.Audience() != u.Issuer() {
+			return nil, errors.Wrapf(ErrBrokenChain, "proof audience=%v issuer=%v", proof.Audience().Hex(), u.Issuer().Hex())
+		} else if u.Expiry().After(proof.Expiry()) || u.NotBefore().Before(proof.NotBefore()) {
+			return nil, errors.Wrap(ErrInvalidToken, "ucan outlives its proof")
+		}
+		proofIssuers, err := v.verify(proof, roots, now)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad proof")
+		}
+		for addr := range proofIssuers {
+			issuers.Add(addr)
+		}
+	}
+
+	if len(v.store.Revokers(u.Hash()).Intersection(issuers)) > 0 {
+		return nil, ErrRevoked
+	}
+
+	if roots.Contains(u.Issuer()) {
+		return issuers, nil
+	}
+	for _, capability := range u.Payload.Capabilities {
+		var delegated bool
+		for _, proof := range u.Proofs {
+			if proof.Grants(capability) {
+				delegated = true
+				break
+			}
+		}
+		if !delegated {
+			return nil, errors.Wrapf(ErrEscalation, "with=%v path=%v can=%v", capability.StateURI, capability.Keypath, capability.Ability)
+		}
+	}
+	return issuers, nil
+}
+
+// SaveCredential verifies a token presented by a peer and, if it's valid,
+// stores it so that HasCapability can find it later.
+func (v *Verifier) SaveCredential(token string) (*UCAN, error) {
+	u, err := v.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	return u, v.store.SaveCredential(u)
+}
+
+// HasCapability returns true if any of the given addresses is one of the
+// node's identities, or holds a valid credential granting the capability.
+func (v *Verifier) HasCapability(addresses types.AddressSet, capability Capability) (bool, error) {
+	roots, err := v.keyStore.Addresses()
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for addr := range addresses {
+		if roots.Contains(addr) {
+			return true, nil
+		}
+		for _, u := range v.store.Credentials(addr) {
+			if !u.Grants(capability) {
+				continue
+			}
+			_, err := v.verify(u, roots, now)
+			if err != nil {
+				continue
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying a verified token, so that handlers
+// downstream of the middleware that verified it can check its capabilities.
+func NewContext(ctx context.Context, u *UCAN) context.Context {
+	return context.WithValue(ctx, contextKey{}, u)
+}
+
+func FromContext(ctx context.Context) (*UCAN, bool) {
+	u, ok := ctx.Value(contextKey{}).(*UCAN)
+	return u, ok
+}
@@ -0,0 +1,223 @@
+package ucan_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"redwood.dev/crypto"
+	"redwood.dev/errors"
+	"redwood.dev/identity"
+	"redwood.dev/state"
+	"redwood.dev/types"
+	"redwood.dev/ucan"
+	"redwood.dev/utils/badgerutils"
+)
+
+func TestVerifier(t *testing.T) {
+	badgerOpts := badgerutils.OptsBuilder{}
+	dir := t.TempDir()
+
+	keyStore := identity.NewBadgerKeyStore(badgerOpts.ForPath(filepath.Join(dir, "keystore")), identity.InsecureScryptParams)
+	require.NoError(t, keyStore.Unlock("password", ""))
+	t.Cleanup(func() { keyStore.Close() })
+
+	node, err := keyStore.DefaultPublicIdentity()
+	require.NoError(t, err)
+
+	openStore := func(t *testing.T) (*state.DBTree, ucan.Store) {
+		t.Helper()
+		db, err := state.NewDBTree(badgerOpts.ForPath(filepath.Join(dir, "shared")))
+		require.NoError(t, err)
+		store, err := ucan.NewStore(db)
+		require.NoError(t, err)
+		return db, store
+	}
+
+	db, store := openStore(t)
+	verifier := ucan.NewVerifier(keyStore, store)
+
+	alice, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	bob, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+	mallory, err := crypto.GenerateSigKeypair()
+	require.NoError(t, err)
+
+	readFoo := ucan.Capability{StateURI: "foo.local/bar", Keypath: state.Keypath("messages"), Ability: ucan.AbilityRead}
+	writeFoo := ucan.Capability{StateURI: "foo.local/bar", Ability: ucan.AbilityWrite}
+	expiry := time.Now().Add(time.Hour)
+
+	rootToken, err := verifier.Issue(alice.Address(), []ucan.Capability{writeFoo}, expiry)
+	require.NoError(t, err)
+
+	t.Run("tokens issued by the node are valid", func(t *testing.T) {
+		u, err := verifier.Verify("Bearer " + rootToken.String())
+		require.NoError(t, err)
+		require.Equal(t, node.Address(), u.Issuer())
+		require.Equal(t, alice.Address(), u.Audience())
+		require.True(t, u.Grants(writeFoo))
+		require.True(t, u.Grants(readFoo))
+		require.False(t, u.Grants(ucan.Capability{StateURI: "foo.local/other", Ability: ucan.AbilityRead}))
+		require.False(t, u.Grants(ucan.AdminCapability))
+	})
+
+	t.Run("tokens can be delegated with narrower capabilities", func(t *testing.T) {
+		delegated, err := ucan.Issue(alice, bob.Address(), []ucan.Capability{readFoo}, expiry.Add(-time.Minute), rootToken)
+		require.NoError(t, err)
+
+		u, err := verifier.Verify(delegated.String())
+		require.NoError(t, err)
+		require.Equal(t, bob.Address(), u.Audience())
+		require.True(t, u.Grants(ucan.Capability{StateURI: "foo.local/bar", Keypath: state.Keypath("messages/0"), Ability: ucan.AbilityRead}))
+		require.False(t, u.Grants(ucan.Capability{StateURI: "foo.local/bar", Keypath: state.Keypath("members"), Ability: ucan.AbilityRead}))
+		require.False(t, u.Grants(writeFoo))
+	})
+
+	t.Run("delegated tokens may not claim capabilities they weren't granted", func(t *testing.T) {
+		escalated, err := ucan.Issue(alice, bob.Address(), []ucan.Capability{ucan.AdminCapability}, expiry.Add(-time.Minute), rootToken)
+		require.NoError(t, err)
+		_, err = verifier.Verify(escalated.String())
+		require.True(t, errors.Cause(err) == ucan.ErrEscalation)
+
+		unrooted, err := ucan.Issue(mallory, bob.Address(), []ucan.Capability{readFoo}, expiry)
+		require.NoError(t, err)
+		_, err = verifier.Verify(unrooted.String())
+		require.True(t, errors.Cause(err) == ucan.ErrEscalation)
+	})
+
+	t.Run("proofs must have been issued to the delegator", func(t *testing.T) {
+		stolen, err := ucan.Issue(mallory, bob.Address(), []ucan.Capability{readFoo}, expiry.Add(-time.Minute), rootToken)
+		require.NoError(t, err)
+		_, err = verifier.Verify(stolen.String())
+		require.True(t, errors.Cause(err) == ucan.ErrBrokenChain)
+	})
+
+	t.Run("delegated tokens may not outlive their proofs", func(t *testing.T) {
+		tooLong, err := ucan.Issue(alice, bob.Address(), []ucan.Capability{readFoo}, expiry.Add(time.Hour), rootToken)
+		require.NoError(t, err)
+		_, err = verifier.Verify(tooLong.String())
+		require.True(t, errors.Cause(err) == ucan.ErrInvalidToken)
+	})
+
+	t.Run("expired tokens are rejected", func(t *testing.T) {
+		expired, err := verifier.Issue(alice.Address(), []ucan.Capability{writeFoo}, time.Now().Add(-time.Second))
+		require.NoError(t, err)
+		_, err = verifier.Verify(expired.String())
+		require.True(t, errors.Cause(err) == ucan.ErrExpired)
+	})
+
+	t.Run("tampered tokens are rejected", func(t *testing.T) {
+		payload := rootToken.Payload
+		payload.Audience = mallory.Address()
+		payloadJSON, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		parts := strings.Split(rootToken.String(), ".")
+		parts[1] = base64.RawURLEncoding.EncodeToString(payloadJSON)
+		_, err = verifier.Verify(strings.Join(parts, "."))
+		require.True(t, errors.Cause(err) == ucan.ErrInvalidSignature)
+	})
+
+	t.Run("credentials grant capabilities to their audience", func(t *testing.T) {
+		delegated, err := ucan.Issue(alice, bob.Address(), []ucan.Capability{readFoo}, expiry.Add(-time.Minute), rootToken)
+		require.NoError(t, err)
+		_, err = verifier.SaveCredential(delegated.String())
+		require.NoError(t, err)
+
+		ok, err := verifier.HasCapability(types.NewAddressSet([]types.Address{bob.Address()}), readFoo)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = verifier.HasCapability(types.NewAddressSet([]types.Address{bob.Address()}), writeFoo)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = verifier.HasCapability(types.NewAddressSet([]types.Address{mallory.Address()}), readFoo)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = verifier.HasCapability(types.NewAddressSet([]types.Address{node.Address()}), ucan.AdminCapability)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("tokens that grant nothing are rejected", func(t *testing.T) {
+		selfIssued, err := ucan.Issue(mallory, mallory.Address(), nil, expiry)
+		require.NoError(t, err)
+		_, err = verifier.Verify(selfIssued.String())
+		require.True(t, errors.Cause(err) == ucan.ErrInvalidToken)
+		_, err = verifier.SaveCredential(selfIssued.String())
+		require.True(t, errors.Cause(err) == ucan.ErrInvalidToken)
+		require.Len(t, store.Credentials(mallory.Address()), 0)
+	})
+
+	t.Run("credentials are bounded per audience", func(t *testing.T) {
+		carol, err := crypto.GenerateSigKeypair()
+		require.NoError(t, err)
+
+		soonest, err := ucan.Issue(alice, carol.Address(), []ucan.Capability{readFoo}, expiry.Add(-30*time.Minute), rootToken)
+		require.NoError(t, err)
+		_, err = verifier.SaveCredential(soonest.String())
+		require.NoError(t, err)
+
+		for i := 0; i < ucan.MaxCredentialsPerAudience; i++ {
+			delegated, err := ucan.Issue(alice, carol.Address(), []ucan.Capability{readFoo}, expiry.Add(-time.Minute), rootToken)
+			require.NoError(t, err)
+			_, err = verifier.SaveCredential(delegated.String())
+			require.NoError(t, err)
+		}
+
+		creds := store.Credentials(carol.Address())
+		require.Len(t, creds, ucan.MaxCredentialsPerAudience)
+		for _, u := range creds {
+			require.NotEqual(t, soonest.Hash(), u.Hash())
+		}
+	})
+
+	t.Run("revoking a token revokes everything delegated from it", func(t *testing.T) {
+		delegated, err := ucan.Issue(alice, bob.Address(), []ucan.Capability{readFoo}, expiry.Add(-time.Minute), rootToken)
+		require.NoError(t, err)
+
+		// Only the issuers in the chain may revoke a token
+		rev, err := ucan.Revoke(mallory, rootToken)
+		require.NoError(t, err)
+		require.NoError(t, verifier.AddRevocation(rev))
+		_, err = verifier.Verify(delegated.String())
+		require.NoError(t, err)
+
+		require.NoError(t, verifier.Revoke(delegated))
+		_, err = verifier.Verify(rootToken.String())
+		require.NoError(t, err)
+		_, err = verifier.Verify(delegated.String())
+		require.True(t, errors.Cause(err) == ucan.ErrRevoked)
+
+		other, err := ucan.Issue(alice, bob.Address(), []ucan.Capability{readFoo}, expiry.Add(-time.Minute), rootToken)
+		require.NoError(t, err)
+		_, err = verifier.Verify(other.String())
+		require.NoError(t, err)
+
+		require.NoError(t, verifier.Revoke(rootToken))
+		_, err = verifier.Verify(other.String())
+		require.True(t, errors.Cause(err) == ucan.ErrRevoked)
+
+		ok, err := verifier.HasCapability(types.NewAddressSet([]types.Address{bob.Address()}), readFoo)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("revocations and credentials are persisted", func(t *testing.T) {
+		require.NoError(t, db.Close())
+		db, store := openStore(t)
+		defer db.Close()
+
+		require.Len(t, store.Credentials(bob.Address()), 1)
+		require.True(t, store.Revokers(rootToken.Hash()).Contains(node.Address()))
+		require.True(t, store.Revokers(rootToken.Hash()).Contains(mallory.Address()))
+	})
+}